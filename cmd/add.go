@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"x-tracker/config"
+	"x-tracker/internal/api"
+	"x-tracker/internal/db"
+	"x-tracker/internal/logger"
+	"x-tracker/internal/tracker"
+)
+
+var addFromFile string
+
+var addCmd = &cobra.Command{
+	Use:   "add <username>...",
+	Short: "Add accounts to the watch list",
+	Long: `Look up accounts and add them to the watch list, storing their current
+followings as the baseline (or deferring that to the next check with
+BASELINE_MODE=deferred). If the tracker is running, the running instance
+does the work.
+
+Several usernames may be given, separated by spaces or commas, and
+--from-file reads more from a file with one or more per line; anything
+after a # is ignored. Accounts are added one at a time, and a
+failure doesn't stop the rest.`,
+	RunE: runAdd,
+}
+
+func init() {
+	addCmd.Flags().StringVar(&addFromFile, "from-file", "", "read usernames from a file")
+	rootCmd.AddCommand(addCmd)
+}
+
+func runAdd(cmd *cobra.Command, args []string) error {
+	input := strings.Join(args, " ")
+	if addFromFile != "" {
+		text, err := readUsernameFile(addFromFile)
+		if err != nil {
+			return err
+		}
+		input += "\n" + text
+	}
+	usernames := tracker.SplitUsernames(input)
+	if len(usernames) == 0 {
+		return fmt.Errorf("no usernames given")
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	// Accounts go to the running tracker if there is one; otherwise the
+	// database is opened on the first add and used for the rest
+	var checker *tracker.Tracker
+	var database db.Store
+	defer func() {
+		if database != nil {
+			database.Close()
+			logger.Close()
+		}
+	}()
+	add := func(username string) (string, error) {
+		if checker == nil {
+			if resp, ok, err := delegate(cfg, controlAdd, username); ok {
+				if err != nil {
+					return "", err
+				}
+				return resp.Message, nil
+			}
+
+			var localCfg *config.Config
+			if localCfg, database, err = setup(); err != nil {
+				return "", err
+			}
+			checker = tracker.New(database, api.NewClient(localCfg), nil, localCfg)
+		}
+
+		account, err := checker.AddAccount(username)
+		if err != nil {
+			return "", addError(err)
+		}
+		audit(database, "add", "@"+account.Username, "")
+		return fmt.Sprintf("Added @%s", account.Username), nil
+	}
+
+	if len(usernames) == 1 {
+		message, err := add(usernames[0])
+		if err != nil {
+			return err
+		}
+		fmt.Println(message)
+		return nil
+	}
+
+	var failed []string
+	for i, username := range usernames {
+		message, err := add(username)
+		if err != nil {
+			failed = append(failed, "@"+strings.TrimPrefix(username, "@"))
+			message = fmt.Sprintf("Failed to add @%s: %v", strings.TrimPrefix(username, "@"), err)
+		}
+		fmt.Printf("[%d/%d] %s\n", i+1, len(usernames), message)
+	}
+
+	fmt.Printf("Added %d of %d accounts\n", len(usernames)-len(failed), len(usernames))
+	if len(failed) > 0 {
+		return fmt.Errorf("could not add %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// readUsernameFile reads a list of usernames, leaving out # comments
+func readUsernameFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("opening username file: %w", err)
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("reading username file: %w", err)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// addError points at resync when the account is already watched, which
+// is usually what re-adding it was meant to achieve
+func addError(err error) error {
+	var watched *tracker.AlreadyWatchedError
+	if errors.As(err, &watched) {
+		return fmt.Errorf("%w; run `x-tracker resync %s` to rebuild its followings instead", err, watched.Account.Username)
+	}
+	return err
+}
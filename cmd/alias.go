@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"x-tracker/internal/logger"
+)
+
+var aliasCmd = &cobra.Command{
+	Use:   "alias <username> [alias|none]",
+	Short: "Show or set the name an account is known by",
+	Long: `Give a watched account a name of your own, e.g.
+"x-tracker alias elonmusk 'Tesla CEO'", kept next to its username in the
+watch list export. Use "none" to remove it; without an alias the current one
+is shown.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runAlias,
+}
+
+func init() {
+	rootCmd.AddCommand(aliasCmd)
+}
+
+func runAlias(cmd *cobra.Command, args []string) error {
+	_, database, err := setup()
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+	defer database.Close()
+
+	username := strings.TrimPrefix(args[0], "@")
+	account, err := database.GetWatchedAccountByUsername(username)
+	if err != nil {
+		return fmt.Errorf("loading account: %w", err)
+	}
+	if account == nil {
+		return fmt.Errorf("account @%s is not watched", username)
+	}
+
+	if len(args) == 1 {
+		if account.Alias == "" {
+			fmt.Printf("@%s has no alias\n", account.Username)
+		} else {
+			fmt.Printf("@%s: %s\n", account.Username, account.Alias)
+		}
+		return nil
+	}
+
+	alias := strings.TrimSpace(args[1])
+	if alias == "none" {
+		alias = ""
+	}
+	if err := database.SetAlias(account.ID, alias); err != nil {
+		return fmt.Errorf("setting alias: %w", err)
+	}
+	if alias == "" {
+		audit(database, "alias", "@"+account.Username, "none")
+		fmt.Printf("Removed the alias of @%s\n", account.Username)
+		return nil
+	}
+	audit(database, "alias", "@"+account.Username, alias)
+	fmt.Printf("@%s is now known as %s\n", account.Username, alias)
+	return nil
+}
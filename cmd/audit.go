@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"x-tracker/internal/db"
+	"x-tracker/internal/logger"
+)
+
+var auditLimit int
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "List recent management actions",
+	Long: `List the most recent management actions, newest first: accounts added,
+removed, paused or resynced, tags, mutes, check jitter and notification
+settings changed. Each entry records when it happened, where it was done
+(tui, cli, api or telegram) and by whom: the local user for the TUI and the
+CLI, the client's address for the HTTP API and the sender for Telegram bot
+commands.`,
+	Args: cobra.NoArgs,
+	RunE: runAudit,
+}
+
+func init() {
+	auditCmd.Flags().IntVarP(&auditLimit, "limit", "n", 50, "maximum number of entries")
+	rootCmd.AddCommand(auditCmd)
+}
+
+// audit records an action taken through the CLI
+func audit(database db.Store, action, target, detail string) {
+	database.Audit(db.AuditEntry{
+		Source: db.AuditSourceCLI,
+		Actor:  db.LocalActor(),
+		Action: action,
+		Target: target,
+		Detail: detail,
+	})
+}
+
+func runAudit(cmd *cobra.Command, args []string) error {
+	_, database, err := setup()
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+	defer database.Close()
+
+	entries, err := database.GetAuditLog(auditLimit)
+	if err != nil {
+		return fmt.Errorf("reading the audit log: %w", err)
+	}
+	if len(entries) == 0 {
+		fmt.Println("No management actions recorded")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, "TIME\tSOURCE\tACTOR\tACTION\tTARGET\tDETAIL")
+	for _, entry := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			entry.At.Local().Format("2006-01-02 15:04:05"),
+			entry.Source, entry.Actor, entry.Action,
+			orDash(entry.Target), orDash(entry.Detail))
+	}
+	return nil
+}
+
+// orDash fills an empty table cell
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"x-tracker/config"
+	"x-tracker/internal/backup"
+	"x-tracker/internal/db"
+	"x-tracker/internal/logger"
+)
+
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Manage database snapshots in cloud storage",
+	Long: `Manage the daily database snapshots uploaded to the storage configured
+with BACKUP_URL. The running tracker uploads one automatically when the newest
+snapshot is a day old; these commands work without it.`,
+}
+
+var backupNowCmd = &cobra.Command{
+	Use:   "now",
+	Short: "Upload a snapshot of the database right away",
+	Long: `Take a consistent snapshot of the database, upload it and delete the oldest
+snapshots beyond BACKUP_KEEP.`,
+	Args: cobra.NoArgs,
+	RunE: runBackupNow,
+}
+
+var backupListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the stored snapshots",
+	Long: `List the snapshots at the backup destination, oldest first. Restore one by
+downloading it, decompressing it with gunzip and pointing DB_PATH at it.`,
+	Args: cobra.NoArgs,
+	RunE: runBackupList,
+}
+
+func init() {
+	backupCmd.AddCommand(backupNowCmd)
+	backupCmd.AddCommand(backupListCmd)
+	rootCmd.AddCommand(backupCmd)
+}
+
+// openUploader returns the uploader for the configured backup destination
+func openUploader(cfg *config.Config, database db.Store) (*backup.Uploader, error) {
+	if cfg.BackupURL == "" {
+		return nil, fmt.Errorf("no backup destination, set BACKUP_URL")
+	}
+	store, err := backup.Open(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return backup.NewUploader(store, database, cfg.BackupKeep), nil
+}
+
+func runBackupNow(cmd *cobra.Command, args []string) error {
+	cfg, database, err := setup()
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+	defer database.Close()
+
+	uploader, err := openUploader(cfg, database)
+	if err != nil {
+		return err
+	}
+	snapshot, err := uploader.Upload()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Uploaded %s\n", snapshot.Name)
+	return nil
+}
+
+func runBackupList(cmd *cobra.Command, args []string) error {
+	cfg, database, err := setup()
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+	defer database.Close()
+
+	uploader, err := openUploader(cfg, database)
+	if err != nil {
+		return err
+	}
+	snapshots, err := uploader.Snapshots()
+	if err != nil {
+		return err
+	}
+	if len(snapshots) == 0 {
+		fmt.Println("No snapshots stored yet")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+	for _, snapshot := range snapshots {
+		fmt.Fprintf(w, "%s\t%s\n", snapshot.Name, snapshot.TakenAt.Local().Format("2006-01-02 15:04:05"))
+	}
+	return nil
+}
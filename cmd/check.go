@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"x-tracker/config"
+	"x-tracker/internal/api"
+	"x-tracker/internal/logger"
+	"x-tracker/internal/tracker"
+	"x-tracker/internal/webhook"
+)
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Run one check cycle over all watched accounts",
+	Long: `Check every watched account once, recording changes and sending
+notifications. If the tracker is running, the running instance runs the
+cycle so API calls aren't duplicated.`,
+	Args: cobra.NoArgs,
+	RunE: runCheck,
+}
+
+func init() {
+	rootCmd.AddCommand(checkCmd)
+}
+
+func runCheck(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if resp, ok, err := delegate(cfg, controlCheck); ok {
+		if err != nil {
+			return err
+		}
+		fmt.Println(resp.Message)
+		return nil
+	}
+
+	cfg, database, err := setup()
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+	defer database.Close()
+
+	notifications := webhook.NewNotificationManager(cfg)
+	notifications.SetDeliveryLog(database)
+	notifications.SetEventAnnotator(database)
+	if cfg.DiscordBotToken != "" {
+		// The running tracker picks up reactions to these messages later
+		notifications.SetMessageLog(database)
+	}
+	checker := tracker.New(database, api.NewClient(cfg), notifications, cfg)
+	if err := checker.CheckAll(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Check completed at %s\n", time.Now().Format("15:04:05"))
+	return nil
+}
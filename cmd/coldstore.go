@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"x-tracker/internal/format"
+	"x-tracker/internal/logger"
+)
+
+var (
+	coldstoreOlderThan time.Duration
+	coldstoreVacuum    bool
+)
+
+var coldstoreCmd = &cobra.Command{
+	Use:   "coldstore",
+	Short: "Move old follow events to the cold archive database",
+	Long: `Move follow events older than EVENT_ARCHIVE_AFTER, with their annotations, to
+the SQLite database at EVENT_ARCHIVE_PATH, or the schema of that name with
+DATABASE_URL, keeping their daily counts in the main database. The running tracker does this once a day when
+EVENT_ARCHIVE_AFTER is set; these commands work without it.`,
+}
+
+var coldstoreNowCmd = &cobra.Command{
+	Use:   "now",
+	Short: "Archive old events right away",
+	Args:  cobra.NoArgs,
+	RunE:  runColdstoreNow,
+}
+
+var coldstoreListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the archive runs, newest first",
+	Args:  cobra.NoArgs,
+	RunE:  runColdstoreList,
+}
+
+func init() {
+	coldstoreNowCmd.Flags().DurationVar(&coldstoreOlderThan, "older-than", 0, "archive events older than this, e.g. 2160h, instead of EVENT_ARCHIVE_AFTER")
+	coldstoreNowCmd.Flags().BoolVar(&coldstoreVacuum, "vacuum", false, "shrink the database file afterwards")
+	coldstoreCmd.AddCommand(coldstoreNowCmd)
+	coldstoreCmd.AddCommand(coldstoreListCmd)
+	rootCmd.AddCommand(coldstoreCmd)
+}
+
+func runColdstoreNow(cmd *cobra.Command, args []string) error {
+	cfg, database, err := setup()
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+	defer database.Close()
+
+	olderThan := coldstoreOlderThan
+	if olderThan <= 0 {
+		olderThan = cfg.EventArchiveAfter
+	}
+	if olderThan <= 0 {
+		return fmt.Errorf("no age to archive events at, set EVENT_ARCHIVE_AFTER or pass --older-than")
+	}
+
+	cutoff := time.Now().Add(-olderThan)
+	moved, err := database.ArchiveEvents(cfg.EventArchivePath, cutoff)
+	if err != nil {
+		return fmt.Errorf("archiving events: %w", err)
+	}
+	audit(database, "archive events", cfg.EventArchivePath,
+		fmt.Sprintf("%d events detected before %s", moved, cutoff.Format("2006-01-02 15:04")))
+	fmt.Printf("Moved %s events detected before %s to %s\n", format.Number(moved), cutoff.Local().Format("2006-01-02 15:04"), cfg.EventArchivePath)
+
+	if coldstoreVacuum {
+		if err := database.Vacuum(); err != nil {
+			return fmt.Errorf("vacuuming database: %w", err)
+		}
+		fmt.Println("Vacuumed the database")
+	}
+	return nil
+}
+
+func runColdstoreList(cmd *cobra.Command, args []string) error {
+	_, database, err := setup()
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+	defer database.Close()
+
+	archives, err := database.GetEventArchives()
+	if err != nil {
+		return fmt.Errorf("listing archive runs: %w", err)
+	}
+	if len(archives) == 0 {
+		fmt.Println("No events archived yet")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+	fmt.Fprintln(w, "RUN\tBEFORE\tEVENTS\tARCHIVE")
+	for _, archive := range archives {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", archive.ArchivedAt.Local().Format("2006-01-02 15:04:05"),
+			archive.Cutoff.Local().Format("2006-01-02 15:04"), format.Number(archive.Events), archive.Path)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"x-tracker/config"
+	"x-tracker/internal/api"
+	"x-tracker/internal/daemon"
+	"x-tracker/internal/db"
+	"x-tracker/internal/tracker"
+	"x-tracker/internal/ui"
+)
+
+// Control commands understood by the running tracker
+const (
+	controlAdd    = "add"
+	controlCheck  = "check"
+	controlResync = "resync"
+	controlStatus = "status"
+)
+
+// liveStatus is what the running tracker reports to `x-tracker status`
+type liveStatus struct {
+	PID            int       `json:"pid"`
+	StartedAt      time.Time `json:"started_at"`
+	QuotaRemaining int       `json:"quota_remaining"`
+}
+
+// controlHandlers serves commands delegated by other x-tracker invocations,
+// so they share this process's API client and database connection
+func controlHandlers(checker *tracker.Tracker, database db.Store, apiClient *api.Client, p *tea.Program) map[string]daemon.Handler {
+	startedAt := time.Now()
+
+	return map[string]daemon.Handler{
+		controlAdd: func(args []string) (string, any, error) {
+			if len(args) != 1 {
+				return "", nil, fmt.Errorf("add takes exactly one username")
+			}
+			account, err := checker.AddAccount(args[0])
+			if err != nil {
+				return "", nil, addError(err)
+			}
+			audit(database, "add", "@"+account.Username, "delegated to the running tracker")
+			message := fmt.Sprintf("Added @%s", account.Username)
+			p.Send(ui.AccountsChangedMsg{Notice: message})
+			return message, nil, nil
+		},
+		controlCheck: func(args []string) (string, any, error) {
+			if err := checker.CheckAll(); err != nil {
+				return "", nil, err
+			}
+			message := fmt.Sprintf("Check completed at %s", time.Now().Format("15:04:05"))
+			p.Send(ui.AccountsChangedMsg{Notice: message})
+			return message, nil, nil
+		},
+		controlResync: func(args []string) (string, any, error) {
+			if len(args) != 1 {
+				return "", nil, fmt.Errorf("resync takes exactly one username")
+			}
+			account, err := checker.ResyncAccount(args[0])
+			if err != nil {
+				return "", nil, err
+			}
+			audit(database, "resync", "@"+account.Username, "delegated to the running tracker")
+			message := fmt.Sprintf("Re-synced @%s", account.Username)
+			p.Send(ui.AccountsChangedMsg{Notice: message})
+			return message, nil, nil
+		},
+		controlStatus: func(args []string) (string, any, error) {
+			return "", liveStatus{
+				PID:            os.Getpid(),
+				StartedAt:      startedAt,
+				QuotaRemaining: apiClient.RemainingRequests(),
+			}, nil
+		},
+	}
+}
+
+// delegate runs a command in the running tracker. It returns false if no
+// tracker is running, in which case the caller should do the work itself.
+func delegate(cfg *config.Config, command string, args ...string) (*daemon.Response, bool, error) {
+	resp, err := daemon.Call(cfg.ControlSocket, command, args...)
+	if errors.Is(err, daemon.ErrNotRunning) {
+		return nil, false, nil
+	}
+	return resp, true, err
+}
+
+// decodeData unpacks the data of a control response
+func decodeData(resp *daemon.Response, v any) error {
+	if len(resp.Data) == 0 {
+		return fmt.Errorf("empty response from the running tracker")
+	}
+	return json.Unmarshal(resp.Data, v)
+}
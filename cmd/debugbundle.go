@@ -0,0 +1,321 @@
+package cmd
+
+import (
+	"archive/zip"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"x-tracker/config"
+	"x-tracker/internal/db"
+	"x-tracker/internal/logger"
+)
+
+const (
+	bundleLogFiles = 3       // newest log files included
+	bundleLogBytes = 1 << 20 // of each, only the end is included
+)
+
+var (
+	bundleOutput string
+	bundleRows   int
+)
+
+var debugBundleCmd = &cobra.Command{
+	Use:   "debug-bundle",
+	Short: "Write an anonymized zip of logs, config and sample data for bug reports",
+	Long: `Write a zip file to attach to a GitHub issue. It contains the newest log
+files, the configuration with every secret removed, the schema version, row
+counts and a few sample rows of each table.
+
+User IDs, usernames, profile text and other identifying values in the sample
+rows and logs are replaced by salted hashes. The same value hashes the same
+within a bundle, so rows can still be related to each other and to the logs,
+but the salt is not stored, so the values can't be recovered from the bundle.
+Review the bundle before sharing it all the same.`,
+	Args: cobra.NoArgs,
+	RunE: runDebugBundle,
+}
+
+func init() {
+	debugBundleCmd.Flags().StringVarP(&bundleOutput, "output", "o", "", "zip file to write (default x-tracker-debug-<time>.zip)")
+	debugBundleCmd.Flags().IntVar(&bundleRows, "rows", 20, "sample rows per table")
+	rootCmd.AddCommand(debugBundleCmd)
+}
+
+// bundleInfo is info.json in the bundle
+type bundleInfo struct {
+	CreatedAt     time.Time      `json:"created_at"`
+	GoVersion     string         `json:"go_version"`
+	Platform      string         `json:"platform"`
+	SchemaVersion int            `json:"schema_version"`
+	LatestSchema  int            `json:"latest_schema_version"`
+	RowCounts     map[string]int `json:"row_counts"`
+}
+
+func runDebugBundle(cmd *cobra.Command, args []string) error {
+	cfg, database, err := setup()
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+	defer database.Close()
+
+	path := bundleOutput
+	if path == "" {
+		path = fmt.Sprintf("x-tracker-debug-%s.zip", time.Now().Format("20060102-150405"))
+	}
+
+	anon, err := newAnonymizer(cfg, database)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating bundle: %w", err)
+	}
+	defer file.Close()
+	bundle := zip.NewWriter(file)
+
+	if err := writeBundleData(bundle, cfg, database, anon); err != nil {
+		os.Remove(path)
+		return err
+	}
+	logs, err := writeBundleLogs(bundle, cfg.LogDir, anon)
+	if err != nil {
+		os.Remove(path)
+		return err
+	}
+
+	if err := bundle.Close(); err != nil {
+		os.Remove(path)
+		return fmt.Errorf("writing bundle: %w", err)
+	}
+	fmt.Printf("Wrote %s (%d log files)\n", path, logs)
+	return nil
+}
+
+// writeBundleData adds info.json, config.json and the sample rows
+func writeBundleData(bundle *zip.Writer, cfg *config.Config, database db.Store, anon *anonymizer) error {
+	current, latest, err := database.SchemaVersion()
+	if err != nil {
+		return fmt.Errorf("reading schema version: %w", err)
+	}
+	tables, err := database.Tables()
+	if err != nil {
+		return fmt.Errorf("listing tables: %w", err)
+	}
+
+	info := bundleInfo{
+		CreatedAt:     time.Now(),
+		GoVersion:     runtime.Version(),
+		Platform:      runtime.GOOS + "/" + runtime.GOARCH,
+		SchemaVersion: current,
+		LatestSchema:  latest,
+		RowCounts:     make(map[string]int, len(tables)),
+	}
+	for _, table := range tables {
+		count, err := database.CountRows(table)
+		if err != nil {
+			return fmt.Errorf("counting %s: %w", table, err)
+		}
+		info.RowCounts[table] = count
+
+		rows, err := database.SampleRows(table, bundleRows)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			for column, value := range row {
+				row[column] = anon.value(column, value)
+			}
+		}
+		if err := writeBundleJSON(bundle, "samples/"+table+".json", rows); err != nil {
+			return err
+		}
+	}
+
+	if err := writeBundleJSON(bundle, "info.json", info); err != nil {
+		return err
+	}
+	return writeBundleJSON(bundle, "config.json", cfg.Redacted())
+}
+
+func writeBundleJSON(bundle *zip.Writer, name string, v interface{}) error {
+	w, err := bundle.Create(name)
+	if err != nil {
+		return fmt.Errorf("adding %s: %w", name, err)
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+	return nil
+}
+
+// writeBundleLogs adds the end of the newest uncompressed log files,
+// anonymized line by line, and returns how many were added
+func writeBundleLogs(bundle *zip.Writer, dir string, anon *anonymizer) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("reading log directory: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".log") {
+			names = append(names, entry.Name())
+		}
+	}
+	// Dated names sort oldest first
+	sort.Sort(sort.Reverse(sort.StringSlice(names)))
+	if len(names) > bundleLogFiles {
+		names = names[:bundleLogFiles]
+	}
+
+	for _, name := range names {
+		content, err := readTail(filepath.Join(dir, name), bundleLogBytes)
+		if err != nil {
+			return 0, fmt.Errorf("reading log %s: %w", name, err)
+		}
+		w, err := bundle.Create("logs/" + name)
+		if err != nil {
+			return 0, fmt.Errorf("adding log %s: %w", name, err)
+		}
+		if _, err := io.WriteString(w, anon.text(content)); err != nil {
+			return 0, fmt.Errorf("writing log %s: %w", name, err)
+		}
+	}
+	return len(names), nil
+}
+
+// readTail returns up to the last n bytes of a file, starting at a line
+func readTail(path string, n int64) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return "", err
+	}
+	cut := info.Size() > n
+	if cut {
+		if _, err := file.Seek(-n, io.SeekEnd); err != nil {
+			return "", err
+		}
+	}
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return "", err
+	}
+	if cut {
+		if i := strings.IndexByte(string(content), '\n'); i >= 0 {
+			content = content[i+1:]
+		}
+	}
+	return string(content), nil
+}
+
+// keptColumns hold values that never identify anyone and stay readable
+var keptColumns = map[string]bool{
+	"event_type": true,
+	"status":     true,
+	"field":      true,
+}
+
+var (
+	// Snowflake user IDs and @handles in free text
+	numericIDPattern = regexp.MustCompile(`\b\d{6,20}\b`)
+	handlePattern    = regexp.MustCompile(`@[A-Za-z0-9_]{1,15}\b`)
+)
+
+// anonymizer replaces identifying values with salted hashes
+type anonymizer struct {
+	salt      []byte
+	secrets   []string
+	usernames *regexp.Regexp // watched usernames, nil if there are none
+}
+
+func newAnonymizer(cfg *config.Config, database db.Store) (*anonymizer, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("generating salt: %w", err)
+	}
+	anon := &anonymizer{salt: salt, secrets: cfg.Secrets()}
+
+	accounts, err := database.GetWatchedAccounts()
+	if err != nil {
+		return nil, fmt.Errorf("loading watched accounts: %w", err)
+	}
+	var quoted []string
+	for _, account := range accounts {
+		quoted = append(quoted, regexp.QuoteMeta(account.Username))
+	}
+	if len(quoted) > 0 {
+		anon.usernames = regexp.MustCompile(`\b(` + strings.Join(quoted, "|") + `)\b`)
+	}
+	return anon, nil
+}
+
+// hash returns a short stable stand-in for s
+func (a *anonymizer) hash(s string) string {
+	sum := sha256.Sum256(append(append([]byte{}, a.salt...), s...))
+	return "h:" + hex.EncodeToString(sum[:6])
+}
+
+// value anonymizes a column value. Numbers and times are kept; JSON lists
+// of IDs are hashed element by element.
+func (a *anonymizer) value(column string, v interface{}) interface{} {
+	s, ok := v.(string)
+	if !ok || keptColumns[column] {
+		return v
+	}
+	if column == "error" {
+		return a.text(s)
+	}
+
+	var list []string
+	if json.Unmarshal([]byte(s), &list) == nil {
+		for i := range list {
+			list[i] = a.hash(list[i])
+		}
+		encoded, _ := json.Marshal(list)
+		return string(encoded)
+	}
+	return a.hash(s)
+}
+
+// text anonymizes free text such as log lines: secrets are removed and
+// user IDs, @handles and watched usernames hashed
+func (a *anonymizer) text(s string) string {
+	for _, secret := range a.secrets {
+		s = strings.ReplaceAll(s, secret, "[redacted]")
+	}
+	s = numericIDPattern.ReplaceAllStringFunc(s, a.hash)
+	s = handlePattern.ReplaceAllStringFunc(s, func(handle string) string {
+		return "@" + a.hash(handle[1:])
+	})
+	if a.usernames != nil {
+		s = a.usernames.ReplaceAllStringFunc(s, a.hash)
+	}
+	return s
+}
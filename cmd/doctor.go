@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"x-tracker/config"
+	"x-tracker/internal/api"
+	"x-tracker/internal/db"
+	"x-tracker/internal/format"
+	"x-tracker/internal/webhook"
+)
+
+var (
+	doctorSkipAPI    bool
+	doctorSkipNotify bool
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the configuration, database, API key and notification channels",
+	Long: `Run every health check in one go and print a report: the configuration is
+loaded and validated, the database gets a full integrity check and a search
+for rows left behind by removed accounts, the API key is probed (one API
+request) and a test message is sent to each enabled notification channel.
+The database is only read, but opening it applies pending migrations.
+
+Exits with status 1 if any check failed; warnings don't count.`,
+	Args: cobra.NoArgs,
+	RunE: runDoctor,
+}
+
+func init() {
+	doctorCmd.Flags().BoolVar(&doctorSkipAPI, "skip-api", false, "don't probe the API")
+	doctorCmd.Flags().BoolVar(&doctorSkipNotify, "skip-notify", false, "don't send test notifications")
+	rootCmd.AddCommand(doctorCmd)
+}
+
+// doctorResult is one line of the doctor report
+type doctorResult struct {
+	check  string
+	result string // "ok", "warn", "FAIL" or "skipped"
+	detail string
+}
+
+type doctorReport []doctorResult
+
+func (r *doctorReport) add(check, result, detail string, args ...interface{}) {
+	*r = append(*r, doctorResult{check: check, result: result, detail: fmt.Sprintf(detail, args...)})
+}
+
+func (r doctorReport) failed() int {
+	failed := 0
+	for _, line := range r {
+		if line.result == "FAIL" {
+			failed++
+		}
+	}
+	return failed
+}
+
+func (r doctorReport) print() {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+	fmt.Fprintln(w, "CHECK\tRESULT\tDETAIL")
+	for _, line := range r {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", line.check, line.result, line.detail)
+	}
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	var report doctorReport
+
+	cfg := doctorConfig(&report)
+	if cfg != nil {
+		doctorDatabase(&report, cfg)
+		doctorAPI(&report, cfg)
+		doctorNotifications(&report, cfg)
+	}
+
+	report.print()
+	if failed := report.failed(); failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(report))
+	}
+	return nil
+}
+
+// doctorConfig loads and validates the configuration, returning nil when
+// it can't be used
+func doctorConfig(report *doctorReport) *config.Config {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		report.add("Config", "FAIL", "%v", err)
+		return nil
+	}
+	if err := format.Configure(cfg.NumberFormat, cfg.NumberLocale); err != nil {
+		report.add("Config", "FAIL", "%v", err)
+		return nil
+	}
+	if err := webhook.ConfigureTransport(cfg); err != nil {
+		report.add("Config", "FAIL", "%v", err)
+		return nil
+	}
+	if err := webhook.ConfigureTemplates(cfg); err != nil {
+		report.add("Config", "FAIL", "%v", err)
+		return nil
+	}
+	report.add("Config", "ok", "loaded and valid")
+	return cfg
+}
+
+// doctorDatabase opens the database without offering recovery and checks
+// its integrity, schema version and foreign keys
+func doctorDatabase(report *doctorReport, cfg *config.Config) {
+	open := func() (*db.Database, error) { return db.NewDatabase(cfg.DBPath) }
+	if cfg.DatabaseURL != "" {
+		open = func() (*db.Database, error) { return db.NewPostgresDatabase(cfg.DatabaseURL) }
+	} else if cfg.DBPath != db.MemoryPath {
+		if _, err := os.Stat(cfg.DBPath); errors.Is(err, os.ErrNotExist) {
+			report.add("Database", "warn", "%s doesn't exist yet, it is created on the first run", cfg.DBPath)
+			return
+		}
+	}
+	database, err := open()
+	if db.IsCorrupt(err) {
+		report.add("Database", "FAIL", "corrupted (%v), start x-tracker to salvage it or restore a backup", err)
+		return
+	}
+	if err != nil {
+		report.add("Database", "FAIL", "%v", err)
+		return
+	}
+	defer database.Close()
+	report.add("Database", "ok", "opened")
+
+	current, latest, err := database.SchemaVersion()
+	switch {
+	case err != nil:
+		report.add("Schema", "FAIL", "%v", err)
+	case current > latest:
+		report.add("Schema", "warn", "version %d was written by a newer x-tracker, this build knows up to %d", current, latest)
+	default:
+		report.add("Schema", "ok", "version %d", current)
+	}
+
+	start := time.Now()
+	integrity, err := database.CheckIntegrity()
+	if err != nil {
+		if db.IsCorrupt(err) {
+			report.add("Integrity", "FAIL", "corrupted (%v), start x-tracker to salvage it or restore a backup", err)
+		} else {
+			report.add("Integrity", "FAIL", "%v", err)
+		}
+		return
+	}
+	if len(integrity.Problems) > 0 {
+		report.add("Integrity", "FAIL", "%d problems, first: %s", len(integrity.Problems), integrity.Problems[0])
+	} else {
+		report.add("Integrity", "ok", "no damage found (%s)", time.Since(start).Round(time.Millisecond))
+	}
+
+	if len(integrity.Orphans) == 0 {
+		report.add("Foreign keys", "ok", "no orphaned rows")
+		return
+	}
+	tables := make([]string, 0, len(integrity.Orphans))
+	for table := range integrity.Orphans {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	for _, table := range tables {
+		report.add("Foreign keys", "warn", "%s orphaned rows in %s", format.Number(integrity.Orphans[table]), table)
+	}
+}
+
+func doctorAPI(report *doctorReport, cfg *config.Config) {
+	if doctorSkipAPI {
+		report.add("API", "skipped", "--skip-api")
+		return
+	}
+
+	result, err := api.NewClient(cfg).Probe()
+	switch {
+	case errors.Is(err, api.ErrInvalidKey):
+		report.add("API", "FAIL", "key rejected by %s, check RAPID_API_KEY and RAPID_API_HOST", cfg.RapidAPIHost)
+	case err != nil:
+		report.add("API", "FAIL", "%v", err)
+	case result.Exhausted:
+		report.add("API", "warn", "key accepted, but the quota is used up until it resets")
+	case result.QuotaRemaining >= 0:
+		report.add("API", "ok", "key accepted in %s, %s requests left", result.Latency.Round(time.Millisecond), format.Number(result.QuotaRemaining))
+	default:
+		report.add("API", "ok", "key accepted in %s", result.Latency.Round(time.Millisecond))
+	}
+}
+
+func doctorNotifications(report *doctorReport, cfg *config.Config) {
+	if doctorSkipNotify {
+		report.add("Notifications", "skipped", "--skip-notify")
+		return
+	}
+
+	results := webhook.NewNotificationManager(cfg).SendTest()
+	if len(results) == 0 {
+		report.add("Notifications", "warn", "no channel is enabled, changes are only shown in the UI")
+		return
+	}
+	for _, result := range results {
+		check := "Notify " + result.Channel
+		if result.Err != nil {
+			report.add(check, "FAIL", "%v", result.Err)
+		} else {
+			report.add(check, "ok", "test message sent")
+		}
+	}
+}
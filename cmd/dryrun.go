@@ -0,0 +1,27 @@
+package cmd
+
+import "x-tracker/config"
+
+// dryRun checks accounts against a scratch copy of the database and sends
+// nothing, set by --dry-run on the tracker and run-once
+var dryRun bool
+
+// dryRunConfig returns cfg with everything that reaches outside the
+// process switched off: notification channels, ops alerts, the heartbeat,
+// backups, the reaction poller, Telegram commands, the cold archive and
+// the event log
+func dryRunConfig(cfg *config.Config) *config.Config {
+	dry := *cfg
+	dry.EnableDiscordNotifications = false
+	dry.EnableTelegramNotifications = false
+	dry.EnableSignalNotifications = false
+	dry.EnableDesktopNotifications = false
+	dry.OpsDiscordWebhookURL = ""
+	dry.HeartbeatURL = ""
+	dry.BackupURL = ""
+	dry.DiscordBotToken = ""
+	dry.TelegramCommands = false
+	dry.EventArchiveAfter = 0
+	dry.EventLog = false
+	return &dry
+}
@@ -0,0 +1,346 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"x-tracker/internal/db"
+	"x-tracker/internal/logger"
+)
+
+var (
+	exportFormat string
+	exportOutput string
+	exportLabel  string
+	exportKind   string
+	exportSince  time.Duration
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export tracker data",
+}
+
+var exportAccountsCmd = &cobra.Command{
+	Use:   "accounts",
+	Short: "Export the watch list with metadata as CSV or JSON",
+	Long: `Export every watched account with its platform, user ID, alias, tags, the
+date it was added and its current stored following count, as an inventory
+of the watch list. Tags are separated by semicolons in CSV. "x-tracker
+import --accounts" reads the file back.`,
+	Args: cobra.NoArgs,
+	RunE: runExportAccounts,
+}
+
+var exportEventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "Export follow events with their annotations as CSV or JSON",
+	Long: `Export every recorded follow and unfollow event, dismissed ones included,
+with the annotations attached to it (for example from Discord reactions).
+Use --label to only export events carrying a given annotation.`,
+	Args: cobra.NoArgs,
+	RunE: runExportEvents,
+}
+
+var exportInsightsCmd = &cobra.Command{
+	Use:   "insights",
+	Short: "Export the insights found by the insights job as CSV or JSON",
+	Long: `Export the derived insights stored by the insights job after every check
+cycle: new mutuals between watched accounts, follow sprees, coordinated
+follows and churny targets, newest first. Use --kind to export one kind
+and --since to only export recent ones.`,
+	Args: cobra.NoArgs,
+	RunE: runExportInsights,
+}
+
+func init() {
+	exportAccountsCmd.Flags().StringVarP(&exportFormat, "format", "f", "csv", "output format: csv or json")
+	exportAccountsCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "write to this file instead of stdout")
+	exportEventsCmd.Flags().StringVarP(&exportFormat, "format", "f", "csv", "output format: csv or json")
+	exportEventsCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "write to this file instead of stdout")
+	exportEventsCmd.Flags().StringVarP(&exportLabel, "label", "l", "", "only export events with this annotation")
+	exportInsightsCmd.Flags().StringVarP(&exportFormat, "format", "f", "csv", "output format: csv or json")
+	exportInsightsCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "write to this file instead of stdout")
+	exportInsightsCmd.Flags().StringVarP(&exportKind, "kind", "k", "", "only export insights of this kind: mutual, spree, coordinated or churn")
+	exportInsightsCmd.Flags().DurationVar(&exportSince, "since", 0, "only export insights found within this long, e.g. 168h")
+	exportCmd.AddCommand(exportAccountsCmd)
+	exportCmd.AddCommand(exportEventsCmd)
+	exportCmd.AddCommand(exportInsightsCmd)
+	rootCmd.AddCommand(exportCmd)
+}
+
+// accountExport is one row of the watch list export
+type accountExport struct {
+	Username       string   `json:"username"`
+	Platform       string   `json:"platform"`
+	UserID         string   `json:"user_id"`
+	Alias          string   `json:"alias,omitempty"`
+	Tags           []string `json:"tags"`
+	AddedAt        string   `json:"added_at,omitempty"`
+	FollowingCount int      `json:"following_count"`
+}
+
+func runExportAccounts(cmd *cobra.Command, args []string) error {
+	if exportFormat != "csv" && exportFormat != "json" {
+		return fmt.Errorf("unsupported format %q, use csv or json", exportFormat)
+	}
+
+	_, database, err := setup()
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+	defer database.Close()
+
+	accounts, err := database.GetWatchedAccounts()
+	if err != nil {
+		return fmt.Errorf("loading watched accounts: %w", err)
+	}
+	counts, err := database.GetFollowingCounts()
+	if err != nil {
+		return fmt.Errorf("counting followings: %w", err)
+	}
+
+	rows := make([]accountExport, 0, len(accounts))
+	for _, account := range accounts {
+		row := accountExport{
+			Username:       account.Username,
+			Platform:       account.Platform,
+			UserID:         account.UserID,
+			Alias:          account.Alias,
+			Tags:           account.Tags,
+			FollowingCount: counts[account.ID],
+		}
+		if row.Tags == nil {
+			row.Tags = []string{}
+		}
+		if !account.AddedAt.IsZero() {
+			row.AddedAt = account.AddedAt.UTC().Format(time.RFC3339)
+		}
+		rows = append(rows, row)
+	}
+
+	out, closeOut, err := openExportOutput()
+	if err != nil {
+		return err
+	}
+	defer closeOut()
+
+	if exportFormat == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(rows)
+	}
+
+	writer := csv.NewWriter(out)
+	writer.Write([]string{"username", "platform", "user_id", "alias", "tags", "added_at", "following_count"})
+	for _, row := range rows {
+		writer.Write([]string{
+			row.Username,
+			row.Platform,
+			row.UserID,
+			row.Alias,
+			strings.Join(row.Tags, ";"),
+			row.AddedAt,
+			strconv.Itoa(row.FollowingCount),
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("writing csv: %w", err)
+	}
+
+	logger.Info("Exported %d watched accounts as %s", len(rows), exportFormat)
+	return nil
+}
+
+// openExportOutput returns the --output file, or stdout if none was given
+func openExportOutput() (io.Writer, func(), error) {
+	if exportOutput == "" {
+		return os.Stdout, func() {}, nil
+	}
+	file, err := os.Create(exportOutput)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating output file: %w", err)
+	}
+	return file, func() { file.Close() }, nil
+}
+
+// eventExport is one row of the events export
+type eventExport struct {
+	ID          int64    `json:"id"`
+	UUID        string   `json:"uuid"`
+	Account     string   `json:"account"`
+	UserID      string   `json:"user_id"`
+	EventType   string   `json:"event_type"`
+	DetectedAt  string   `json:"detected_at"`
+	Dismissed   bool     `json:"dismissed"`
+	Flaps       int      `json:"flaps"`
+	Annotations []string `json:"annotations"`
+}
+
+func runExportEvents(cmd *cobra.Command, args []string) error {
+	if exportFormat != "csv" && exportFormat != "json" {
+		return fmt.Errorf("unsupported format %q, use csv or json", exportFormat)
+	}
+
+	_, database, err := setup()
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+	defer database.Close()
+
+	events, err := database.GetEvents(db.EventQuery{Label: exportLabel, IncludeDismissed: true})
+	if err != nil {
+		return fmt.Errorf("loading events: %w", err)
+	}
+
+	rows := make([]eventExport, 0, len(events))
+	for _, event := range events {
+		annotations := event.Annotations
+		if annotations == nil {
+			annotations = []string{}
+		}
+		rows = append(rows, eventExport{
+			ID:          event.ID,
+			UUID:        event.UUID,
+			Account:     event.AccountUsername,
+			UserID:      event.UserID,
+			EventType:   string(event.EventType),
+			DetectedAt:  event.DetectedAt.UTC().Format(time.RFC3339),
+			Dismissed:   event.DismissedAt != nil,
+			Flaps:       event.Flaps,
+			Annotations: annotations,
+		})
+	}
+
+	out, closeOut, err := openExportOutput()
+	if err != nil {
+		return err
+	}
+	defer closeOut()
+
+	if exportFormat == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(rows)
+	}
+
+	writer := csv.NewWriter(out)
+	writer.Write([]string{"id", "uuid", "account", "user_id", "event_type", "detected_at", "dismissed", "flaps", "annotations"})
+	for _, row := range rows {
+		writer.Write([]string{
+			strconv.FormatInt(row.ID, 10),
+			row.UUID,
+			row.Account,
+			row.UserID,
+			row.EventType,
+			row.DetectedAt,
+			strconv.FormatBool(row.Dismissed),
+			strconv.Itoa(row.Flaps),
+			strings.Join(row.Annotations, ";"),
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("writing csv: %w", err)
+	}
+
+	logger.Info("Exported %d events as %s", len(rows), exportFormat)
+	return nil
+}
+
+// insightExport is one row of the insights export
+type insightExport struct {
+	ID         int64    `json:"id"`
+	Kind       string   `json:"kind"`
+	Key        string   `json:"key"`
+	Summary    string   `json:"summary"`
+	Accounts   []string `json:"accounts"`
+	UserIDs    []string `json:"user_ids"`
+	DetectedAt string   `json:"detected_at"`
+	UpdatedAt  string   `json:"updated_at"`
+}
+
+func runExportInsights(cmd *cobra.Command, args []string) error {
+	if exportFormat != "csv" && exportFormat != "json" {
+		return fmt.Errorf("unsupported format %q, use csv or json", exportFormat)
+	}
+	switch exportKind {
+	case "", db.InsightMutual, db.InsightSpree, db.InsightCoordinated, db.InsightChurn:
+	default:
+		return fmt.Errorf("unknown insight kind %q, use mutual, spree, coordinated or churn", exportKind)
+	}
+	var since time.Time
+	if exportSince > 0 {
+		since = time.Now().Add(-exportSince)
+	}
+
+	_, database, err := setup()
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+	defer database.Close()
+
+	insights, err := database.GetInsights(since, exportKind, 0)
+	if err != nil {
+		return fmt.Errorf("loading insights: %w", err)
+	}
+
+	rows := make([]insightExport, 0, len(insights))
+	for _, insight := range insights {
+		rows = append(rows, insightExport{
+			ID:         insight.ID,
+			Kind:       insight.Kind,
+			Key:        insight.Key,
+			Summary:    insight.Summary,
+			Accounts:   insight.Accounts,
+			UserIDs:    insight.UserIDs,
+			DetectedAt: insight.DetectedAt.UTC().Format(time.RFC3339),
+			UpdatedAt:  insight.UpdatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+
+	out, closeOut, err := openExportOutput()
+	if err != nil {
+		return err
+	}
+	defer closeOut()
+
+	if exportFormat == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(rows)
+	}
+
+	writer := csv.NewWriter(out)
+	writer.Write([]string{"id", "kind", "key", "summary", "accounts", "user_ids", "detected_at", "updated_at"})
+	for _, row := range rows {
+		writer.Write([]string{
+			strconv.FormatInt(row.ID, 10),
+			row.Kind,
+			row.Key,
+			row.Summary,
+			strings.Join(row.Accounts, ";"),
+			strings.Join(row.UserIDs, ";"),
+			row.DetectedAt,
+			row.UpdatedAt,
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("writing csv: %w", err)
+	}
+
+	logger.Info("Exported %d insights as %s", len(rows), exportFormat)
+	return nil
+}
@@ -0,0 +1,337 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"x-tracker/internal/api"
+	"x-tracker/internal/db"
+	"x-tracker/internal/logger"
+	"x-tracker/internal/tracker"
+)
+
+var (
+	importReplace  bool
+	importAccounts bool
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import <username> <file> | --accounts <file>",
+	Short: "Load a following snapshot from a file as an account's baseline",
+	Long: `Load the list of user IDs an account follows from a JSON or CSV file and
+store it as the account's baseline, instead of crawling it from the API.
+Useful for accounts following hundreds of thousands of users.
+
+JSON files may contain an array of IDs ("123" or 123) or an object with an
+"ids" array, as returned by the following-ids endpoint. Any other file is
+read as CSV with the ID in the first column; a header row is skipped.
+
+The account is added to the watch list if needed (one user lookup). No
+follow events are recorded for the imported IDs.
+
+With --accounts the file is a watch list written by "x-tracker export
+accounts", as CSV or JSON, and every account in it is watched again with its
+platform, alias and tags. No lookups are made, and the accounts get their
+baseline on the next check. Accounts already watched keep their alias unless
+the file has one, and gain its tags.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if importAccounts {
+			return cobra.ExactArgs(1)(cmd, args)
+		}
+		return cobra.ExactArgs(2)(cmd, args)
+	},
+	RunE: runImport,
+}
+
+func init() {
+	importCmd.Flags().BoolVar(&importReplace, "replace", false, "overwrite an existing baseline")
+	importCmd.Flags().BoolVar(&importAccounts, "accounts", false, "restore the watch list from an accounts export")
+	rootCmd.AddCommand(importCmd)
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	if importAccounts {
+		return runImportAccounts(args[0])
+	}
+	username, path := args[0], args[1]
+
+	ids, err := readFollowingIDs(path)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return fmt.Errorf("no user IDs found in %s", path)
+	}
+
+	cfg, database, err := setup()
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+	defer database.Close()
+
+	checker := tracker.New(database, api.NewClient(cfg), nil, cfg)
+	account, err := checker.ImportBaseline(username, ids, importReplace)
+	if err != nil {
+		return err
+	}
+	detail := fmt.Sprintf("%d followings from %s", len(ids), filepath.Base(path))
+	if importReplace {
+		detail += ", replacing the snapshot"
+	}
+	audit(database, "import baseline", "@"+account.Username, detail)
+
+	fmt.Printf("Imported %d followings for @%s\n", len(ids), account.Username)
+	return nil
+}
+
+// runImportAccounts watches every account of an accounts export again
+func runImportAccounts(path string) error {
+	rows, err := readAccountExport(path)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return fmt.Errorf("no accounts found in %s", path)
+	}
+
+	_, database, err := setup()
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+	defer database.Close()
+
+	added := 0
+	for _, row := range rows {
+		account, err := database.GetWatchedAccountByUsername(row.Username)
+		if err != nil {
+			return fmt.Errorf("loading @%s: %w", row.Username, err)
+		}
+		if account == nil {
+			account = &db.WatchedAccount{Username: row.Username, UserID: row.UserID, Platform: row.Platform}
+			if err := database.AddWatchedAccount(account); err != nil {
+				return fmt.Errorf("adding @%s: %w", row.Username, err)
+			}
+			added++
+		}
+		if row.Alias != "" && row.Alias != account.Alias {
+			if err := database.SetAlias(account.ID, row.Alias); err != nil {
+				return fmt.Errorf("setting the alias of @%s: %w", row.Username, err)
+			}
+		}
+		if len(row.Tags) > 0 {
+			if err := database.AddTags(account.ID, row.Tags); err != nil {
+				return fmt.Errorf("tagging @%s: %w", row.Username, err)
+			}
+		}
+	}
+	audit(database, "import accounts", filepath.Base(path), fmt.Sprintf("%d accounts, %d added", len(rows), added))
+
+	fmt.Printf("Imported %d accounts from %s, %d newly watched\n", len(rows), filepath.Base(path), added)
+	return nil
+}
+
+// readAccountExport loads the rows of an accounts export, checking every
+// row has what watching the account needs
+func readAccountExport(path string) ([]accountExport, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening import file: %w", err)
+	}
+	defer file.Close()
+
+	var rows []accountExport
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		if err := json.NewDecoder(file).Decode(&rows); err != nil {
+			return nil, fmt.Errorf("decoding JSON: %w", err)
+		}
+	} else if rows, err = parseCSVAccounts(file); err != nil {
+		return nil, err
+	}
+
+	for i := range rows {
+		row := &rows[i]
+		username, err := tracker.NormalizeUsername(row.Username)
+		if err != nil {
+			return nil, fmt.Errorf("entry %d: %w", i+1, err)
+		}
+		row.Username = username
+		if row.Platform == "" {
+			row.Platform = tracker.PlatformOf(username)
+		}
+		if row.Platform != db.PlatformX && row.Platform != db.PlatformBluesky {
+			return nil, fmt.Errorf("entry %d: unknown platform %q", i+1, row.Platform)
+		}
+		if row.UserID == "" {
+			return nil, fmt.Errorf("entry %d: @%s has no user ID, add it with x-tracker add instead", i+1, username)
+		}
+		tags := row.Tags[:0]
+		for _, tag := range row.Tags {
+			if tag = db.NormalizeTag(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+		row.Tags = tags
+		row.Alias = strings.TrimSpace(row.Alias)
+	}
+	return rows, nil
+}
+
+// parseCSVAccounts reads an accounts export by its header, so columns may
+// be reordered or left out
+func parseCSVAccounts(r io.Reader) ([]accountExport, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	if _, ok := columns["username"]; !ok {
+		return nil, fmt.Errorf("CSV has no username column, expected the header written by x-tracker export accounts")
+	}
+
+	var rows []accountExport
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading CSV: %w", err)
+		}
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		if field("username") == "" {
+			continue
+		}
+		row := accountExport{
+			Username: field("username"),
+			Platform: field("platform"),
+			UserID:   field("user_id"),
+			Alias:    field("alias"),
+		}
+		if tags := field("tags"); tags != "" {
+			row.Tags = strings.Split(tags, ";")
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// readFollowingIDs loads unique user IDs from a JSON or CSV file
+func readFollowingIDs(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening import file: %w", err)
+	}
+	defer file.Close()
+
+	var raw []string
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		raw, err = parseJSONIDs(file)
+	} else {
+		raw, err = parseCSVIDs(file)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(raw))
+	ids := make([]string, 0, len(raw))
+	for i, id := range raw {
+		id = strings.TrimSpace(id)
+		if !isNumericID(id) {
+			return nil, fmt.Errorf("entry %d: %q is not a numeric user ID", i+1, id)
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func parseJSONIDs(r io.Reader) ([]string, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decoding JSON: %w", err)
+	}
+
+	// Accept {"ids": [...]} as well as a bare array
+	if obj, ok := doc.(map[string]interface{}); ok {
+		doc = obj["ids"]
+	}
+	list, ok := doc.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a JSON array of IDs or an object with an \"ids\" array")
+	}
+
+	ids := make([]string, 0, len(list))
+	for _, v := range list {
+		switch id := v.(type) {
+		case string:
+			ids = append(ids, id)
+		case json.Number:
+			ids = append(ids, id.String())
+		default:
+			return nil, fmt.Errorf("unexpected JSON value %v in ID list", v)
+		}
+	}
+	return ids, nil
+}
+
+func parseCSVIDs(r io.Reader) ([]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	var ids []string
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading CSV: %w", err)
+		}
+		if len(record) == 0 || strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		// Skip a header row
+		if line == 1 && !isNumericID(strings.TrimSpace(record[0])) {
+			continue
+		}
+		ids = append(ids, record[0])
+	}
+	return ids, nil
+}
+
+func isNumericID(id string) bool {
+	if id == "" {
+		return false
+	}
+	for _, r := range id {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"x-tracker/internal/logger"
+)
+
+var jitterCmd = &cobra.Command{
+	Use:   "jitter <username> [duration|default]",
+	Short: "Show or set an account's check jitter",
+	Long: `Show or override how much random delay is added to an account's periodic
+checks, e.g. "x-tracker jitter elonmusk 45s". Use "default" to go back to
+CHECK_JITTER, or 0 to check the account exactly at its slot. The running
+tracker picks the change up at its next cycle.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runJitter,
+}
+
+func init() {
+	rootCmd.AddCommand(jitterCmd)
+}
+
+func runJitter(cmd *cobra.Command, args []string) error {
+	cfg, database, err := setup()
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+	defer database.Close()
+
+	username := strings.TrimPrefix(args[0], "@")
+	account, err := database.GetWatchedAccountByUsername(username)
+	if err != nil {
+		return fmt.Errorf("loading account: %w", err)
+	}
+	if account == nil {
+		return fmt.Errorf("account @%s is not watched", username)
+	}
+
+	if len(args) == 1 {
+		if account.CheckJitter == nil {
+			fmt.Printf("@%s uses the default jitter (%s)\n", account.Username, cfg.CheckJitter)
+		} else {
+			fmt.Printf("@%s jitter: %s\n", account.Username, *account.CheckJitter)
+		}
+		return nil
+	}
+
+	if args[1] == "default" {
+		if err := database.SetCheckJitter(account.ID, nil); err != nil {
+			return fmt.Errorf("resetting jitter: %w", err)
+		}
+		audit(database, "jitter", "@"+account.Username, "default")
+		fmt.Printf("@%s now uses the default jitter (%s)\n", account.Username, cfg.CheckJitter)
+		return nil
+	}
+
+	jitter, err := time.ParseDuration(args[1])
+	if err != nil || jitter < 0 || jitter >= cfg.CheckInterval {
+		return fmt.Errorf("invalid jitter %q, expected a duration shorter than the check interval (%s)", args[1], cfg.CheckInterval)
+	}
+	if err := database.SetCheckJitter(account.ID, &jitter); err != nil {
+		return fmt.Errorf("setting jitter: %w", err)
+	}
+	audit(database, "jitter", "@"+account.Username, jitter.String())
+	fmt.Printf("@%s jitter set to %s\n", account.Username, jitter)
+	return nil
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"x-tracker/internal/format"
+	"x-tracker/internal/logger"
+)
+
+var muteCmd = &cobra.Command{
+	Use:   "mute",
+	Short: "Manage the global mute list of users never notified about",
+	Long: `Users on the global mute list never show up in notifications, whichever
+watched account follows, unfollows or loses them. Their events are still
+recorded and shown in the history. "x-tracker status" reports how many events
+the list has suppressed.`,
+}
+
+var muteImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Mute every user ID in a CSV or JSON file",
+	Long: `Mute the user IDs listed in a file, such as an exported block or mute list.
+CSV files have the ID in the first column, a header row is skipped; JSON files
+contain an array of IDs or an object with an "ids" array. IDs already muted
+are left as they are.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMuteImport,
+}
+
+var muteAddCmd = &cobra.Command{
+	Use:   "add <user id>...",
+	Short: "Mute users by ID",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return updateMuteList(args, true)
+	},
+}
+
+var muteRemoveCmd = &cobra.Command{
+	Use:   "remove <user id>...",
+	Short: "Unmute users by ID",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return updateMuteList(args, false)
+	},
+}
+
+func init() {
+	muteCmd.AddCommand(muteImportCmd)
+	muteCmd.AddCommand(muteAddCmd)
+	muteCmd.AddCommand(muteRemoveCmd)
+	rootCmd.AddCommand(muteCmd)
+}
+
+func runMuteImport(cmd *cobra.Command, args []string) error {
+	path := args[0]
+	ids, err := readFollowingIDs(path)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return fmt.Errorf("no user IDs found in %s", path)
+	}
+
+	_, database, err := setup()
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+	defer database.Close()
+
+	added, err := database.MuteUsers(ids, "import:"+filepath.Base(path))
+	if err != nil {
+		return fmt.Errorf("muting users: %w", err)
+	}
+	audit(database, "mute", fmt.Sprintf("%d users", len(ids)), "imported from "+filepath.Base(path))
+	fmt.Printf("Muted %s new users (%s in %s were already muted)\n",
+		format.Number(added), format.Number(len(ids)-added), filepath.Base(path))
+	return nil
+}
+
+func updateMuteList(ids []string, mute bool) error {
+	for _, id := range ids {
+		if !isNumericID(id) {
+			return fmt.Errorf("%q is not a numeric user ID", id)
+		}
+	}
+
+	_, database, err := setup()
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+	defer database.Close()
+
+	if mute {
+		added, err := database.MuteUsers(ids, "manual")
+		if err != nil {
+			return fmt.Errorf("muting users: %w", err)
+		}
+		audit(database, "mute", strings.Join(ids, ", "), "")
+		fmt.Printf("Muted %d users\n", added)
+		return nil
+	}
+	removed, err := database.UnmuteUsers(ids)
+	if err != nil {
+		return fmt.Errorf("unmuting users: %w", err)
+	}
+	audit(database, "unmute", strings.Join(ids, ", "), "")
+	fmt.Printf("Unmuted %d users\n", removed)
+	return nil
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"x-tracker/config"
+	"x-tracker/internal/db"
+	"x-tracker/internal/logger"
+	"x-tracker/internal/webhook"
+)
+
+var notifyTest bool
+
+var (
+	notifyLogFailed bool
+	notifyLogLimit  int
+)
+
+var notifyCmd = &cobra.Command{
+	Use:   "notify --test",
+	Short: "Send a test notification through every enabled channel",
+	Long: `With --test, send a sample follow and unfollow notification through every
+enabled notification channel (Discord, per-tag Discord webhooks, Telegram,
+Signal and desktop notifications) and a sample alert to the ops channel if one is configured, then
+report which channels accepted it. Use it to check the webhook setup without
+waiting for a real event. The channels are taken from the configuration;
+channels switched off at runtime in a running tracker are still tested.
+
+Exits with an error if any channel failed or none is enabled.`,
+	Args: cobra.NoArgs,
+	RunE: runNotify,
+}
+
+var notifyLogCmd = &cobra.Command{
+	Use:   "log",
+	Short: "List recent notification deliveries and failures",
+	Long: `List the most recent attempts to send a notification, newest first: when,
+through which channel, about which account, what was sent, how long the
+channel took to answer and, for failures, the HTTP status and error.
+The last 5,000 attempts are kept.`,
+	Args: cobra.NoArgs,
+	RunE: runNotifyLog,
+}
+
+func init() {
+	notifyCmd.Flags().BoolVar(&notifyTest, "test", false, "send a sample notification and report the result per channel")
+	notifyLogCmd.Flags().BoolVar(&notifyLogFailed, "failed", false, "only list failed deliveries")
+	notifyLogCmd.Flags().IntVarP(&notifyLogLimit, "limit", "n", 50, "maximum number of deliveries")
+	notifyCmd.AddCommand(notifyLogCmd)
+	rootCmd.AddCommand(notifyCmd)
+}
+
+func runNotify(cmd *cobra.Command, args []string) error {
+	if !notifyTest {
+		return cmd.Help()
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if err := webhook.ConfigureTransport(cfg); err != nil {
+		return err
+	}
+	if err := webhook.ConfigureTemplates(cfg); err != nil {
+		return err
+	}
+
+	results := webhook.NewNotificationManager(cfg).SendTest()
+	if len(results) == 0 {
+		return fmt.Errorf("no notification channel is enabled; set DISCORD_WEBHOOK_URL or TELEGRAM_BOT_TOKEN and TELEGRAM_CHAT_ID")
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+			fmt.Printf("%s: failed: %v\n", result.Channel, result.Err)
+		} else {
+			fmt.Printf("%s: ok\n", result.Channel)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d channels failed", failed, len(results))
+	}
+	return nil
+}
+
+func runNotifyLog(cmd *cobra.Command, args []string) error {
+	_, database, err := setup()
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+	defer database.Close()
+
+	deliveries, err := database.GetDeliveries(notifyLogLimit, notifyLogFailed)
+	if err != nil {
+		return fmt.Errorf("reading the notification log: %w", err)
+	}
+	if len(deliveries) == 0 {
+		fmt.Println("No notification deliveries recorded")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, "TIME\tCHANNEL\tACCOUNT\tSENT\tLATENCY\tRESULT")
+	for _, delivery := range deliveries {
+		account := "-"
+		if delivery.Account != "" {
+			account = "@" + delivery.Account
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			delivery.AttemptedAt.Local().Format("2006-01-02 15:04:05"),
+			delivery.Channel,
+			account,
+			deliverySent(delivery),
+			delivery.Latency,
+			deliveryResult(delivery))
+	}
+	return nil
+}
+
+// deliverySent describes what a delivery sent, e.g. "follows: 3 follows"
+func deliverySent(delivery db.Delivery) string {
+	if delivery.Summary == "" || delivery.Summary == delivery.Kind {
+		return delivery.Kind
+	}
+	return delivery.Kind + ": " + delivery.Summary
+}
+
+// deliveryResult is "ok" or the failure with its HTTP status
+func deliveryResult(delivery db.Delivery) string {
+	if delivery.Error == "" {
+		return "ok"
+	}
+	if delivery.StatusCode != 0 {
+		return fmt.Sprintf("failed (%d): %s", delivery.StatusCode, delivery.Error)
+	}
+	return "failed: " + delivery.Error
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"x-tracker/internal/logger"
+)
+
+var pauseCmd = &cobra.Command{
+	Use:   "pause <username>",
+	Short: "Stop checking an account without removing it",
+	Long: `Pause the periodic checks of a watched account. Its snapshot, events and
+settings are kept, and no notifications are sent for it while it is paused.
+The running tracker skips it from its next cycle on. Changes made while it was
+paused show up as one batch after "x-tracker unpause".`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setPaused(args[0], true)
+	},
+}
+
+var unpauseCmd = &cobra.Command{
+	Use:   "unpause <username>",
+	Short: "Resume checking a paused account",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setPaused(args[0], false)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pauseCmd)
+	rootCmd.AddCommand(unpauseCmd)
+}
+
+func setPaused(username string, paused bool) error {
+	_, database, err := setup()
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+	defer database.Close()
+
+	username = strings.TrimPrefix(username, "@")
+	account, err := database.GetWatchedAccountByUsername(username)
+	if err != nil {
+		return fmt.Errorf("loading account: %w", err)
+	}
+	if account == nil {
+		return fmt.Errorf("account @%s is not watched", username)
+	}
+
+	if account.Paused() == paused {
+		if paused {
+			fmt.Printf("@%s is already paused\n", account.Username)
+		} else {
+			fmt.Printf("@%s is not paused\n", account.Username)
+		}
+		return nil
+	}
+	if err := database.SetPaused(account.ID, paused); err != nil {
+		return fmt.Errorf("updating account: %w", err)
+	}
+	if paused {
+		audit(database, "pause", "@"+account.Username, "")
+	} else {
+		audit(database, "resume", "@"+account.Username, "")
+	}
+	if paused {
+		fmt.Printf("Paused checks of @%s\n", account.Username)
+	} else {
+		fmt.Printf("Resumed checks of @%s\n", account.Username)
+	}
+	return nil
+}
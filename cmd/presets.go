@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/spf13/cobra"
+	"x-tracker/config"
+)
+
+var presetsCmd = &cobra.Command{
+	Use:   "presets",
+	Short: "List the notification presets and the settings each one makes",
+	Long: `List the notification presets selectable with NOTIFY_PRESET and the
+variables each one sets. A preset only changes defaults: any of these
+variables set in the environment or .env keeps its value, so a preset can
+be adjusted afterwards. Such overrides of the active preset are marked.`,
+	Args: cobra.NoArgs,
+	RunE: runPresets,
+}
+
+func init() {
+	rootCmd.AddCommand(presetsCmd)
+}
+
+func runPresets(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	for i, name := range config.NotifyPresets() {
+		if i > 0 {
+			fmt.Println()
+		}
+		active := name == cfg.NotifyPreset
+		if active {
+			fmt.Printf("%s (active)\n", name)
+		} else {
+			fmt.Println(name)
+		}
+
+		settings, _ := config.NotifyPresetSettings(name)
+		keys := make([]string, 0, len(settings))
+		for key := range settings {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			line := fmt.Sprintf("  %s=%s", key, settings[key])
+			if value := os.Getenv(key); active && value != "" && value != settings[key] {
+				line += fmt.Sprintf("  (overridden: %s)", value)
+			}
+			fmt.Println(line)
+		}
+	}
+	if cfg.NotifyPreset == "" {
+		fmt.Println("\nNo preset is active; set NOTIFY_PRESET to use one")
+	}
+	return nil
+}
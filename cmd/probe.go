@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"x-tracker/internal/api"
+	"x-tracker/internal/format"
+	"x-tracker/internal/logger"
+)
+
+var probeCmd = &cobra.Command{
+	Use:   "probe",
+	Short: "Check that the API key works and show the plan's quota",
+	Long: `Make one authenticated request (a lookup of @X) to confirm the API key is
+accepted, then print the plan's request quota and how much of it is left as
+reported by the provider. Costs one API request.
+
+Exits with status 1 if the key is rejected or the API can't be reached.`,
+	Args: cobra.NoArgs,
+	RunE: runProbe,
+}
+
+func init() {
+	rootCmd.AddCommand(probeCmd)
+}
+
+func runProbe(cmd *cobra.Command, args []string) error {
+	cfg, database, err := setup()
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+	defer database.Close()
+
+	result, err := api.NewClient(cfg).Probe()
+	if errors.Is(err, api.ErrInvalidKey) {
+		return fmt.Errorf("%w\nCheck RAPID_API_KEY and RAPID_API_HOST, and that the key is subscribed to the API", err)
+	}
+	if err != nil {
+		return fmt.Errorf("probing the API: %w", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintf(w, "API key\taccepted by %s\n", cfg.RapidAPIHost)
+	fmt.Fprintf(w, "Latency\t%s\n", result.Latency.Round(time.Millisecond))
+	if result.QuotaLimit >= 0 {
+		fmt.Fprintf(w, "Plan quota\t%s requests per period\n", format.Number(result.QuotaLimit))
+	} else {
+		fmt.Fprintf(w, "Plan quota\tnot reported\n")
+	}
+	if result.QuotaRemaining >= 0 {
+		fmt.Fprintf(w, "Remaining\t%s requests\n", format.Number(result.QuotaRemaining))
+	}
+	if result.QuotaReset > 0 {
+		fmt.Fprintf(w, "Resets in\t%s\n", format.Age(result.QuotaReset))
+	}
+	if result.Exhausted {
+		fmt.Fprintf(w, "Warning\tthe quota is used up, checks fail until it resets\n")
+	}
+	return nil
+}
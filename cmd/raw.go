@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"x-tracker/internal/api"
+	"x-tracker/internal/db"
+	"x-tracker/internal/format"
+	"x-tracker/internal/logger"
+	"x-tracker/internal/tracker"
+)
+
+var rawCmd = &cobra.Command{
+	Use:   "raw",
+	Short: "Audit the raw following lists stored with RAW_SNAPSHOTS",
+	Long: `With RAW_SNAPSHOTS=true every following list fetched by a check, baseline or
+re-sync is stored compressed, exactly as fetched, keeping the newest
+RAW_SNAPSHOT_KEEP per account. These commands list them, compare them to
+audit a suspicious diff, and rebuild an account's baseline from one without
+calling the API.`,
+}
+
+var rawListCmd = &cobra.Command{
+	Use:   "list [username]",
+	Short: "List the stored raw snapshots, newest first",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runRawList,
+}
+
+var rawShowCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Print the user IDs of a raw snapshot, one per line",
+	Long: `Print the user IDs of a raw snapshot in the order they were fetched, one per
+line. The output can be loaded back with x-tracker import.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRawShow,
+}
+
+var rawDiffCmd = &cobra.Command{
+	Use:   "diff <id> [other-id]",
+	Short: "Compare a raw snapshot with another or with the stored followings",
+	Long: `List the user IDs followed (+) and unfollowed (-) between a raw snapshot and
+a later one, or the account's stored followings if no other snapshot is
+given.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runRawDiff,
+}
+
+var rawRestoreCmd = &cobra.Command{
+	Use:   "restore <id>",
+	Short: "Rebuild an account's baseline from a raw snapshot",
+	Long: `Replace the account's stored followings with the user IDs of a raw snapshot,
+like x-tracker import --replace. No follow or unfollow events are recorded
+for the differences; the next check diffs against the restored list.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRawRestore,
+}
+
+func init() {
+	rawCmd.AddCommand(rawListCmd)
+	rawCmd.AddCommand(rawShowCmd)
+	rawCmd.AddCommand(rawDiffCmd)
+	rawCmd.AddCommand(rawRestoreCmd)
+	rootCmd.AddCommand(rawCmd)
+}
+
+func runRawList(cmd *cobra.Command, args []string) error {
+	_, database, err := setup()
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+	defer database.Close()
+
+	var accountID int64
+	if len(args) == 1 {
+		username := strings.TrimPrefix(args[0], "@")
+		account, err := database.GetWatchedAccountByUsername(username)
+		if err != nil {
+			return fmt.Errorf("loading account: %w", err)
+		}
+		if account == nil {
+			return fmt.Errorf("account @%s is not watched", username)
+		}
+		accountID = account.ID
+	}
+
+	snapshots, err := database.GetRawSnapshots(accountID)
+	if err != nil {
+		return fmt.Errorf("listing raw snapshots: %w", err)
+	}
+	if len(snapshots) == 0 {
+		fmt.Println("No raw snapshots stored, set RAW_SNAPSHOTS=true to keep them")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+	fmt.Fprintln(w, "ID\tACCOUNT\tTAKEN\tFOLLOWINGS\tSIZE")
+	for _, snapshot := range snapshots {
+		fmt.Fprintf(w, "%d\t@%s\t%s\t%s\t%s KB\n", snapshot.ID, snapshot.Username,
+			snapshot.TakenAt.Local().Format("2006-01-02 15:04:05"), format.Number(snapshot.Count),
+			format.Number((snapshot.Size+1023)/1024))
+	}
+	return nil
+}
+
+// loadRawSnapshot reads the raw snapshot with the ID given as an argument
+func loadRawSnapshot(database db.Store, arg string) (*db.RawSnapshot, []string, error) {
+	id, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid raw snapshot ID %q", arg)
+	}
+	snapshot, ids, err := database.GetRawSnapshot(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if snapshot == nil {
+		return nil, nil, fmt.Errorf("no raw snapshot with ID %d", id)
+	}
+	return snapshot, ids, nil
+}
+
+func runRawShow(cmd *cobra.Command, args []string) error {
+	_, database, err := setup()
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+	defer database.Close()
+
+	_, ids, err := loadRawSnapshot(database, args[0])
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		fmt.Println(id)
+	}
+	return nil
+}
+
+func runRawDiff(cmd *cobra.Command, args []string) error {
+	_, database, err := setup()
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+	defer database.Close()
+
+	snapshot, ids, err := loadRawSnapshot(database, args[0])
+	if err != nil {
+		return err
+	}
+
+	var against map[string]bool
+	var label string
+	if len(args) == 2 {
+		other, otherIDs, err := loadRawSnapshot(database, args[1])
+		if err != nil {
+			return err
+		}
+		if other.WatchedAccountID != snapshot.WatchedAccountID {
+			return fmt.Errorf("raw snapshots %d and %d are of different accounts", snapshot.ID, other.ID)
+		}
+		against = make(map[string]bool, len(otherIDs))
+		for _, id := range otherIDs {
+			against[id] = true
+		}
+		label = fmt.Sprintf("raw snapshot %d (%s)", other.ID, other.TakenAt.Local().Format("2006-01-02 15:04"))
+	} else {
+		if against, err = database.GetCurrentFollowings(snapshot.WatchedAccountID); err != nil {
+			return fmt.Errorf("getting stored followings: %w", err)
+		}
+		label = "the stored followings"
+	}
+
+	before := make(map[string]bool, len(ids))
+	unfollowed := 0
+	for _, id := range ids {
+		before[id] = true
+		if !against[id] {
+			fmt.Println("- " + id)
+			unfollowed++
+		}
+	}
+	followed := 0
+	for id := range against {
+		if !before[id] {
+			fmt.Println("+ " + id)
+			followed++
+		}
+	}
+	fmt.Fprintf(os.Stderr, "@%s: raw snapshot %d (%s) to %s: +%s followed, -%s unfollowed\n",
+		snapshot.Username, snapshot.ID, snapshot.TakenAt.Local().Format("2006-01-02 15:04"), label,
+		format.Number(followed), format.Number(unfollowed))
+	return nil
+}
+
+func runRawRestore(cmd *cobra.Command, args []string) error {
+	cfg, database, err := setup()
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+	defer database.Close()
+
+	snapshot, ids, err := loadRawSnapshot(database, args[0])
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return fmt.Errorf("raw snapshot %d is empty, refusing to wipe the stored followings", snapshot.ID)
+	}
+
+	checker := tracker.New(database, api.NewClient(cfg), nil, cfg)
+	account, err := checker.ImportBaseline(snapshot.Username, ids, true)
+	if err != nil {
+		return err
+	}
+	audit(database, "restore baseline", "@"+account.Username,
+		fmt.Sprintf("%d followings from raw snapshot %d taken %s", len(ids), snapshot.ID, snapshot.TakenAt.Format("2006-01-02 15:04")))
+
+	fmt.Printf("Restored %s followings for @%s from raw snapshot %d\n", format.Number(len(ids)), account.Username, snapshot.ID)
+	return nil
+}
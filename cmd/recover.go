@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/term"
+	"x-tracker/config"
+	"x-tracker/internal/backup"
+	"x-tracker/internal/daemon"
+	"x-tracker/internal/db"
+	"x-tracker/internal/logger"
+)
+
+// recoveryOption is one way out of a corrupted database. build writes the
+// replacement to a temporary path; without it the tracker starts fresh.
+type recoveryOption struct {
+	label string
+	build func(tmp string) error
+}
+
+// recoverDatabase walks the user through replacing the corrupted database
+// at cfg.DBPath. The broken file is always kept next to it. It returns nil
+// once DBPath can be opened again.
+func recoverDatabase(cfg *config.Config, cause error) error {
+	// Replacing the file under a running tracker would leave it writing to
+	// the broken one, or to nothing once it is moved aside
+	running, err := daemon.Probe(cfg.PIDFile)
+	if err != nil {
+		return fmt.Errorf("database %s is corrupted: %w\nNot recovering it, can't tell whether a tracker is running: %v", cfg.DBPath, cause, err)
+	}
+	if running != nil {
+		return fmt.Errorf("database %s is corrupted: %w\nStop the tracker running as pid %d, then run x-tracker again to recover it", cfg.DBPath, cause, running.PID)
+	}
+	if !isInteractive() {
+		return fmt.Errorf("database %s is corrupted: %w\nRun x-tracker in a terminal to recover it", cfg.DBPath, cause)
+	}
+
+	fmt.Printf("The database at %s is corrupted:\n  %v\n\n", cfg.DBPath, cause)
+	options := []recoveryOption{{
+		label: "Salvage what can still be read into a new database",
+		build: func(tmp string) error {
+			report, err := db.Salvage(cfg.DBPath, tmp)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Salvaged %d tables with %s\n", report.Tables, report.Method)
+			if report.Rows > 0 {
+				fmt.Printf("Copied %d rows\n", report.Rows)
+			}
+			for _, skipped := range report.Skipped {
+				fmt.Printf("  incomplete: %s\n", skipped)
+			}
+			return nil
+		},
+	}}
+	if cfg.BackupURL != "" {
+		options = append(options, recoveryOption{
+			label: "Restore the latest backup from " + cfg.BackupURL,
+			build: func(tmp string) error {
+				return restoreLatestBackup(cfg, tmp)
+			},
+		})
+	}
+	options = append(options, recoveryOption{label: "Start fresh with an empty database"})
+
+	for i, option := range options {
+		fmt.Printf("  %d) %s\n", i+1, option.label)
+	}
+	fmt.Printf("  q) Quit and leave the database as it is\n\n")
+
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Print("Choose an option: ")
+		line, err := reader.ReadString('\n')
+		answer := strings.TrimSpace(line)
+		if answer == "q" || (err != nil && answer == "") {
+			return fmt.Errorf("database %s is corrupted: %w", cfg.DBPath, cause)
+		}
+
+		var choice int
+		if _, scanErr := fmt.Sscanf(answer, "%d", &choice); scanErr != nil || choice < 1 || choice > len(options) {
+			fmt.Printf("Enter a number from 1 to %d, or q\n", len(options))
+			continue
+		}
+		option := options[choice-1]
+
+		tmp := cfg.DBPath + ".recovering"
+		removeDatabaseFiles(tmp)
+		if option.build != nil {
+			if err := option.build(tmp); err != nil {
+				removeDatabaseFiles(tmp)
+				fmt.Printf("That didn't work: %v\n\n", err)
+				continue
+			}
+		}
+
+		kept, err := replaceDatabase(cfg.DBPath, tmp, option.build != nil)
+		if err != nil {
+			return err
+		}
+		logger.Warn("Replaced corrupted database %s (%s), broken file kept as %s", cfg.DBPath, option.label, kept)
+		fmt.Printf("The broken database was kept as %s\n\n", kept)
+		return nil
+	}
+}
+
+// restoreLatestBackup downloads the newest snapshot at BACKUP_URL to path
+func restoreLatestBackup(cfg *config.Config, path string) error {
+	store, err := backup.Open(cfg)
+	if err != nil {
+		return err
+	}
+	uploader := backup.NewUploader(store, nil, cfg.BackupKeep)
+	snapshots, err := uploader.Snapshots()
+	if err != nil {
+		return err
+	}
+	if len(snapshots) == 0 {
+		return errors.New("no snapshots stored")
+	}
+	latest := snapshots[len(snapshots)-1]
+	fmt.Printf("Restoring %s, taken %s\n", latest.Name, latest.TakenAt.Local().Format("2006-01-02 15:04:05"))
+	return uploader.Download(latest, path)
+}
+
+// replaceDatabase moves the broken database at path aside, with its
+// journal, and with replace the one built at tmp into its place. It
+// returns where the broken file went.
+func replaceDatabase(path, tmp string, replace bool) (string, error) {
+	kept := path + ".corrupt-" + time.Now().Format("20060102-150405")
+	for _, suffix := range databaseFileSuffixes {
+		if err := os.Rename(path+suffix, kept+suffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("moving the broken database aside: %w", err)
+		}
+	}
+	if replace {
+		if err := os.Rename(tmp, path); err != nil {
+			return "", fmt.Errorf("putting the recovered database in place: %w", err)
+		}
+	}
+	return kept, nil
+}
+
+// databaseFileSuffixes name the files SQLite keeps next to a database
+var databaseFileSuffixes = []string{"", "-journal", "-wal", "-shm"}
+
+// removeDatabaseFiles deletes a database and the files next to it
+func removeDatabaseFiles(path string) {
+	for _, suffix := range databaseFileSuffixes {
+		os.Remove(path + suffix)
+	}
+}
+
+// isInteractive reports whether stdin is a terminal someone can answer on
+func isInteractive() bool {
+	return term.IsTerminal(os.Stdin.Fd())
+}
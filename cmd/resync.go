@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"x-tracker/config"
+	"x-tracker/internal/api"
+	"x-tracker/internal/logger"
+	"x-tracker/internal/tracker"
+)
+
+var resyncCmd = &cobra.Command{
+	Use:     "resync <username>",
+	Aliases: []string{"rebaseline"},
+	Short:   "Rebuild an account's following snapshot from the API",
+	Long: `Wipe the stored following snapshot of a watched account, fetch its full
+following list again and store it as the new baseline. No follow or unfollow
+events are recorded for the differences, so this is the way to recover from
+a corrupted or incomplete snapshot without a storm of bogus notifications.
+If the tracker is running, it does the re-sync once its current check cycle
+is done. "x-tracker rebaseline" is the same command.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runResync,
+}
+
+func init() {
+	rootCmd.AddCommand(resyncCmd)
+}
+
+func runResync(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if resp, ok, err := delegate(cfg, controlResync, args[0]); ok {
+		if err != nil {
+			return err
+		}
+		fmt.Println(resp.Message)
+		return nil
+	}
+
+	cfg, database, err := setup()
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+	defer database.Close()
+
+	checker := tracker.New(database, api.NewClient(cfg), nil, cfg)
+	account, err := checker.ResyncAccount(args[0])
+	if err != nil {
+		return err
+	}
+	audit(database, "resync", "@"+account.Username, "")
+
+	fmt.Printf("Re-synced @%s\n", account.Username)
+	return nil
+}
@@ -1,23 +1,261 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
+	"x-tracker/config"
+	"x-tracker/internal/api"
+	"x-tracker/internal/daemon"
+	"x-tracker/internal/format"
+	"x-tracker/internal/logger"
+	"x-tracker/internal/server"
+	"x-tracker/internal/tracker"
+	"x-tracker/internal/ui"
+	"x-tracker/internal/webhook"
 )
 
+// listenAddr enables the HTTP API on this address
+var listenAddr string
+
 var rootCmd = &cobra.Command{
 	Use:   "x-tracker",
 	Short: "A CLI tool to track X (Twitter) following changes",
 	Long: `x-tracker is a command-line tool that monitors X (Twitter) accounts
 and tracks their following changes in real-time. It supports Discord webhook
 notifications and provides an interactive terminal user interface.`,
+	SilenceUsage:  true,
+	SilenceErrors: true, // Execute prints the error itself
+	RunE:          runTUI,
+}
+
+func init() {
+	rootCmd.Flags().StringVar(&listenAddr, "listen", "", "serve the HTTP API on this address, e.g. 127.0.0.1:8080")
+	rootCmd.Flags().BoolVar(&dryRun, "dry-run", false, "check accounts without saving anything or sending notifications")
+}
+
+// exitError ends the program with a specific exit code and no message
+type exitError struct {
+	code int
+}
+
+func (e exitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
 }
 
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
+		var exit exitError
+		if errors.As(err, &exit) {
+			os.Exit(exit.code)
+		}
 		fmt.Println(err)
 		os.Exit(1)
 	}
-} 
\ No newline at end of file
+}
+
+// runTUI starts the interactive tracker, the default when no subcommand is given
+func runTUI(cmd *cobra.Command, args []string) error {
+	cfg, database, err := setup()
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+	defer database.Close()
+
+	// Keep recent log lines for the activity pane
+	activity := logger.NewRing(500)
+	logger.AddSink(activity)
+
+	logger.Info("CLI X Track starting up...")
+	started := time.Now()
+
+	// Mark this process as the running tracker for `x-tracker status`. A
+	// dry run doesn't, so other commands never hand it work meant to be
+	// saved.
+	var pidFile *daemon.PIDFile
+	if dryRun {
+		logger.Info("Dry run: changes are shown but not saved, and no notifications are sent")
+	} else if pidFile, err = daemon.Acquire(cfg.PIDFile); err != nil {
+		logger.Info("Not registering as the running instance: %v", err)
+	} else {
+		defer pidFile.Release()
+	}
+
+	// Initialize API client
+	apiClient := api.NewClient(cfg)
+
+	// Don't start checking with a key that would fail every cycle
+	if cfg.ProbeOnStartup {
+		result, err := apiClient.Probe()
+		switch {
+		case errors.Is(err, api.ErrInvalidKey):
+			return fmt.Errorf("%w\nNot starting: fix RAPID_API_KEY and verify it with `x-tracker probe`", err)
+		case err != nil:
+			logger.Warn("API probe failed, starting anyway: %v", err)
+		case result.Exhausted:
+			logger.Warn("API quota is used up, checks will fail until it resets")
+		default:
+			logger.Info("API key accepted (%d of %d requests left)", result.QuotaRemaining, result.QuotaLimit)
+		}
+	}
+
+	// Initialize notification manager
+	notificationManager := webhook.NewNotificationManager(cfg)
+	notificationManager.SetDeliveryLog(database)
+	notificationManager.SetEventAnnotator(database)
+
+	// Start the external heartbeat if configured
+	stop := make(chan struct{})
+	defer close(stop)
+	if cfg.HeartbeatURL != "" {
+		go webhook.NewHeartbeat(cfg.HeartbeatURL, cfg.HeartbeatInterval).Run(stop)
+	}
+
+	// Upload a daily snapshot of the database if configured
+	if cfg.BackupURL != "" {
+		uploader, err := openUploader(cfg, database)
+		if err != nil {
+			return err
+		}
+		go uploader.Run(stop)
+	}
+
+	// Turn reactions on Discord notifications into event annotations
+	if cfg.DiscordBotToken != "" && cfg.DiscordWebhookURL != "" {
+		notificationManager.SetMessageLog(database)
+		go webhook.NewReactionPoller(cfg.DiscordBotToken, cfg.ReactionLabels,
+			cfg.ReactionPollInterval, cfg.ReactionWindow, database).Run(stop)
+	}
+
+	checker := tracker.New(database, apiClient, notificationManager, cfg)
+
+	// Initialize UI model with notification manager
+	model := ui.NewModel(database, apiClient, notificationManager, checker, cfg)
+	model.SetActivityLog(activity)
+	if dryRun {
+		model.SetDryRun()
+	}
+
+	// Create and start the Bubble Tea program
+	p := tea.NewProgram(
+		model,
+		tea.WithAltScreen(),       // Use alternate screen buffer
+		tea.WithMouseCellMotion(), // Enable mouse support
+	)
+
+	// Show check cycles as they run in the status area
+	checker.SetProgressFunc(func(progress tracker.Progress) {
+		p.Send(ui.CheckProgressMsg(progress))
+	})
+
+	// Report long-running operations in the UI as they finish
+	checker.SetCompletionFunc(func(completion tracker.Completion) {
+		p.Send(ui.CompletionMsg(completion))
+	})
+
+	// Let other x-tracker commands delegate to this process
+	if pidFile != nil {
+		control, err := daemon.Listen(cfg.ControlSocket, controlHandlers(checker, database, apiClient, p))
+		if err != nil {
+			logger.Info("Control socket unavailable: %v", err)
+		} else {
+			defer control.Close()
+			go control.Serve()
+		}
+	}
+
+	// Answer bot commands in the Telegram chat. Only the running instance
+	// does, since Telegram hands each message to one poller.
+	if cfg.TelegramCommands && pidFile != nil {
+		if cfg.TelegramBotToken == "" || cfg.TelegramChatID == "" {
+			return fmt.Errorf("TELEGRAM_COMMANDS needs TELEGRAM_BOT_TOKEN and TELEGRAM_CHAT_ID; run `x-tracker telegram link` to set the chat")
+		}
+		bot, err := webhook.NewTelegramBot(cfg.TelegramBotToken, cfg.TelegramChatID, telegramCommands(checker, database, p))
+		if err != nil {
+			return err
+		}
+		go bot.Run(stop)
+	}
+
+	// Serve the HTTP API if requested
+	if listenAddr != "" {
+		apiServer := server.New(listenAddr, cfg.APIToken, database, checker, func(notice string) {
+			p.Send(ui.AccountsChangedMsg{Notice: notice})
+		})
+		if cfg.APIGraphQL {
+			apiServer.EnableGraphQL()
+		}
+		if err := apiServer.Start(); err != nil {
+			return err
+		}
+		defer apiServer.Close()
+	}
+
+	// Handle graceful shutdown, and reload the configuration on SIGHUP
+	go func() {
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+		for sig := range sigChan {
+			if sig != syscall.SIGHUP {
+				p.Kill()
+				return
+			}
+
+			newCfg, err := reloadConfig(apiClient, notificationManager, checker)
+			if err != nil {
+				logger.Error("Config reload failed, keeping current settings: %v", err)
+				continue
+			}
+			p.Send(ui.ConfigReloadedMsg{Config: newCfg})
+		}
+	}()
+
+	// Run the application
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("running program: %w", err)
+	}
+
+	if checker.Config().SessionSummary {
+		printSessionSummary(os.Stdout, time.Since(started), checker, apiClient, notificationManager)
+	}
+	return nil
+}
+
+// reloadConfig re-reads the configuration and hands it to every component
+// that supports live updates. Settings that need a restart (database path,
+// logging) keep their startup values.
+func reloadConfig(apiClient *api.Client, notifications *webhook.NotificationManager, checker *tracker.Tracker) (*config.Config, error) {
+	logger.Info("Reloading configuration...")
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	if dryRun {
+		cfg = dryRunConfig(cfg)
+	}
+	if err := format.Configure(cfg.NumberFormat, cfg.NumberLocale); err != nil {
+		return nil, err
+	}
+	if err := webhook.ConfigureTransport(cfg); err != nil {
+		return nil, err
+	}
+	if err := webhook.ConfigureTemplates(cfg); err != nil {
+		return nil, err
+	}
+
+	logger.SetLevel(cfg.LogLevel)
+	apiClient.SetConfig(cfg)
+	notifications.Reload(cfg)
+	checker.SetConfig(cfg)
+
+	logger.Info("Configuration reloaded (check interval: %s)", cfg.CheckInterval)
+	return cfg, nil
+}
@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"x-tracker/config"
+	"x-tracker/internal/api"
+	"x-tracker/internal/db"
+	"x-tracker/internal/logger"
+	"x-tracker/internal/tracker"
+	"x-tracker/internal/webhook"
+)
+
+// Exit codes of run-once
+const (
+	exitNoChanges = 0
+	exitFailed    = 1 // also used for any error
+	exitChanges   = 2
+)
+
+// runOnceListLimit is how many user IDs the summary lists per account and type
+const runOnceListLimit = 10
+
+var runOnceQuiet bool
+
+var runOnceCmd = &cobra.Command{
+	Use:   "run-once",
+	Short: "Run one check cycle, print a summary of the changes and exit",
+	Long: `Check every watched account once, print what changed and exit, for
+running x-tracker from cron or another scheduler. Notifications are sent as
+usual unless --quiet is given. With --dry-run the accounts are fetched and
+compared as usual, but the changes are only printed: they are recorded in a
+scratch copy of the database that is thrown away, and nothing is sent.
+
+Exit codes: 0 when nothing changed, 2 when follows or unfollows were
+found, 1 when the cycle failed or, without changes, any account could not
+be checked.`,
+	Args: cobra.NoArgs,
+	RunE: runRunOnce,
+}
+
+func init() {
+	runOnceCmd.Flags().BoolVarP(&runOnceQuiet, "quiet", "q", false, "don't send notifications")
+	runOnceCmd.Flags().BoolVar(&dryRun, "dry-run", false, "print what changed without saving it or sending notifications")
+	rootCmd.AddCommand(runOnceCmd)
+}
+
+func runRunOnce(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	start := time.Now()
+
+	// A running tracker does the work so the two don't check concurrently;
+	// it always notifies, so --quiet can't be honoured then
+	switch {
+	case dryRun:
+		// Nothing is saved, so checking alongside the tracker is harmless
+	case runOnceQuiet:
+		if _, ok, _ := delegate(cfg, controlStatus); ok {
+			return fmt.Errorf("x-tracker is running and would send notifications; stop it or drop --quiet")
+		}
+	default:
+		if _, ok, err := delegate(cfg, controlCheck); ok {
+			if err != nil {
+				return err
+			}
+			return summarizeRun(start)
+		}
+	}
+
+	cfg, database, err := setup()
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+	defer database.Close()
+
+	// Left nil, not a nil manager, so the tracker sends nothing
+	var notifications tracker.Notifier
+	if !runOnceQuiet && !dryRun {
+		manager := webhook.NewNotificationManager(cfg)
+		manager.SetDeliveryLog(database)
+		manager.SetEventAnnotator(database)
+		if cfg.DiscordBotToken != "" {
+			manager.SetMessageLog(database)
+		}
+		notifications = manager
+	}
+	checker := tracker.New(database, api.NewClient(cfg), notifications, cfg)
+	if err := checker.CheckAll(); err != nil {
+		return err
+	}
+
+	if dryRun {
+		fmt.Println("Dry run: nothing was saved and no notifications were sent")
+	}
+	return printRunSummary(database, start)
+}
+
+// summarizeRun prints the summary of a cycle the running tracker ran
+func summarizeRun(start time.Time) error {
+	_, database, err := setup()
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+	defer database.Close()
+
+	return printRunSummary(database, start)
+}
+
+// printRunSummary prints the changes recorded since start and returns the
+// exit status as an error
+func printRunSummary(database db.Store, start time.Time) error {
+	events, err := database.GetEvents(db.EventQuery{Since: start, IncludeDismissed: true})
+	if err != nil {
+		return fmt.Errorf("loading events: %w", err)
+	}
+	run, err := database.GetLastCheckRun()
+	if err != nil {
+		return fmt.Errorf("loading check run: %w", err)
+	}
+
+	// Group by account, in the order changes were detected
+	type accountChanges struct {
+		follows, unfollows []string
+	}
+	var accounts []string
+	changes := make(map[string]*accountChanges)
+	for i := len(events) - 1; i >= 0; i-- {
+		event := events[i]
+		account, ok := changes[event.AccountUsername]
+		if !ok {
+			account = &accountChanges{}
+			changes[event.AccountUsername] = account
+			accounts = append(accounts, event.AccountUsername)
+		}
+		if event.EventType == db.EventTypeFollow {
+			account.follows = append(account.follows, event.UserID)
+		} else {
+			account.unfollows = append(account.unfollows, event.UserID)
+		}
+	}
+
+	for _, name := range accounts {
+		account := changes[name]
+		fmt.Printf("@%s: +%d follows, -%d unfollows\n", name, len(account.follows), len(account.unfollows))
+		printRunIDs("+", account.follows)
+		printRunIDs("-", account.unfollows)
+	}
+
+	failures := 0
+	if run != nil && !run.StartedAt.Before(start) {
+		failures = run.Failures
+		fmt.Printf("Checked %d accounts in %s, %d failed, %d changed\n",
+			run.Accounts, run.FinishedAt.Sub(run.StartedAt).Round(time.Second), failures, len(accounts))
+	}
+
+	switch {
+	case len(accounts) > 0:
+		return exitError{exitChanges}
+	case failures > 0:
+		return exitError{exitFailed}
+	}
+	fmt.Println("No changes")
+	return nil
+}
+
+// printRunIDs lists user IDs of one change type, capped at runOnceListLimit
+func printRunIDs(sign string, ids []string) {
+	if len(ids) == 0 {
+		return
+	}
+	shown := ids
+	if len(shown) > runOnceListLimit {
+		shown = shown[:runOnceListLimit]
+	}
+	line := "  " + sign + " " + strings.Join(shown, ", ")
+	if len(ids) > len(shown) {
+		line += fmt.Sprintf(" and %d more", len(ids)-len(shown))
+	}
+	fmt.Println(line)
+}
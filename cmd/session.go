@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"x-tracker/internal/api"
+	"x-tracker/internal/format"
+	"x-tracker/internal/tracker"
+	"x-tracker/internal/webhook"
+)
+
+// printSessionSummary writes what the TUI session did, shown on quit when
+// SESSION_SUMMARY is set
+func printSessionSummary(out io.Writer, elapsed time.Duration, checker *tracker.Tracker, apiClient *api.Client, notifications *webhook.NotificationManager) {
+	totals := checker.Totals()
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintf(w, "Session\t%s\n", elapsed.Round(time.Second))
+	fmt.Fprintf(w, "Checks run\t%s\n", format.Number(int(totals.Checks)))
+	fmt.Fprintf(w, "Events detected\t%s (%s follows, %s unfollows)\n",
+		format.Number(int(totals.Follows+totals.Unfollows)),
+		format.Number(int(totals.Follows)), format.Number(int(totals.Unfollows)))
+	fmt.Fprintf(w, "API calls\t%s", format.Number(int(apiClient.Requests())))
+	if remaining := apiClient.RemainingRequests(); remaining > 0 {
+		fmt.Fprintf(w, " (%s left in quota)", format.Number(remaining))
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Notifications sent\t%s", format.Number(int(notifications.Sent())))
+	if failed := notifications.Failures(); failed > 0 {
+		fmt.Fprintf(w, " (%s failed)", format.Number(int(failed)))
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+
+	"x-tracker/config"
+	"x-tracker/internal/db"
+	"x-tracker/internal/format"
+	"x-tracker/internal/logger"
+	"x-tracker/internal/webhook"
+)
+
+// setup loads the configuration, starts logging and opens the database,
+// or a scratch copy of it with --dry-run. Callers must close the database
+// and the logger when done.
+func setup() (*config.Config, db.Store, error) {
+	// Load configuration
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading config: %w", err)
+	}
+	if dryRun {
+		cfg = dryRunConfig(cfg)
+	}
+
+	if err := format.Configure(cfg.NumberFormat, cfg.NumberLocale); err != nil {
+		return nil, nil, err
+	}
+	if err := webhook.ConfigureTransport(cfg); err != nil {
+		return nil, nil, err
+	}
+	if err := webhook.ConfigureTemplates(cfg); err != nil {
+		return nil, nil, err
+	}
+
+	// Initialize logger
+	if err := logger.Initialize(logger.Options{
+		Enabled: cfg.LoggingEnabled,
+		Dir:     cfg.LogDir,
+		Level:   cfg.LogLevel,
+		JSON:    cfg.LogFormat == "json",
+
+		MaxSize:  cfg.LogMaxSize,
+		MaxFiles: cfg.LogMaxFiles,
+		MaxAge:   cfg.LogMaxAge,
+	}); err != nil {
+		return nil, nil, fmt.Errorf("initializing logger: %w", err)
+	}
+
+	// Initialize database
+	database, err := openStore(cfg)
+	if err != nil {
+		logger.Close()
+		return nil, nil, fmt.Errorf("initializing database: %w", err)
+	}
+
+	if err := database.SetDiffMode(cfg.DiffMode); err != nil {
+		database.Close()
+		logger.Close()
+		return nil, nil, err
+	}
+	database.SetPartitionThreshold(cfg.PartitionThreshold)
+
+	return cfg, database, nil
+}
+
+// openStore opens the Postgres database at DATABASE_URL, or else the SQLite
+// file at DB_PATH, offering recovery when it is corrupted. A dry run works
+// on a scratch copy of the file instead.
+func openStore(cfg *config.Config) (db.Store, error) {
+	if cfg.DatabaseURL != "" {
+		if dryRun {
+			return nil, fmt.Errorf("--dry-run works on a copy of the SQLite database and can't be used with DATABASE_URL")
+		}
+		return db.NewPostgresDatabase(cfg.DatabaseURL)
+	}
+	if dryRun {
+		return db.OpenScratchCopy(cfg.DBPath)
+	}
+
+	database, err := db.NewDatabase(cfg.DBPath)
+	if db.IsCorrupt(err) {
+		if err = recoverDatabase(cfg, err); err == nil {
+			database, err = db.NewDatabase(cfg.DBPath)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return database, nil
+}
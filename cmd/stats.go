@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"x-tracker/internal/db"
+	"x-tracker/internal/format"
+	"x-tracker/internal/logger"
+)
+
+var (
+	statsDays  int
+	statsLimit int
+	statsJSON  bool
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Print aggregate statistics of the changes detected",
+	Long: `Print the follows and unfollows detected per day, the average number of
+new follows per week, the users the most watched accounts follow and the
+accounts whose following lists changed the most. Only stored data is used.
+Use --json for scripts.`,
+	Args: cobra.NoArgs,
+	RunE: runStats,
+}
+
+func init() {
+	statsCmd.Flags().IntVar(&statsDays, "days", 30, "only count changes from the last this many days")
+	statsCmd.Flags().IntVarP(&statsLimit, "limit", "n", 10, "maximum number of targets and accounts")
+	statsCmd.Flags().BoolVar(&statsJSON, "json", false, "print the statistics as JSON")
+	rootCmd.AddCommand(statsCmd)
+}
+
+// statsReport is the JSON output of the stats command
+type statsReport struct {
+	Since          time.Time      `json:"since"`
+	Follows        int            `json:"follows"`
+	Unfollows      int            `json:"unfollows"`
+	FollowsPerWeek float64        `json:"follows_per_week"`
+	Days           []statsDay     `json:"days"`
+	TopTargets     []statsTarget  `json:"top_targets"`
+	Churn          []statsAccount `json:"churn"`
+}
+
+type statsDay struct {
+	Day       string `json:"day"` // YYYY-MM-DD, local time
+	Follows   int    `json:"follows"`
+	Unfollows int    `json:"unfollows"`
+}
+
+type statsTarget struct {
+	UserID   string `json:"user_id"`
+	Accounts int    `json:"accounts"`
+}
+
+type statsAccount struct {
+	Username       string  `json:"username"`
+	Follows        int     `json:"follows"`
+	Unfollows      int     `json:"unfollows"`
+	Following      int     `json:"following"`
+	Rate           float64 `json:"rate"`
+	FollowsPerWeek float64 `json:"follows_per_week"`
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	if statsDays < 1 {
+		return fmt.Errorf("--days must be at least 1")
+	}
+
+	_, database, err := setup()
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+	defer database.Close()
+
+	stats, err := database.GetStats(time.Now().AddDate(0, 0, -statsDays), statsLimit)
+	if err != nil {
+		return fmt.Errorf("computing statistics: %w", err)
+	}
+
+	if statsJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(newStatsReport(stats))
+	}
+	printStats(stats)
+	return nil
+}
+
+func newStatsReport(stats *db.Stats) statsReport {
+	report := statsReport{
+		Since:          stats.Since,
+		Follows:        stats.Follows,
+		Unfollows:      stats.Unfollows,
+		FollowsPerWeek: stats.FollowsPerWeek,
+		Days:           []statsDay{},
+		TopTargets:     []statsTarget{},
+		Churn:          []statsAccount{},
+	}
+	for _, day := range stats.Days {
+		report.Days = append(report.Days, statsDay{
+			Day:       day.Day.Format("2006-01-02"),
+			Follows:   day.Follows,
+			Unfollows: day.Unfollows,
+		})
+	}
+	for _, target := range stats.TopTargets {
+		report.TopTargets = append(report.TopTargets, statsTarget{UserID: target.UserID, Accounts: target.Accounts})
+	}
+	for _, account := range stats.Churn {
+		report.Churn = append(report.Churn, statsAccount{
+			Username:       account.Username,
+			Follows:        account.Follows,
+			Unfollows:      account.Unfollows,
+			Following:      account.Following,
+			Rate:           account.Rate,
+			FollowsPerWeek: account.FollowsPerWeek,
+		})
+	}
+	return report
+}
+
+// printStats writes the statistics as a summary followed by one table per
+// aggregate
+func printStats(stats *db.Stats) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(w, "Since\t%s\n", stats.Since.Local().Format("2006-01-02"))
+	fmt.Fprintf(w, "Follows\t%s\n", format.Number(stats.Follows))
+	fmt.Fprintf(w, "Unfollows\t%s\n", format.Number(stats.Unfollows))
+	fmt.Fprintf(w, "New follows per week\t%.1f\n", stats.FollowsPerWeek)
+
+	fmt.Fprintln(w, "\nDAY\tFOLLOWS\tUNFOLLOWS")
+	for _, day := range stats.Days {
+		fmt.Fprintf(w, "%s\t%d\t%d\n", day.Day.Format("2006-01-02 Mon"), day.Follows, day.Unfollows)
+	}
+
+	fmt.Fprintln(w, "\nMOST FOLLOWED\tWATCHED ACCOUNTS")
+	if len(stats.TopTargets) == 0 {
+		fmt.Fprintln(w, "(no user is followed by more than one watched account)")
+	}
+	for _, target := range stats.TopTargets {
+		fmt.Fprintf(w, "%s\t%d\n", target.UserID, target.Accounts)
+	}
+
+	fmt.Fprintln(w, "\nHIGHEST CHURN\tFOLLOWS\tUNFOLLOWS\tFOLLOWING\tCHURN\tFOLLOWS/WEEK")
+	if len(stats.Churn) == 0 {
+		fmt.Fprintln(w, "(no changes)")
+	}
+	for _, account := range stats.Churn {
+		fmt.Fprintf(w, "@%s\t%d\t%d\t%s\t%.1f%%\t%.1f\n",
+			account.Username,
+			account.Follows,
+			account.Unfollows,
+			format.Number(account.Following),
+			account.Rate*100,
+			account.FollowsPerWeek)
+	}
+	w.Flush()
+}
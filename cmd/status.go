@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"x-tracker/internal/api"
+	"x-tracker/internal/daemon"
+	"x-tracker/internal/format"
+	"x-tracker/internal/logger"
+)
+
+var statusJSON bool
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Print a quick health snapshot of the tracker",
+	Long: `Print whether the tracker is running, how many accounts are watched, how
+the last check cycle went, the remaining API quota, failed notifications and
+the events suppressed by the mute list.
+Live numbers come from the running tracker when there is one. Use --json for
+scripts.`,
+	Args: cobra.NoArgs,
+	RunE: runStatus,
+}
+
+func init() {
+	statusCmd.Flags().BoolVar(&statusJSON, "json", false, "print the status as JSON")
+	rootCmd.AddCommand(statusCmd)
+}
+
+// statusReport is the output of the status command
+type statusReport struct {
+	Running   bool       `json:"running"`
+	PID       int        `json:"pid,omitempty"`
+	StartedAt *time.Time `json:"started_at,omitempty"`
+
+	Accounts         int `json:"accounts"`
+	Unavailable      int `json:"unavailable"`
+	Paused           int `json:"paused"`
+	AwaitingBaseline int `json:"awaiting_baseline"`
+	Drifting         int `json:"drifting"`
+
+	LastCycle *cycleReport `json:"last_cycle,omitempty"`
+
+	MutedUsers      int `json:"muted_users"`
+	MutedSuppressed int `json:"muted_suppressed"` // events kept out of notifications by the mute list
+
+	QuotaRemaining *int `json:"quota_remaining,omitempty"` // live from the running tracker
+
+	RateLimitTokens   *float64 `json:"rate_limit_tokens,omitempty"`
+	RateLimitCapacity int      `json:"rate_limit_capacity,omitempty"`
+}
+
+// cycleReport summarizes the last check cycle
+type cycleReport struct {
+	StartedAt      time.Time `json:"started_at"`
+	FinishedAt     time.Time `json:"finished_at"`
+	Accounts       int       `json:"accounts"`
+	Failures       int       `json:"failures"`
+	NotifyFailures int       `json:"notify_failures"`
+	QuotaRemaining int       `json:"quota_remaining"`
+	Error          string    `json:"error,omitempty"`
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	cfg, database, err := setup()
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+	defer database.Close()
+
+	var report statusReport
+
+	// Ask the running tracker for live numbers, falling back to the pid file
+	// for instances without a control socket
+	var live liveStatus
+	if resp, ok, err := delegate(cfg, controlStatus); ok {
+		if err == nil {
+			err = decodeData(resp, &live)
+		}
+		if err != nil {
+			return fmt.Errorf("querying the running tracker: %w", err)
+		}
+		report.Running = true
+		report.PID = live.PID
+		report.StartedAt = &live.StartedAt
+		report.QuotaRemaining = &live.QuotaRemaining
+	} else {
+		info, err := daemon.Probe(cfg.PIDFile)
+		if err != nil {
+			return err
+		}
+		if info != nil {
+			report.Running = true
+			report.PID = info.PID
+			report.StartedAt = &info.StartedAt
+		}
+	}
+
+	accounts, err := database.GetWatchedAccounts()
+	if err != nil {
+		return fmt.Errorf("loading watched accounts: %w", err)
+	}
+	report.Accounts = len(accounts)
+	for _, account := range accounts {
+		if !account.Available() {
+			report.Unavailable++
+		}
+		if account.Paused() {
+			report.Paused++
+		}
+		if account.BaselinedAt.IsZero() {
+			report.AwaitingBaseline++
+		}
+		if account.Drift != nil {
+			report.Drifting++
+		}
+	}
+
+	run, err := database.GetLastCheckRun()
+	if err != nil {
+		return fmt.Errorf("loading last check run: %w", err)
+	}
+	if run != nil {
+		report.LastCycle = &cycleReport{
+			StartedAt:      run.StartedAt,
+			FinishedAt:     run.FinishedAt,
+			Accounts:       run.Accounts,
+			Failures:       run.Failures,
+			NotifyFailures: run.NotifyFailures,
+			QuotaRemaining: run.QuotaRemaining,
+			Error:          run.Error,
+		}
+	}
+
+	mutes, err := database.GetMuteStats()
+	if err != nil {
+		return fmt.Errorf("loading mute list: %w", err)
+	}
+	report.MutedUsers = mutes.Users
+	report.MutedSuppressed = mutes.Suppressed
+
+	if cfg.MaxRequestsPerMinute > 0 {
+		limiter := api.NewSharedLimiter(cfg.RateLimitFile, cfg.MaxRequestsPerMinute)
+		tokens, err := limiter.Available()
+		if err != nil {
+			return err
+		}
+		report.RateLimitTokens = &tokens
+		report.RateLimitCapacity = limiter.Capacity()
+	}
+
+	if statusJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+	printStatus(report)
+	return nil
+}
+
+// printStatus writes the report as a two-column table
+func printStatus(report statusReport) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+
+	if report.Running {
+		fmt.Fprintf(w, "Tracker\trunning (pid %d, up %s)\n", report.PID, time.Since(*report.StartedAt).Round(time.Second))
+	} else {
+		fmt.Fprintf(w, "Tracker\tnot running\n")
+	}
+
+	fmt.Fprintf(w, "Accounts\t%s watched, %d paused, %d unavailable, %d awaiting baseline, %d drifting\n",
+		format.Number(report.Accounts), report.Paused, report.Unavailable, report.AwaitingBaseline, report.Drifting)
+
+	cycle := report.LastCycle
+	switch {
+	case cycle == nil:
+		fmt.Fprintf(w, "Last cycle\tnever\n")
+	case cycle.Error != "":
+		fmt.Fprintf(w, "Last cycle\t%s (%s ago), failed: %s\n",
+			cycle.FinishedAt.Local().Format("2006-01-02 15:04:05"),
+			time.Since(cycle.FinishedAt).Round(time.Second), cycle.Error)
+	default:
+		fmt.Fprintf(w, "Last cycle\t%s (%s ago), %d checked, %d failed, took %s\n",
+			cycle.FinishedAt.Local().Format("2006-01-02 15:04:05"),
+			time.Since(cycle.FinishedAt).Round(time.Second),
+			cycle.Accounts, cycle.Failures,
+			cycle.FinishedAt.Sub(cycle.StartedAt).Round(time.Second))
+	}
+
+	if report.QuotaRemaining != nil {
+		fmt.Fprintf(w, "API quota\t%s requests left\n", format.Number(*report.QuotaRemaining))
+	} else if cycle != nil {
+		fmt.Fprintf(w, "API quota\t%s requests left after the last cycle\n", format.Number(cycle.QuotaRemaining))
+	}
+	if cycle != nil {
+		fmt.Fprintf(w, "Notifications\t%d failed in the last cycle\n", cycle.NotifyFailures)
+	}
+	if report.MutedUsers > 0 {
+		fmt.Fprintf(w, "Mute list\t%s users, %s events suppressed\n",
+			format.Number(report.MutedUsers), format.Number(report.MutedSuppressed))
+	}
+	if report.RateLimitTokens != nil {
+		fmt.Fprintf(w, "Rate limiter\t%.0f/%d tokens\n", *report.RateLimitTokens, report.RateLimitCapacity)
+	}
+}
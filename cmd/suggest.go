@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"x-tracker/internal/api"
+	"x-tracker/internal/format"
+	"x-tracker/internal/logger"
+	"x-tracker/internal/tracker"
+)
+
+var (
+	suggestDays    int
+	suggestLimit   int
+	suggestResolve bool
+)
+
+var suggestCmd = &cobra.Command{
+	Use:   "suggest <username>",
+	Short: "Suggest accounts to watch from a watched account's recent follows",
+	Long: `Rank the users a watched account followed recently by how many of your
+other watched accounts follow them too. Users followed by several accounts
+you already track are the highest-signal candidates for the watch list.
+
+Only stored data is used. Pass --resolve to look up the usernames of the
+suggestions, which costs one API request each.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSuggest,
+}
+
+func init() {
+	suggestCmd.Flags().IntVar(&suggestDays, "days", 30, "only consider follows from the last this many days")
+	suggestCmd.Flags().IntVarP(&suggestLimit, "limit", "n", 10, "maximum number of suggestions")
+	suggestCmd.Flags().BoolVar(&suggestResolve, "resolve", false, "look up usernames and follower counts through the API")
+	rootCmd.AddCommand(suggestCmd)
+}
+
+func runSuggest(cmd *cobra.Command, args []string) error {
+	if suggestDays < 1 {
+		return fmt.Errorf("--days must be at least 1")
+	}
+
+	cfg, database, err := setup()
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+	defer database.Close()
+
+	username := strings.TrimPrefix(args[0], "@")
+	account, err := database.GetWatchedAccountByUsername(username)
+	if err != nil {
+		return fmt.Errorf("loading account: %w", err)
+	}
+	if account == nil {
+		return fmt.Errorf("account @%s is not watched", username)
+	}
+
+	since := time.Now().AddDate(0, 0, -suggestDays)
+	suggestions, err := database.GetSuggestions(account.ID, since, suggestLimit)
+	if err != nil {
+		return fmt.Errorf("ranking suggestions: %w", err)
+	}
+	if len(suggestions) == 0 {
+		fmt.Printf("No users @%s followed in the last %d days are followed by other watched accounts\n",
+			account.Username, suggestDays)
+		return nil
+	}
+
+	// Looks up every user through the provider of their platform
+	var checker *tracker.Tracker
+	if suggestResolve {
+		checker = tracker.New(database, api.NewClient(cfg), nil, cfg)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, "#\tUSER\tALSO FOLLOWED BY\tFOLLOWED")
+	for i, suggestion := range suggestions {
+		user := suggestion.UserID
+		if checker != nil {
+			if details, err := checker.ProviderFor(account.Platform).GetUserByID(suggestion.UserID); err != nil {
+				logger.Warn("Failed to look up suggestion %s: %v", suggestion.UserID, err)
+			} else {
+				user = fmt.Sprintf("@%s (%s followers)", details.Legacy.ScreenName, format.Number(details.Legacy.FollowersCount))
+			}
+		}
+
+		sharedBy := make([]string, len(suggestion.SharedBy))
+		for j, name := range suggestion.SharedBy {
+			sharedBy[j] = "@" + name
+		}
+		fmt.Fprintf(w, "%d\t%s\t%d: %s\t%s\n",
+			i+1,
+			user,
+			len(sharedBy),
+			strings.Join(sharedBy, ", "),
+			suggestion.FollowedAt.Local().Format("2006-01-02"))
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"x-tracker/internal/db"
+	"x-tracker/internal/logger"
+)
+
+var tagRemove bool
+
+var tagCmd = &cobra.Command{
+	Use:   "tag <username> [tags...]",
+	Short: "Show, add or remove an account's tags",
+	Long: `Tag a watched account to group it with others, e.g.
+"x-tracker tag elonmusk founders tesla". Tags are case-insensitive; with
+--remove the given tags are taken off instead, and without any the account's
+tags are listed. The TUI account list can be filtered by tag, notification
+titles show an account's tags, and TAG_DISCORD_WEBHOOKS sends the
+notifications of tagged accounts to a Discord channel of their own.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runTag,
+}
+
+func init() {
+	tagCmd.Flags().BoolVar(&tagRemove, "remove", false, "remove the given tags instead of adding them")
+	rootCmd.AddCommand(tagCmd)
+}
+
+func runTag(cmd *cobra.Command, args []string) error {
+	_, database, err := setup()
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+	defer database.Close()
+
+	username := strings.TrimPrefix(args[0], "@")
+	account, err := database.GetWatchedAccountByUsername(username)
+	if err != nil {
+		return fmt.Errorf("loading account: %w", err)
+	}
+	if account == nil {
+		return fmt.Errorf("account @%s is not watched", username)
+	}
+
+	var tags []string
+	for _, arg := range args[1:] {
+		if tag := db.NormalizeTag(arg); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	if len(tags) == 0 {
+		if tagRemove {
+			return fmt.Errorf("no tags to remove")
+		}
+		if len(account.Tags) == 0 {
+			fmt.Printf("@%s has no tags\n", account.Username)
+		} else {
+			fmt.Printf("@%s: %s\n", account.Username, strings.Join(account.Tags, ", "))
+		}
+		return nil
+	}
+
+	if tagRemove {
+		if err := database.RemoveTags(account.ID, tags); err != nil {
+			return fmt.Errorf("removing tags: %w", err)
+		}
+		audit(database, "untag", "@"+account.Username, strings.Join(tags, ", "))
+		fmt.Printf("Removed %s from @%s\n", strings.Join(tags, ", "), account.Username)
+		return nil
+	}
+	if err := database.AddTags(account.ID, tags); err != nil {
+		return fmt.Errorf("adding tags: %w", err)
+	}
+	audit(database, "tag", "@"+account.Username, strings.Join(tags, ", "))
+	fmt.Printf("Tagged @%s with %s\n", account.Username, strings.Join(tags, ", "))
+	return nil
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"x-tracker/config"
+	"x-tracker/internal/webhook"
+)
+
+// telegramLinkTimeout bounds how long link waits for the /start message
+var telegramLinkTimeout time.Duration
+
+var telegramCmd = &cobra.Command{
+	Use:   "telegram",
+	Short: "Set up Telegram notifications",
+}
+
+var telegramLinkCmd = &cobra.Command{
+	Use:   "link",
+	Short: "Find the chat ID by sending the bot /start and save it to .env",
+	Long: `Wait for someone to send /start to the bot configured by TELEGRAM_BOT_TOKEN,
+then save the ID of the chat it was sent in as TELEGRAM_CHAT_ID in .env and
+reply in that chat to confirm. Send /start in a private chat with the bot
+to get notifications yourself, or add the bot to a group and send /start
+there to notify the group.
+
+Doesn't work while the bot has a webhook set, since Telegram then doesn't
+hand out messages to other clients.`,
+	Args: cobra.NoArgs,
+	RunE: runTelegramLink,
+}
+
+func init() {
+	telegramLinkCmd.Flags().DurationVar(&telegramLinkTimeout, "timeout", 5*time.Minute, "how long to wait for /start")
+	telegramCmd.AddCommand(telegramLinkCmd)
+	rootCmd.AddCommand(telegramCmd)
+}
+
+func runTelegramLink(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if err := webhook.ConfigureTransport(cfg); err != nil {
+		return err
+	}
+	if cfg.TelegramBotToken == "" {
+		return fmt.Errorf("TELEGRAM_BOT_TOKEN is not set; create a bot with @BotFather and add its token to .env first")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, telegramLinkTimeout)
+	defer cancel()
+
+	since := time.Now()
+	bot, err := webhook.TelegramBotUsername(ctx, cfg.TelegramBotToken)
+	if err != nil {
+		return fmt.Errorf("checking the bot token: %w", err)
+	}
+	fmt.Printf("Send /start to @%s on Telegram (https://t.me/%s), or in a group it's a member of.\n", bot, bot)
+	fmt.Printf("Waiting up to %s...\n", telegramLinkTimeout)
+
+	chat, err := webhook.WaitForTelegramStart(ctx, cfg.TelegramBotToken, since)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("no /start received within %s", telegramLinkTimeout)
+	}
+	if err != nil {
+		return fmt.Errorf("waiting for /start: %w", err)
+	}
+
+	chatID := strconv.FormatInt(chat.ID, 10)
+	if err := config.SetDotEnv("TELEGRAM_CHAT_ID", chatID); err != nil {
+		return err
+	}
+	fmt.Printf("Linked %s chat %s (%s) and saved TELEGRAM_CHAT_ID=%s to .env\n", chat.Type, chat.Name(), chatID, chatID)
+	if config.SetInEnvironment("TELEGRAM_CHAT_ID") {
+		fmt.Println("Note: TELEGRAM_CHAT_ID is also set in the environment, which takes precedence over .env")
+	}
+
+	if err := webhook.ConfirmTelegramLink(cfg.TelegramBotToken, chat); err != nil {
+		return fmt.Errorf("sending the confirmation: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"x-tracker/internal/db"
+	"x-tracker/internal/format"
+	"x-tracker/internal/tracker"
+	"x-tracker/internal/ui"
+	"x-tracker/internal/webhook"
+)
+
+// telegramCommands are the commands the Telegram bot answers in the
+// configured chat, managing the watch list of this process
+func telegramCommands(checker *tracker.Tracker, database db.Store, p *tea.Program) map[string]webhook.BotCommand {
+	audit := func(sender, action, target string) {
+		database.Audit(db.AuditEntry{
+			Source: db.AuditSourceTelegram,
+			Actor:  sender,
+			Action: action,
+			Target: target,
+		})
+	}
+
+	return map[string]webhook.BotCommand{
+		"add": {
+			Usage:       "/add <username>",
+			Description: "watch an account",
+			Run: func(sender string, args []string) (string, error) {
+				if len(args) != 1 {
+					return "", fmt.Errorf("usage: /add <username>")
+				}
+				account, err := checker.AddAccount(args[0])
+				if err != nil {
+					return "", addError(err)
+				}
+				audit(sender, "add", "@"+account.Username)
+				message := fmt.Sprintf("Added @%s", account.Username)
+				p.Send(ui.AccountsChangedMsg{Notice: message})
+				return message, nil
+			},
+		},
+		"remove": {
+			Usage:       "/remove <username>",
+			Description: "stop watching an account",
+			Run: func(sender string, args []string) (string, error) {
+				if len(args) != 1 {
+					return "", fmt.Errorf("usage: /remove <username>")
+				}
+				username := strings.TrimPrefix(args[0], "@")
+				account, err := database.GetWatchedAccountByUsername(username)
+				if err != nil {
+					return "", fmt.Errorf("loading account: %w", err)
+				}
+				if account == nil {
+					return "", fmt.Errorf("account @%s is not watched", username)
+				}
+				if err := checker.RemoveAccount(account); err != nil {
+					return "", err
+				}
+				audit(sender, "remove", "@"+account.Username)
+				message := fmt.Sprintf("Removed @%s", account.Username)
+				p.Send(ui.AccountsChangedMsg{Notice: message})
+				return message, nil
+			},
+		},
+		"list": {
+			Usage:       "/list",
+			Description: "show the watched accounts",
+			Run: func(sender string, args []string) (string, error) {
+				return telegramAccountList(database)
+			},
+		},
+		"check": {
+			Usage:       "/check",
+			Description: "check every account now",
+			Run: func(sender string, args []string) (string, error) {
+				if err := checker.CheckAll(); err != nil {
+					return "", err
+				}
+				message := fmt.Sprintf("Check completed at %s", time.Now().Format("15:04:05"))
+				p.Send(ui.AccountsChangedMsg{Notice: message})
+				return message, nil
+			},
+		},
+	}
+}
+
+// telegramAccountList describes the watch list, one account per line
+func telegramAccountList(database db.Store) (string, error) {
+	accounts, err := database.GetWatchedAccounts()
+	if err != nil {
+		return "", fmt.Errorf("loading accounts: %w", err)
+	}
+	if len(accounts) == 0 {
+		return "No accounts are watched; add one with /add <username>", nil
+	}
+	counts, err := database.GetFollowingCounts()
+	if err != nil {
+		return "", fmt.Errorf("loading following counts: %w", err)
+	}
+
+	var list strings.Builder
+	fmt.Fprintf(&list, "Watching %d accounts:\n", len(accounts))
+	for _, account := range accounts {
+		fmt.Fprintf(&list, "@%s: %s following", account.Username, format.Number(counts[account.ID]))
+		switch {
+		case account.Paused():
+			list.WriteString(" (paused)")
+		case !account.Available():
+			fmt.Fprintf(&list, " (%s)", account.Status)
+		}
+		list.WriteString("\n")
+	}
+	return list.String(), nil
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"x-tracker/internal/db"
+	"x-tracker/internal/format"
+	"x-tracker/internal/logger"
+)
+
+// timezoneMinBursts is how many bursts of activity a guess needs at all
+const timezoneMinBursts = 10
+
+// timezoneBarWidth is the width of the busiest hour's bar
+const timezoneBarWidth = 40
+
+// offsetZones names a few places on each whole-hour UTC offset, as of
+// standard time
+var offsetZones = map[int]string{
+	-10: "Pacific/Honolulu",
+	-9:  "America/Anchorage",
+	-8:  "America/Los_Angeles",
+	-7:  "America/Denver, America/Phoenix",
+	-6:  "America/Chicago, America/Mexico_City",
+	-5:  "America/New_York, America/Bogota",
+	-4:  "America/Halifax, America/Caracas",
+	-3:  "America/Sao_Paulo, America/Buenos_Aires",
+	0:   "Europe/London, Africa/Accra",
+	1:   "Europe/Berlin, Europe/Paris, Africa/Lagos",
+	2:   "Europe/Kyiv, Africa/Cairo",
+	3:   "Europe/Moscow, Europe/Istanbul, Asia/Riyadh",
+	4:   "Asia/Dubai",
+	5:   "Asia/Karachi, Asia/Tashkent",
+	6:   "Asia/Dhaka, Asia/Almaty",
+	7:   "Asia/Bangkok, Asia/Jakarta",
+	8:   "Asia/Shanghai, Asia/Singapore",
+	9:   "Asia/Tokyo, Asia/Seoul",
+	10:  "Australia/Sydney",
+	12:  "Pacific/Auckland",
+}
+
+var timezoneDays int
+
+var timezoneCmd = &cobra.Command{
+	Use:   "timezone <username>",
+	Short: "Infer a watched account's likely timezone from when it is active",
+	Long: `Chart when a watched account follows and unfollows by hour of the day and
+guess its timezone from the hours it is quiet, taken to be its night.
+
+Only stored events are used. They are timed by the check that noticed them,
+so they lag by up to the check interval, and changes noticed by one check
+count once. The API responses the tracker keeps carry no locations, so the
+guess rests on timing alone; daylight saving time can shift it by an hour.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTimezone,
+}
+
+func init() {
+	timezoneCmd.Flags().IntVar(&timezoneDays, "days", 90, "only consider events from the last this many days")
+	rootCmd.AddCommand(timezoneCmd)
+}
+
+func runTimezone(cmd *cobra.Command, args []string) error {
+	if timezoneDays < 1 {
+		return fmt.Errorf("--days must be at least 1")
+	}
+
+	_, database, err := setup()
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+	defer database.Close()
+
+	username := strings.TrimPrefix(args[0], "@")
+	account, err := database.GetWatchedAccountByUsername(username)
+	if err != nil {
+		return fmt.Errorf("loading account: %w", err)
+	}
+	if account == nil {
+		return fmt.Errorf("account @%s is not watched", username)
+	}
+
+	activity, err := database.GetActivityHours(account.ID, time.Now().AddDate(0, 0, -timezoneDays))
+	if err != nil {
+		return fmt.Errorf("loading events: %w", err)
+	}
+	fmt.Printf("@%s: %s bursts of activity from %s follows and unfollows over the last %d days\n", account.Username,
+		format.Number(activity.Bursts), format.Number(activity.Events), timezoneDays)
+	if activity.Bursts < timezoneMinBursts {
+		fmt.Printf("Not enough activity to guess a timezone; at least %d bursts are needed\n", timezoneMinBursts)
+		return nil
+	}
+
+	guess := db.InferTimezone(activity)
+	zone := fmt.Sprintf("UTC%+d", guess.Offset)
+	if guess.Offset == 0 {
+		zone = "UTC"
+	}
+	if places, ok := offsetZones[guess.Offset]; ok {
+		zone += fmt.Sprintf(" (e.g. %s)", places)
+	}
+	fmt.Printf("Likely %s, %s confidence\n", zone, guess.Confidence)
+	fmt.Printf("Quietest %d hours: %s UTC (%s local), %.0f%% of the activity\n", guess.QuietHours,
+		hourRange(guess.QuietStart, guess.QuietHours), hourRange(localHour(guess.QuietStart, guess.Offset), guess.QuietHours), guess.QuietShare*100)
+	fmt.Printf("Busiest hour: %02d:00 UTC (%02d:00 local)\n\n", guess.PeakHour, localHour(guess.PeakHour, guess.Offset))
+
+	busiest := activity.Hours[guess.PeakHour]
+	fmt.Println("UTC    LOCAL  BURSTS")
+	for h, count := range activity.Hours {
+		bar := strings.Repeat("█", (count*timezoneBarWidth+busiest-1)/busiest)
+		fmt.Printf("%02d:00  %02d:00  %-*s %s\n", h, localHour(h, guess.Offset), timezoneBarWidth, bar, format.Number(count))
+	}
+	return nil
+}
+
+// localHour converts a UTC hour of the day to one offset hours east of UTC
+func localHour(utc, offset int) int {
+	return ((utc+offset)%24 + 24) % 24
+}
+
+// hourRange formats hours starting at start, e.g. "23:00-05:00"
+func hourRange(start, hours int) string {
+	return fmt.Sprintf("%02d:00-%02d:00", start, (start+hours)%24)
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"x-tracker/internal/api"
+	"x-tracker/internal/format"
+	"x-tracker/internal/logger"
+	"x-tracker/internal/tracker"
+)
+
+var (
+	trendingDays    int
+	trendingLimit   int
+	trendingResolve bool
+)
+
+var trendingCmd = &cobra.Command{
+	Use:   "trending",
+	Short: "Rank users several watched accounts followed recently",
+	Long: `Rank the users followed in the last days by how many of your watched
+accounts followed them. Each follow is weighted by how selective the account
+is, so a follow from an account following a few hundred users counts for
+more than one from an account following tens of thousands. Users followed
+by a single account, and users already watched, are left out.
+
+Only stored data is used. Pass --resolve to look up the usernames of the
+targets, which costs one API request each; add the ones worth watching
+with x-tracker add, or press w on them in the TUI's trending view.`,
+	Args: cobra.NoArgs,
+	RunE: runTrending,
+}
+
+func init() {
+	trendingCmd.Flags().IntVar(&trendingDays, "days", 7, "only consider follows from the last this many days")
+	trendingCmd.Flags().IntVarP(&trendingLimit, "limit", "n", 20, "maximum number of targets")
+	trendingCmd.Flags().BoolVar(&trendingResolve, "resolve", false, "look up usernames and follower counts through the API")
+	rootCmd.AddCommand(trendingCmd)
+}
+
+func runTrending(cmd *cobra.Command, args []string) error {
+	if trendingDays < 1 {
+		return fmt.Errorf("--days must be at least 1")
+	}
+
+	cfg, database, err := setup()
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+	defer database.Close()
+
+	since := time.Now().AddDate(0, 0, -trendingDays)
+	trending, err := database.GetTrendingTargets(since, trendingLimit)
+	if err != nil {
+		return fmt.Errorf("ranking trending targets: %w", err)
+	}
+	if len(trending) == 0 {
+		fmt.Printf("No user was followed by more than one watched account in the last %d days\n", trendingDays)
+		return nil
+	}
+
+	// Looks up every user through the provider of their platform
+	var checker *tracker.Tracker
+	if trendingResolve {
+		checker = tracker.New(database, api.NewClient(cfg), nil, cfg)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, "#\tUSER\tSCORE\tFOLLOWED BY\tLAST FOLLOWED")
+	for i, target := range trending {
+		user := target.UserID
+		if checker != nil {
+			if details, err := checker.ProviderFor(tracker.PlatformOfUserID(target.UserID)).GetUserByID(target.UserID); err != nil {
+				logger.Warn("Failed to look up trending target %s: %v", target.UserID, err)
+			} else {
+				user = fmt.Sprintf("@%s (%s followers)", details.Legacy.ScreenName, format.Number(details.Legacy.FollowersCount))
+			}
+		}
+
+		followedBy := make([]string, len(target.FollowedBy))
+		for j, name := range target.FollowedBy {
+			followedBy[j] = "@" + name
+		}
+		fmt.Fprintf(w, "%d\t%s\t%.1f\t%d: %s\t%s\n",
+			i+1,
+			user,
+			target.Score,
+			len(followedBy),
+			strings.Join(followedBy, ", "),
+			target.LastFollowedAt.Local().Format("2006-01-02"))
+	}
+	return nil
+}
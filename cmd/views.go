@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"x-tracker/internal/db"
+	"x-tracker/internal/logger"
+)
+
+var viewsCmd = &cobra.Command{
+	Use:   "views",
+	Short: "Manage SQL views for Grafana dashboards",
+	Long: `Create SQL views inside the database shaped for Grafana's SQLite
+or Postgres datasource, so dashboards can chart the tracker's data without custom SQL.
+Every view has a "time" column in Unix seconds. Once installed, the views
+are kept up to date with the schema whenever the database is opened.`,
+}
+
+var viewsInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Create or update the dashboard views and describe their columns",
+	Args:  cobra.NoArgs,
+	RunE:  runViewsInstall,
+}
+
+var viewsRemoveCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Drop the dashboard views",
+	Args:  cobra.NoArgs,
+	RunE:  runViewsRemove,
+}
+
+func init() {
+	viewsCmd.AddCommand(viewsInstallCmd)
+	viewsCmd.AddCommand(viewsRemoveCmd)
+	rootCmd.AddCommand(viewsCmd)
+}
+
+func runViewsInstall(cmd *cobra.Command, args []string) error {
+	cfg, database, err := setup()
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+	defer database.Close()
+
+	if err := database.InstallViews(); err != nil {
+		return fmt.Errorf("installing views: %w", err)
+	}
+	location := cfg.DBPath
+	if cfg.DatabaseURL != "" {
+		location = "the Postgres database"
+	}
+	fmt.Printf("Installed %d views in %s\n", len(db.Views), location)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+	for _, view := range db.Views {
+		fmt.Fprintf(w, "\n%s: %s\n", view.Name, view.Description)
+		for _, column := range view.Columns {
+			fmt.Fprintf(w, "  %s\t%s\n", column.Name, column.Description)
+		}
+	}
+	fmt.Fprintf(w, "\nExample panel query: SELECT time, account, following FROM account_growth ORDER BY time\n")
+	return nil
+}
+
+func runViewsRemove(cmd *cobra.Command, args []string) error {
+	_, database, err := setup()
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+	defer database.Close()
+
+	if err := database.RemoveViews(); err != nil {
+		return fmt.Errorf("removing views: %w", err)
+	}
+	fmt.Println("Removed the dashboard views")
+	return nil
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// dotEnvFile is the file loadDotEnv reads, relative to the working directory
+const dotEnvFile = ".env"
+
+// SetDotEnv sets key to value in .env, replacing an existing assignment in
+// place so comments and the order of the file are kept, or appending one.
+// The file is created if it doesn't exist. value is written as is, so it
+// must not need quoting.
+func SetDotEnv(key, value string) error {
+	data, err := os.ReadFile(dotEnvFile)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reading %s: %w", dotEnvFile, err)
+	}
+
+	assignment := key + "=" + value
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(data) == 0 {
+		lines = nil
+	}
+	replaced := false
+	for i, line := range lines {
+		name, _, ok := strings.Cut(strings.TrimPrefix(strings.TrimSpace(line), "export "), "=")
+		if ok && strings.TrimSpace(name) == key {
+			lines[i] = assignment
+			replaced = true
+		}
+	}
+	if !replaced {
+		lines = append(lines, assignment)
+	}
+
+	if err := os.WriteFile(dotEnvFile, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		return fmt.Errorf("writing %s: %w", dotEnvFile, err)
+	}
+	return nil
+}
+
+// SetInEnvironment reports whether key is set in the process environment,
+// where it takes precedence over .env. LoadConfig must have run first.
+func SetInEnvironment(key string) bool {
+	return processEnv[key]
+}
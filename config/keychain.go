@@ -0,0 +1,55 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// keychainPrefix marks a value to be read from the OS keychain instead,
+// e.g. BACKUP_SECRET_KEY=keychain:backup-secret
+const keychainPrefix = "keychain:"
+
+// keychainService is the service the keychain entries are stored under
+const keychainService = "x-tracker"
+
+// getSecret reads a secret from the environment, following a keychain:
+// reference to the OS keychain
+func getSecret(key string) (string, error) {
+	value := os.Getenv(key)
+	name, ok := strings.CutPrefix(value, keychainPrefix)
+	if !ok {
+		return value, nil
+	}
+	secret, err := readKeychain(name)
+	if err != nil {
+		return "", fmt.Errorf("reading %s from the keychain: %w", key, err)
+	}
+	return secret, nil
+}
+
+// readKeychain looks up the entry for name with the platform's keychain
+// tool: security on macOS, secret-tool (libsecret) elsewhere
+func readKeychain(name string) (string, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", keychainService, "-a", name, "-w")
+	case "windows":
+		return "", fmt.Errorf("keychain lookups are not supported on Windows")
+	default:
+		cmd = exec.Command("secret-tool", "lookup", "service", keychainService, "account", name)
+	}
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", cmd.Path, err)
+	}
+	secret := strings.TrimRight(string(out), "\r\n")
+	if secret == "" {
+		return "", fmt.Errorf("no entry %q for service %s", name, keychainService)
+	}
+	return secret, nil
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// notifyPresets are named sets of notification settings selected with
+// NOTIFY_PRESET. They replace the built-in defaults only, so any of the
+// variables set explicitly still wins.
+var notifyPresets = map[string]map[string]string{
+	// Nothing is sent at all
+	"silent": {
+		"ENABLE_DISCORD_NOTIFICATIONS":       "false",
+		"ENABLE_TELEGRAM_NOTIFICATIONS":      "false",
+		"ENABLE_SIGNAL_NOTIFICATIONS":        "false",
+		"ENABLE_DESKTOP_NOTIFICATIONS":       "false",
+		"ENABLE_FOLLOW_NOTIFICATIONS":        "false",
+		"ENABLE_UNFOLLOW_NOTIFICATIONS":      "false",
+		"ENABLE_PROFILE_NOTIFICATIONS":       "false",
+		"ENABLE_LOST_FOLLOWER_NOTIFICATIONS": "false",
+		"CYCLE_SUMMARY":                      "false",
+		"COMPLETION_NOTIFY":                  "false",
+	},
+	// One summary line per check cycle instead of a message per change
+	"digest": {
+		"ENABLE_FOLLOW_NOTIFICATIONS":        "false",
+		"ENABLE_UNFOLLOW_NOTIFICATIONS":      "false",
+		"ENABLE_PROFILE_NOTIFICATIONS":       "false",
+		"ENABLE_LOST_FOLLOWER_NOTIFICATIONS": "false",
+		"CYCLE_SUMMARY":                      "true",
+		"COMPLETION_NOTIFY":                  "false",
+	},
+	// Every change, summary and completion, nothing filtered out
+	"everything": {
+		"ENABLE_FOLLOW_NOTIFICATIONS":        "true",
+		"ENABLE_UNFOLLOW_NOTIFICATIONS":      "true",
+		"ENABLE_PROFILE_NOTIFICATIONS":       "true",
+		"ENABLE_LOST_FOLLOWER_NOTIFICATIONS": "true",
+		"FIRST_CHECK_NOTIFY":                 "true",
+		"BOT_SCORE_THRESHOLD":                "0",
+		"CYCLE_SUMMARY":                      "true",
+		"COMPLETION_NOTIFY":                  "true",
+	},
+	// Only alerts about the tracker itself: API anomalies and finished
+	// long-running operations
+	"ops-only": {
+		"ENABLE_FOLLOW_NOTIFICATIONS":        "false",
+		"ENABLE_UNFOLLOW_NOTIFICATIONS":      "false",
+		"ENABLE_PROFILE_NOTIFICATIONS":       "false",
+		"ENABLE_LOST_FOLLOWER_NOTIFICATIONS": "false",
+		"CYCLE_SUMMARY":                      "false",
+		"COMPLETION_NOTIFY":                  "true",
+	},
+}
+
+// presetDefaults holds the defaults of the preset LoadConfig is applying
+var presetDefaults map[string]string
+
+// NotifyPresets returns the names of the notification presets, sorted
+func NotifyPresets() []string {
+	names := make([]string, 0, len(notifyPresets))
+	for name := range notifyPresets {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// NotifyPresetSettings returns the variables a preset sets, or an error if
+// there is no such preset
+func NotifyPresetSettings(name string) (map[string]string, error) {
+	settings, ok := notifyPresets[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown notification preset %q, expected one of %s", name, strings.Join(NotifyPresets(), ", "))
+	}
+	return settings, nil
+}
+
+// applyNotifyPreset makes the preset named by NOTIFY_PRESET the defaults
+// for the rest of LoadConfig; an empty name keeps the built-in defaults
+func applyNotifyPreset(name string) error {
+	presetDefaults = nil
+	if name == "" {
+		return nil
+	}
+	settings, err := NotifyPresetSettings(name)
+	if err != nil {
+		return err
+	}
+	presetDefaults = settings
+	return nil
+}
+
+// presetDefault returns the active preset's value for key, if it sets one
+func presetDefault(key string) (string, bool) {
+	value, ok := presetDefaults[key]
+	return value, ok
+}
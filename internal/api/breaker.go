@@ -0,0 +1,114 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"x-tracker/config"
+	"x-tracker/internal/logger"
+)
+
+// ErrCircuitOpen matches the error returned instead of sending a request
+// while the circuit breaker is open
+var ErrCircuitOpen = errors.New("API degraded")
+
+// CircuitOpenError is returned instead of sending a request while the
+// circuit breaker is open after too many failed requests in a row
+type CircuitOpenError struct {
+	Until  time.Time // when a request is tried again
+	Reason string    // why the last request failed
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("API degraded (%s), not retrying before %s", e.Reason, e.Until.Local().Format("15:04:05"))
+}
+
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// BreakerStatus is the state of the circuit breaker
+type BreakerStatus struct {
+	Open     bool
+	Until    time.Time // when the open circuit lets a request through again
+	Failures int       // failed requests in a row
+	Reason   string    // why the last failed request failed
+}
+
+// breaker stops requests to the API after BreakerThreshold failures in a
+// row for BreakerCooldown. After the cooldown a single request is let
+// through: it closes the circuit if it succeeds and opens it again if not.
+type breaker struct {
+	mu        sync.Mutex
+	failures  int
+	reason    string
+	openUntil time.Time // zero while the circuit is closed
+	probing   bool      // a request is testing the API after the cooldown
+}
+
+// allow returns an error if no request should be sent now
+func (b *breaker) allow(cfg *config.Config, now time.Time) error {
+	if cfg.BreakerThreshold <= 0 {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.openUntil.IsZero() {
+		return nil
+	}
+	if now.Before(b.openUntil) || b.probing {
+		return &CircuitOpenError{Until: b.openUntil, Reason: b.reason}
+	}
+	b.probing = true
+	return nil
+}
+
+// record counts the outcome of a request, failed with reason or not
+func (b *breaker) record(cfg *config.Config, failed bool, reason string, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !failed {
+		if !b.openUntil.IsZero() {
+			logger.Info("API requests succeed again, closing the circuit breaker")
+		}
+		b.failures, b.reason, b.openUntil, b.probing = 0, "", time.Time{}, false
+		return
+	}
+
+	b.failures++
+	b.reason = reason
+	if cfg.BreakerThreshold <= 0 || (b.failures < cfg.BreakerThreshold && !b.probing) {
+		return
+	}
+	b.openUntil = now.Add(cfg.BreakerCooldown)
+	b.probing = false
+	logger.Warn("API degraded after %d failed requests in a row (%s), pausing requests until %s",
+		b.failures, reason, b.openUntil.Local().Format("15:04:05"))
+}
+
+// abort lets another request test the API after the one allowed to was
+// never sent
+func (b *breaker) abort() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// status returns the breaker state at now
+func (b *breaker) status(now time.Time) BreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return BreakerStatus{
+		Open:     !b.openUntil.IsZero() && now.Before(b.openUntil),
+		Until:    b.openUntil,
+		Failures: b.failures,
+		Reason:   b.reason,
+	}
+}
+
+// Breaker returns the state of the circuit breaker guarding API requests
+func (c *Client) Breaker() BreakerStatus {
+	return c.breaker.status(time.Now())
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -15,104 +16,245 @@ import (
 )
 
 type Client struct {
+	mu         sync.RWMutex // guards httpClient, config, limiter and transport, swapped on reload
 	httpClient *http.Client
 	config     *config.Config
+	limiter    *SharedLimiter // nil when rate limiting is disabled
+	transport  http.RoundTripper // the middleware chain requests go through
+	middlewares []Middleware     // added with Use
 	remainingRequests int32  // Using atomic for thread safety
+	requests          atomic.Int64 // requests sent since startup
+	pages             atomic.Int64 // ID list pages fetched since startup
+	lookups           atomic.Int64 // user lookups and searches since startup
+	schema            schemaStats
+	quotas            quotaTracker
+	breaker           breaker
+	refresher         keyRefresher
 }
 
 func NewClient(cfg *config.Config) *Client {
-	return &Client{
+	c := &Client{
 		httpClient: &http.Client{
-			Timeout: cfg.RequestTimeout,
+			Timeout:   cfg.RequestTimeout,
+			Transport: newTransport(cfg),
 		},
-		config: cfg,
+		config:  cfg,
+		limiter: newLimiter(cfg),
 	}
+	c.transport = c.chain(c.config, c.httpClient, c.limiter)
+	return c
+}
+
+// newLimiter creates the shared rate limiter, or nil if it is disabled
+func newLimiter(cfg *config.Config) *SharedLimiter {
+	if cfg.MaxRequestsPerMinute <= 0 {
+		return nil
+	}
+	return NewSharedLimiter(cfg.RateLimitFile, cfg.MaxRequestsPerMinute)
+}
+
+// SetConfig swaps in a reloaded configuration for subsequent requests. A
+// key the credentials command refreshed stays in use unless the reloaded
+// configuration changed RAPID_API_KEY.
+func (c *Client) SetConfig(cfg *config.Config) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cfg = c.keepRefreshedKey(cfg)
+	c.config = cfg
+	c.httpClient = &http.Client{
+		Timeout:   cfg.RequestTimeout,
+		Transport: newTransport(cfg),
+	}
+	c.limiter = newLimiter(cfg)
+	c.transport = c.chain(c.config, c.httpClient, c.limiter)
+}
+
+// settings returns the current config and HTTP client
+func (c *Client) settings() (*config.Config, *http.Client) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.config, c.httpClient
+}
+
+// host returns the configured RapidAPI host
+func (c *Client) host() string {
+	cfg, _ := c.settings()
+	return cfg.RapidAPIHost
 }
 
 func (c *Client) GetUser(username string) (*UserResponse, error) {
 	logger.Info("Starting user lookup for: %s", username)
 	
-	url := fmt.Sprintf("https://%s/v2/user/by-username?username=%s", c.config.RapidAPIHost, username)
-	logger.Info("Making request to: %s", url)
+	url := fmt.Sprintf("https://%s/v2/user/by-username?username=%s", c.host(), username)
+	logger.Debug("Making request to: %s", url)
 	
 	req, err := c.newRequest("GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("creating request: %w", err)
 	}
 
+	c.lookups.Add(1)
 	var response UserResponse
 	if err := c.doRequest(req, &response); err != nil {
-		logger.Info("User lookup failed for %s: %v", username, err)
+		logger.Warn("User lookup failed for %s: %v", username, err)
 		return nil, err
 	}
+	c.schema.observeUser(&response)
 
 	logger.Info("User lookup completed for %s (ID: %s) with a following count of %d", 
 		username, response.RestID, response.Legacy.FriendsCount)
 	return &response, nil
 }
 
+// SearchUsers returns up to count users matching query, best matches first,
+// at the cost of one request
+func (c *Client) SearchUsers(query string, count int) ([]UserResponse, error) {
+	params := url.Values{}
+	params.Add("query", query)
+	params.Add("count", strconv.Itoa(count))
+
+	req, err := c.newRequest("GET", fmt.Sprintf("https://%s/v2/user/search?%s", c.host(), params.Encode()), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	c.lookups.Add(1)
+	var response SearchUsersResponse
+	if err := c.doRequest(req, &response); err != nil {
+		logger.Warn("User search failed for %q: %v", query, err)
+		return nil, err
+	}
+
+	logger.Debug("User search for %q returned %d users", query, len(response.Users))
+	if len(response.Users) > count {
+		response.Users = response.Users[:count]
+	}
+	return response.Users, nil
+}
+
 func (c *Client) GetFollowingIDs(userID string) (*FollowingIDsResponse, error) {
+	return c.getAllIDs("following-ids", userID, nil)
+}
+
+// GetFollowingIDsWithProgress is GetFollowingIDs calling progress with the
+// number of IDs fetched so far after every page
+func (c *Client) GetFollowingIDsWithProgress(userID string, progress func(fetched int)) (*FollowingIDsResponse, error) {
+	return c.getAllIDs("following-ids", userID, progress)
+}
+
+// GetFollowerIDs fetches the IDs of every account following userID
+func (c *Client) GetFollowerIDs(userID string) (*FollowingIDsResponse, error) {
+	return c.getAllIDs("followers-ids", userID, nil)
+}
+
+// ForEachFollowingPage pages through the accounts userID follows, handing
+// each page of IDs to fn as it arrives instead of collecting them all. It
+// stops at the first error fn returns and returns it.
+func (c *Client) ForEachFollowingPage(userID string, fn func(ids []string) error) error {
+	return c.forEachIDPage("following-ids", userID, fn)
+}
+
+// getAllIDs pages through an ID list endpoint until the cursor runs out,
+// reporting the running total to progress if it's set
+func (c *Client) getAllIDs(path, userID string, progress func(fetched int)) (*FollowingIDsResponse, error) {
 	var allIDs []string
+	err := c.forEachIDPage(path, userID, func(ids []string) error {
+		allIDs = append(allIDs, ids...)
+		if progress != nil {
+			progress(len(allIDs))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("client.go.getAllIDs - Fetched a total of %d %s for user %s", len(allIDs), path, userID)
+	// Return all collected IDs in the response structure
+	return &FollowingIDsResponse{
+		IDs: allIDs,
+	}, nil
+}
+
+// forEachIDPage pages through an ID list endpoint until the cursor runs
+// out, calling fn with every page
+func (c *Client) forEachIDPage(path, userID string, fn func(ids []string) error) error {
 	nextCursor := "0"
 	
 	for {
-		endpoint := fmt.Sprintf("https://%s/v2/user/following-ids", c.config.RapidAPIHost)
-		
-		// Build query parameters
-		params := url.Values{}
-		params.Add("userId", userID)
-		params.Add("count", "5000")
-		if nextCursor != "0" {
-			params.Add("cursor", nextCursor)
-		}
-		
-		req, err := c.newRequest("GET", endpoint+"?"+params.Encode(), nil)
+		response, err := c.getIDsPage(path, userID, nextCursor, 5000)
 		if err != nil {
-			return nil, fmt.Errorf("creating request: %w", err)
+			return err
 		}
-
-		var response FollowingIDsResponse
-		if err := c.doRequest(req, &response); err != nil {
-			return nil, fmt.Errorf("sending request: %w", err)
+		if err := fn(response.IDs); err != nil {
+			return err
 		}
 
-		// Append the current page of IDs
-		allIDs = append(allIDs, response.IDs...)
-
 		// Check if we need to fetch more pages
 		if response.NextCursor == 0 {
-			break
+			return nil
 		}
 		nextCursor = response.NextCursorStr
 
 		// Add a small delay to avoid rate limiting
 		time.Sleep(time.Second)
 		
-		logger.Info("client.go.GetFollowingIDs - Fetching next page with cursor: %s", nextCursor)
+		logger.Debug("client.go.forEachIDPage - Fetching next page of %s with cursor: %s", path, nextCursor)
 	}
-    logger.Info("client.go.GetFollowingIDs - Fetched a total of %d IDs for user %s", len(allIDs), userID)
-	// Return all collected IDs in the response structure
-	return &FollowingIDsResponse{
-		IDs: allIDs,
-	}, nil
+}
+
+// GetFirstFollowingIDs fetches only the first page of up to count following
+// IDs, costing a single request
+func (c *Client) GetFirstFollowingIDs(userID string, count int) (*FollowingIDsResponse, error) {
+	return c.getIDsPage("following-ids", userID, "0", count)
+}
+
+// getIDsPage fetches one page of an ID list endpoint starting at cursor
+func (c *Client) getIDsPage(path, userID, cursor string, count int) (*FollowingIDsResponse, error) {
+	endpoint := fmt.Sprintf("https://%s/v2/user/%s", c.host(), path)
+
+	// Build query parameters
+	params := url.Values{}
+	params.Add("userId", userID)
+	params.Add("count", strconv.Itoa(count))
+	if cursor != "0" {
+		params.Add("cursor", cursor)
+	}
+
+	req, err := c.newRequest("GET", endpoint+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	c.pages.Add(1)
+	var response FollowingIDsResponse
+	if err := c.doRequest(req, &response); err != nil {
+		return nil, fmt.Errorf("sending request: %w", err)
+	}
+	// Suspended or deleted users come back as an error list without IDs
+	if err := response.apiErrorPayload.err(); err != nil && len(response.IDs) == 0 {
+		return nil, err
+	}
+	return &response, nil
 }
 
 func (c *Client) GetUserByID(userID string) (*UserByIDResponse, error) {
 	logger.Info("Looking up user by ID: %s", userID)
 	
 	url := fmt.Sprintf("https://%s/v2/user/by-id?userId=%s", 
-		c.config.RapidAPIHost, userID)
+		c.host(), userID)
 	
 	req, err := c.newRequest("GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("creating request: %w", err)
 	}
 
+	c.lookups.Add(1)
 	var response UserByIDResponse
 	if err := c.doRequest(req, &response); err != nil {
-		logger.Info("User lookup failed for ID %s: %v", userID, err)
+		logger.Warn("User lookup failed for ID %s: %v", userID, err)
 		return nil, err
 	}
+	c.schema.observeUserByID(&response)
 
 	logger.Info("User lookup completed for ID %s: @%s with %d followers", userID, response.Legacy.ScreenName, response.Legacy.FollowersCount)
 	return &response, nil
@@ -124,31 +266,27 @@ func (c *Client) newRequest(method, url string, body io.Reader) (*http.Request,
 		return nil, err
 	}
 
-	req.Header.Add("x-rapidapi-key", c.config.RapidAPIKey)
-	req.Header.Add("x-rapidapi-host", c.config.RapidAPIHost)
+	cfg, _ := c.settings()
+	req.Header.Add("x-rapidapi-key", cfg.RapidAPIKey)
+	req.Header.Add("x-rapidapi-host", cfg.RapidAPIHost)
 
-	logger.Info("Request headers: Host=%s", c.config.RapidAPIHost)
+	logger.Debug("Request headers: Host=%s", cfg.RapidAPIHost)
 
 	return req, nil
 }
 
+// doRequest sends req through the middleware chain and decodes the JSON
+// response into v
 func (c *Client) doRequest(req *http.Request, v interface{}) error {
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.roundTripper().RoundTrip(req)
 	if err != nil {
-		return fmt.Errorf("making request: %w", err)
+		return err
 	}
 	defer resp.Body.Close()
 
-	// Check rate limit header
-	if remaining := resp.Header.Get("x-ratelimit-requests-remaining"); remaining != "" {
-		if count, err := strconv.Atoi(remaining); err == nil {
-			atomic.StoreInt32(&c.remainingRequests, int32(count))
-		}
-	}
-
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API error: status=%d body=%s", resp.StatusCode, string(body))
+		return errorResponse(resp.StatusCode, body)
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
@@ -158,6 +296,36 @@ func (c *Client) doRequest(req *http.Request, v interface{}) error {
 	return nil
 }
 
+// withCurrentKey returns a copy of req carrying the current API key
+func (c *Client) withCurrentKey(req *http.Request) *http.Request {
+	cfg, _ := c.settings()
+	retry := req.Clone(req.Context())
+	retry.Header.Set("x-rapidapi-key", cfg.RapidAPIKey)
+	return retry
+}
+
+// Requests returns how many API requests were sent since startup
+func (c *Client) Requests() int64 {
+	return c.requests.Load()
+}
+
+// Usage counts the API calls made since startup by kind
+type Usage struct {
+	Pages   int64 // following and follower ID pages
+	Lookups int64 // user lookups and searches
+}
+
+// Sub returns the calls made between an earlier count and u
+func (u Usage) Sub(earlier Usage) Usage {
+	return Usage{Pages: u.Pages - earlier.Pages, Lookups: u.Lookups - earlier.Lookups}
+}
+
+// Usage returns the API calls made since startup by kind. Calls made by
+// the checks of one account are the difference before and after them.
+func (c *Client) Usage() Usage {
+	return Usage{Pages: c.pages.Load(), Lookups: c.lookups.Load()}
+}
+
 // Add getter for remaining requests
 func (c *Client) RemainingRequests() int {
 	return int(atomic.LoadInt32(&c.remainingRequests))
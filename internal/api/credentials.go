@@ -0,0 +1,129 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"x-tracker/config"
+	"x-tracker/internal/logger"
+)
+
+const (
+	// refreshInterval is the least time between two runs of the
+	// credentials command, so a key the provider keeps rejecting doesn't
+	// run it on every request
+	refreshInterval = time.Minute
+	// refreshTimeout bounds a run of the credentials command
+	refreshTimeout = 30 * time.Second
+)
+
+// keyRefresher serializes runs of the credentials command
+type keyRefresher struct {
+	mu      sync.Mutex
+	lastRun time.Time
+
+	// Guarded by Client.mu: the key the last refresh put in place and the
+	// configured RAPID_API_KEY it stands in for, both empty before one
+	refreshed  string
+	configured string
+}
+
+// refreshKey runs the credentials command after the provider rejected
+// the key and swaps in the key it prints. It reports whether a new key is
+// in place, which may also be the result of a concurrent refresh.
+func (c *Client) refreshKey(rejected string) bool {
+	cfg, _ := c.settings()
+	if cfg.CredentialsCommand == "" {
+		return false
+	}
+
+	c.refresher.mu.Lock()
+	defer c.refresher.mu.Unlock()
+
+	// Another request may have refreshed the key while this one waited
+	if current, _ := c.settings(); current.RapidAPIKey != rejected {
+		return true
+	}
+	if since := time.Since(c.refresher.lastRun); since < refreshInterval {
+		logger.Warn("API key rejected again %s after refreshing it, not running the credentials command", since.Round(time.Second))
+		return false
+	}
+	c.refresher.lastRun = time.Now()
+
+	logger.Info("API key rejected, running the credentials command")
+	key, err := runCredentialsCommand(cfg.CredentialsCommand)
+	if err != nil {
+		logger.Error("Credentials command failed: %v", err)
+		return false
+	}
+	if key == rejected {
+		logger.Warn("Credentials command returned the rejected API key")
+		return false
+	}
+
+	c.mu.Lock()
+	if c.config.RapidAPIKey != c.refresher.refreshed {
+		c.refresher.configured = c.config.RapidAPIKey
+	}
+	c.refresher.refreshed = key
+	updated := *c.config
+	updated.RapidAPIKey = key
+	c.config = &updated
+	c.mu.Unlock()
+
+	logger.Info("API key refreshed by the credentials command")
+	return true
+}
+
+// keepRefreshedKey returns cfg with the refreshed key in place of
+// RAPID_API_KEY if a refresh replaced the same key cfg configures, so a
+// reload doesn't bring back the rejected key. A reload configuring another
+// key drops the refreshed one. c.mu must be held.
+func (c *Client) keepRefreshedKey(cfg *config.Config) *config.Config {
+	if c.refresher.refreshed == "" {
+		return cfg
+	}
+	if cfg.RapidAPIKey != c.refresher.configured {
+		c.refresher.refreshed, c.refresher.configured = "", ""
+		return cfg
+	}
+	kept := *cfg
+	kept.RapidAPIKey = c.refresher.refreshed
+	return &kept
+}
+
+// runCredentialsCommand runs command through the shell and returns the
+// first non-empty line it prints
+func runCredentialsCommand(command string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), refreshTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		if key := strings.TrimSpace(line); key != "" {
+			return key, nil
+		}
+	}
+	return "", fmt.Errorf("no key in the output")
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Error codes the X API uses for accounts that can't be viewed
+const (
+	errCodePageNotFound = 34
+	errCodeUserNotFound = 50
+	errCodeSuspended    = 63
+)
+
+// APIError is returned when the API answers with an error status or an
+// error payload
+type APIError struct {
+	StatusCode int
+	Code       int    // X error code from the payload, 0 if none
+	Message    string // response body or error message
+}
+
+func (e *APIError) Error() string {
+	if e.Code != 0 {
+		return fmt.Sprintf("API error: status=%d code=%d message=%s", e.StatusCode, e.Code, e.Message)
+	}
+	return fmt.Sprintf("API error: status=%d body=%s", e.StatusCode, e.Message)
+}
+
+// Unavailable reports whether the error means the user itself can't be
+// viewed (suspended, deactivated or deleted) rather than a transient failure.
+// A 403 without one of X's error codes is about the key, not the user.
+func (e *APIError) Unavailable() bool {
+	switch e.Code {
+	case errCodePageNotFound, errCodeUserNotFound, errCodeSuspended:
+		return true
+	}
+	return e.StatusCode == http.StatusNotFound
+}
+
+// KeyRejected reports whether the provider refused the API key: RapidAPI
+// answers 401 for unknown keys and 403 for keys whose plan or subscription
+// doesn't cover the request
+func (e *APIError) KeyRejected() bool {
+	return e.Code == 0 && (e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden)
+}
+
+// Unwrap makes a rejected key match ErrInvalidKey
+func (e *APIError) Unwrap() error {
+	if e.KeyRejected() {
+		return ErrInvalidKey
+	}
+	return nil
+}
+
+// Suspended reports whether the error says the user is suspended
+func (e *APIError) Suspended() bool {
+	return e.Code == errCodeSuspended || strings.Contains(strings.ToLower(e.Message), "suspended")
+}
+
+// apiErrorPayload is the error list some endpoints return with a 200 status
+type apiErrorPayload struct {
+	Errors []struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// errorResponse converts an error status into an APIError, with the X
+// error code if the body carries one
+func errorResponse(status int, body []byte) *APIError {
+	apiErr := &APIError{StatusCode: status, Message: string(body)}
+	var payload apiErrorPayload
+	if json.Unmarshal(body, &payload) == nil && len(payload.Errors) > 0 {
+		apiErr.Code = payload.Errors[0].Code
+	}
+	return apiErr
+}
+
+// err converts the first payload error into an APIError, nil if there is none
+func (p apiErrorPayload) err() error {
+	if len(p.Errors) == 0 {
+		return nil
+	}
+	return &APIError{
+		StatusCode: http.StatusOK,
+		Code:       p.Errors[0].Code,
+		Message:    p.Errors[0].Message,
+	}
+}
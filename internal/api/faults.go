@@ -0,0 +1,116 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"x-tracker/config"
+	"x-tracker/internal/logger"
+)
+
+// faultTransport injects latency, failures and truncated ID pages into API
+// requests, so the tracker's guards against bad responses can be tried out
+// before trusting them with real data
+type faultTransport struct {
+	next         http.RoundTripper
+	latency      time.Duration
+	errorRate    float64
+	truncateRate float64
+}
+
+// newTransport returns the transport for API requests, going through the
+// configured proxy if any, recording responses to API_RECORD_DIR or
+// replaying them from API_REPLAY_DIR if set, and wrapped with fault
+// injection when any API_FAULT_* setting is on. Requests for a MockProvider
+// are answered by it beneath the fault injection, so its answers get the
+// same faults.
+func newTransport(cfg *config.Config) http.RoundTripper {
+	var base http.RoundTripper = http.DefaultTransport
+	if proxy := cfg.ProxyURL(); proxy != nil {
+		proxied := http.DefaultTransport.(*http.Transport).Clone()
+		proxied.Proxy = http.ProxyURL(proxy)
+		base = proxied
+	}
+	switch {
+	case cfg.APIReplayDir != "":
+		base = newReplayTransport(cfg.APIReplayDir)
+	case cfg.APIRecordDir != "":
+		base = newRecordTransport(base, cfg.APIRecordDir)
+	}
+	base = answerMocked(base)
+	if cfg.FaultLatency == 0 && cfg.FaultErrorRate == 0 && cfg.FaultTruncateRate == 0 {
+		return base
+	}
+	logger.Warn("API fault injection is on (latency %s, error rate %.2f, truncate rate %.2f)",
+		cfg.FaultLatency, cfg.FaultErrorRate, cfg.FaultTruncateRate)
+	return &faultTransport{
+		next:         base,
+		latency:      cfg.FaultLatency,
+		errorRate:    cfg.FaultErrorRate,
+		truncateRate: cfg.FaultTruncateRate,
+	}
+}
+
+func (f *faultTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if f.latency > 0 {
+		select {
+		case <-time.After(f.latency):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+
+	// Injected failures never reach the provider, so they cost no quota
+	if rand.Float64() < f.errorRate {
+		logger.Debug("Injecting API error for %s", req.URL.Path)
+		return &http.Response{
+			Status:     "503 Service Unavailable",
+			StatusCode: http.StatusServiceUnavailable,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader(`{"message":"injected fault"}`)),
+			Request:    req,
+		}, nil
+	}
+
+	resp, err := f.next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK || !strings.HasSuffix(req.URL.Path, "-ids") {
+		return resp, err
+	}
+	if rand.Float64() >= f.truncateRate {
+		return resp, nil
+	}
+	return truncatePage(resp)
+}
+
+// truncatePage drops the second half of an ID page and its next cursor, the
+// way a provider cutting a response short would
+func truncatePage(resp *http.Response) (*http.Response, error) {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var page map[string]json.RawMessage
+	var ids []string
+	if json.Unmarshal(body, &page) == nil && json.Unmarshal(page["ids"], &ids) == nil {
+		logger.Debug("Truncating ID page from %d to %d IDs", len(ids), len(ids)/2)
+		page["ids"], _ = json.Marshal(ids[:len(ids)/2])
+		page["next_cursor"] = json.RawMessage("0")
+		page["next_cursor_str"] = json.RawMessage(`"0"`)
+		if truncated, err := json.Marshal(page); err == nil {
+			body = truncated
+		}
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	resp.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	return resp, nil
+}
@@ -0,0 +1,172 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"x-tracker/config"
+	"x-tracker/internal/logger"
+)
+
+// Middleware wraps the round tripper API requests are sent through with one
+// layer of handling, such as rate limiting or logging
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc lets a function serve as an http.RoundTripper
+type RoundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Chain wraps base in middlewares, the first one being the outermost layer
+func Chain(base http.RoundTripper, middlewares ...Middleware) http.RoundTripper {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		base = middlewares[i](base)
+	}
+	return base
+}
+
+// Use adds layers around the ones every request goes through, the first
+// one being the outermost
+func (c *Client) Use(middlewares ...Middleware) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.middlewares = append(c.middlewares, middlewares...)
+	c.transport = c.chain(c.config, c.httpClient, c.limiter)
+}
+
+// chain builds the layers a request goes through, from the outside in: any
+// added with Use, the retry with a refreshed key, the circuit breaker, the
+// rate limiter, request counting and quota tracking, and logging before the
+// HTTP client sends it. Callers hold c.mu.
+func (c *Client) chain(cfg *config.Config, httpClient *http.Client, limiter *SharedLimiter) http.RoundTripper {
+	layers := append([]Middleware{}, c.middlewares...)
+	layers = append(layers,
+		c.retryRejectedKey,
+		c.tripBreaker(cfg),
+		rateLimit(limiter),
+		c.observeQuota,
+		logRequests,
+	)
+	return Chain(sendWith(httpClient), layers...)
+}
+
+// roundTripper returns the chain requests are sent through
+func (c *Client) roundTripper() http.RoundTripper {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.transport
+}
+
+// notSentError is a failure before the request reached the network, which
+// says nothing about the API's health
+type notSentError struct {
+	err error
+}
+
+func (e *notSentError) Error() string { return e.err.Error() }
+func (e *notSentError) Unwrap() error { return e.err }
+
+// sendWith sends requests with httpClient, so its timeout covers only the
+// request itself and not the time spent in the layers around it
+func sendWith(httpClient *http.Client) http.RoundTripper {
+	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("making request: %w", err)
+		}
+		return resp, nil
+	})
+}
+
+// retryRejectedKey sends a request once more with the key from the
+// credentials command when the provider rejects the one it carried
+func (c *Client) retryRejectedKey(next http.RoundTripper) http.RoundTripper {
+	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		resp, err := next.RoundTrip(req)
+		if err != nil || resp.StatusCode != http.StatusUnauthorized || !c.refreshKey(req.Header.Get("x-rapidapi-key")) {
+			return resp, err
+		}
+		resp.Body.Close()
+		return next.RoundTrip(c.withCurrentKey(req))
+	})
+}
+
+// tripBreaker keeps requests from being sent while the circuit breaker is
+// open, and feeds it whether the ones sent got an answer
+func (c *Client) tripBreaker(cfg *config.Config) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if err := c.breaker.allow(cfg, time.Now()); err != nil {
+				return nil, err
+			}
+			resp, err := next.RoundTrip(req)
+			var notSent *notSentError
+			switch {
+			case errors.As(err, &notSent):
+				c.breaker.abort()
+			case err != nil:
+				c.breaker.record(cfg, true, "no response", time.Now())
+			case resp.StatusCode >= http.StatusInternalServerError:
+				c.breaker.record(cfg, true, fmt.Sprintf("status %d", resp.StatusCode), time.Now())
+			default:
+				c.breaker.record(cfg, false, "", time.Now())
+			}
+			return resp, err
+		})
+	}
+}
+
+// rateLimit waits for the shared rate limiter before every request a
+// MockProvider doesn't answer, or does nothing when rate limiting is
+// disabled
+func rateLimit(limiter *SharedLimiter) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		if limiter == nil {
+			return next
+		}
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if mockedBy(req) != nil {
+				return next.RoundTrip(req)
+			}
+			if err := limiter.Wait(); err != nil {
+				return nil, &notSentError{fmt.Errorf("waiting for rate limit: %w", err)}
+			}
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+// observeQuota counts the requests sent and records the rate limit headers
+// of their responses
+func (c *Client) observeQuota(next http.RoundTripper) http.RoundTripper {
+	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		c.requests.Add(1)
+		resp, err := next.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		if quota, ok := c.quotas.observe(req, resp, time.Now()); ok {
+			atomic.StoreInt32(&c.remainingRequests, int32(quota.Remaining))
+		}
+		return resp, nil
+	})
+}
+
+// logRequests logs every request with its outcome and how long it took
+func logRequests(next http.RoundTripper) http.RoundTripper {
+	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		start := time.Now()
+		resp, err := next.RoundTrip(req)
+		if err != nil {
+			logger.Debug("%s %s failed after %s: %v", req.Method, req.URL.Path, time.Since(start).Round(time.Millisecond), err)
+			return nil, err
+		}
+		logger.Debug("%s %s: %d in %s", req.Method, req.URL.Path, resp.StatusCode, time.Since(start).Round(time.Millisecond))
+		return resp, nil
+	})
+}
@@ -0,0 +1,223 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MockUser is a user served by a MockProvider
+type MockUser struct {
+	ID        string
+	Username  string
+	Name      string
+	Followers int // reported follower count, unrelated to SetFollowers
+	Created   time.Time
+	Verified  bool
+	Suspended bool // lookups answer with the suspended error
+}
+
+// MockProvider answers API requests from users and following lists held
+// in memory, so the tracker, database and notifiers can be exercised
+// without the provider or its quota. Hand it to a client with
+// client.Use(provider.Middleware()); the lists can be changed between
+// checks to simulate follows and unfollows. The client's API_FAULT_*
+// settings apply to its answers as they would to the provider's.
+type MockProvider struct {
+	mu        sync.Mutex
+	users     map[string]MockUser // by ID
+	following map[string][]string // IDs followed, by user ID
+	followers map[string][]string // IDs following, by user ID
+	requests  int
+}
+
+func NewMockProvider() *MockProvider {
+	return &MockProvider{
+		users:     make(map[string]MockUser),
+		following: make(map[string][]string),
+		followers: make(map[string][]string),
+	}
+}
+
+// AddUser adds a user, or replaces the one with the same ID
+func (p *MockProvider) AddUser(user MockUser) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if user.Created.IsZero() {
+		user.Created = time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	p.users[user.ID] = user
+}
+
+// SetFollowing replaces the IDs a user follows
+func (p *MockProvider) SetFollowing(userID string, ids ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.following[userID] = append([]string(nil), ids...)
+}
+
+// SetFollowers replaces the IDs following a user
+func (p *MockProvider) SetFollowers(userID string, ids ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.followers[userID] = append([]string(nil), ids...)
+}
+
+// Requests returns how many requests the provider answered
+func (p *MockProvider) Requests() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.requests
+}
+
+// mockKey is the request context key of the MockProvider answering it
+type mockKey struct{}
+
+// Middleware returns a layer having every request answered by the
+// provider instead of the API. The request still passes the client's other
+// layers and fault injection; only the sending is replaced, and the rate
+// limiter is skipped as no quota is spent.
+func (p *MockProvider) Middleware() Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return next.RoundTrip(req.WithContext(context.WithValue(req.Context(), mockKey{}, p)))
+		})
+	}
+}
+
+// mockedBy returns the MockProvider a request is meant for, or nil
+func mockedBy(req *http.Request) *MockProvider {
+	p, _ := req.Context().Value(mockKey{}).(*MockProvider)
+	return p
+}
+
+// answerMocked hands requests meant for a MockProvider to it, and sends
+// the others with next
+func answerMocked(next http.RoundTripper) http.RoundTripper {
+	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if p := mockedBy(req); p != nil {
+			return p.RoundTrip(req)
+		}
+		return next.RoundTrip(req)
+	})
+}
+
+func (p *MockProvider) RoundTrip(req *http.Request) (*http.Response, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.requests++
+
+	query := req.URL.Query()
+	switch path.Base(req.URL.Path) {
+	case "by-username":
+		for _, user := range p.users {
+			if strings.EqualFold(user.Username, query.Get("username")) {
+				return p.userResponse(req, user)
+			}
+		}
+	case "by-id":
+		if user, ok := p.users[query.Get("userId")]; ok {
+			return p.userResponse(req, user)
+		}
+	case "following-ids":
+		return p.idsResponse(req, p.following[query.Get("userId")])
+	case "followers-ids":
+		return p.idsResponse(req, p.followers[query.Get("userId")])
+	case "search":
+		var users []mockUserJSON
+		for _, user := range p.users {
+			if strings.Contains(strings.ToLower(user.Username+" "+user.Name), strings.ToLower(query.Get("query"))) {
+				users = append(users, p.userJSON(user))
+			}
+		}
+		return mockResponse(req, http.StatusOK, map[string]interface{}{"users": users})
+	default:
+		return mockResponse(req, http.StatusNotFound, map[string]string{"message": "Endpoint not found"})
+	}
+	return mockResponse(req, http.StatusNotFound, map[string]string{"message": "User not found"})
+}
+
+// mockUserJSON is a user as the provider's lookup endpoints return it
+type mockUserJSON struct {
+	RestID string `json:"rest_id"`
+	Legacy struct {
+		CreatedAt      string `json:"created_at"`
+		Name           string `json:"name"`
+		ScreenName     string `json:"screen_name"`
+		FollowersCount int    `json:"followers_count"`
+		FriendsCount   int    `json:"friends_count"`
+		Verified       bool   `json:"verified"`
+	} `json:"legacy"`
+}
+
+func (p *MockProvider) userJSON(user MockUser) mockUserJSON {
+	var u mockUserJSON
+	u.RestID = user.ID
+	u.Legacy.CreatedAt = user.Created.Format(twitterTimeLayout)
+	u.Legacy.Name = user.Name
+	u.Legacy.ScreenName = user.Username
+	u.Legacy.FollowersCount = user.Followers
+	u.Legacy.FriendsCount = len(p.following[user.ID])
+	u.Legacy.Verified = user.Verified
+	return u
+}
+
+func (p *MockProvider) userResponse(req *http.Request, user MockUser) (*http.Response, error) {
+	if user.Suspended {
+		return mockResponse(req, http.StatusForbidden, map[string]interface{}{
+			"errors": []map[string]interface{}{{"code": errCodeSuspended, "message": "User has been suspended."}},
+		})
+	}
+	return mockResponse(req, http.StatusOK, p.userJSON(user))
+}
+
+// idsResponse returns the page of ids the request's cursor and count ask
+// for; cursors are offsets into the list
+func (p *MockProvider) idsResponse(req *http.Request, ids []string) (*http.Response, error) {
+	query := req.URL.Query()
+	start, _ := strconv.Atoi(query.Get("cursor"))
+	count, err := strconv.Atoi(query.Get("count"))
+	if err != nil || count <= 0 {
+		count = 5000
+	}
+	start = min(max(start, 0), len(ids))
+	end := min(start+count, len(ids))
+
+	next := 0
+	if end < len(ids) {
+		next = end
+	}
+	return mockResponse(req, http.StatusOK, map[string]interface{}{
+		"ids":             append([]string{}, ids[start:end]...),
+		"next_cursor":     next,
+		"next_cursor_str": strconv.Itoa(next),
+	})
+}
+
+func mockResponse(req *http.Request, status int, v interface{}) (*http.Response, error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("encoding mock response: %w", err)
+	}
+	header := make(http.Header)
+	header.Set("Content-Type", "application/json")
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// ErrInvalidKey is returned by Probe when the provider rejects the API key
+var ErrInvalidKey = errors.New("API key rejected")
+
+// probeUsername is looked up by Probe; the account always exists
+const probeUsername = "X"
+
+// ProbeResult is what a probe request learned about the API key and plan
+type ProbeResult struct {
+	Latency        time.Duration
+	QuotaLimit     int           // requests per billing period on the plan, -1 if not reported
+	QuotaRemaining int           // -1 if not reported
+	QuotaReset     time.Duration // until the quota resets, 0 if not reported
+	Exhausted      bool          // the key is valid but the quota is used up
+}
+
+// Probe makes one cheap authenticated request to check that the API key
+// works and to read the plan's quota from the response headers. A rejected
+// key returns an error wrapping ErrInvalidKey, unless the credentials
+// command supplies one that works.
+func (c *Client) Probe() (*ProbeResult, error) {
+	cfg, httpClient := c.settings()
+	if cfg.RapidAPIKey == "" {
+		return nil, fmt.Errorf("%w: RAPID_API_KEY is not set", ErrInvalidKey)
+	}
+
+	req, err := c.newRequest("GET", fmt.Sprintf("https://%s/v2/user/by-username?username=%s", c.host(), probeUsername), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	start := time.Now()
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("making request: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	result := &ProbeResult{
+		Latency:        time.Since(start),
+		QuotaLimit:     headerInt(resp.Header, "x-ratelimit-requests-limit"),
+		QuotaRemaining: headerInt(resp.Header, "x-ratelimit-requests-remaining"),
+	}
+	if reset := headerInt(resp.Header, "x-ratelimit-requests-reset"); reset > 0 {
+		result.QuotaReset = time.Duration(reset) * time.Second
+	}
+	if result.QuotaRemaining >= 0 {
+		atomic.StoreInt32(&c.remainingRequests, int32(result.QuotaRemaining))
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return result, nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		if resp.StatusCode == http.StatusUnauthorized && c.refreshKey(cfg.RapidAPIKey) {
+			return c.Probe()
+		}
+		// RapidAPI answers 403 for keys that aren't subscribed to the API
+		return nil, fmt.Errorf("%w (status %d): %s", ErrInvalidKey, resp.StatusCode, strings.TrimSpace(string(body)))
+	case http.StatusTooManyRequests:
+		result.Exhausted = true
+		return result, nil
+	}
+	return nil, &APIError{StatusCode: resp.StatusCode, Message: string(body)}
+}
+
+// headerInt parses a numeric header, -1 if it is missing or malformed
+func headerInt(header http.Header, key string) int {
+	n, err := strconv.Atoi(header.Get(key))
+	if err != nil {
+		return -1
+	}
+	return n
+}
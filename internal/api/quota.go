@@ -0,0 +1,100 @@
+package api
+
+import (
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// EndpointQuota is the rate limit state of one API endpoint as of its last
+// response
+type EndpointQuota struct {
+	Endpoint  string    // last path element, e.g. following-ids
+	Limit     int       // requests allowed per period, 0 if not reported
+	Remaining int       // requests left in the period
+	Reset     time.Time // when the period ends, zero if not reported
+	Updated   time.Time // when the response carrying these was received
+}
+
+// Exhausted reports whether the endpoint has no requests left until its
+// reset
+func (q EndpointQuota) Exhausted(now time.Time) bool {
+	return q.Remaining <= 0 && (q.Reset.IsZero() || q.Reset.After(now))
+}
+
+// quotaTracker keeps the rate limit headers of the latest response of every
+// endpoint
+type quotaTracker struct {
+	mu        sync.Mutex
+	endpoints map[string]EndpointQuota
+}
+
+// observe records the rate limit headers of a response to req, if any
+func (t *quotaTracker) observe(req *http.Request, resp *http.Response, now time.Time) (EndpointQuota, bool) {
+	remaining, err := strconv.Atoi(resp.Header.Get("x-ratelimit-requests-remaining"))
+	if err != nil {
+		return EndpointQuota{}, false
+	}
+	quota := EndpointQuota{
+		Endpoint:  path.Base(req.URL.Path),
+		Remaining: remaining,
+		Updated:   now,
+	}
+	quota.Limit, _ = strconv.Atoi(resp.Header.Get("x-ratelimit-requests-limit"))
+	if reset, err := strconv.ParseInt(resp.Header.Get("x-ratelimit-requests-reset"), 10, 64); err == nil {
+		quota.Reset = resetTime(reset, now)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.endpoints == nil {
+		t.endpoints = make(map[string]EndpointQuota)
+	}
+	t.endpoints[quota.Endpoint] = quota
+	return quota, true
+}
+
+// resetTime reads a reset header, sent as seconds until the reset by
+// RapidAPI and as a Unix time by some providers
+func resetTime(value int64, now time.Time) time.Time {
+	if value > 1_000_000_000 {
+		return time.Unix(value, 0)
+	}
+	return now.Add(time.Duration(value) * time.Second)
+}
+
+// RateLimitStatus returns the rate limit state of every endpoint called
+// since startup, sorted by endpoint
+func (c *Client) RateLimitStatus() []EndpointQuota {
+	c.quotas.mu.Lock()
+	defer c.quotas.mu.Unlock()
+	status := make([]EndpointQuota, 0, len(c.quotas.endpoints))
+	for _, quota := range c.quotas.endpoints {
+		status = append(status, quota)
+	}
+	sort.Slice(status, func(i, j int) bool { return status[i].Endpoint < status[j].Endpoint })
+	return status
+}
+
+// NextReset returns the endpoint whose quota resets soonest, preferring the
+// exhausted ones since checks resume when they reset, and false if no
+// upcoming reset is known
+func NextReset(status []EndpointQuota, now time.Time) (EndpointQuota, bool) {
+	var next EndpointQuota
+	found, exhausted := false, false
+	for _, quota := range status {
+		if quota.Reset.IsZero() || !quota.Reset.After(now) {
+			continue
+		}
+		isExhausted := quota.Exhausted(now)
+		switch {
+		case !found, isExhausted && !exhausted,
+			isExhausted == exhausted && quota.Reset.Before(next.Reset):
+			next, found, exhausted = quota, true, isExhausted
+		}
+	}
+	return next, found
+}
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"x-tracker/internal/lockfile"
+	"x-tracker/internal/logger"
+)
+
+// SharedLimiter is a token bucket kept in a file, so every process using the
+// same API key (the TUI, CLI commands, other profiles) draws from one budget.
+// The file is locked while the bucket is updated.
+type SharedLimiter struct {
+	path      string
+	perMinute int
+}
+
+// bucketState is the on-disk form of the token bucket
+type bucketState struct {
+	Tokens  float64   `json:"tokens"`
+	Updated time.Time `json:"updated"`
+}
+
+func NewSharedLimiter(path string, perMinute int) *SharedLimiter {
+	return &SharedLimiter{
+		path:      path,
+		perMinute: perMinute,
+	}
+}
+
+// refill adds the tokens due since the last update, up to capacity
+func (b *bucketState) refill(now time.Time, capacity float64) {
+	elapsed := now.Sub(b.Updated)
+	if elapsed > 0 {
+		b.Tokens += elapsed.Minutes() * capacity
+	}
+	if b.Tokens > capacity {
+		b.Tokens = capacity
+	}
+	b.Updated = now
+}
+
+// Available returns how many request tokens the bucket holds right now,
+// without taking one. A missing file means a full bucket.
+func (l *SharedLimiter) Available() (float64, error) {
+	capacity := float64(l.perMinute)
+	file, err := os.Open(l.path)
+	if os.IsNotExist(err) {
+		return capacity, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("opening rate limit file: %w", err)
+	}
+	defer file.Close()
+
+	// The bucket is rewritten in place, so read it under the lock too
+	if err := lockfile.Lock(file); err != nil {
+		return 0, fmt.Errorf("locking rate limit file: %w", err)
+	}
+	defer lockfile.Unlock(file)
+
+	var state bucketState
+	if data, err := io.ReadAll(file); err != nil || json.Unmarshal(data, &state) != nil {
+		return capacity, nil
+	}
+	state.refill(time.Now(), capacity)
+	return state.Tokens, nil
+}
+
+// Capacity returns the bucket size, the configured requests per minute
+func (l *SharedLimiter) Capacity() int {
+	return l.perMinute
+}
+
+// Wait blocks until a request token is available
+func (l *SharedLimiter) Wait() error {
+	for {
+		wait, err := l.take()
+		if err != nil {
+			return err
+		}
+		if wait == 0 {
+			return nil
+		}
+		logger.Info("Rate limit reached, waiting %s for a request token", wait.Round(time.Millisecond))
+		time.Sleep(wait)
+	}
+}
+
+// take removes a token if one is available, otherwise it returns how long
+// until the next token is due
+func (l *SharedLimiter) take() (time.Duration, error) {
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return 0, fmt.Errorf("creating rate limit directory: %w", err)
+	}
+
+	file, err := os.OpenFile(l.path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return 0, fmt.Errorf("opening rate limit file: %w", err)
+	}
+	defer file.Close()
+
+	if err := lockfile.Lock(file); err != nil {
+		return 0, fmt.Errorf("locking rate limit file: %w", err)
+	}
+	defer lockfile.Unlock(file)
+
+	capacity := float64(l.perMinute)
+	now := time.Now()
+
+	// A missing or unreadable state starts with a full bucket
+	state := bucketState{Tokens: capacity, Updated: now}
+	if data, err := io.ReadAll(file); err == nil && len(data) > 0 {
+		if err := json.Unmarshal(data, &state); err != nil {
+			state = bucketState{Tokens: capacity, Updated: now}
+		}
+	}
+
+	state.refill(now, capacity)
+
+	var wait time.Duration
+	if state.Tokens >= 1 {
+		state.Tokens--
+	} else {
+		wait = time.Duration((1 - state.Tokens) / capacity * float64(time.Minute))
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return 0, err
+	}
+	if err := file.Truncate(0); err != nil {
+		return 0, fmt.Errorf("writing rate limit file: %w", err)
+	}
+	if _, err := file.WriteAt(data, 0); err != nil {
+		return 0, fmt.Errorf("writing rate limit file: %w", err)
+	}
+	return wait, nil
+}
@@ -0,0 +1,180 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"x-tracker/internal/logger"
+)
+
+// ErrNotRecorded is returned in replay mode for requests no response was
+// recorded for
+var ErrNotRecorded = errors.New("no recorded response")
+
+// recording is a response as stored on disk, without the request headers
+// carrying the API key
+type recording struct {
+	Method string          `json:"method"`
+	URL    string          `json:"url"` // path and query, without the host
+	Status int             `json:"status"`
+	Header http.Header     `json:"header"`
+	Body   json.RawMessage `json:"body,omitempty"` // the body if it is JSON
+	Text   string          `json:"text,omitempty"` // the body otherwise
+}
+
+// recordingKey names the recordings of a request after its endpoint and a
+// hash of its method, path and query, leaving out the host so recordings
+// can be replayed against another one
+func recordingKey(req *http.Request) string {
+	sum := sha256.Sum256([]byte(req.Method + " " + req.URL.Path + "?" + req.URL.Query().Encode()))
+	return path.Base(req.URL.Path) + "-" + hex.EncodeToString(sum[:8])
+}
+
+// recordingFile returns the path of the nth recording of a request, from 1
+func recordingFile(dir, key string, n int) string {
+	return filepath.Join(dir, key+"-"+strconv.Itoa(n)+".json")
+}
+
+// recordTransport saves every response to dir while passing it on, one
+// file per response. Repeated requests, like the following list of an
+// account on every check, are numbered in order.
+type recordTransport struct {
+	next  http.RoundTripper
+	dir   string
+	mu    sync.Mutex
+	count map[string]int // responses recorded per request key
+}
+
+func newRecordTransport(next http.RoundTripper, dir string) *recordTransport {
+	logger.Warn("Recording API responses to %s", dir)
+	return &recordTransport{next: next, dir: dir, count: make(map[string]int)}
+}
+
+func (r *recordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	if err := r.save(req, resp, body); err != nil {
+		logger.Warn("Failed to record API response for %s: %v", req.URL.Path, err)
+	}
+	return resp, nil
+}
+
+// save writes a response to the next free file of its request
+func (r *recordTransport) save(req *http.Request, resp *http.Response, body []byte) error {
+	rec := recording{
+		Method: req.Method,
+		URL:    req.URL.RequestURI(),
+		Status: resp.StatusCode,
+		Header: resp.Header.Clone(),
+	}
+	rec.Header.Del("Set-Cookie")
+	if json.Valid(body) {
+		rec.Body = body
+	} else {
+		rec.Text = string(body)
+	}
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := os.MkdirAll(r.dir, 0o755); err != nil {
+		return err
+	}
+	key := recordingKey(req)
+	n := r.count[key] + 1
+	// Carry on after the recordings of earlier runs
+	for {
+		if _, err := os.Stat(recordingFile(r.dir, key, n)); errors.Is(err, os.ErrNotExist) {
+			break
+		}
+		n++
+	}
+	if err := os.WriteFile(recordingFile(r.dir, key, n), data, 0o644); err != nil {
+		return err
+	}
+	r.count[key] = n
+	return nil
+}
+
+// replayTransport answers requests from the responses recorded in dir
+// instead of sending them. Repeated requests get the recorded responses in
+// order, then the last one again.
+type replayTransport struct {
+	dir    string
+	mu     sync.Mutex
+	served map[string]int // recordings served per request key
+}
+
+func newReplayTransport(dir string) *replayTransport {
+	logger.Warn("Replaying API responses from %s, no requests reach the provider", dir)
+	return &replayTransport{dir: dir, served: make(map[string]int)}
+}
+
+func (r *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := recordingKey(req)
+
+	r.mu.Lock()
+	n := r.served[key] + 1
+	data, err := os.ReadFile(recordingFile(r.dir, key, n))
+	if errors.Is(err, os.ErrNotExist) && n > 1 {
+		n--
+		data, err = os.ReadFile(recordingFile(r.dir, key, n))
+	}
+	if err == nil {
+		r.served[key] = n
+	}
+	r.mu.Unlock()
+
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w for %s %s", ErrNotRecorded, req.Method, req.URL.RequestURI())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var rec recording
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("reading recording %s: %w", recordingFile(r.dir, key, n), err)
+	}
+	body := []byte(rec.Body)
+	if rec.Body == nil {
+		body = []byte(rec.Text)
+	}
+	if rec.Header == nil {
+		rec.Header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rec.Status, http.StatusText(rec.Status)),
+		StatusCode:    rec.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        rec.Header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
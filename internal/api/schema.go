@@ -0,0 +1,83 @@
+package api
+
+import (
+	"sort"
+	"sync"
+)
+
+// minSchemaSamples is how many decoded users a field needs before a run of
+// zero values is considered suspicious rather than coincidence
+const minSchemaSamples = 5
+
+// schemaStats counts decoded user objects and how often each key field came
+// back empty. A field that is empty in every response usually means the
+// provider renamed or moved it.
+type schemaStats struct {
+	mu    sync.Mutex
+	users int
+	zero  map[string]int
+}
+
+// observe records which key fields of a decoded user are empty
+func (s *schemaStats) observe(fields map[string]bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.zero == nil {
+		s.zero = make(map[string]int)
+	}
+	s.users++
+	for name, empty := range fields {
+		if empty {
+			s.zero[name]++
+		}
+	}
+}
+
+func (s *schemaStats) observeUser(u *UserResponse) {
+	s.observe(map[string]bool{
+		"rest_id":         u.RestID == "",
+		"screen_name":     u.Legacy.ScreenName == "",
+		"created_at":      u.Legacy.CreatedAt == "",
+		"followers_count": u.Legacy.FollowersCount == 0,
+		"friends_count":   u.Legacy.FriendsCount == 0,
+	})
+}
+
+func (s *schemaStats) observeUserByID(u *UserByIDResponse) {
+	s.observe(map[string]bool{
+		"rest_id":         u.RestID == "",
+		"screen_name":     u.Legacy.ScreenName == "",
+		"created_at":      u.Legacy.CreatedAt == "",
+		"followers_count": u.Legacy.FollowersCount == 0,
+		"friends_count":   u.Legacy.FriendsCount == 0,
+	})
+}
+
+// ResetSchemaStats starts a new observation window, typically a check cycle
+func (c *Client) ResetSchemaStats() {
+	c.schema.mu.Lock()
+	defer c.schema.mu.Unlock()
+	c.schema.users = 0
+	c.schema.zero = nil
+}
+
+// SuspiciousFields returns the user fields that were empty in every user
+// decoded since the last reset, sorted by name. It returns nil until enough
+// users have been seen to tell.
+func (c *Client) SuspiciousFields() []string {
+	c.schema.mu.Lock()
+	defer c.schema.mu.Unlock()
+
+	if c.schema.users < minSchemaSamples {
+		return nil
+	}
+	var fields []string
+	for name, count := range c.schema.zero {
+		if count == c.schema.users {
+			fields = append(fields, name)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
@@ -1,5 +1,7 @@
 package api
 
+import "time"
+
 // User by Name Response 
 type UserResponse struct {
 	RestID string `json:"rest_id"`
@@ -16,6 +18,11 @@ type UserResponse struct {
 	IsBlueVerified bool `json:"is_blue_verified"`
 }
 
+// SearchUsersResponse represents the API response for a user search
+type SearchUsersResponse struct {
+	Users []UserResponse `json:"users"`
+}
+
 // FollowingIDsResponse represents the API response for following IDs
 type FollowingIDsResponse struct {
 	IDs                []string `json:"ids"`
@@ -24,14 +31,45 @@ type FollowingIDsResponse struct {
 	PreviousCursor     int64    `json:"previous_cursor"`
 	PreviousCursorStr  string   `json:"previous_cursor_str"`
 	TotalCount         *int     `json:"total_count"`
+
+	apiErrorPayload
 }
 
 // UserByIDResponse represents the API response for user lookup by ID
 type UserByIDResponse struct {
 	RestID string `json:"rest_id"`
 	Legacy struct {
+		CreatedAt           string `json:"created_at"`
 		ScreenName string `json:"screen_name"`
 		Name       string `json:"name"`
+		Description         string `json:"description"`
 		FollowersCount     int    `json:"followers_count"`
+		FriendsCount        int    `json:"friends_count"`
+		StatusesCount       int    `json:"statuses_count"`
+		DefaultProfileImage bool   `json:"default_profile_image"`
+		ProfileImageURLHTTPS string `json:"profile_image_url_https"`
+		Verified            bool   `json:"verified"`
+		VerifiedType        string `json:"verified_type"` // "Business" or "Government" for verified organizations
 	} `json:"legacy"`
+	IsBlueVerified bool `json:"is_blue_verified"`
+}
+
+// IsVerified reports whether the user has any kind of verification
+func (u *UserByIDResponse) IsVerified() bool {
+	return u.Legacy.Verified || u.IsBlueVerified || u.Legacy.VerifiedType != ""
+}
+
+// twitterTimeLayout is the format used by the legacy created_at fields
+const twitterTimeLayout = time.RubyDate
+
+// CreatedTime parses the account creation date, returning false if it is missing or malformed
+func (u *UserByIDResponse) CreatedTime() (time.Time, bool) {
+	if u.Legacy.CreatedAt == "" {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(twitterTimeLayout, u.Legacy.CreatedAt)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
 } 
\ No newline at end of file
@@ -0,0 +1,269 @@
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"x-tracker/config"
+	"x-tracker/internal/db"
+	"x-tracker/internal/logger"
+)
+
+// Snapshot names sort by the time they were taken
+const (
+	namePrefix = "x-tracker-"
+	nameSuffix = ".db.gz"
+	nameLayout = "20060102-150405"
+)
+
+// snapshotAge is how old the newest snapshot may get before the next one
+// is taken
+const snapshotAge = 24 * time.Hour
+
+// checkInterval is how often the scheduler looks for a due snapshot. A
+// laptop that slept through the night catches up within this interval.
+const checkInterval = time.Hour
+
+// Store is a remote destination for snapshots
+type Store interface {
+	// Put uploads size bytes from r as name
+	Put(name string, r io.ReadSeeker, size int64) error
+	// List returns the names of the stored objects
+	List() ([]string, error)
+	// Get downloads the object called name
+	Get(name string) ([]byte, error)
+	Delete(name string) error
+}
+
+// Open returns the store for a backup URL: s3://bucket/prefix,
+// gs://bucket/prefix, or webdav(s)://host/path
+func Open(cfg *config.Config) (Store, error) {
+	u, err := url.Parse(cfg.BackupURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backup URL: %w", err)
+	}
+	prefix := strings.Trim(u.Path, "/")
+
+	switch u.Scheme {
+	case "s3":
+		endpoint := cfg.BackupEndpoint
+		if endpoint == "" {
+			endpoint = "https://s3." + cfg.BackupRegion + ".amazonaws.com"
+		}
+		return newS3Store(endpoint, cfg.BackupRegion, u.Host, prefix, cfg.BackupAccessKey, cfg.BackupSecretKey), nil
+	case "gs":
+		// Cloud Storage speaks the S3 protocol with HMAC keys
+		return newS3Store("https://storage.googleapis.com", "auto", u.Host, prefix, cfg.BackupAccessKey, cfg.BackupSecretKey), nil
+	case "webdav", "webdavs":
+		scheme := "http"
+		if u.Scheme == "webdavs" {
+			scheme = "https"
+		}
+		base := scheme + "://" + u.Host + "/" + prefix
+		return newWebDAVStore(base, cfg.BackupAccessKey, cfg.BackupSecretKey), nil
+	}
+	return nil, fmt.Errorf("unsupported backup URL scheme %q", u.Scheme)
+}
+
+// Uploader snapshots the database to a store and prunes old snapshots
+type Uploader struct {
+	store Store
+	db    db.Store
+	keep  int // snapshots kept, 0 keeps all
+}
+
+func NewUploader(store Store, database db.Store, keep int) *Uploader {
+	return &Uploader{store: store, db: database, keep: keep}
+}
+
+// Snapshot describes a stored snapshot
+type Snapshot struct {
+	Name    string
+	TakenAt time.Time
+}
+
+// Snapshots returns the stored snapshots, oldest first. Other objects at
+// the destination are ignored.
+func (u *Uploader) Snapshots() ([]Snapshot, error) {
+	names, err := u.store.List()
+	if err != nil {
+		return nil, fmt.Errorf("listing snapshots: %w", err)
+	}
+
+	var snapshots []Snapshot
+	for _, name := range names {
+		stamp, ok := strings.CutPrefix(name, namePrefix)
+		if !ok {
+			continue
+		}
+		stamp, ok = strings.CutSuffix(stamp, nameSuffix)
+		if !ok {
+			continue
+		}
+		takenAt, err := time.Parse(nameLayout, stamp)
+		if err != nil {
+			continue
+		}
+		snapshots = append(snapshots, Snapshot{Name: name, TakenAt: takenAt})
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].TakenAt.Before(snapshots[j].TakenAt)
+	})
+	return snapshots, nil
+}
+
+// Upload takes a snapshot of the database, uploads it and deletes the
+// oldest snapshots beyond the retention
+func (u *Uploader) Upload() (Snapshot, error) {
+	snapshot := Snapshot{TakenAt: time.Now().UTC().Truncate(time.Second)}
+	snapshot.Name = namePrefix + snapshot.TakenAt.Format(nameLayout) + nameSuffix
+
+	dir, err := os.MkdirTemp("", "x-tracker-backup")
+	if err != nil {
+		return snapshot, err
+	}
+	defer os.RemoveAll(dir)
+
+	copyPath := filepath.Join(dir, "data.db")
+	if err := u.db.Backup(copyPath); err != nil {
+		return snapshot, fmt.Errorf("copying database: %w", err)
+	}
+	archivePath := filepath.Join(dir, snapshot.Name)
+	if err := compress(copyPath, archivePath); err != nil {
+		return snapshot, fmt.Errorf("compressing snapshot: %w", err)
+	}
+
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return snapshot, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return snapshot, err
+	}
+	if err := u.store.Put(snapshot.Name, f, info.Size()); err != nil {
+		return snapshot, fmt.Errorf("uploading %s: %w", snapshot.Name, err)
+	}
+	logger.Info("Uploaded backup %s (%d bytes)", snapshot.Name, info.Size())
+
+	return snapshot, u.prune()
+}
+
+// Download fetches a stored snapshot and writes it, decompressed, to path
+func (u *Uploader) Download(snapshot Snapshot, path string) error {
+	data, err := u.store.Get(snapshot.Name)
+	if err != nil {
+		return fmt.Errorf("downloading %s: %w", snapshot.Name, err)
+	}
+	if err := decompress(data, path); err != nil {
+		return fmt.Errorf("decompressing %s: %w", snapshot.Name, err)
+	}
+	logger.Info("Downloaded backup %s to %s", snapshot.Name, path)
+	return nil
+}
+
+// prune deletes the oldest snapshots until keep are left
+func (u *Uploader) prune() error {
+	if u.keep == 0 {
+		return nil
+	}
+	snapshots, err := u.Snapshots()
+	if err != nil {
+		return err
+	}
+	for len(snapshots) > u.keep {
+		if err := u.store.Delete(snapshots[0].Name); err != nil {
+			return fmt.Errorf("deleting old backup %s: %w", snapshots[0].Name, err)
+		}
+		logger.Info("Deleted old backup %s", snapshots[0].Name)
+		snapshots = snapshots[1:]
+	}
+	return nil
+}
+
+// Run uploads a snapshot whenever the newest stored one is a day old,
+// checking at startup and then every hour until stop is closed
+func (u *Uploader) Run(stop <-chan struct{}) {
+	logger.Info("Starting daily backups, keeping %d snapshots", u.keep)
+
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := u.uploadIfDue(); err != nil {
+			logger.Warn("Backup failed: %v", err)
+		}
+
+		select {
+		case <-stop:
+			logger.Info("Backups stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (u *Uploader) uploadIfDue() error {
+	snapshots, err := u.Snapshots()
+	if err != nil {
+		return err
+	}
+	if n := len(snapshots); n > 0 && time.Since(snapshots[n-1].TakenAt) < snapshotAge {
+		return nil
+	}
+	_, err = u.Upload()
+	return err
+}
+
+// compress gzips the file at src into dst
+func compress(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(out)
+	if _, err := io.Copy(zw, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// decompress gunzips data into a new file at dst
+func decompress(data []byte, dst string) error {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, zr); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,234 @@
+package backup
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// s3Store keeps snapshots in an S3 bucket, or any service speaking the
+// S3 protocol, signing requests with AWS Signature Version 4
+type s3Store struct {
+	endpoint   string // scheme and host; buckets are addressed by path
+	region     string
+	bucket     string
+	prefix     string
+	accessKey  string
+	secretKey  string
+	httpClient *http.Client
+}
+
+func newS3Store(endpoint, region, bucket, prefix, accessKey, secretKey string) *s3Store {
+	return &s3Store{
+		endpoint:  strings.TrimRight(endpoint, "/"),
+		region:    region,
+		bucket:    bucket,
+		prefix:    prefix,
+		accessKey: accessKey,
+		secretKey: secretKey,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Minute,
+		},
+	}
+}
+
+// key returns the object key for a snapshot name
+func (s *s3Store) key(name string) string {
+	if s.prefix == "" {
+		return name
+	}
+	return s.prefix + "/" + name
+}
+
+func (s *s3Store) Put(name string, r io.ReadSeeker, size int64) error {
+	hash := sha256.New()
+	if _, err := io.Copy(hash, r); err != nil {
+		return err
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPut, s.endpoint+s.path(s.key(name)), io.NopCloser(r))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/gzip")
+	_, err = s.do(req, hex.EncodeToString(hash.Sum(nil)))
+	return err
+}
+
+// listResult is the part of a ListObjects response we read
+type listResult struct {
+	IsTruncated bool `xml:"IsTruncated"`
+	Contents    []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+}
+
+func (s *s3Store) List() ([]string, error) {
+	prefix := s.key("")
+
+	var names []string
+	marker := ""
+	for {
+		query := url.Values{"prefix": {prefix}}
+		if marker != "" {
+			query.Set("marker", marker)
+		}
+		req, err := http.NewRequest(http.MethodGet, s.endpoint+s.path("")+"?"+canonicalQuery(query), nil)
+		if err != nil {
+			return nil, err
+		}
+		body, err := s.do(req, emptyHash)
+		if err != nil {
+			return nil, err
+		}
+
+		var result listResult
+		if err := xml.Unmarshal(body, &result); err != nil {
+			return nil, fmt.Errorf("parsing object list: %w", err)
+		}
+		for _, object := range result.Contents {
+			if name := strings.TrimPrefix(object.Key, prefix); !strings.Contains(name, "/") {
+				names = append(names, name)
+			}
+		}
+		if !result.IsTruncated || len(result.Contents) == 0 {
+			return names, nil
+		}
+		marker = result.Contents[len(result.Contents)-1].Key
+	}
+}
+
+func (s *s3Store) Get(name string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, s.endpoint+s.path(s.key(name)), nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.do(req, emptyHash)
+}
+
+func (s *s3Store) Delete(name string) error {
+	req, err := http.NewRequest(http.MethodDelete, s.endpoint+s.path(s.key(name)), nil)
+	if err != nil {
+		return err
+	}
+	_, err = s.do(req, emptyHash)
+	return err
+}
+
+// path returns the escaped request path for a key in the bucket
+func (s *s3Store) path(key string) string {
+	if key == "" {
+		return "/" + awsEscape(s.bucket)
+	}
+	segments := strings.Split(s.bucket+"/"+key, "/")
+	for i, segment := range segments {
+		segments[i] = awsEscape(segment)
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+// emptyHash is the SHA-256 of an empty payload
+const emptyHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// do signs and sends a request, returning the response body
+func (s *s3Store) do(req *http.Request, payloadHash string) ([]byte, error) {
+	s.sign(req, payloadHash, time.Now().UTC())
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("storage error: status=%d, body=%s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+// sign adds a Signature Version 4 authorization header to req
+func (s *s3Store) sign(req *http.Request, payloadHash string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		"host:" + req.URL.Host,
+		"x-amz-content-sha256:" + payloadHash,
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), date)
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// canonicalQuery encodes query parameters sorted by name, escaped the way
+// the signature expects
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, key := range keys {
+		for _, value := range query[key] {
+			pairs = append(pairs, awsEscape(key)+"="+awsEscape(value))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// awsEscape percent-encodes everything but the unreserved characters
+func awsEscape(s string) string {
+	var b strings.Builder
+	for _, c := range []byte(s) {
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || strings.IndexByte("-_.~", c) >= 0 {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,122 @@
+package backup
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// webdavStore keeps snapshots in a WebDAV collection, such as a Nextcloud
+// folder. The collection must exist.
+type webdavStore struct {
+	base       string // collection URL without a trailing slash
+	username   string
+	password   string
+	httpClient *http.Client
+}
+
+func newWebDAVStore(base, username, password string) *webdavStore {
+	return &webdavStore{
+		base:     strings.TrimRight(base, "/"),
+		username: username,
+		password: password,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Minute,
+		},
+	}
+}
+
+func (w *webdavStore) Put(name string, r io.ReadSeeker, size int64) error {
+	req, err := http.NewRequest(http.MethodPut, w.base+"/"+url.PathEscape(name), io.NopCloser(r))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/gzip")
+	_, err = w.do(req)
+	return err
+}
+
+// multistatus is the part of a PROPFIND response we read
+type multistatus struct {
+	Responses []struct {
+		Href string `xml:"href"`
+	} `xml:"response"`
+}
+
+func (w *webdavStore) List() ([]string, error) {
+	req, err := http.NewRequest("PROPFIND", w.base+"/", strings.NewReader(
+		`<?xml version="1.0"?><propfind xmlns="DAV:"><prop><resourcetype/></prop></propfind>`))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Depth", "1")
+	req.Header.Set("Content-Type", "application/xml")
+	body, err := w.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var result multistatus
+	if err := xml.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("parsing collection listing: %w", err)
+	}
+
+	var names []string
+	for _, response := range result.Responses {
+		// The collection itself is listed too, with a trailing slash
+		if strings.HasSuffix(response.Href, "/") {
+			continue
+		}
+		href, err := url.PathUnescape(response.Href)
+		if err != nil {
+			continue
+		}
+		names = append(names, path.Base(href))
+	}
+	return names, nil
+}
+
+func (w *webdavStore) Get(name string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, w.base+"/"+url.PathEscape(name), nil)
+	if err != nil {
+		return nil, err
+	}
+	return w.do(req)
+}
+
+func (w *webdavStore) Delete(name string) error {
+	req, err := http.NewRequest(http.MethodDelete, w.base+"/"+url.PathEscape(name), nil)
+	if err != nil {
+		return err
+	}
+	_, err = w.do(req)
+	return err
+}
+
+// do sends a request with the credentials, returning the response body
+func (w *webdavStore) do(req *http.Request) ([]byte, error) {
+	if w.username != "" {
+		req.SetBasicAuth(w.username, w.password)
+	}
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("WebDAV error: status=%d, body=%s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
@@ -0,0 +1,331 @@
+// Package bluesky fetches Bluesky profiles and follow lists from the public
+// AT Protocol AppView, in the shapes the tracker uses for X. User IDs are
+// DIDs; no account or key is needed.
+package bluesky
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"x-tracker/config"
+	"x-tracker/internal/api"
+	"x-tracker/internal/logger"
+)
+
+// pageSize is the most follows getFollows returns per request
+const pageSize = 100
+
+// Client reads profiles and follows from an AppView. It implements the
+// tracker's Provider interface.
+type Client struct {
+	host       string
+	httpClient *http.Client
+
+	pages     atomic.Int64 // follow and follower pages fetched since startup
+	lookups   atomic.Int64 // profile lookups since startup
+	remaining atomic.Int64 // requests left as of the last ratelimit-remaining header, -1 before one was seen
+
+	mu    sync.Mutex
+	quota api.EndpointQuota // from the last response carrying rate limit headers
+}
+
+// NewClient returns a client for cfg.BlueskyHost, going through the
+// configured proxy
+func NewClient(cfg *config.Config) *Client {
+	transport := http.DefaultTransport
+	if proxy := cfg.ProxyURL(); proxy != nil {
+		proxied := http.DefaultTransport.(*http.Transport).Clone()
+		proxied.Proxy = http.ProxyURL(proxy)
+		transport = proxied
+	}
+	c := &Client{
+		host:       strings.TrimSuffix(cfg.BlueskyHost, "/"),
+		httpClient: &http.Client{Timeout: cfg.RequestTimeout, Transport: transport},
+	}
+	c.remaining.Store(-1)
+	return c
+}
+
+// profile is app.bsky.actor.defs#profileViewDetailed
+type profile struct {
+	DID            string `json:"did"`
+	Handle         string `json:"handle"`
+	DisplayName    string `json:"displayName"`
+	Description    string `json:"description"`
+	Avatar         string `json:"avatar"`
+	FollowersCount int    `json:"followersCount"`
+	FollowsCount   int    `json:"followsCount"`
+	PostsCount     int    `json:"postsCount"`
+	CreatedAt      string `json:"createdAt"`
+	Verification   *struct {
+		VerifiedStatus string `json:"verifiedStatus"`
+	} `json:"verification"`
+}
+
+// followsPage is one page of app.bsky.graph.getFollows or getFollowers
+type followsPage struct {
+	Cursor  string `json:"cursor"`
+	Follows []struct {
+		DID string `json:"did"`
+	} `json:"follows"`
+	Followers []struct {
+		DID string `json:"did"`
+	} `json:"followers"`
+}
+
+// xrpcError is the body of a failed XRPC request
+type xrpcError struct {
+	Error   string `json:"error"`
+	Message string `json:"message"`
+}
+
+// GetUser looks up a handle
+func (c *Client) GetUser(handle string) (*api.UserResponse, error) {
+	p, err := c.getProfile(handle)
+	if err != nil {
+		return nil, err
+	}
+	var user api.UserResponse
+	user.RestID = p.DID
+	user.Legacy.CreatedAt = createdAt(p.CreatedAt)
+	user.Legacy.Name = p.DisplayName
+	user.Legacy.ScreenName = p.Handle
+	user.Legacy.FriendsCount = p.FollowsCount
+	user.Legacy.FollowersCount = p.FollowersCount
+	user.Legacy.ProfileImageURLHTTPS = p.Avatar
+	user.Legacy.Verified = p.verified()
+	return &user, nil
+}
+
+// GetUserByID looks up a DID
+func (c *Client) GetUserByID(did string) (*api.UserByIDResponse, error) {
+	p, err := c.getProfile(did)
+	if err != nil {
+		return nil, err
+	}
+	var user api.UserByIDResponse
+	user.RestID = p.DID
+	user.Legacy.CreatedAt = createdAt(p.CreatedAt)
+	user.Legacy.ScreenName = p.Handle
+	user.Legacy.Name = p.DisplayName
+	user.Legacy.Description = p.Description
+	user.Legacy.FollowersCount = p.FollowersCount
+	user.Legacy.FriendsCount = p.FollowsCount
+	user.Legacy.StatusesCount = p.PostsCount
+	user.Legacy.DefaultProfileImage = p.Avatar == ""
+	user.Legacy.ProfileImageURLHTTPS = p.Avatar
+	user.Legacy.Verified = p.verified()
+	return &user, nil
+}
+
+func (c *Client) getProfile(actor string) (*profile, error) {
+	c.lookups.Add(1)
+	var p profile
+	if err := c.get("app.bsky.actor.getProfile", url.Values{"actor": {actor}}, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// verified reports whether Bluesky or a trusted verifier vouches for the
+// account
+func (p *profile) verified() bool {
+	return p.Verification != nil && p.Verification.VerifiedStatus == "valid"
+}
+
+// ForEachFollowingPage pages through the accounts did follows, handing each
+// page of DIDs to fn as it arrives. It stops at the first error fn returns.
+func (c *Client) ForEachFollowingPage(did string, fn func(ids []string) error) error {
+	return c.forEachPage("app.bsky.graph.getFollows", did, 0, fn)
+}
+
+// GetFollowingIDsWithProgress fetches every account did follows, calling
+// progress with the number fetched so far after every page
+func (c *Client) GetFollowingIDsWithProgress(did string, progress func(fetched int)) (*api.FollowingIDsResponse, error) {
+	return c.collect("app.bsky.graph.getFollows", did, progress)
+}
+
+// GetFirstFollowingIDs fetches up to count of the accounts did follows,
+// newest first
+func (c *Client) GetFirstFollowingIDs(did string, count int) (*api.FollowingIDsResponse, error) {
+	var ids []string
+	err := c.forEachPage("app.bsky.graph.getFollows", did, count, func(page []string) error {
+		ids = append(ids, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &api.FollowingIDsResponse{IDs: ids}, nil
+}
+
+// GetFollowerIDs fetches every account following did
+func (c *Client) GetFollowerIDs(did string) (*api.FollowingIDsResponse, error) {
+	return c.collect("app.bsky.graph.getFollowers", did, nil)
+}
+
+func (c *Client) collect(method, did string, progress func(fetched int)) (*api.FollowingIDsResponse, error) {
+	var ids []string
+	err := c.forEachPage(method, did, 0, func(page []string) error {
+		ids = append(ids, page...)
+		if progress != nil {
+			progress(len(ids))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("Fetched %d DIDs from %s for %s", len(ids), method, did)
+	return &api.FollowingIDsResponse{IDs: ids}, nil
+}
+
+// forEachPage pages through a follow list until the cursor runs out, or
+// until limit DIDs were handed to fn if limit is above 0
+func (c *Client) forEachPage(method, did string, limit int, fn func(ids []string) error) error {
+	params := url.Values{"actor": {did}}
+	fetched := 0
+	for {
+		size := pageSize
+		if limit > 0 {
+			size = min(pageSize, limit-fetched)
+		}
+		params.Set("limit", strconv.Itoa(size))
+
+		c.pages.Add(1)
+		var page followsPage
+		if err := c.get(method, params, &page); err != nil {
+			return err
+		}
+		entries := page.Follows
+		if method == "app.bsky.graph.getFollowers" {
+			entries = page.Followers
+		}
+		ids := make([]string, len(entries))
+		for i, entry := range entries {
+			ids[i] = entry.DID
+		}
+		if err := fn(ids); err != nil {
+			return err
+		}
+
+		fetched += len(ids)
+		if page.Cursor == "" || len(ids) == 0 || (limit > 0 && fetched >= limit) {
+			return nil
+		}
+		params.Set("cursor", page.Cursor)
+	}
+}
+
+// get calls an XRPC query method and decodes its JSON response into v.
+// Accounts that are gone or taken down come back as an api.APIError the
+// tracker reads as unavailable or suspended.
+func (c *Client) get(method string, params url.Values, v interface{}) error {
+	resp, err := c.httpClient.Get(c.host + "/xrpc/" + method + "?" + params.Encode())
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	c.observe(method, resp)
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return responseError(resp.StatusCode, body)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("decoding %s response: %w", method, err)
+	}
+	return nil
+}
+
+// responseError converts a failed XRPC response into an api.APIError
+func responseError(status int, body []byte) error {
+	var payload xrpcError
+	json.Unmarshal(body, &payload)
+	message := strings.TrimSpace(payload.Error + ": " + payload.Message)
+	if payload.Error == "" {
+		message = string(body)
+	}
+
+	switch {
+	case payload.Error == "AccountTakedown":
+		return &api.APIError{StatusCode: http.StatusNotFound, Message: "suspended: " + message}
+	case payload.Error == "AccountDeactivated",
+		strings.Contains(payload.Message, "not found"),
+		strings.Contains(payload.Message, "Unable to resolve"):
+		return &api.APIError{StatusCode: http.StatusNotFound, Message: message}
+	}
+	return &api.APIError{StatusCode: status, Message: message}
+}
+
+// observe keeps the rate limit headers of a response
+func (c *Client) observe(method string, resp *http.Response) {
+	remaining, err := strconv.Atoi(resp.Header.Get("ratelimit-remaining"))
+	if err != nil {
+		return
+	}
+	c.remaining.Store(int64(remaining))
+
+	quota := api.EndpointQuota{Endpoint: method, Remaining: remaining, Updated: time.Now()}
+	quota.Limit, _ = strconv.Atoi(resp.Header.Get("ratelimit-limit"))
+	if reset, err := strconv.ParseInt(resp.Header.Get("ratelimit-reset"), 10, 64); err == nil {
+		quota.Reset = time.Unix(reset, 0)
+	}
+	c.mu.Lock()
+	c.quota = quota
+	c.mu.Unlock()
+}
+
+// createdAt converts an ISO 8601 timestamp to the layout X uses, so
+// account ages read the same for both
+func createdAt(value string) string {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return ""
+	}
+	return t.UTC().Format(time.RubyDate)
+}
+
+// Usage returns the requests made since startup by kind
+func (c *Client) Usage() api.Usage {
+	return api.Usage{Pages: c.pages.Load(), Lookups: c.lookups.Load()}
+}
+
+// RemainingRequests returns the requests the AppView allows until its rate
+// limit resets, -1 before it has reported any
+func (c *Client) RemainingRequests() int {
+	return int(c.remaining.Load())
+}
+
+// RateLimitStatus returns the AppView's rate limit as of the last response
+// that reported it. The limit is shared by all methods.
+func (c *Client) RateLimitStatus() []api.EndpointQuota {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.quota.Updated.IsZero() {
+		return nil
+	}
+	return []api.EndpointQuota{c.quota}
+}
+
+// Breaker always reports a closed circuit; failed requests are retried on
+// the next check
+func (c *Client) Breaker() api.BreakerStatus {
+	return api.BreakerStatus{}
+}
+
+// SuspiciousFields returns nothing, the AppView's responses follow a
+// published lexicon
+func (c *Client) SuspiciousFields() []string {
+	return nil
+}
+
+func (c *Client) ResetSchemaStats() {}
@@ -0,0 +1,144 @@
+package daemon
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"x-tracker/internal/logger"
+)
+
+// ErrNotRunning is returned by Call when no tracker is listening
+var ErrNotRunning = errors.New("x-tracker is not running")
+
+// callTimeout bounds a control call; a check cycle over many accounts can
+// take a while
+const callTimeout = 30 * time.Minute
+
+// Request is one command sent to the running tracker
+type Request struct {
+	Command string   `json:"command"`
+	Args    []string `json:"args,omitempty"`
+}
+
+// Response is the running tracker's answer to a Request
+type Response struct {
+	Message string          `json:"message,omitempty"`
+	Error   string          `json:"error,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// Handler runs a control command, returning a message for the user and
+// optional data for the caller to decode
+type Handler func(args []string) (message string, data any, err error)
+
+// ControlServer accepts commands from other x-tracker invocations over a
+// unix socket (supported on Windows 10 and later too), one request per
+// connection
+type ControlServer struct {
+	listener net.Listener
+	handlers map[string]Handler
+}
+
+// Listen opens the control socket at path. Callers must hold the pid file,
+// so a socket left behind by a crashed instance can be safely replaced.
+func Listen(path string, handlers map[string]Handler) (*ControlServer, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating control socket directory: %w", err)
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("removing stale control socket: %w", err)
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("opening control socket: %w", err)
+	}
+	// Only the owner may drive the tracker
+	if err := os.Chmod(path, 0600); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("restricting control socket: %w", err)
+	}
+
+	return &ControlServer{listener: listener, handlers: handlers}, nil
+}
+
+// Serve handles connections until Close is called
+func (s *ControlServer) Serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			logger.Warn("Control socket accept failed: %v", err)
+			continue
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *ControlServer) handle(conn net.Conn) {
+	defer conn.Close()
+
+	var req Request
+	if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&req); err != nil {
+		logger.Info("Invalid control request: %v", err)
+		return
+	}
+	logger.Info("Control command received: %s %v", req.Command, req.Args)
+
+	var resp Response
+	handler, ok := s.handlers[req.Command]
+	if !ok {
+		resp.Error = fmt.Sprintf("unknown command %q", req.Command)
+	} else if message, data, err := handler(req.Args); err != nil {
+		resp.Error = err.Error()
+	} else {
+		resp.Message = message
+		if data != nil {
+			if resp.Data, err = json.Marshal(data); err != nil {
+				resp.Error = fmt.Sprintf("encoding response: %v", err)
+			}
+		}
+	}
+
+	if err := json.NewEncoder(conn).Encode(resp); err != nil {
+		logger.Warn("Writing control response failed: %v", err)
+	}
+}
+
+// Close stops accepting commands and removes the socket
+func (s *ControlServer) Close() error {
+	return s.listener.Close()
+}
+
+// Call sends a command to the tracker listening at path. It returns
+// ErrNotRunning when nothing is listening, and the handler's error as an
+// error.
+func Call(path, command string, args ...string) (*Response, error) {
+	conn, err := net.DialTimeout("unix", path, 2*time.Second)
+	if err != nil {
+		return nil, ErrNotRunning
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(callTimeout))
+
+	if err := json.NewEncoder(conn).Encode(Request{Command: command, Args: args}); err != nil {
+		return nil, fmt.Errorf("sending control request: %w", err)
+	}
+
+	var resp Response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("reading control response: %w", err)
+	}
+	if resp.Error != "" {
+		return nil, errors.New(resp.Error)
+	}
+	return &resp, nil
+}
@@ -0,0 +1,110 @@
+// Package daemon tracks the running tracker process so other invocations
+// can find it
+package daemon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"x-tracker/internal/lockfile"
+)
+
+// ErrRunning is returned by Acquire when another process holds the pid file
+var ErrRunning = errors.New("another x-tracker instance is running")
+
+// Info is what the running instance writes to its pid file
+type Info struct {
+	PID       int       `json:"pid"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// PIDFile is a pid file locked for as long as the process runs. The lock,
+// not the file's existence, tells whether the instance is alive, so a
+// crash never leaves a stale "running" state behind.
+type PIDFile struct {
+	file *os.File
+}
+
+// Acquire locks the pid file at path and records this process in it
+func Acquire(path string) (*PIDFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating pid file directory: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("opening pid file: %w", err)
+	}
+
+	if err := lockfile.TryLock(file); err != nil {
+		file.Close()
+		if errors.Is(err, lockfile.ErrLocked) {
+			return nil, ErrRunning
+		}
+		return nil, fmt.Errorf("locking pid file: %w", err)
+	}
+
+	data, err := json.Marshal(Info{PID: os.Getpid(), StartedAt: time.Now()})
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if err := file.Truncate(0); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("writing pid file: %w", err)
+	}
+	if _, err := file.WriteAt(data, 0); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("writing pid file: %w", err)
+	}
+
+	return &PIDFile{file: file}, nil
+}
+
+// Release unlocks and removes the pid file
+func (p *PIDFile) Release() error {
+	path := p.file.Name()
+	lockfile.Unlock(p.file)
+	if err := p.file.Close(); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// Probe reports the instance holding the pid file at path, or nil if no
+// instance is running
+func Probe(path string) (*Info, error) {
+	file, err := os.OpenFile(path, os.O_RDWR, 0644)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening pid file: %w", err)
+	}
+	defer file.Close()
+
+	err = lockfile.TryLock(file)
+	if err == nil {
+		// Nobody holds it, so whatever the file says is stale
+		lockfile.Unlock(file)
+		return nil, nil
+	}
+	if !errors.Is(err, lockfile.ErrLocked) {
+		return nil, fmt.Errorf("checking pid file lock: %w", err)
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading pid file: %w", err)
+	}
+	var info Info
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("parsing pid file: %w", err)
+	}
+	return &info, nil
+}
@@ -0,0 +1,133 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"x-tracker/internal/logger"
+)
+
+// RecordNotificationMessage remembers which events a channel message announced
+func (d *Database) RecordNotificationMessage(msg NotificationMessage) error {
+	userIDs, err := json.Marshal(msg.UserIDs)
+	if err != nil {
+		return fmt.Errorf("encoding user IDs: %w", err)
+	}
+
+	_, err = d.db.Exec(`
+		INSERT INTO notification_messages
+		(message_id, channel_id, watched_account_id, event_type, user_ids, sent_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (message_id) DO UPDATE SET
+			channel_id = excluded.channel_id,
+			watched_account_id = excluded.watched_account_id,
+			event_type = excluded.event_type,
+			user_ids = excluded.user_ids,
+			sent_at = excluded.sent_at`,
+		msg.MessageID, msg.ChannelID, msg.WatchedAccountID, msg.EventType, string(userIDs), msg.SentAt)
+	if err != nil {
+		return fmt.Errorf("storing notification message: %w", err)
+	}
+	return nil
+}
+
+// GetNotificationMessagesSince returns the messages sent after since
+func (d *Database) GetNotificationMessagesSince(since time.Time) ([]NotificationMessage, error) {
+	rows, err := d.db.Query(`
+		SELECT message_id, channel_id, watched_account_id, event_type, user_ids, sent_at
+		FROM notification_messages
+		WHERE sent_at > ?
+		ORDER BY sent_at`, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []NotificationMessage
+	for rows.Next() {
+		var msg NotificationMessage
+		var userIDs string
+		if err := rows.Scan(&msg.MessageID, &msg.ChannelID, &msg.WatchedAccountID,
+			&msg.EventType, &userIDs, &msg.SentAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(userIDs), &msg.UserIDs); err != nil {
+			return nil, fmt.Errorf("decoding user IDs of message %s: %w", msg.MessageID, err)
+		}
+		messages = append(messages, msg)
+	}
+	return messages, rows.Err()
+}
+
+// AnnotateEvent attaches a label from source to the event with the given
+// UUID, doing nothing if it already has the label
+func (d *Database) AnnotateEvent(uuid, label, source string) error {
+	var eventID int64
+	err := d.db.QueryRow("SELECT id FROM follow_events WHERE uuid = ?", strings.ToLower(uuid)).Scan(&eventID)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("finding event: %w", err)
+	}
+
+	if _, err := d.db.Exec(`
+		INSERT INTO event_annotations (event_id, label, source, created_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT DO NOTHING`,
+		eventID, label, source, time.Now()); err != nil {
+		return fmt.Errorf("storing annotation: %w", err)
+	}
+	return nil
+}
+
+// SyncMessageAnnotations makes the annotations from source on the events a
+// message announced match labels: missing ones are added and ones no
+// longer present (a removed reaction) are deleted. Annotations from other
+// sources are left alone.
+func (d *Database) SyncMessageAnnotations(msg NotificationMessage, labels []string, source string) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	for _, userID := range msg.UserIDs {
+		// The event this message announced is the latest one for the user
+		// detected before it was sent
+		var eventID int64
+		err := tx.QueryRow(`
+			SELECT id FROM follow_events
+			WHERE watched_account_id = ? AND event_type = ? AND user_id = ? AND detected_at <= ?
+			ORDER BY detected_at DESC
+			LIMIT 1`, msg.WatchedAccountID, msg.EventType, userID, msg.SentAt).Scan(&eventID)
+		if err != nil {
+			// The event may have been archived or removed with its account
+			continue
+		}
+
+		if _, err := tx.Exec("DELETE FROM event_annotations WHERE event_id = ? AND source = ?", eventID, source); err != nil {
+			return fmt.Errorf("clearing annotations: %w", err)
+		}
+		for _, label := range labels {
+			if _, err := tx.Exec(`
+				INSERT INTO event_annotations (event_id, label, source, created_at)
+				VALUES (?, ?, ?, ?)
+				ON CONFLICT DO NOTHING`, eventID, label, source, now); err != nil {
+				return fmt.Errorf("storing annotation: %w", err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+	if len(labels) > 0 {
+		logger.Info("Synced annotations %v for message %s", labels, msg.MessageID)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// GetFollowingsAsOf rebuilds the account's following set as it was at the
+// given time by undoing, starting from the stored set, every event detected
+// since. Changes collapsed into an earlier event as flaps after that time
+// are taken back to the event's own change. Before the account's baseline
+// the result is the baseline set, since nothing earlier is known. Times
+// before the cold archive cutoff are rebuilt from the archived events too,
+// and fail if an archive holding them is gone.
+func (d *Database) GetFollowingsAsOf(watchedAccountID int64, at time.Time) (map[string]bool, error) {
+	cutoff, err := d.ArchivedBefore()
+	if err != nil {
+		return nil, fmt.Errorf("reading archive cutoff: %w", err)
+	}
+
+	followings, err := d.GetCurrentFollowings(watchedAccountID)
+	if err != nil {
+		return nil, fmt.Errorf("getting current followings: %w", err)
+	}
+
+	undo := func(db rowsQuerier, schemas []string) error {
+		return undoEventsSince(db, archived("follow_events", eventFields, schemas), watchedAccountID, at, followings)
+	}
+	if at.Before(cutoff) {
+		err = d.withArchives(true, undo)
+	} else {
+		err = undo(d.db, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return followings, nil
+}
+
+// undoEventsSince takes the account's changes detected or flapped after at
+// back out of followings, reading them from events
+func undoEventsSince(db rowsQuerier, events string, watchedAccountID int64, at time.Time, followings map[string]bool) error {
+	rows, err := db.QueryContext(context.Background(), `
+		SELECT user_id, event_type, detected_at
+		FROM `+events+` e
+		WHERE watched_account_id = ? AND (detected_at > ? OR flapped_at > ?)
+		ORDER BY id ASC`, watchedAccountID, at, at)
+	if err != nil {
+		return fmt.Errorf("querying events since %s: %w", at.Format(time.RFC3339), err)
+	}
+	defer rows.Close()
+
+	// Only the oldest event about a user since then tells whether the user
+	// was followed at the time
+	undone := make(map[string]bool)
+	for rows.Next() {
+		var userID string
+		var eventType EventType
+		var detected archivedTime
+		if err := rows.Scan(&userID, &eventType, &detected); err != nil {
+			return err
+		}
+		detectedAt := detected.Time
+		if undone[userID] {
+			continue
+		}
+		undone[userID] = true
+
+		followed := eventType == EventTypeUnfollow
+		if !detectedAt.After(at) {
+			// Detected before, only flapped since: the event's change stands
+			followed = eventType == EventTypeFollow
+		}
+		if followed {
+			followings[userID] = true
+		} else {
+			delete(followings, userID)
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,65 @@
+package db
+
+import (
+	"os/user"
+	"time"
+
+	"x-tracker/internal/logger"
+)
+
+// Sources of management actions in the audit log
+const (
+	AuditSourceTUI      = "tui"
+	AuditSourceCLI      = "cli"
+	AuditSourceAPI      = "api"
+	AuditSourceTelegram = "telegram"
+)
+
+// LocalActor names the operating system user running this process, the
+// actor of everything done in the TUI and the CLI
+func LocalActor() string {
+	current, err := user.Current()
+	if err != nil {
+		return "unknown"
+	}
+	return current.Username
+}
+
+// Audit records a management action in the audit log. Failing to record
+// it is logged rather than returned, since the action itself succeeded.
+func (d *Database) Audit(entry AuditEntry) {
+	if entry.At.IsZero() {
+		entry.At = time.Now()
+	}
+	_, err := d.db.Exec(`
+		INSERT INTO audit_log (at, source, actor, action, target, detail)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		entry.At, entry.Source, entry.Actor, entry.Action, entry.Target, entry.Detail)
+	if err != nil {
+		logger.Error("Failed to record %s of %s in the audit log: %v", entry.Action, entry.Target, err)
+	}
+}
+
+// GetAuditLog returns the most recent management actions, newest first
+func (d *Database) GetAuditLog(limit int) ([]AuditEntry, error) {
+	rows, err := d.db.Query(`
+		SELECT id, at, source, actor, action, target, detail
+		FROM audit_log
+		ORDER BY id DESC
+		LIMIT ?`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []AuditEntry
+	for rows.Next() {
+		var entry AuditEntry
+		if err := rows.Scan(&entry.ID, &entry.At, &entry.Source, &entry.Actor,
+			&entry.Action, &entry.Target, &entry.Detail); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
@@ -0,0 +1,11 @@
+package db
+
+import "x-tracker/internal/logger"
+
+// Backup writes a consistent, compacted copy of the database to path,
+// which must not exist yet. The tracker can keep running meanwhile. A
+// Postgres database can't be copied to a file; use pg_dump.
+func (d *Database) Backup(path string) error {
+	logger.Info("Backing up database to %s", path)
+	return d.dialect.backup(d.db, path)
+}
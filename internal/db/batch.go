@@ -0,0 +1,74 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+)
+
+// batchSize is how many IDs one multi-row statement covers, well below
+// SQLite's limit on bound parameters
+const batchSize = 500
+
+// execer runs statements on a database, transaction or connection
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// execBatched runs a statement once per batch of ids and returns the total
+// of rows it affected. build returns the statement for a batch of n IDs and
+// args its arguments.
+func execBatched(e execer, ids []string, build func(n int) string, args func(batch []string) []any) (int64, error) {
+	var affected int64
+	for start := 0; start < len(ids); start += batchSize {
+		batch := ids[start:min(start+batchSize, len(ids))]
+		result, err := e.ExecContext(context.Background(), build(len(batch)), args(batch)...)
+		if err != nil {
+			return affected, err
+		}
+		n, _ := result.RowsAffected()
+		affected += n
+	}
+	return affected, nil
+}
+
+// repeatJoined repeats s n times, separated by commas, e.g. for the
+// placeholders of a multi-row VALUES list
+func repeatJoined(s string, n int) string {
+	return strings.TrimSuffix(strings.Repeat(s+", ", n), ", ")
+}
+
+// withAccount returns the arguments of a batch whose rows each start with
+// the account ID, followed by one ID and then extra
+func withAccount(watchedAccountID int64, extra ...any) func(batch []string) []any {
+	return func(batch []string) []any {
+		args := make([]any, 0, len(batch)*(2+len(extra)))
+		for _, id := range batch {
+			args = append(args, watchedAccountID, id)
+			args = append(args, extra...)
+		}
+		return args
+	}
+}
+
+// accountAndIDs returns the arguments of a batch matching IDs of one
+// account with IN (...)
+func accountAndIDs(watchedAccountID int64) func(batch []string) []any {
+	return func(batch []string) []any {
+		args := make([]any, 0, len(batch)+1)
+		args = append(args, watchedAccountID)
+		for _, id := range batch {
+			args = append(args, id)
+		}
+		return args
+	}
+}
+
+// plainIDs returns the IDs of a batch as its arguments
+func plainIDs(batch []string) []any {
+	args := make([]any, len(batch))
+	for i, id := range batch {
+		args[i] = id
+	}
+	return args
+}
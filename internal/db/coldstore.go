@@ -0,0 +1,319 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+	"x-tracker/internal/logger"
+)
+
+// coldTables are the tables whose old rows move to the cold archive, in the
+// order they are copied
+var coldTables = []string{"follow_events", "event_annotations"}
+
+// ArchiveEvents moves the events detected before cutoff, with their
+// annotations, into the cold archive and returns how many were moved. The
+// archive is the SQLite database at the path archive names, or on Postgres
+// the schema of that name, and is created if needed. Their daily follow and
+// unfollow counts stay behind in archived_event_counts, so totals and
+// dashboards still cover them. The newest event is never moved, so event
+// IDs are never reused.
+func (d *Database) ArchiveEvents(archive string, cutoff time.Time) (int, error) {
+	ctx := context.Background()
+	conn, err := d.db.Conn(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("opening connection: %w", err)
+	}
+	defer conn.Close()
+
+	// The archive is opened on a connection of its own, since SQLite only
+	// attaches it to the connection ATTACH runs on
+	cold, err := d.dialect.openArchive(ctx, conn, archive)
+	if err != nil {
+		return 0, fmt.Errorf("opening archive %s: %w", archive, err)
+	}
+	defer d.dialect.closeArchive(ctx, conn, cold)
+
+	columns := make(map[string]string, len(coldTables))
+	for _, table := range coldTables {
+		if columns[table], err = d.prepareColdTable(ctx, conn, cold, table); err != nil {
+			return 0, fmt.Errorf("preparing archived %s: %w", table, err)
+		}
+	}
+	if _, err := conn.ExecContext(ctx, d.dialect.archiveIndex(cold)); err != nil {
+		return 0, fmt.Errorf("indexing archived events: %w", err)
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Unqualified names are the main database's: SQLite looks there
+	// before the attached archive, Postgres in the search path
+	var newest int64
+	if err := tx.QueryRow("SELECT COALESCE(MAX(id), 0) FROM follow_events").Scan(&newest); err != nil {
+		return 0, err
+	}
+	// An event that flapped after the cutoff stays hot, so as-of lookups
+	// after the cutoff still see it
+	const moved = `SELECT id FROM follow_events
+		WHERE detected_at < ? AND COALESCE(flapped_at, detected_at) < ? AND id < ?`
+
+	if _, err := tx.Exec(`
+		INSERT INTO archived_event_counts (watched_account_id, day, follows, unfollows, dismissed)
+		SELECT watched_account_id, `+d.dialect.utcDay("detected_at")+` AS day,
+		       SUM(CASE WHEN event_type = 'follow' AND dismissed_at IS NULL THEN 1 ELSE 0 END),
+		       SUM(CASE WHEN event_type = 'unfollow' AND dismissed_at IS NULL THEN 1 ELSE 0 END),
+		       SUM(CASE WHEN dismissed_at IS NOT NULL THEN 1 ELSE 0 END)
+		FROM follow_events
+		WHERE id IN (`+moved+`)
+		GROUP BY watched_account_id, day
+		ON CONFLICT (watched_account_id, day) DO UPDATE SET
+		    follows = archived_event_counts.follows + excluded.follows,
+		    unfollows = archived_event_counts.unfollows + excluded.unfollows,
+		    dismissed = archived_event_counts.dismissed + excluded.dismissed`, cutoff, cutoff, newest); err != nil {
+		return 0, fmt.Errorf("counting archived events: %w", err)
+	}
+
+	if _, err := tx.Exec(fmt.Sprintf(`
+		INSERT INTO %[3]s.event_annotations (%[1]s)
+		SELECT %[1]s FROM event_annotations WHERE event_id IN (%[2]s)`,
+		columns["event_annotations"], moved, cold), cutoff, cutoff, newest); err != nil {
+		return 0, fmt.Errorf("copying annotations: %w", err)
+	}
+	result, err := tx.Exec(fmt.Sprintf(`
+		INSERT INTO %[3]s.follow_events (%[1]s)
+		SELECT %[1]s FROM follow_events WHERE id IN (%[2]s)`,
+		columns["follow_events"], moved, cold), cutoff, cutoff, newest)
+	if err != nil {
+		return 0, fmt.Errorf("copying events: %w", err)
+	}
+	events, _ := result.RowsAffected()
+
+	if _, err := tx.Exec("DELETE FROM event_annotations WHERE event_id IN ("+moved+")", cutoff, cutoff, newest); err != nil {
+		return 0, fmt.Errorf("deleting annotations: %w", err)
+	}
+	if _, err := tx.Exec("DELETE FROM follow_events WHERE id IN ("+moved+")", cutoff, cutoff, newest); err != nil {
+		return 0, fmt.Errorf("deleting events: %w", err)
+	}
+
+	if _, err := tx.Exec(`
+		INSERT INTO event_archives (archived_at, cutoff, events, path) VALUES (?, ?, ?, ?)`,
+		time.Now(), cutoff, events, archive); err != nil {
+		return 0, fmt.Errorf("recording archive run: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("committing transaction: %w", err)
+	}
+	return int(events), nil
+}
+
+// prepareColdTable creates the archived copy of a table in the cold schema
+// if needed and adds any columns added to the table since, returning the
+// column list to copy
+func (d *Database) prepareColdTable(ctx context.Context, conn *sql.Conn, cold, table string) (string, error) {
+	if _, err := conn.ExecContext(ctx,
+		"CREATE TABLE IF NOT EXISTS "+cold+"."+table+" AS SELECT * FROM "+table+" WHERE 1 = 0"); err != nil {
+		return "", err
+	}
+	hot, err := d.dialect.columns(ctx, conn, "", table)
+	if err != nil {
+		return "", err
+	}
+	coldColumns, err := d.dialect.columns(ctx, conn, cold, table)
+	if err != nil {
+		return "", err
+	}
+
+	archived := make(map[string]bool, len(coldColumns))
+	for _, column := range coldColumns {
+		archived[column.name] = true
+	}
+	names := make([]string, 0, len(hot))
+	for _, column := range hot {
+		names = append(names, column.name)
+		if archived[column.name] {
+			continue
+		}
+		if _, err := conn.ExecContext(ctx, fmt.Sprintf("ALTER TABLE %s.%s ADD COLUMN %s %s", cold, table, column.name, column.kind)); err != nil {
+			return "", err
+		}
+	}
+	return strings.Join(names, ", "), nil
+}
+
+// rowsQuerier runs queries on a database or on one of its connections
+type rowsQuerier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// withArchives runs fn with a querier seeing the cold archives next to the
+// main database, and the schemas the archives are open under, so it can
+// read through them with archived. Without archived events fn gets the
+// database itself and no schemas. An archive that no longer exists is left
+// out, or fails with strict set, for reads that would be wrong without it.
+func (d *Database) withArchives(strict bool, fn func(q rowsQuerier, schemas []string) error) error {
+	paths, err := d.archivePaths()
+	if err != nil {
+		return fmt.Errorf("listing archives: %w", err)
+	}
+	if len(paths) == 0 {
+		return fn(d.db, nil)
+	}
+
+	ctx := context.Background()
+	conn, err := d.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("opening connection: %w", err)
+	}
+	defer conn.Close()
+
+	schemas, err := d.openArchives(ctx, conn, paths, strict)
+	if err != nil {
+		return err
+	}
+	defer d.closeArchives(ctx, conn, schemas)
+	return fn(conn, schemas)
+}
+
+// archivePaths returns the archives holding moved events
+func (d *Database) archivePaths() ([]string, error) {
+	rows, err := d.db.Query("SELECT DISTINCT path FROM event_archives WHERE events > 0 ORDER BY path")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var paths []string
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, rows.Err()
+}
+
+// openArchives opens the existing archives at paths on conn for reading,
+// returning the schemas they are open under
+func (d *Database) openArchives(ctx context.Context, conn *sql.Conn, paths []string, strict bool) ([]string, error) {
+	var schemas []string
+	for i, path := range paths {
+		schema, err := d.dialect.readArchive(ctx, conn, path, fmt.Sprintf("cold%d", i))
+		if err != nil {
+			d.closeArchives(ctx, conn, schemas)
+			return nil, fmt.Errorf("opening archive %s: %w", path, err)
+		}
+		if schema == "" {
+			if strict {
+				d.closeArchives(ctx, conn, schemas)
+				return nil, fmt.Errorf("archive %s holding old events no longer exists", path)
+			}
+			logger.Debug("Archive %s no longer exists, leaving its events out", path)
+			continue
+		}
+		schemas = append(schemas, schema)
+	}
+	return schemas, nil
+}
+
+func (d *Database) closeArchives(ctx context.Context, conn *sql.Conn, schemas []string) {
+	for _, schema := range schemas {
+		d.dialect.closeArchive(ctx, conn, schema)
+	}
+}
+
+// archived returns a table expression for the columns of table holding
+// its rows in the main database and in the archives open under schemas
+func archived(table, columns string, schemas []string) string {
+	if len(schemas) == 0 {
+		return table
+	}
+	parts := []string{"SELECT " + columns + " FROM " + table}
+	for _, schema := range schemas {
+		parts = append(parts, "SELECT "+columns+" FROM "+schema+"."+table)
+	}
+	return "(" + strings.Join(parts, " UNION ALL ") + ")"
+}
+
+// archivedTime scans a timestamp that may have been read from a SQLite
+// archive. Its tables were created from a query and lost their declared
+// types, so the driver hands their timestamps over as text.
+type archivedTime struct {
+	sql.NullTime
+}
+
+func (t *archivedTime) Scan(value any) error {
+	text, ok := value.(string)
+	if !ok {
+		if b, isBytes := value.([]byte); isBytes {
+			text, ok = string(b), true
+		}
+	}
+	if !ok {
+		return t.NullTime.Scan(value)
+	}
+	text = strings.TrimSuffix(text, "Z")
+	for _, layout := range sqlite3.SQLiteTimestampFormats {
+		if parsed, err := time.ParseInLocation(layout, text, time.UTC); err == nil {
+			t.Time, t.Valid = parsed, true
+			return nil
+		}
+	}
+	return fmt.Errorf("unrecognized timestamp %q", text)
+}
+
+// tableColumn is the name and declared type of a column
+type tableColumn struct {
+	name, kind string
+}
+
+// GetEventArchives lists the archive runs, newest first
+func (d *Database) GetEventArchives() ([]EventArchive, error) {
+	rows, err := d.db.Query(`
+		SELECT id, archived_at, cutoff, events, path
+		FROM event_archives
+		ORDER BY id DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var archives []EventArchive
+	for rows.Next() {
+		var archive EventArchive
+		if err := rows.Scan(&archive.ID, &archive.ArchivedAt, &archive.Cutoff, &archive.Events, &archive.Path); err != nil {
+			return nil, err
+		}
+		archives = append(archives, archive)
+	}
+	return archives, rows.Err()
+}
+
+// ArchivedBefore returns the time before which events have been moved to
+// the cold archive, zero if none have
+func (d *Database) ArchivedBefore() (time.Time, error) {
+	var cutoff sql.NullTime
+	err := d.db.QueryRow("SELECT cutoff FROM event_archives WHERE events > 0 ORDER BY cutoff DESC LIMIT 1").Scan(&cutoff)
+	if err == sql.ErrNoRows {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return cutoff.Time, nil
+}
+
+// Vacuum rebuilds the database file, giving back to the filesystem the
+// space freed by deleted rows; on Postgres it marks that space for reuse
+func (d *Database) Vacuum() error {
+	_, err := d.db.Exec("VACUUM")
+	return err
+}
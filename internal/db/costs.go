@@ -0,0 +1,93 @@
+package db
+
+import (
+	"fmt"
+	"time"
+)
+
+// RecordCheckCosts stores the API calls of every account check of a cycle
+// and drops the costs of cycles no longer kept in check_runs
+func (d *Database) RecordCheckCosts(checkRunID int64, costs []CheckCost) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO check_costs (check_run_id, watched_account_id, checked_at, pages, lookups)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (check_run_id, watched_account_id) DO UPDATE SET
+			checked_at = excluded.checked_at,
+			pages = excluded.pages,
+			lookups = excluded.lookups`)
+	if err != nil {
+		return fmt.Errorf("preparing statement: %w", err)
+	}
+	defer stmt.Close()
+	for _, cost := range costs {
+		if _, err := stmt.Exec(checkRunID, cost.WatchedAccountID, cost.CheckedAt, cost.Pages, cost.Lookups); err != nil {
+			return fmt.Errorf("storing check cost: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(`
+		DELETE FROM check_costs
+		WHERE check_run_id < (SELECT COALESCE(MIN(id), 0) FROM check_runs)`); err != nil {
+		return fmt.Errorf("pruning check costs: %w", err)
+	}
+	return tx.Commit()
+}
+
+// GetLastCheckCosts returns the cost of the latest check of every account
+// that has one, by account ID
+func (d *Database) GetLastCheckCosts() (map[int64]CheckCost, error) {
+	rows, err := d.db.Query(`
+		SELECT check_run_id, watched_account_id, checked_at, pages, lookups
+		FROM (
+			SELECT *, ROW_NUMBER() OVER (PARTITION BY watched_account_id ORDER BY check_run_id DESC) AS n
+			FROM check_costs
+		) ranked
+		WHERE n = 1`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	costs := make(map[int64]CheckCost)
+	for rows.Next() {
+		var cost CheckCost
+		if err := rows.Scan(&cost.CheckRunID, &cost.WatchedAccountID, &cost.CheckedAt, &cost.Pages, &cost.Lookups); err != nil {
+			return nil, err
+		}
+		costs[cost.WatchedAccountID] = cost
+	}
+	return costs, rows.Err()
+}
+
+// GetCostRanking sums up the API calls of each watched account's checks
+// since a time, most expensive first, up to limit accounts
+func (d *Database) GetCostRanking(since time.Time, limit int) ([]AccountCost, error) {
+	rows, err := d.db.Query(`
+		SELECT a.username, COUNT(*), SUM(c.pages), SUM(c.lookups)
+		FROM check_costs c
+		JOIN watched_accounts a ON a.id = c.watched_account_id
+		WHERE c.checked_at >= ?
+		GROUP BY a.id
+		ORDER BY SUM(c.pages) + SUM(c.lookups) DESC, a.username
+		LIMIT ?`, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ranking []AccountCost
+	for rows.Next() {
+		var cost AccountCost
+		if err := rows.Scan(&cost.Username, &cost.Checks, &cost.Pages, &cost.Lookups); err != nil {
+			return nil, err
+		}
+		ranking = append(ranking, cost)
+	}
+	return ranking, rows.Err()
+}
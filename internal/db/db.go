@@ -1,17 +1,25 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync/atomic"
 	_ "github.com/mattn/go-sqlite3"
 	"x-tracker/internal/logger"
 	"time"
 )
 
 type Database struct {
-	db *sql.DB
+	db                 *sql.DB
+	dialect            dialect
+	differ             differ
+	partitionThreshold int       // followings an account stores before moving to its own table, 0 never moves
+	scratchDir         string    // removed on Close, set for scratch copies
+	pinned             *sql.Conn // keeps an in-memory database alive until Close
 }
 
 const schema = `
@@ -38,52 +46,481 @@ CREATE TABLE IF NOT EXISTS follow_events (
 );
 
 CREATE INDEX IF NOT EXISTS idx_follow_events_account 
-ON follow_events(watched_account_id, detected_at);`
+ON follow_events(watched_account_id, detected_at);
+
+CREATE INDEX IF NOT EXISTS idx_following_user
+ON following(followed_user_id);
+
+CREATE TABLE IF NOT EXISTS following_tombstones (
+    watched_account_id INTEGER,
+    followed_user_id TEXT,
+    unfollow_count INTEGER NOT NULL DEFAULT 0,
+    refollow_count INTEGER NOT NULL DEFAULT 0,
+    last_seen_at TIMESTAMP,
+    PRIMARY KEY (watched_account_id, followed_user_id),
+    FOREIGN KEY(watched_account_id) REFERENCES watched_accounts(id)
+) WITHOUT ROWID;
+
+CREATE TABLE IF NOT EXISTS following_count_samples (
+    watched_account_id INTEGER,
+    following_count INTEGER NOT NULL,
+    sampled_at TIMESTAMP NOT NULL,
+    FOREIGN KEY(watched_account_id) REFERENCES watched_accounts(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_following_count_samples_account
+ON following_count_samples(watched_account_id, sampled_at);
+
+CREATE TABLE IF NOT EXISTS notification_filters (
+    watched_account_id INTEGER PRIMARY KEY,
+    min_followers INTEGER NOT NULL DEFAULT 0,
+    verified_only INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY(watched_account_id) REFERENCES watched_accounts(id)
+);
+
+CREATE TABLE IF NOT EXISTS profile_events (
+    id INTEGER PRIMARY KEY,
+    watched_account_id INTEGER,
+    field TEXT NOT NULL,
+    old_value TEXT,
+    new_value TEXT,
+    detected_at TIMESTAMP,
+    FOREIGN KEY(watched_account_id) REFERENCES watched_accounts(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_profile_events_account
+ON profile_events(watched_account_id, detected_at);
+
+CREATE TABLE IF NOT EXISTS target_snapshots (
+    id INTEGER PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    following_ids TEXT NOT NULL,
+    taken_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_target_snapshots_user
+ON target_snapshots(user_id, taken_at);
+
+CREATE TABLE IF NOT EXISTS check_runs (
+    id INTEGER PRIMARY KEY,
+    started_at TIMESTAMP NOT NULL,
+    finished_at TIMESTAMP NOT NULL,
+    accounts INTEGER NOT NULL DEFAULT 0,
+    failures INTEGER NOT NULL DEFAULT 0,
+    notify_failures INTEGER NOT NULL DEFAULT 0,
+    quota_remaining INTEGER NOT NULL DEFAULT 0,
+    error TEXT
+);
+
+CREATE TABLE IF NOT EXISTS notification_messages (
+    message_id TEXT PRIMARY KEY,
+    channel_id TEXT NOT NULL,
+    watched_account_id INTEGER,
+    event_type TEXT,
+    user_ids TEXT NOT NULL,
+    sent_at TIMESTAMP NOT NULL,
+    FOREIGN KEY(watched_account_id) REFERENCES watched_accounts(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_messages_sent
+ON notification_messages(sent_at);
+
+CREATE TABLE IF NOT EXISTS event_annotations (
+    event_id INTEGER,
+    label TEXT NOT NULL,
+    source TEXT NOT NULL,
+    created_at TIMESTAMP,
+    PRIMARY KEY (event_id, label),
+    FOREIGN KEY(event_id) REFERENCES follow_events(id)
+) WITHOUT ROWID;
+
+CREATE TABLE IF NOT EXISTS followers (
+    watched_account_id INTEGER,
+    follower_user_id TEXT,
+    first_seen_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (watched_account_id, follower_user_id),
+    FOREIGN KEY(watched_account_id) REFERENCES watched_accounts(id)
+) WITHOUT ROWID;
+
+CREATE TABLE IF NOT EXISTS lost_followers (
+    id INTEGER PRIMARY KEY,
+    watched_account_id INTEGER,
+    user_id TEXT NOT NULL,
+    username TEXT,
+    display_name TEXT,
+    followers_count INTEGER NOT NULL DEFAULT 0,
+    first_seen_at TIMESTAMP NOT NULL,
+    lost_at TIMESTAMP NOT NULL,
+    FOREIGN KEY(watched_account_id) REFERENCES watched_accounts(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_lost_followers_account
+ON lost_followers(watched_account_id, lost_at);
+
+CREATE TABLE IF NOT EXISTS account_tags (
+    watched_account_id INTEGER,
+    tag TEXT NOT NULL,
+    PRIMARY KEY (watched_account_id, tag),
+    FOREIGN KEY(watched_account_id) REFERENCES watched_accounts(id)
+) WITHOUT ROWID;
+
+CREATE TABLE IF NOT EXISTS muted_users (
+    user_id TEXT PRIMARY KEY,
+    source TEXT NOT NULL,
+    muted_at TIMESTAMP NOT NULL,
+    suppressed INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS notification_log (
+    id INTEGER PRIMARY KEY,
+    attempted_at TIMESTAMP NOT NULL,
+    channel TEXT NOT NULL,
+    account TEXT NOT NULL DEFAULT '',
+    kind TEXT NOT NULL,
+    summary TEXT NOT NULL DEFAULT '',
+    status_code INTEGER NOT NULL DEFAULT 0,
+    latency_ms INTEGER NOT NULL DEFAULT 0,
+    error TEXT
+);
+
+CREATE TABLE IF NOT EXISTS audit_log (
+    id INTEGER PRIMARY KEY,
+    at TIMESTAMP NOT NULL,
+    source TEXT NOT NULL,
+    actor TEXT NOT NULL DEFAULT '',
+    action TEXT NOT NULL,
+    target TEXT NOT NULL DEFAULT '',
+    detail TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS insights (
+    id INTEGER PRIMARY KEY,
+    kind TEXT NOT NULL,
+    key TEXT NOT NULL,
+    summary TEXT NOT NULL,
+    accounts TEXT NOT NULL DEFAULT '[]',
+    user_ids TEXT NOT NULL DEFAULT '[]',
+    detected_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    UNIQUE (kind, key)
+);
+
+CREATE TABLE IF NOT EXISTS raw_snapshots (
+    id INTEGER PRIMARY KEY,
+    watched_account_id INTEGER NOT NULL,
+    taken_at TIMESTAMP NOT NULL,
+    id_count INTEGER NOT NULL,
+    data BLOB NOT NULL,
+    FOREIGN KEY(watched_account_id) REFERENCES watched_accounts(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_raw_snapshots_account
+ON raw_snapshots(watched_account_id, taken_at);
+
+CREATE TABLE IF NOT EXISTS archived_event_counts (
+    watched_account_id INTEGER,
+    day TEXT NOT NULL,
+    follows INTEGER NOT NULL DEFAULT 0,
+    unfollows INTEGER NOT NULL DEFAULT 0,
+    dismissed INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (watched_account_id, day),
+    FOREIGN KEY(watched_account_id) REFERENCES watched_accounts(id)
+) WITHOUT ROWID;
+
+CREATE TABLE IF NOT EXISTS event_archives (
+    id INTEGER PRIMARY KEY,
+    archived_at TIMESTAMP NOT NULL,
+    cutoff TIMESTAMP NOT NULL,
+    events INTEGER NOT NULL,
+    path TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS check_costs (
+    check_run_id INTEGER NOT NULL,
+    watched_account_id INTEGER NOT NULL,
+    checked_at TIMESTAMP NOT NULL,
+    pages INTEGER NOT NULL DEFAULT 0,
+    lookups INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (check_run_id, watched_account_id),
+    FOREIGN KEY(watched_account_id) REFERENCES watched_accounts(id)
+) WITHOUT ROWID;
+
+CREATE INDEX IF NOT EXISTS idx_check_costs_account
+ON check_costs(watched_account_id, checked_at);`
+
+// MemoryPath as the database path keeps the database in memory for the
+// life of the process, for tests and throwaway runs
+const MemoryPath = ":memory:"
+
+// memoryDatabases numbers the in-memory databases so each is separate
+var memoryDatabases atomic.Int64
 
 func NewDatabase(dbPath string) (*Database, error) {
+	if dbPath == MemoryPath {
+		return NewMemoryDatabase()
+	}
+
 	// Create directory if it doesn't exist
 	dir := filepath.Dir(dbPath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return nil, fmt.Errorf("creating database directory: %w", err)
 	}
 
+	return openDatabase(dbPath)
+}
+
+// NewMemoryDatabase returns an empty database held in memory, gone once
+// it is closed. Every call returns a separate one.
+func NewMemoryDatabase() (*Database, error) {
+	// Each connection to a plain :memory: database gets a database of its
+	// own; the memdb VFS shares one named database between the pool's
+	// connections instead
+	dsn := fmt.Sprintf("file:/x-tracker-%d-%d?vfs=memdb", os.Getpid(), memoryDatabases.Add(1))
+	d, err := openDatabase(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	// The database lives as long as a connection to it is open, so hold
+	// one even when the pool closes its idle ones
+	d.pinned, err = d.db.Conn(context.Background())
+	if err != nil {
+		d.db.Close()
+		return nil, fmt.Errorf("connecting to database: %w", err)
+	}
+	return d, nil
+}
+
+// openDatabase opens the database a SQLite DSN names and brings its schema
+// up to date
+func openDatabase(dsn string) (*Database, error) {
 	// Open database
-	db, err := sql.Open("sqlite3", dbPath)
+	db, err := sql.Open("sqlite3", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("opening database: %w", err)
 	}
+	return open(db, sqliteDialect{})
+}
 
+// open connects to db and brings its schema up to date, closing db if
+// either fails
+func open(db *sql.DB, dialect dialect) (*Database, error) {
 	if err := db.Ping(); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("connecting to database: %w", err)
 	}
 
 	// Initialize schema
-	if _, err := db.Exec(schema); err != nil {
+	if _, err := db.Exec(dialect.schema()); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("initializing schema: %w", err)
 	}
 
-	return &Database{db: db}, nil
+	if err := dialect.migrate(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrating schema: %w", err)
+	}
+
+	d := &Database{db: db, dialect: dialect, differ: deleteDiffer{}}
+	if err := d.refreshViews(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("refreshing dashboard views: %w", err)
+	}
+	return d, nil
+}
+
+// migrations upgrade the base schema in order; applying migrations[i]
+// brings the database to user_version i+1. Only ever append to this list,
+// adding the change to postgresSchema and its Postgres form to
+// postgresMigrations too.
+var migrations = []string{
+	`ALTER TABLE follow_events ADD COLUMN dismissed_at TIMESTAMP`,
+	`ALTER TABLE watched_accounts ADD COLUMN added_at TIMESTAMP`,
+	// Existing accounts already have a baseline and have been checked before
+	`ALTER TABLE watched_accounts ADD COLUMN baselined_at TIMESTAMP;
+	 ALTER TABLE watched_accounts ADD COLUMN last_checked_at TIMESTAMP;
+	 UPDATE watched_accounts SET baselined_at = CURRENT_TIMESTAMP, last_checked_at = CURRENT_TIMESTAMP`,
+	// Last seen profile; profile_seen_at stays NULL until it is first captured
+	`ALTER TABLE watched_accounts ADD COLUMN display_name TEXT;
+	 ALTER TABLE watched_accounts ADD COLUMN bio TEXT;
+	 ALTER TABLE watched_accounts ADD COLUMN avatar_url TEXT;
+	 ALTER TABLE watched_accounts ADD COLUMN profile_seen_at TIMESTAMP`,
+	`ALTER TABLE watched_accounts ADD COLUMN status TEXT NOT NULL DEFAULT 'active';
+	 ALTER TABLE watched_accounts ADD COLUMN status_changed_at TIMESTAMP`,
+	`ALTER TABLE watched_accounts ADD COLUMN drift_reported INTEGER;
+	 ALTER TABLE watched_accounts ADD COLUMN drift_fetched INTEGER;
+	 ALTER TABLE watched_accounts ADD COLUMN drift_detected_at TIMESTAMP`,
+	// Per-account check jitter in milliseconds; NULL uses CHECK_JITTER
+	`ALTER TABLE watched_accounts ADD COLUMN check_jitter_ms INTEGER`,
+	// Archived accounts keep their history but aren't watched
+	`ALTER TABLE watched_accounts ADD COLUMN archived_at TIMESTAMP`,
+	// Paused accounts are kept with their snapshot but not checked
+	`ALTER TABLE watched_accounts ADD COLUMN paused_at TIMESTAMP`,
+	// Handles are case-insensitive: username keeps the display casing and
+	// username_key the lowercase form lookups match on. Accounts added twice
+	// in different casings are merged into the watched (else the oldest)
+	// one, which takes over their history; their snapshots are dropped.
+	`ALTER TABLE watched_accounts ADD COLUMN username_key TEXT;
+	 UPDATE watched_accounts SET username_key = LOWER(username);
+	 CREATE TEMP TABLE duplicate_accounts AS
+	     SELECT a.id, (SELECT b.id FROM watched_accounts b WHERE b.username_key = a.username_key
+	                   ORDER BY b.archived_at IS NOT NULL, b.id LIMIT 1) AS keep_id
+	     FROM watched_accounts a;
+	 DELETE FROM duplicate_accounts WHERE id = keep_id;
+	 UPDATE follow_events SET watched_account_id = (SELECT keep_id FROM duplicate_accounts WHERE id = watched_account_id)
+	     WHERE watched_account_id IN (SELECT id FROM duplicate_accounts);
+	 UPDATE profile_events SET watched_account_id = (SELECT keep_id FROM duplicate_accounts WHERE id = watched_account_id)
+	     WHERE watched_account_id IN (SELECT id FROM duplicate_accounts);
+	 UPDATE lost_followers SET watched_account_id = (SELECT keep_id FROM duplicate_accounts WHERE id = watched_account_id)
+	     WHERE watched_account_id IN (SELECT id FROM duplicate_accounts);
+	 DELETE FROM following WHERE watched_account_id IN (SELECT id FROM duplicate_accounts);
+	 DELETE FROM following_tombstones WHERE watched_account_id IN (SELECT id FROM duplicate_accounts);
+	 DELETE FROM following_count_samples WHERE watched_account_id IN (SELECT id FROM duplicate_accounts);
+	 DELETE FROM followers WHERE watched_account_id IN (SELECT id FROM duplicate_accounts);
+	 DELETE FROM notification_filters WHERE watched_account_id IN (SELECT id FROM duplicate_accounts);
+	 DELETE FROM notification_messages WHERE watched_account_id IN (SELECT id FROM duplicate_accounts);
+	 DELETE FROM account_tags WHERE watched_account_id IN (SELECT id FROM duplicate_accounts);
+	 DELETE FROM watched_accounts WHERE id IN (SELECT id FROM duplicate_accounts);
+	 CREATE UNIQUE INDEX idx_watched_accounts_username_key ON watched_accounts(username_key)`,
+	// Random version 4 UUIDs for the events recorded so far
+	`ALTER TABLE follow_events ADD COLUMN uuid TEXT;
+	 UPDATE follow_events SET uuid = lower(
+	     hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' ||
+	     substr('89ab', 1 + abs(random()) % 4, 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6)));
+	 CREATE UNIQUE INDEX idx_follow_events_uuid ON follow_events(uuid)`,
+	// Flap counts of events reversed and redone within the flap window
+	`ALTER TABLE follow_events ADD COLUMN flaps INTEGER NOT NULL DEFAULT 0;
+	 ALTER TABLE follow_events ADD COLUMN flapped_at TIMESTAMP;
+	 CREATE INDEX idx_follow_events_user ON follow_events(watched_account_id, user_id)`,
+	// Network an account is watched on; everything before was on X
+	`ALTER TABLE watched_accounts ADD COLUMN platform TEXT NOT NULL DEFAULT 'x'`,
+	// Name the user knows an account by, for inventories of the watch list
+	`ALTER TABLE watched_accounts ADD COLUMN alias TEXT`,
+}
+
+// migrationHooks finish the migration at the same index from Go, in its
+// transaction, where the SQL alone can't
+var migrationHooks = map[int]func(tx *sql.Tx) error{
+	9: dropDuplicatePartitions,
+}
+
+// dropDuplicatePartitions drops the following tables of the accounts the
+// username_key migration merged away; their names are made from the IDs
+func dropDuplicatePartitions(tx *sql.Tx) error {
+	rows, err := tx.Query("SELECT id FROM duplicate_accounts")
+	if err != nil {
+		return err
+	}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if err := dropPartition(tx, id); err != nil {
+			return err
+		}
+	}
+	_, err = tx.Exec("DROP TABLE duplicate_accounts")
+	return err
+}
+
+// migrate applies any migrations newer than the database's user_version
+func migrate(db *sql.DB) error {
+	var version int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return fmt.Errorf("reading schema version: %w", err)
+	}
+
+	for i := version; i < len(migrations); i++ {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(migrations[i]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+		if hook := migrationHooks[i]; hook != nil {
+			if err := hook(tx); err != nil {
+				tx.Rollback()
+				return fmt.Errorf("migration %d: %w", i+1, err)
+			}
+		}
+		// PRAGMA doesn't accept bound parameters
+		if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", i+1)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("setting schema version %d: %w", i+1, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		logger.Info("Applied database migration %d", i+1)
+	}
+	return nil
 }
 
 func (d *Database) Close() error {
-	return d.db.Close()
+	if d.pinned != nil {
+		d.pinned.Close()
+	}
+	err := d.db.Close()
+	if d.scratchDir != "" {
+		os.RemoveAll(d.scratchDir)
+	}
+	return err
 }
 
-// AddWatchedAccount adds a new account to watch
+// AddWatchedAccount adds a new account to watch. An archived account with
+// the same username is brought back, keeping its history.
 func (d *Database) AddWatchedAccount(account *WatchedAccount) error {
 	logger.Info("Adding account to watch list: %s", account.Username)
-	query := `
-		INSERT INTO watched_accounts (username, user_id)
-		VALUES (?, ?)`
-	
-	result, err := d.db.Exec(query,
-		account.Username,
-		account.UserID)
-	if err != nil {
+	account.AddedAt = time.Now()
+	account.Status = AccountStatusActive
+	if account.Platform == "" {
+		account.Platform = PlatformX
+	}
+
+	var archivedID int64
+	err := d.db.QueryRow(`
+		SELECT id FROM watched_accounts WHERE username_key = ? AND archived_at IS NOT NULL`,
+		UsernameKey(account.Username)).Scan(&archivedID)
+	if err == nil {
+		if _, err := d.db.Exec(`
+			UPDATE watched_accounts
+			SET username = ?, user_id = ?, platform = ?, added_at = ?, archived_at = NULL, paused_at = NULL, alias = NULL, baselined_at = NULL, last_checked_at = NULL,
+			    status = ?, status_changed_at = NULL,
+			    drift_reported = NULL, drift_fetched = NULL, drift_detected_at = NULL
+			WHERE id = ?`,
+			account.Username, account.UserID, account.Platform, account.AddedAt, account.Status, archivedID); err != nil {
+			return err
+		}
+		account.ID = archivedID
+		logger.Info("Restored archived account: %s (ID: %d)", account.Username, account.ID)
+		return nil
+	} else if err != sql.ErrNoRows {
 		return err
 	}
 
-	id, err := result.LastInsertId()
+	query := `
+		INSERT INTO watched_accounts (username, username_key, user_id, platform, added_at)
+		VALUES (?, ?, ?, ?, ?)`
+	
+	id, err := d.dialect.insert(context.Background(), d.db, query,
+		account.Username,
+		UsernameKey(account.Username),
+		account.UserID,
+		account.Platform,
+		account.AddedAt)
 	if err != nil {
 		return err
 	}
@@ -93,12 +530,18 @@ func (d *Database) AddWatchedAccount(account *WatchedAccount) error {
 	return nil
 }
 
-// GetWatchedAccounts returns all watched accounts
+// GetWatchedAccounts returns all watched accounts, leaving out archived ones
 func (d *Database) GetWatchedAccounts() ([]WatchedAccount, error) {
 	var accounts []WatchedAccount
 	rows, err := d.db.Query(`
-		SELECT id, username, user_id 
-		FROM watched_accounts`)
+		SELECT a.id, a.username, a.user_id, a.platform, a.added_at, a.baselined_at, a.last_checked_at,
+		       COALESCE(a.display_name, ''), COALESCE(a.bio, ''), COALESCE(a.avatar_url, ''), a.profile_seen_at,
+		       a.status, a.status_changed_at,
+		       a.drift_reported, a.drift_fetched, a.drift_detected_at, a.check_jitter_ms, a.paused_at, COALESCE(a.alias, ''),
+		       COALESCE(f.min_followers, 0), COALESCE(f.verified_only, FALSE)
+		FROM watched_accounts a
+		LEFT JOIN notification_filters f ON f.watched_account_id = a.id
+		WHERE a.archived_at IS NULL`)
 	if err != nil {
 		return nil, err
 	}
@@ -106,109 +549,409 @@ func (d *Database) GetWatchedAccounts() ([]WatchedAccount, error) {
 
 	for rows.Next() {
 		var account WatchedAccount
+		var addedAt, baselinedAt, lastCheckedAt, profileSeenAt, statusChangedAt, driftDetectedAt, pausedAt sql.NullTime
+		var driftReported, driftFetched, checkJitter sql.NullInt64
 		err := rows.Scan(
 			&account.ID,
 			&account.Username,
-			&account.UserID)
+			&account.UserID,
+			&account.Platform,
+			&addedAt,
+			&baselinedAt,
+			&lastCheckedAt,
+			&account.Profile.DisplayName,
+			&account.Profile.Bio,
+			&account.Profile.AvatarURL,
+			&profileSeenAt,
+			&account.Status,
+			&statusChangedAt,
+			&driftReported,
+			&driftFetched,
+			&driftDetectedAt,
+			&checkJitter,
+			&pausedAt,
+			&account.Alias,
+			&account.Filter.MinFollowers,
+			&account.Filter.VerifiedOnly)
 		if err != nil {
 			return nil, err
 		}
+		account.AddedAt = addedAt.Time
+		account.BaselinedAt = baselinedAt.Time
+		account.LastCheckedAt = lastCheckedAt.Time
+		account.Profile.Username = account.Username
+		account.ProfileSeenAt = profileSeenAt.Time
+		account.StatusChangedAt = statusChangedAt.Time
+		account.PausedAt = pausedAt.Time
+		if driftDetectedAt.Valid {
+			account.Drift = &FollowingDrift{
+				Reported:   int(driftReported.Int64),
+				Fetched:    int(driftFetched.Int64),
+				DetectedAt: driftDetectedAt.Time,
+			}
+		}
+		if checkJitter.Valid {
+			jitter := time.Duration(checkJitter.Int64) * time.Millisecond
+			account.CheckJitter = &jitter
+		}
+		account.Filter.WatchedAccountID = account.ID
 		accounts = append(accounts, account)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	tags, err := d.getTags()
+	if err != nil {
+		return nil, fmt.Errorf("loading tags: %w", err)
+	}
+	for i := range accounts {
+		accounts[i].Tags = tags[accounts[i].ID]
+	}
 	return accounts, nil
 }
 
-// RemoveWatchedAccount removes a watched account
+// UsernameKey returns the form usernames are matched on; handles are
+// case-insensitive on X and Bluesky
+func UsernameKey(username string) string {
+	return strings.ToLower(username)
+}
+
+// GetWatchedAccountByUsername returns the watched account with this username, in
+// any casing, or nil if there is none
+func (d *Database) GetWatchedAccountByUsername(username string) (*WatchedAccount, error) {
+	accounts, err := d.GetWatchedAccounts()
+	if err != nil {
+		return nil, err
+	}
+	for i := range accounts {
+		if UsernameKey(accounts[i].Username) == UsernameKey(username) {
+			return &accounts[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// snapshotTables hold an account's current state, which is only useful
+// while it is watched
+var snapshotTables = []string{
+	"following",
+	"following_tombstones",
+	"following_count_samples",
+	"followers",
+	"notification_filters",
+	"notification_messages",
+	"account_tags",
+	"check_costs",
+}
+
+// historyTables hold what was recorded about an account over time, which
+// archiving keeps
+var historyTables = []string{
+	"follow_events",
+	"profile_events",
+	"lost_followers",
+	"raw_snapshots",
+	"archived_event_counts",
+}
+
+// deleteAccountRows deletes an account's rows from the given tables
+func deleteAccountRows(tx *sql.Tx, id int64, tables []string) error {
+	for _, table := range tables {
+		if _, err := tx.Exec("DELETE FROM "+table+" WHERE watched_account_id = ?", id); err != nil {
+			return fmt.Errorf("deleting from %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// RemoveWatchedAccount removes a watched account along with its snapshot
+// and its whole history
 func (d *Database) RemoveWatchedAccount(id int64) error {
 	logger.Info("Removing watched account ID: %d", id)
-	tx, err := d.db.Begin()
+	paths, err := d.archivePaths()
+	if err != nil {
+		return fmt.Errorf("listing archives: %w", err)
+	}
+
+	// The archived events go too, or an account later given the same ID
+	// would inherit them
+	ctx := context.Background()
+	conn, err := d.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	archives, err := d.openArchives(ctx, conn, paths, false)
+	if err != nil {
+		return err
+	}
+	defer d.closeArchives(ctx, conn, archives)
+
+	tx, err := conn.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
-	// Delete from following table first (foreign key constraint)
-	if _, err := tx.Exec("DELETE FROM following WHERE watched_account_id = ?", id); err != nil {
+	if err := deleteAccountRows(tx, id, snapshotTables); err != nil {
+		return err
+	}
+	if err := dropPartition(tx, id); err != nil {
 		return err
 	}
 
-	// Delete from watched_accounts
+	// Annotations belong to events, so they go before them
+	if _, err := tx.Exec(`
+		DELETE FROM event_annotations
+		WHERE event_id IN (SELECT id FROM follow_events WHERE watched_account_id = ?)`, id); err != nil {
+		return fmt.Errorf("deleting annotations: %w", err)
+	}
+	if err := deleteAccountRows(tx, id, historyTables); err != nil {
+		return err
+	}
+	for _, archive := range archives {
+		if _, err := tx.Exec(`
+			DELETE FROM `+archive+`.event_annotations
+			WHERE event_id IN (SELECT id FROM `+archive+`.follow_events WHERE watched_account_id = ?)`, id); err != nil {
+			return fmt.Errorf("deleting archived annotations: %w", err)
+		}
+		if _, err := tx.Exec("DELETE FROM "+archive+".follow_events WHERE watched_account_id = ?", id); err != nil {
+			return fmt.Errorf("deleting archived events: %w", err)
+		}
+	}
+
 	if _, err := tx.Exec("DELETE FROM watched_accounts WHERE id = ?", id); err != nil {
 		return err
 	}
 
-	return tx.Commit()
-	
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
 	logger.Info("Successfully removed account ID: %d", id)
 	return nil
 }
 
-// StoreFollowings stores multiple following relationships
+// ArchiveWatchedAccount stops watching an account but keeps its events,
+// profile changes and lost followers. Its snapshot is dropped, so adding
+// the account again takes a fresh baseline and continues the history.
+func (d *Database) ArchiveWatchedAccount(id int64) error {
+	logger.Info("Archiving watched account ID: %d", id)
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := deleteAccountRows(tx, id, snapshotTables); err != nil {
+		return err
+	}
+	if err := dropPartition(tx, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE watched_accounts SET archived_at = ? WHERE id = ?", time.Now(), id); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	logger.Info("Successfully archived account ID: %d", id)
+	return nil
+}
+
+// MarkBaselined records that the account's following snapshot is in place
+func (d *Database) MarkBaselined(id int64) error {
+	_, err := d.db.Exec("UPDATE watched_accounts SET baselined_at = ? WHERE id = ?", time.Now(), id)
+	return err
+}
+
+// MarkChecked records when the account was last diffed against its snapshot
+func (d *Database) MarkChecked(id int64, at time.Time) error {
+	_, err := d.db.Exec("UPDATE watched_accounts SET last_checked_at = ? WHERE id = ?", at, id)
+	return err
+}
+
+// SetAccountStatus records whether the account can currently be viewed
+func (d *Database) SetAccountStatus(id int64, status AccountStatus, at time.Time) error {
+	_, err := d.db.Exec("UPDATE watched_accounts SET status = ?, status_changed_at = ? WHERE id = ?", status, at, id)
+	return err
+}
+
+// SetFollowingDrift flags the account with a following count mismatch, or
+// clears the flag when drift is nil
+func (d *Database) SetFollowingDrift(id int64, drift *FollowingDrift) error {
+	if drift == nil {
+		_, err := d.db.Exec(`
+			UPDATE watched_accounts
+			SET drift_reported = NULL, drift_fetched = NULL, drift_detected_at = NULL
+			WHERE id = ?`, id)
+		return err
+	}
+
+	_, err := d.db.Exec(`
+		UPDATE watched_accounts
+		SET drift_reported = ?, drift_fetched = ?, drift_detected_at = ?
+		WHERE id = ?`, drift.Reported, drift.Fetched, drift.DetectedAt, id)
+	return err
+}
+
+// SetPaused pauses or resumes checks of an account
+func (d *Database) SetPaused(id int64, paused bool) error {
+	var pausedAt interface{}
+	if paused {
+		pausedAt = time.Now()
+	}
+	_, err := d.db.Exec("UPDATE watched_accounts SET paused_at = ? WHERE id = ?", pausedAt, id)
+	return err
+}
+
+// SetAlias sets the name the user knows an account by; an empty alias
+// removes it
+func (d *Database) SetAlias(id int64, alias string) error {
+	var value interface{}
+	if alias != "" {
+		value = alias
+	}
+	_, err := d.db.Exec("UPDATE watched_accounts SET alias = ? WHERE id = ?", value, id)
+	return err
+}
+
+// SetCheckJitter overrides the check jitter of an account; nil restores
+// the configured default
+func (d *Database) SetCheckJitter(id int64, jitter *time.Duration) error {
+	var value interface{}
+	if jitter != nil {
+		value = jitter.Milliseconds()
+	}
+	_, err := d.db.Exec("UPDATE watched_accounts SET check_jitter_ms = ? WHERE id = ?", value, id)
+	return err
+}
+
+// SetNotificationFilter saves the notification filter for an account,
+// removing it entirely when it no longer filters anything
+func (d *Database) SetNotificationFilter(filter *NotificationFilter) error {
+	logger.Info("Setting notification filter for account ID %d: min followers %d, verified only %t",
+		filter.WatchedAccountID, filter.MinFollowers, filter.VerifiedOnly)
+
+	if !filter.Active() {
+		_, err := d.db.Exec("DELETE FROM notification_filters WHERE watched_account_id = ?", filter.WatchedAccountID)
+		return err
+	}
+
+	_, err := d.db.Exec(`
+		INSERT INTO notification_filters (watched_account_id, min_followers, verified_only)
+		VALUES (?, ?, ?)
+		ON CONFLICT(watched_account_id) DO UPDATE SET
+			min_followers = excluded.min_followers,
+			verified_only = excluded.verified_only`,
+		filter.WatchedAccountID, filter.MinFollowers, filter.VerifiedOnly)
+	return err
+}
+
+// StoreFollowings stores multiple following relationships, replacing the
+// ones stored before. The list is compared with the stored one in SQL, so
+// only the changes are written.
 func (d *Database) StoreFollowings(watchedAccountID int64, followingIDs []string) error {
+	stage, err := d.StageFollowings(watchedAccountID)
+	if err != nil {
+		return err
+	}
+	defer stage.Close()
+
+	if err := stage.Add(followingIDs); err != nil {
+		return err
+	}
+	diff, err := stage.Diff()
+	if err != nil {
+		return err
+	}
+	return d.ApplyFollowingChanges(watchedAccountID, diff.Follows, diff.Unfollows, diff.Fetched)
+}
+
+// ApplyFollowingChanges updates the stored followings by an already computed
+// diff, for callers that never hold the whole following list. total is the
+// number of followings after the change.
+func (d *Database) ApplyFollowingChanges(watchedAccountID int64, follows, unfollows []string, total int) error {
 	tx, err := d.db.Begin()
 	if err != nil {
 		return fmt.Errorf("beginning transaction: %w", err)
 	}
 	defer tx.Rollback()
 
-	// Get current followings
-	currentFollowings, err := d.GetCurrentFollowings(watchedAccountID)
-	if err != nil {
-		return fmt.Errorf("getting current followings: %w", err)
+	if err := d.applyFollowingChanges(tx, watchedAccountID, follows, unfollows, total); err != nil {
+		return err
 	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+
+	logger.Info("Updated following relationships for account ID %d", watchedAccountID)
+	return nil
+}
 
-	// Create map of new followings for efficient lookup
-	newFollowingsMap := make(map[string]bool)
-	for _, id := range followingIDs {
-		newFollowingsMap[id] = true
+func (d *Database) applyFollowingChanges(tx *sql.Tx, watchedAccountID int64, follows, unfollows []string, total int) error {
+	now := time.Now()
+	table, err := d.followingTable(tx, watchedAccountID)
+	if err != nil {
+		return err
+	}
+	if err := d.differ.removeFollowings(tx, table, watchedAccountID, unfollows, now); err != nil {
+		return err
+	}
+	if err := d.differ.addFollowings(tx, table, watchedAccountID, follows, now); err != nil {
+		return err
 	}
+	return d.partitionIfLarge(tx, watchedAccountID, table, total)
+}
 
-	// Find and delete unfollows
-	for id := range currentFollowings {
-		if !newFollowingsMap[id] {
-			_, err = tx.Exec("DELETE FROM following WHERE watched_account_id = ? AND followed_user_id = ?", 
-				watchedAccountID, id)
-			if err != nil {
-				return fmt.Errorf("deleting unfollow %s: %w", id, err)
-			}
-			logger.Info("Removed following relationship: account %d -> user %s", watchedAccountID, id)
-		}
+// ReplaceFollowings swaps the account's whole following snapshot for ids
+// and marks it baselined, without recording any follow events
+func (d *Database) ReplaceFollowings(watchedAccountID int64, followingIDs []string) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	// Insert only new follows
-	stmt, err := tx.Prepare(`
-		INSERT OR IGNORE INTO following 
-		(watched_account_id, followed_user_id)
-		VALUES (?, ?)
-	`)
+	table, err := d.followingTable(tx, watchedAccountID)
 	if err != nil {
-		return fmt.Errorf("preparing statement: %w", err)
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM "+table+" WHERE watched_account_id = ?", watchedAccountID); err != nil {
+		return fmt.Errorf("clearing followings: %w", err)
 	}
-	defer stmt.Close()
 
-	// Insert each new following relationship
-	for _, id := range followingIDs {
-		if !currentFollowings[id] {
-			_, err := stmt.Exec(watchedAccountID, id)
-			if err != nil {
-				return fmt.Errorf("inserting new follow %s: %w", id, err)
-			}
-			//logger.Info("Added new following relationship: account %d -> user %s", watchedAccountID, id)
-		}
+	// A replaced snapshot re-follows no one, so tombstones are left alone
+	if err := (deleteDiffer{}).addFollowings(tx, table, watchedAccountID, followingIDs, time.Now()); err != nil {
+		return err
+	}
+	if err := d.partitionIfLarge(tx, watchedAccountID, table, len(followingIDs)); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("UPDATE watched_accounts SET baselined_at = ? WHERE id = ?", time.Now(), watchedAccountID); err != nil {
+		return fmt.Errorf("marking baseline: %w", err)
 	}
 
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("committing transaction: %w", err)
 	}
 
-	logger.Info("Updated following relationships for account ID %d", watchedAccountID)
+	logger.Info("Replaced following snapshot for account ID %d with %d IDs", watchedAccountID, len(followingIDs))
 	return nil
 }
 
 // GetCurrentFollowings gets all current following IDs for an account
 func (d *Database) GetCurrentFollowings(watchedAccountID int64) (map[string]bool, error) {
+	table, err := d.followingTable(d.db, watchedAccountID)
+	if err != nil {
+		return nil, err
+	}
 	rows, err := d.db.Query(
-		"SELECT followed_user_id FROM following WHERE watched_account_id = ?",
+		"SELECT followed_user_id FROM "+table+" WHERE watched_account_id = ?",
 		watchedAccountID)
 	if err != nil {
 		return nil, err
@@ -226,50 +969,145 @@ func (d *Database) GetCurrentFollowings(watchedAccountID int64) (map[string]bool
 	return followings, nil
 }
 
-// StoreFollowEvents records follow/unfollow events
-func (d *Database) StoreFollowEvents(watchedAccountID int64, follows, unfollows []string) error {
+// GetFollowingCounts returns the number of stored followings per account
+func (d *Database) GetFollowingCounts() (map[int64]int, error) {
+	rows, err := d.db.Query(
+		"SELECT watched_account_id, COUNT(*) FROM following GROUP BY watched_account_id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[int64]int)
+	for rows.Next() {
+		var id int64
+		var count int
+		if err := rows.Scan(&id, &count); err != nil {
+			return nil, err
+		}
+		counts[id] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	partitioned, err := d.partitionedAccounts()
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range partitioned {
+		var count int
+		if err := d.db.QueryRow("SELECT COUNT(*) FROM " + partitionTable(id)).Scan(&count); err != nil {
+			return nil, err
+		}
+		counts[id] = count
+	}
+	return counts, nil
+}
+
+// StoreFollowEvents records follow/unfollow events and returns them, each
+// with the UUID it was given
+func (d *Database) StoreFollowEvents(watchedAccountID int64, follows, unfollows []string) ([]FollowEvent, error) {
 	tx, err := d.db.Begin()
 	if err != nil {
-		return fmt.Errorf("beginning transaction: %w", err)
+		return nil, fmt.Errorf("beginning transaction: %w", err)
 	}
 	defer tx.Rollback()
 
-	stmt, err := tx.Prepare(`
-		INSERT INTO follow_events 
-		(watched_account_id, user_id, event_type, detected_at)
-		VALUES (?, ?, ?, ?)
-	`)
+	events, err := d.storeFollowEvents(tx, watchedAccountID, follows, unfollows)
 	if err != nil {
-		return fmt.Errorf("preparing statement: %w", err)
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("committing transaction: %w", err)
 	}
-	defer stmt.Close()
 
-	now := time.Now()
+	logger.Info("Successfully stored %d follow and %d unfollow events", len(follows), len(unfollows))
+	return events, nil
+}
 
-	// Store new follows
-	for _, userID := range follows {
-		_, err := stmt.Exec(watchedAccountID, userID, EventTypeFollow, now)
+// RecordedChanges is what RecordChanges stored for a check
+type RecordedChanges struct {
+	Events    []FollowEvent // the new events, each with its UUID
+	Flapped   []FollowEvent // the events changes were counted as flaps of
+	Follows   []string      // the follows stored as events
+	Unfollows []string      // the unfollows stored as events
+}
+
+// RecordChanges stores the changes a check found in one transaction:
+// changes reversing an event detected or last flapped since flapSince are
+// counted as flaps of it (none are with a zero flapSince), the others are
+// stored as events, and the followings are updated, total being their
+// number afterwards. Nothing is stored if any of it fails, so the next
+// check finding the same changes can't count a flap twice.
+func (d *Database) RecordChanges(watchedAccountID int64, follows, unfollows []string, total int, flapSince time.Time) (*RecordedChanges, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	recorded := &RecordedChanges{Follows: follows, Unfollows: unfollows}
+	if !flapSince.IsZero() {
+		recorded.Flapped, recorded.Follows, recorded.Unfollows, err = recordFlaps(tx, watchedAccountID, follows, unfollows, flapSince)
 		if err != nil {
-			return fmt.Errorf("inserting follow event for %s: %w", userID, err)
+			return nil, fmt.Errorf("recording flaps: %w", err)
 		}
-		logger.Info("Stored follow event for account %d: following %s", watchedAccountID, userID)
+	}
+	if recorded.Events, err = d.storeFollowEvents(tx, watchedAccountID, recorded.Follows, recorded.Unfollows); err != nil {
+		return nil, fmt.Errorf("storing follow events: %w", err)
+	}
+	if err := d.applyFollowingChanges(tx, watchedAccountID, follows, unfollows, total); err != nil {
+		return nil, fmt.Errorf("updating followings: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("committing transaction: %w", err)
 	}
 
-	// Store unfollows
-	for _, userID := range unfollows {
-		_, err := stmt.Exec(watchedAccountID, userID, EventTypeUnfollow, now)
+	logger.Info("Stored %d follow and %d unfollow events and updated the followings of account ID %d",
+		len(recorded.Follows), len(recorded.Unfollows), watchedAccountID)
+	return recorded, nil
+}
+
+// storeFollowEvents inserts follow/unfollow events in tx
+func (d *Database) storeFollowEvents(tx *sql.Tx, watchedAccountID int64, follows, unfollows []string) ([]FollowEvent, error) {
+	// Postgres keeps microseconds, so the events returned match the stored ones
+	now := time.Now().Truncate(time.Microsecond)
+	events := make([]FollowEvent, 0, len(follows)+len(unfollows))
+	store := func(userID string, eventType EventType) error {
+		var err error
+		event := FollowEvent{WatchedAccountID: watchedAccountID, UserID: userID, EventType: eventType, DetectedAt: now}
+		if event.UUID, err = newUUID(); err != nil {
+			return err
+		}
+		event.ID, err = d.dialect.insert(context.Background(), tx, `
+			INSERT INTO follow_events
+			(watched_account_id, user_id, event_type, detected_at, uuid)
+			VALUES (?, ?, ?, ?, ?)`,
+			watchedAccountID, userID, eventType, now, event.UUID)
 		if err != nil {
-			return fmt.Errorf("inserting unfollow event for %s: %w", userID, err)
+			return err
 		}
-		logger.Info("Stored unfollow event for account %d: unfollowed %s", watchedAccountID, userID)
+		events = append(events, event)
+		return nil
 	}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("committing transaction: %w", err)
+	// Store new follows
+	for _, userID := range follows {
+		if err := store(userID, EventTypeFollow); err != nil {
+			return nil, fmt.Errorf("inserting follow event for %s: %w", userID, err)
+		}
+		logger.Debug("Stored follow event for account %d: following %s", watchedAccountID, userID)
 	}
 
-	logger.Info("Successfully stored %d follow and %d unfollow events", len(follows), len(unfollows))
-	return nil
+	// Store unfollows
+	for _, userID := range unfollows {
+		if err := store(userID, EventTypeUnfollow); err != nil {
+			return nil, fmt.Errorf("inserting unfollow event for %s: %w", userID, err)
+		}
+		logger.Debug("Stored unfollow event for account %d: unfollowed %s", watchedAccountID, userID)
+	}
+	return events, nil
 }
 
 // ProcessFollowingChanges detects and stores following changes
@@ -283,33 +1121,7 @@ func (d *Database) ProcessFollowingChanges(account *WatchedAccount, newFollowing
 	logger.Info("Current followings in DB for %s: %d, New followings from API: %d", 
 		account.Username, len(currentFollowings), len(newFollowingIDs))
 
-	// Track changes
-	var newFollows []string
-	newFollowingsMap := make(map[string]bool)
-
-	// Debug: Log all current following IDs
-	//logger.Info("Current following IDs in DB for %s: %v", account.Username, currentFollowings)
-	
-	// Debug: Log all new following IDs
-	//logger.Info("New following IDs from API for %s: %v", account.Username, newFollowingIDs)
-
-	// Find new follows
-	for _, id := range newFollowingIDs {
-		newFollowingsMap[id] = true
-		if !currentFollowings[id] {
-			logger.Info("Found new follow: %s", id)
-			newFollows = append(newFollows, id)
-		}
-	}
-
-	// Find unfollows
-	var unfollows []string
-	for id := range currentFollowings {
-		if !newFollowingsMap[id] {
-			logger.Info("Found unfollow: %s", id)
-			unfollows = append(unfollows, id)
-		}
-	}
+	newFollows, unfollows := DiffFollowings(currentFollowings, newFollowingIDs)
 
 	// If there are changes, store them
 	if len(newFollows) > 0 || len(unfollows) > 0 {
@@ -317,7 +1129,7 @@ func (d *Database) ProcessFollowingChanges(account *WatchedAccount, newFollowing
 			account.Username, len(newFollows), len(unfollows))
 
 		// First store the events
-		if err := d.StoreFollowEvents(account.ID, newFollows, unfollows); err != nil {
+		if _, err := d.StoreFollowEvents(account.ID, newFollows, unfollows); err != nil {
 			return fmt.Errorf("storing follow events: %w", err)
 		}
 
@@ -0,0 +1,79 @@
+package db
+
+import (
+	"fmt"
+)
+
+// SchemaVersion returns the database's schema version and the newest one
+// this build knows, which differ only if migrating failed
+func (d *Database) SchemaVersion() (current, latest int, err error) {
+	if current, err = d.dialect.version(d.db); err != nil {
+		return 0, 0, err
+	}
+	return current, len(migrations), nil
+}
+
+// Tables returns the names of the tables in the database
+func (d *Database) Tables() ([]string, error) {
+	rows, err := d.db.Query(d.dialect.objects("table"), "%")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		tables = append(tables, name)
+	}
+	return tables, rows.Err()
+}
+
+// CountRows returns the number of rows in a table. The name must come from
+// Tables, it is not escaped.
+func (d *Database) CountRows(table string) (int, error) {
+	var count int
+	err := d.db.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&count)
+	return count, err
+}
+
+// SampleRows returns up to limit rows of a table as column name to value.
+// The name must come from Tables, it is not escaped.
+func (d *Database) SampleRows(table string, limit int) ([]map[string]interface{}, error) {
+	rows, err := d.db.Query("SELECT * FROM "+table+" LIMIT ?", limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	sample := make([]map[string]interface{}, 0, limit)
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, fmt.Errorf("reading %s: %w", table, err)
+		}
+
+		row := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			// TEXT columns may come back as bytes
+			if b, ok := values[i].([]byte); ok {
+				values[i] = string(b)
+			}
+			row[column] = values[i]
+		}
+		sample = append(sample, row)
+	}
+	return sample, rows.Err()
+}
@@ -0,0 +1,63 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// maxDeliveries is how many notification attempts are kept
+const maxDeliveries = 5000
+
+// RecordDelivery stores a notification attempt and prunes old ones
+func (d *Database) RecordDelivery(delivery *Delivery) error {
+	var deliveryErr sql.NullString
+	if delivery.Error != "" {
+		deliveryErr = sql.NullString{String: delivery.Error, Valid: true}
+	}
+	id, err := d.dialect.insert(context.Background(), d.db, `
+		INSERT INTO notification_log (attempted_at, channel, account, kind, summary, status_code, latency_ms, error)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		delivery.AttemptedAt, delivery.Channel, delivery.Account, delivery.Kind, delivery.Summary,
+		delivery.StatusCode, delivery.Latency.Milliseconds(), deliveryErr)
+	if err != nil {
+		return fmt.Errorf("storing notification delivery: %w", err)
+	}
+	delivery.ID = id
+
+	if _, err := d.db.Exec(`DELETE FROM notification_log WHERE id <= ?`, delivery.ID-maxDeliveries); err != nil {
+		return fmt.Errorf("pruning notification log: %w", err)
+	}
+	return nil
+}
+
+// GetDeliveries returns the most recent notification attempts, newest
+// first, only the failed ones if failedOnly is set
+func (d *Database) GetDeliveries(limit int, failedOnly bool) ([]Delivery, error) {
+	rows, err := d.db.Query(`
+		SELECT id, attempted_at, channel, account, kind, summary, status_code, latency_ms, error
+		FROM notification_log
+		WHERE NOT ? OR error IS NOT NULL
+		ORDER BY id DESC
+		LIMIT ?`, failedOnly, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []Delivery
+	for rows.Next() {
+		var delivery Delivery
+		var latencyMs int64
+		var deliveryErr sql.NullString
+		if err := rows.Scan(&delivery.ID, &delivery.AttemptedAt, &delivery.Channel, &delivery.Account,
+			&delivery.Kind, &delivery.Summary, &delivery.StatusCode, &latencyMs, &deliveryErr); err != nil {
+			return nil, err
+		}
+		delivery.Latency = time.Duration(latencyMs) * time.Millisecond
+		delivery.Error = deliveryErr.String
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, rows.Err()
+}
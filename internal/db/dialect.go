@@ -0,0 +1,247 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// dialect is what differs between the SQL databases a Database can keep
+// its data in. Queries are written with ? placeholders for both.
+type dialect interface {
+	// schema creates the tables a fresh database starts with
+	schema() string
+	// migrate brings the schema up to the latest version
+	migrate(db *sql.DB) error
+	// version returns the schema version of the database
+	version(q querier) (int, error)
+	// objects returns a query listing the tables, or views with kind
+	// "view", whose name is LIKE its one argument
+	objects(kind string) string
+	// insert runs an INSERT into a table with an id column and returns the
+	// id of the new row
+	insert(ctx context.Context, q inserter, query string, args ...any) (int64, error)
+
+	// utcDay and localDay return an expression formatting a timestamp as
+	// YYYY-MM-DD in UTC and in the local timezone
+	utcDay(column string) string
+	localDay(column string) string
+	// joined returns an aggregate joining the values of expr with commas
+	joined(expr string, distinct bool) string
+	// unixTime returns an expression converting a timestamp, or a
+	// YYYY-MM-DD day with day set, to Unix seconds
+	unixTime(expr string, day bool) string
+	// seconds returns an expression for the seconds between two timestamps,
+	// to a tenth
+	seconds(from, to string) string
+
+	// partition creates the following table of one account
+	partition(table string) string
+	// stage creates the temporary table a FollowingStage collects IDs in,
+	// and unstage drops it
+	stage() string
+	unstage() string
+
+	// openArchive makes the cold archive available on conn under the
+	// returned schema name; closeArchive undoes it
+	openArchive(ctx context.Context, conn *sql.Conn, archive string) (string, error)
+	closeArchive(ctx context.Context, conn *sql.Conn, schema string)
+	// readArchive is openArchive for an archive that already exists,
+	// suggesting name as its schema; it returns "" when there is none
+	readArchive(ctx context.Context, conn *sql.Conn, archive, name string) (string, error)
+	// columns returns the columns of a table, in order, in the archive
+	// schema or with an empty schema in the main one
+	columns(ctx context.Context, conn *sql.Conn, schema, table string) ([]tableColumn, error)
+	// archiveIndex creates the index of the archived events
+	archiveIndex(schema string) string
+
+	// backup writes a copy of the database to path
+	backup(db *sql.DB, path string) error
+	// checkPages adds the damage found in the database's own storage to
+	// report, and the rows violating a foreign key to its orphans
+	checkPages(db *sql.DB, report *IntegrityReport) error
+}
+
+// inserter is what dialect.insert needs from a database, transaction or
+// connection
+type inserter interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// sqliteDialect keeps the data in a SQLite file
+type sqliteDialect struct{}
+
+func (sqliteDialect) schema() string {
+	return schema
+}
+
+func (sqliteDialect) migrate(db *sql.DB) error {
+	return migrate(db)
+}
+
+func (sqliteDialect) version(q querier) (int, error) {
+	var version int
+	err := q.QueryRow("PRAGMA user_version").Scan(&version)
+	return version, err
+}
+
+func (sqliteDialect) objects(kind string) string {
+	return "SELECT name FROM sqlite_master WHERE type = '" + kind + "' AND name LIKE ? AND name NOT LIKE 'sqlite_%' ORDER BY name"
+}
+
+func (sqliteDialect) insert(ctx context.Context, q inserter, query string, args ...any) (int64, error) {
+	result, err := q.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+func (sqliteDialect) utcDay(column string) string {
+	return "date(" + column + ")"
+}
+
+func (sqliteDialect) localDay(column string) string {
+	return "date(" + column + ", 'localtime')"
+}
+
+func (sqliteDialect) joined(expr string, distinct bool) string {
+	// SQLite only takes DISTINCT with the default separator, a comma
+	if distinct {
+		return "group_concat(DISTINCT " + expr + ")"
+	}
+	return "group_concat(" + expr + ", ',')"
+}
+
+func (sqliteDialect) unixTime(expr string, day bool) string {
+	return "CAST(strftime('%s', " + expr + ") AS INTEGER)"
+}
+
+func (sqliteDialect) seconds(from, to string) string {
+	return "ROUND((julianday(" + to + ") - julianday(" + from + ")) * 86400, 1)"
+}
+
+func (sqliteDialect) partition(table string) string {
+	return `
+		CREATE TABLE ` + table + ` (
+		    watched_account_id INTEGER NOT NULL,
+		    followed_user_id TEXT NOT NULL,
+		    PRIMARY KEY (watched_account_id, followed_user_id)
+		) WITHOUT ROWID`
+}
+
+func (sqliteDialect) stage() string {
+	return `
+		CREATE TEMP TABLE IF NOT EXISTS staged_followings (
+		    pos INTEGER PRIMARY KEY,
+		    user_id TEXT NOT NULL UNIQUE
+		)`
+}
+
+func (sqliteDialect) unstage() string {
+	return "DROP TABLE IF EXISTS temp.staged_followings"
+}
+
+// openArchive attaches the SQLite file at archive, creating it if needed.
+// ATTACH only applies to the connection it runs on and can't run in a
+// transaction.
+func (sqliteDialect) openArchive(ctx context.Context, conn *sql.Conn, archive string) (string, error) {
+	if _, err := conn.ExecContext(ctx, "ATTACH DATABASE ? AS cold", archive); err != nil {
+		return "", err
+	}
+	return "cold", nil
+}
+
+func (sqliteDialect) closeArchive(ctx context.Context, conn *sql.Conn, schema string) {
+	conn.ExecContext(ctx, "DETACH DATABASE "+schema)
+}
+
+// readArchive checks for the file first, as ATTACH would create it
+func (sqliteDialect) readArchive(ctx context.Context, conn *sql.Conn, archive, name string) (string, error) {
+	if _, err := os.Stat(archive); errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	if _, err := conn.ExecContext(ctx, "ATTACH DATABASE ? AS "+name, archive); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+func (sqliteDialect) columns(ctx context.Context, conn *sql.Conn, schema, table string) ([]tableColumn, error) {
+	if schema == "" {
+		schema = "main"
+	}
+	rows, err := conn.QueryContext(ctx, fmt.Sprintf("PRAGMA %s.table_info(%s)", schema, table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []tableColumn
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, kind string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &name, &kind, &notNull, &defaultValue, &pk); err != nil {
+			return nil, err
+		}
+		columns = append(columns, tableColumn{name, kind})
+	}
+	return columns, rows.Err()
+}
+
+func (sqliteDialect) archiveIndex(schema string) string {
+	return `
+		CREATE INDEX IF NOT EXISTS ` + schema + `.idx_follow_events_account
+		ON follow_events(watched_account_id, detected_at)`
+}
+
+func (sqliteDialect) backup(db *sql.DB, path string) error {
+	_, err := db.Exec("VACUUM INTO ?", path)
+	return err
+}
+
+// checkPages runs SQLite's integrity check over the whole file, reading
+// every page, and its foreign key check
+func (sqliteDialect) checkPages(db *sql.DB, report *IntegrityReport) error {
+	rows, err := db.Query("PRAGMA integrity_check(" + strconv.Itoa(integrityProblemLimit) + ")")
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var message string
+		if err := rows.Scan(&message); err != nil {
+			rows.Close()
+			return err
+		}
+		if message != "ok" {
+			report.Problems = append(report.Problems, message)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// foreign_key_check works whether or not foreign keys are enforced
+	rows, err = db.Query("SELECT \"table\", COUNT(*) FROM pragma_foreign_key_check GROUP BY \"table\"")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var table string
+		var count int
+		if err := rows.Scan(&table, &count); err != nil {
+			return err
+		}
+		report.Orphans[table] = count
+	}
+	return rows.Err()
+}
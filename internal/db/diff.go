@@ -0,0 +1,166 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"x-tracker/internal/logger"
+)
+
+// Diff modes selectable with SetDiffMode
+const (
+	DiffModeDelete    = "delete"    // unfollowed IDs are simply removed from the snapshot
+	DiffModeTombstone = "tombstone" // unfollowed IDs leave a tombstone with counts and last-seen time
+)
+
+// differ applies a computed diff to the stored following snapshot, kept
+// in table
+type differ interface {
+	removeFollowings(tx *sql.Tx, table string, watchedAccountID int64, userIDs []string, now time.Time) error
+	addFollowings(tx *sql.Tx, table string, watchedAccountID int64, userIDs []string, now time.Time) error
+}
+
+// newDiffer returns the differ for a mode
+func newDiffer(mode string) (differ, error) {
+	switch mode {
+	case "", DiffModeDelete:
+		return deleteDiffer{}, nil
+	case DiffModeTombstone:
+		return tombstoneDiffer{}, nil
+	}
+	return nil, fmt.Errorf("unknown diff mode %q", mode)
+}
+
+// DiffFollowings compares a fetched following list with the stored
+// followings: follows are fetched but not stored, in the order fetched,
+// and unfollows stored but not fetched, sorted. It is the in-memory
+// counterpart of FollowingStage, for lists small enough to hold.
+func DiffFollowings(stored map[string]bool, fetched []string) (follows, unfollows []string) {
+	seen := make(map[string]bool, len(fetched))
+	for _, id := range fetched {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if !stored[id] {
+			follows = append(follows, id)
+		}
+	}
+	for id := range stored {
+		if !seen[id] {
+			unfollows = append(unfollows, id)
+		}
+	}
+	sort.Strings(unfollows)
+	return follows, unfollows
+}
+
+// SetDiffMode selects how unfollows are applied to the snapshot
+func (d *Database) SetDiffMode(mode string) error {
+	differ, err := newDiffer(mode)
+	if err != nil {
+		return err
+	}
+	d.differ = differ
+	logger.Info("Using %s diff mode", mode)
+	return nil
+}
+
+// deleteDiffer keeps only the current following set
+type deleteDiffer struct{}
+
+func (deleteDiffer) removeFollowings(tx *sql.Tx, table string, watchedAccountID int64, userIDs []string, now time.Time) error {
+	removed, err := execBatched(tx, userIDs, func(n int) string {
+		return "DELETE FROM " + table + " WHERE watched_account_id = ? AND followed_user_id IN (" + repeatJoined("?", n) + ")"
+	}, accountAndIDs(watchedAccountID))
+	if err != nil {
+		return fmt.Errorf("deleting unfollows: %w", err)
+	}
+	logger.Debug("Removed %d following relationships of account %d", removed, watchedAccountID)
+	return nil
+}
+
+func (deleteDiffer) addFollowings(tx *sql.Tx, table string, watchedAccountID int64, userIDs []string, now time.Time) error {
+	if _, err := execBatched(tx, userIDs, func(n int) string {
+		return "INSERT INTO " + table + " (watched_account_id, followed_user_id) VALUES " + repeatJoined("(?, ?)", n) + " ON CONFLICT DO NOTHING"
+	}, withAccount(watchedAccountID)); err != nil {
+		return fmt.Errorf("inserting new follows: %w", err)
+	}
+	return nil
+}
+
+// tombstoneDiffer additionally remembers every unfollowed ID, counting
+// unfollows and re-follows, so repeat behaviour stays visible
+type tombstoneDiffer struct {
+	deleteDiffer
+}
+
+func (t tombstoneDiffer) removeFollowings(tx *sql.Tx, table string, watchedAccountID int64, userIDs []string, now time.Time) error {
+	if err := t.deleteDiffer.removeFollowings(tx, table, watchedAccountID, userIDs, now); err != nil {
+		return err
+	}
+
+	if _, err := execBatched(tx, userIDs, func(n int) string {
+		return `
+		INSERT INTO following_tombstones
+		(watched_account_id, followed_user_id, unfollow_count, refollow_count, last_seen_at)
+		VALUES ` + repeatJoined("(?, ?, 1, 0, ?)", n) + `
+		ON CONFLICT(watched_account_id, followed_user_id) DO UPDATE SET
+			unfollow_count = following_tombstones.unfollow_count + 1,
+			last_seen_at = excluded.last_seen_at`
+	}, withAccount(watchedAccountID, now)); err != nil {
+		return fmt.Errorf("storing tombstones: %w", err)
+	}
+	return nil
+}
+
+func (t tombstoneDiffer) addFollowings(tx *sql.Tx, table string, watchedAccountID int64, userIDs []string, now time.Time) error {
+	if err := t.deleteDiffer.addFollowings(tx, table, watchedAccountID, userIDs, now); err != nil {
+		return err
+	}
+
+	refollowed, err := execBatched(tx, userIDs, func(n int) string {
+		return `
+		UPDATE following_tombstones SET refollow_count = refollow_count + 1
+		WHERE watched_account_id = ? AND followed_user_id IN (` + repeatJoined("?", n) + ")"
+	}, accountAndIDs(watchedAccountID))
+	if err != nil {
+		return fmt.Errorf("updating tombstones: %w", err)
+	}
+	if refollowed > 0 {
+		logger.Info("Account %d re-followed %d previously unfollowed users", watchedAccountID, refollowed)
+	}
+	return nil
+}
+
+// GetTombstones returns the tombstones of an account keyed by user ID,
+// limited to userIDs when given
+func (d *Database) GetTombstones(watchedAccountID int64, userIDs []string) (map[string]Tombstone, error) {
+	rows, err := d.db.Query(`
+		SELECT followed_user_id, unfollow_count, refollow_count, last_seen_at
+		FROM following_tombstones
+		WHERE watched_account_id = ?`, watchedAccountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	wanted := make(map[string]bool, len(userIDs))
+	for _, id := range userIDs {
+		wanted[id] = true
+	}
+
+	tombstones := make(map[string]Tombstone)
+	for rows.Next() {
+		tombstone := Tombstone{WatchedAccountID: watchedAccountID}
+		if err := rows.Scan(&tombstone.UserID, &tombstone.UnfollowCount, &tombstone.RefollowCount, &tombstone.LastSeenAt); err != nil {
+			return nil, err
+		}
+		if len(userIDs) == 0 || wanted[tombstone.UserID] {
+			tombstones[tombstone.UserID] = tombstone
+		}
+	}
+	return tombstones, rows.Err()
+}
@@ -0,0 +1,301 @@
+package db
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"x-tracker/internal/logger"
+)
+
+// eventFields are the columns of follow_events read back from the cold
+// archives
+const eventFields = "id, uuid, watched_account_id, user_id, event_type, detected_at, dismissed_at, flaps, flapped_at"
+
+// eventColumns selects a follow event with its joined display fields,
+// reading through the archives open under schemas; the query must alias
+// follow_events as e
+func (d *Database) eventColumns(schemas []string) string {
+	return `
+		SELECT e.id, e.uuid, e.watched_account_id, e.user_id, e.event_type, e.detected_at, e.dismissed_at,
+		       e.flaps, e.flapped_at,
+		       COALESCE(a.username, ''), COALESCE(t.unfollow_count, 0),
+		       COALESCE((SELECT ` + d.dialect.joined("label", false) + ` FROM ` + archived("event_annotations", "event_id, label", schemas) + ` n
+		                 WHERE n.event_id = e.id), '')
+		FROM ` + archived("follow_events", eventFields, schemas) + ` e
+		LEFT JOIN watched_accounts a ON a.id = e.watched_account_id
+		LEFT JOIN following_tombstones t
+		       ON t.watched_account_id = e.watched_account_id AND t.followed_user_id = e.user_id`
+}
+
+// GetRecentEvents returns the newest events across all watched accounts,
+// leaving out dismissed ones unless includeDismissed is set
+func (d *Database) GetRecentEvents(limit int, includeDismissed bool) ([]FollowEvent, error) {
+	return d.GetEvents(EventQuery{IncludeDismissed: includeDismissed, Limit: limit})
+}
+
+// GetEvents returns the events matching q, newest first, including those
+// moved to the cold archives
+func (d *Database) GetEvents(q EventQuery) ([]FollowEvent, error) {
+	var events []FollowEvent
+	err := d.withArchives(false, func(db rowsQuerier, schemas []string) error {
+		query, args := d.eventsQuery(q, schemas)
+		var err error
+		events, err = d.queryEvents(db, query, args...)
+		return err
+	})
+	return events, err
+}
+
+// eventsQuery builds the query and arguments of GetEvents
+func (d *Database) eventsQuery(q EventQuery, schemas []string) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if q.AccountID != 0 {
+		conditions = append(conditions, "e.watched_account_id = ?")
+		args = append(args, q.AccountID)
+	}
+	if q.UserID != "" {
+		conditions = append(conditions, "e.user_id = ?")
+		args = append(args, q.UserID)
+	}
+	if q.EventType != "" {
+		conditions = append(conditions, "e.event_type = ?")
+		args = append(args, q.EventType)
+	}
+	if !q.Since.IsZero() {
+		conditions = append(conditions, "e.detected_at >= ?")
+		args = append(args, q.Since)
+	}
+	if !q.Until.IsZero() {
+		conditions = append(conditions, "e.detected_at < ?")
+		args = append(args, q.Until)
+	}
+	if q.Label != "" {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM "+archived("event_annotations", "event_id, label", schemas)+" n WHERE n.event_id = e.id AND n.label = ?)")
+		args = append(args, q.Label)
+	}
+	if !q.DetectedAt.IsZero() {
+		conditions = append(conditions, "e.detected_at = ?")
+		args = append(args, q.DetectedAt)
+	}
+	if !q.IncludeDismissed {
+		conditions = append(conditions, "e.dismissed_at IS NULL")
+	}
+	if q.BatchBelow > 0 {
+		// Events of one cycle share their detection time
+		batch := `(SELECT COUNT(*) FROM ` + archived("follow_events", eventFields, schemas) + ` b
+		           WHERE b.watched_account_id = e.watched_account_id
+		             AND b.event_type = e.event_type AND b.detected_at = e.detected_at`
+		if !q.IncludeDismissed {
+			batch += " AND b.dismissed_at IS NULL"
+		}
+		conditions = append(conditions, batch+") < ?")
+		args = append(args, q.BatchBelow)
+	}
+
+	query := d.eventColumns(schemas)
+	if len(conditions) > 0 {
+		query += `
+		WHERE ` + strings.Join(conditions, " AND ")
+	}
+	query += `
+		ORDER BY e.detected_at DESC, e.id DESC`
+	if q.Limit > 0 || q.Offset > 0 {
+		limit := int64(q.Limit)
+		if limit == 0 {
+			limit = math.MaxInt64 // an offset needs a limit
+		}
+		query += `
+		LIMIT ? OFFSET ?`
+		args = append(args, limit, q.Offset)
+	}
+	return query, args
+}
+
+// queryEvents runs a query built on eventColumns and scans the events
+func (d *Database) queryEvents(db rowsQuerier, query string, args ...interface{}) ([]FollowEvent, error) {
+	rows, err := db.QueryContext(context.Background(), query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []FollowEvent
+	for rows.Next() {
+		var event FollowEvent
+		var detectedAt, dismissedAt, flappedAt archivedTime
+		var annotations string
+		if err := rows.Scan(
+			&event.ID,
+			&event.UUID,
+			&event.WatchedAccountID,
+			&event.UserID,
+			&event.EventType,
+			&detectedAt,
+			&dismissedAt,
+			&event.Flaps,
+			&flappedAt,
+			&event.AccountUsername,
+			&event.UnfollowCount,
+			&annotations); err != nil {
+			return nil, err
+		}
+		if annotations != "" {
+			event.Annotations = strings.Split(annotations, ",")
+		}
+		event.DetectedAt = detectedAt.Time
+		if dismissedAt.Valid {
+			event.DismissedAt = &dismissedAt.Time
+		}
+		if flappedAt.Valid {
+			event.FlappedAt = &flappedAt.Time
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// GetEventByUUID returns the event with the given UUID, archived or not,
+// or nil if there is none
+func (d *Database) GetEventByUUID(uuid string) (*FollowEvent, error) {
+	var events []FollowEvent
+	err := d.withArchives(false, func(db rowsQuerier, schemas []string) error {
+		var err error
+		events, err = d.queryEvents(db, d.eventColumns(schemas)+`
+		WHERE e.uuid = ?`, strings.ToLower(uuid))
+		return err
+	})
+	if err != nil || len(events) == 0 {
+		return nil, err
+	}
+	return &events[0], nil
+}
+
+// newUUID returns a random version 4 UUID
+func newUUID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("generating UUID: %w", err)
+	}
+	b[6] = b[6]&0x0f | 0x40 // version 4
+	b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+// GetEventBatches returns the newest cycles that produced at least minSize
+// events of one type for an account, counting dismissed events only when
+// includeDismissed is set
+func (d *Database) GetEventBatches(minSize, limit int, includeDismissed bool) ([]EventBatch, error) {
+	where := ""
+	if !includeDismissed {
+		where = "WHERE e.dismissed_at IS NULL"
+	}
+	rows, err := d.db.Query(`
+		SELECT e.watched_account_id, COALESCE(a.username, ''), e.event_type, e.detected_at, COUNT(*)
+		FROM follow_events e
+		LEFT JOIN watched_accounts a ON a.id = e.watched_account_id
+		`+where+`
+		GROUP BY e.watched_account_id, a.username, e.event_type, e.detected_at
+		HAVING COUNT(*) >= ?
+		ORDER BY e.detected_at DESC
+		LIMIT ?`, minSize, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var batches []EventBatch
+	for rows.Next() {
+		var batch EventBatch
+		if err := rows.Scan(&batch.WatchedAccountID, &batch.AccountUsername, &batch.EventType, &batch.DetectedAt, &batch.Count); err != nil {
+			return nil, err
+		}
+		batches = append(batches, batch)
+	}
+	return batches, rows.Err()
+}
+
+// GetLastChangeTimes returns when each watched account last followed or
+// unfollowed someone, keyed by account ID; accounts without events are left
+// out
+func (d *Database) GetLastChangeTimes() (map[int64]time.Time, error) {
+	rows, err := d.db.Query(`
+		SELECT watched_account_id, detected_at
+		FROM follow_events
+		WHERE id IN (SELECT MAX(id) FROM follow_events GROUP BY watched_account_id)`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := make(map[int64]time.Time)
+	for rows.Next() {
+		var accountID int64
+		var detectedAt time.Time
+		if err := rows.Scan(&accountID, &detectedAt); err != nil {
+			return nil, err
+		}
+		changes[accountID] = detectedAt
+	}
+	return changes, rows.Err()
+}
+
+// DismissEvent hides an event from views without deleting it
+func (d *Database) DismissEvent(id int64) error {
+	logger.Info("Dismissing event ID: %d", id)
+	_, err := d.db.Exec("UPDATE follow_events SET dismissed_at = ? WHERE id = ? AND dismissed_at IS NULL", time.Now(), id)
+	return err
+}
+
+// RestoreEvent makes a dismissed event visible again
+func (d *Database) RestoreEvent(id int64) error {
+	logger.Info("Restoring event ID: %d", id)
+	_, err := d.db.Exec("UPDATE follow_events SET dismissed_at = NULL WHERE id = ?", id)
+	return err
+}
+
+// DismissEventsByRule dismisses every visible event matching the rule and
+// returns how many were dismissed
+func (d *Database) DismissEventsByRule(rule EventRule) (int64, error) {
+	conditions := []string{"dismissed_at IS NULL"}
+	args := []interface{}{time.Now()}
+
+	if rule.AccountID != 0 {
+		conditions = append(conditions, "watched_account_id = ?")
+		args = append(args, rule.AccountID)
+	}
+	if rule.UserID != "" {
+		conditions = append(conditions, "user_id = ?")
+		args = append(args, rule.UserID)
+	}
+	if rule.EventType != "" {
+		conditions = append(conditions, "event_type = ?")
+		args = append(args, rule.EventType)
+	}
+	if !rule.Before.IsZero() {
+		conditions = append(conditions, "detected_at < ?")
+		args = append(args, rule.Before)
+	}
+	if len(conditions) == 1 {
+		return 0, fmt.Errorf("refusing to dismiss every event, add at least one condition")
+	}
+
+	result, err := d.db.Exec(
+		"UPDATE follow_events SET dismissed_at = ? WHERE "+strings.Join(conditions, " AND "),
+		args...)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	logger.Info("Dismissed %d events by rule %+v", n, rule)
+	return n, nil
+}
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"x-tracker/internal/logger"
+)
+
+// recordFlaps collapses changes that reverse the latest event about the
+// same user, when that event was detected or last flapped since the given
+// time, into that event by counting a flap instead of recording a new
+// event. It returns the flapped events and the follows and unfollows left
+// to be stored.
+func recordFlaps(tx *sql.Tx, watchedAccountID int64, follows, unfollows []string, since time.Time) (flapped []FollowEvent, remainingFollows, remainingUnfollows []string, err error) {
+	now := time.Now()
+	// flap counts the change as a flap of the latest event about userID if
+	// it reverses it within the window, reporting whether it did
+	flap := func(userID string, change EventType) (bool, error) {
+		var event FollowEvent
+		var flappedAt sql.NullTime
+		err := tx.QueryRow(`
+			SELECT id, uuid, event_type, detected_at, flaps, flapped_at
+			FROM follow_events
+			WHERE watched_account_id = ? AND user_id = ?
+			ORDER BY id DESC
+			LIMIT 1`, watchedAccountID, userID).Scan(
+			&event.ID,
+			&event.UUID,
+			&event.EventType,
+			&event.DetectedAt,
+			&event.Flaps,
+			&flappedAt)
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+
+		last := event.DetectedAt
+		if flappedAt.Valid {
+			last = flappedAt.Time
+		}
+		if last.Before(since) || event.Net() == change {
+			return false, nil
+		}
+
+		if _, err := tx.Exec("UPDATE follow_events SET flaps = flaps + 1, flapped_at = ? WHERE id = ?", now, event.ID); err != nil {
+			return false, err
+		}
+		event.WatchedAccountID = watchedAccountID
+		event.UserID = userID
+		event.Flaps++
+		event.FlappedAt = &now
+		flapped = append(flapped, event)
+		return true, nil
+	}
+
+	for _, userID := range follows {
+		ok, err := flap(userID, EventTypeFollow)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("checking follow of %s for flaps: %w", userID, err)
+		}
+		if !ok {
+			remainingFollows = append(remainingFollows, userID)
+		}
+	}
+	for _, userID := range unfollows {
+		ok, err := flap(userID, EventTypeUnfollow)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("checking unfollow of %s for flaps: %w", userID, err)
+		}
+		if !ok {
+			remainingUnfollows = append(remainingUnfollows, userID)
+		}
+	}
+
+	if len(flapped) > 0 {
+		logger.Debug("Collapsed %d flapping changes of account %d", len(flapped), watchedAccountID)
+	}
+	return flapped, remainingFollows, remainingUnfollows, nil
+}
@@ -0,0 +1,101 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// GetFollowers returns the stored followers of an account with when each
+// was first seen
+func (d *Database) GetFollowers(watchedAccountID int64) (map[string]time.Time, error) {
+	rows, err := d.db.Query(`
+		SELECT follower_user_id, first_seen_at FROM followers
+		WHERE watched_account_id = ?`, watchedAccountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	followers := make(map[string]time.Time)
+	for rows.Next() {
+		var userID string
+		var firstSeen time.Time
+		if err := rows.Scan(&userID, &firstSeen); err != nil {
+			return nil, err
+		}
+		followers[userID] = firstSeen
+	}
+	return followers, rows.Err()
+}
+
+// StoreFollowers applies a follower diff in one transaction: gained users
+// are stored as first seen at the given time, lost ones are removed and
+// recorded as lost followers
+func (d *Database) StoreFollowers(watchedAccountID int64, gained []string, lost []LostFollower, at time.Time) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, userID := range gained {
+		if _, err := tx.Exec(`
+			INSERT INTO followers (watched_account_id, follower_user_id, first_seen_at)
+			VALUES (?, ?, ?)
+			ON CONFLICT DO NOTHING`, watchedAccountID, userID, at); err != nil {
+			return fmt.Errorf("inserting follower: %w", err)
+		}
+	}
+
+	for _, follower := range lost {
+		if _, err := tx.Exec(`
+			DELETE FROM followers WHERE watched_account_id = ? AND follower_user_id = ?`,
+			watchedAccountID, follower.UserID); err != nil {
+			return fmt.Errorf("deleting follower: %w", err)
+		}
+		if _, err := tx.Exec(`
+			INSERT INTO lost_followers (watched_account_id, user_id, username, display_name, followers_count, first_seen_at, lost_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			watchedAccountID, follower.UserID, follower.Username, follower.DisplayName,
+			follower.FollowersCount, follower.FirstSeenAt, follower.LostAt); err != nil {
+			return fmt.Errorf("recording lost follower: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+	return nil
+}
+
+// GetLostFollowers returns up to limit of the most recently lost followers
+// of an account, or of every account if watchedAccountID is 0
+func (d *Database) GetLostFollowers(watchedAccountID int64, limit int) ([]LostFollower, error) {
+	rows, err := d.db.Query(`
+		SELECT l.id, l.watched_account_id, w.username, l.user_id,
+		       l.username, l.display_name, l.followers_count, l.first_seen_at, l.lost_at
+		FROM lost_followers l
+		JOIN watched_accounts w ON w.id = l.watched_account_id
+		WHERE ? = 0 OR l.watched_account_id = ?
+		ORDER BY l.lost_at DESC, l.id DESC
+		LIMIT ?`, watchedAccountID, watchedAccountID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lost []LostFollower
+	for rows.Next() {
+		var follower LostFollower
+		var username, displayName sql.NullString
+		if err := rows.Scan(&follower.ID, &follower.WatchedAccountID, &follower.AccountUsername, &follower.UserID,
+			&username, &displayName, &follower.FollowersCount, &follower.FirstSeenAt, &follower.LostAt); err != nil {
+			return nil, err
+		}
+		follower.Username = username.String
+		follower.DisplayName = displayName.String
+		lost = append(lost, follower)
+	}
+	return lost, rows.Err()
+}
@@ -0,0 +1,321 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"x-tracker/internal/format"
+)
+
+// InsightRules sets what the insights job looks for
+type InsightRules struct {
+	Since               time.Time // only events detected (or flapped) since then are considered
+	SpreeFollows        int       // follows by one account in one UTC day making a spree
+	CoordinatedAccounts int       // accounts following one user in one UTC day making it coordinated
+	ChurnChanges        int       // follows, unfollows and flaps by one account about one user making it churn
+}
+
+// RefreshInsights derives the insights from the events since rules.Since
+// and stores them. Insights seen before are updated in place; the ones
+// found for the first time are returned.
+func (d *Database) RefreshInsights(rules InsightRules, now time.Time) ([]Insight, error) {
+	var found []Insight
+	for _, derive := range []func(InsightRules) ([]Insight, error){
+		d.deriveMutuals,
+		d.deriveSprees,
+		d.deriveCoordinated,
+		d.deriveChurn,
+	} {
+		insights, err := derive(rules)
+		if err != nil {
+			return nil, err
+		}
+		found = append(found, insights...)
+	}
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var fresh []Insight
+	for _, insight := range found {
+		accounts, err := json.Marshal(insight.Accounts)
+		if err != nil {
+			return nil, fmt.Errorf("encoding accounts: %w", err)
+		}
+		userIDs, err := json.Marshal(insight.UserIDs)
+		if err != nil {
+			return nil, fmt.Errorf("encoding user IDs: %w", err)
+		}
+
+		result, err := tx.Exec(`
+			UPDATE insights SET summary = ?, accounts = ?, user_ids = ?, updated_at = ?
+			WHERE kind = ? AND key = ?`,
+			insight.Summary, string(accounts), string(userIDs), now, insight.Kind, insight.Key)
+		if err != nil {
+			return nil, fmt.Errorf("updating insight %s %s: %w", insight.Kind, insight.Key, err)
+		}
+		if n, _ := result.RowsAffected(); n > 0 {
+			continue
+		}
+
+		insight.DetectedAt, insight.UpdatedAt = now, now
+		insight.ID, err = d.dialect.insert(context.Background(), tx, `
+			INSERT INTO insights (kind, key, summary, accounts, user_ids, detected_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			insight.Kind, insight.Key, insight.Summary, string(accounts), string(userIDs), now, now)
+		if err != nil {
+			return nil, fmt.Errorf("storing insight %s %s: %w", insight.Kind, insight.Key, err)
+		}
+		fresh = append(fresh, insight)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("committing transaction: %w", err)
+	}
+	return fresh, nil
+}
+
+// GetInsights returns the insights detected since the given time, newest
+// first, of one kind unless kind is empty. limit 0 returns all of them.
+func (d *Database) GetInsights(since time.Time, kind string, limit int) ([]Insight, error) {
+	query := `
+		SELECT id, kind, key, summary, accounts, user_ids, detected_at, updated_at
+		FROM insights
+		WHERE detected_at >= ?`
+	args := []any{since}
+	if kind != "" {
+		query += " AND kind = ?"
+		args = append(args, kind)
+	}
+	query += " ORDER BY detected_at DESC, id DESC"
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var insights []Insight
+	for rows.Next() {
+		var insight Insight
+		var accounts, userIDs string
+		if err := rows.Scan(&insight.ID, &insight.Kind, &insight.Key, &insight.Summary,
+			&accounts, &userIDs, &insight.DetectedAt, &insight.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(accounts), &insight.Accounts); err != nil {
+			return nil, fmt.Errorf("decoding accounts of insight %d: %w", insight.ID, err)
+		}
+		if err := json.Unmarshal([]byte(userIDs), &insight.UserIDs); err != nil {
+			return nil, fmt.Errorf("decoding user IDs of insight %d: %w", insight.ID, err)
+		}
+		insights = append(insights, insight)
+	}
+	return insights, rows.Err()
+}
+
+// deriveMutuals finds pairs of watched accounts where one followed the
+// other recently and both now follow each other
+func (d *Database) deriveMutuals(rules InsightRules) ([]Insight, error) {
+	rows, err := d.db.Query(`
+		SELECT DISTINCT a.id, a.username, a.user_id, b.id, b.username, b.user_id
+		FROM follow_events e
+		JOIN watched_accounts a ON a.id = e.watched_account_id
+		JOIN watched_accounts b ON b.user_id = e.user_id AND b.id != a.id
+		WHERE e.event_type = ? AND e.detected_at >= ?
+		  AND a.archived_at IS NULL AND b.archived_at IS NULL`,
+		EventTypeFollow, rules.Since)
+	if err != nil {
+		return nil, fmt.Errorf("finding follows between watched accounts: %w", err)
+	}
+	type side struct {
+		id               int64
+		username, userID string
+	}
+	var pairs [][2]side
+	for rows.Next() {
+		var a, b side
+		if err := rows.Scan(&a.id, &a.username, &a.userID, &b.id, &b.username, &b.userID); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		pairs = append(pairs, [2]side{a, b})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var insights []Insight
+	for _, pair := range pairs {
+		a, b := pair[0], pair[1]
+		if b.id < a.id {
+			a, b = b, a
+		}
+		key := fmt.Sprintf("%d:%d", a.id, b.id)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		mutual := true
+		for _, edge := range [][2]side{{a, b}, {b, a}} {
+			follows, err := d.storesFollowing(edge[0].id, edge[1].userID)
+			if err != nil {
+				return nil, err
+			}
+			mutual = mutual && follows
+		}
+		if !mutual {
+			continue
+		}
+		insights = append(insights, Insight{
+			Kind:     InsightMutual,
+			Key:      key,
+			Summary:  fmt.Sprintf("@%s and @%s now follow each other", a.username, b.username),
+			Accounts: []string{a.username, b.username},
+			UserIDs:  []string{a.userID, b.userID},
+		})
+	}
+	return insights, nil
+}
+
+// storesFollowing reports whether the account's stored followings include userID
+func (d *Database) storesFollowing(watchedAccountID int64, userID string) (bool, error) {
+	table, err := d.followingTable(d.db, watchedAccountID)
+	if err != nil {
+		return false, err
+	}
+	var found int
+	err = d.db.QueryRow("SELECT 1 FROM "+table+" WHERE watched_account_id = ? AND followed_user_id = ?",
+		watchedAccountID, userID).Scan(&found)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// deriveSprees finds accounts that followed at least rules.SpreeFollows
+// users in one UTC day
+func (d *Database) deriveSprees(rules InsightRules) ([]Insight, error) {
+	if rules.SpreeFollows <= 0 {
+		return nil, nil
+	}
+	rows, err := d.db.Query(`
+		SELECT a.id, a.username, `+d.dialect.utcDay("e.detected_at")+` AS day, COUNT(*)
+		FROM follow_events e
+		JOIN watched_accounts a ON a.id = e.watched_account_id
+		WHERE e.event_type = ? AND e.detected_at >= ? AND e.dismissed_at IS NULL AND a.archived_at IS NULL
+		GROUP BY a.id, day
+		HAVING COUNT(*) >= ?`,
+		EventTypeFollow, rules.Since, rules.SpreeFollows)
+	if err != nil {
+		return nil, fmt.Errorf("finding follow sprees: %w", err)
+	}
+	defer rows.Close()
+
+	var insights []Insight
+	for rows.Next() {
+		var accountID int64
+		var username, day string
+		var follows int
+		if err := rows.Scan(&accountID, &username, &day, &follows); err != nil {
+			return nil, err
+		}
+		insights = append(insights, Insight{
+			Kind:     InsightSpree,
+			Key:      fmt.Sprintf("%d:%s", accountID, day),
+			Summary:  fmt.Sprintf("@%s followed %s accounts on %s", username, format.Number(follows), day),
+			Accounts: []string{username},
+			UserIDs:  []string{},
+		})
+	}
+	return insights, rows.Err()
+}
+
+// deriveCoordinated finds users followed by at least
+// rules.CoordinatedAccounts watched accounts in one UTC day
+func (d *Database) deriveCoordinated(rules InsightRules) ([]Insight, error) {
+	if rules.CoordinatedAccounts <= 1 {
+		return nil, nil
+	}
+	rows, err := d.db.Query(`
+		SELECT e.user_id, `+d.dialect.utcDay("e.detected_at")+` AS day, `+d.dialect.joined("a.username", true)+`
+		FROM follow_events e
+		JOIN watched_accounts a ON a.id = e.watched_account_id
+		WHERE e.event_type = ? AND e.detected_at >= ? AND e.dismissed_at IS NULL AND a.archived_at IS NULL
+		GROUP BY e.user_id, day
+		HAVING COUNT(DISTINCT a.id) >= ?`,
+		EventTypeFollow, rules.Since, rules.CoordinatedAccounts)
+	if err != nil {
+		return nil, fmt.Errorf("finding coordinated follows: %w", err)
+	}
+	defer rows.Close()
+
+	var insights []Insight
+	for rows.Next() {
+		var userID, day, usernames string
+		if err := rows.Scan(&userID, &day, &usernames); err != nil {
+			return nil, err
+		}
+		accounts := strings.Split(usernames, ",")
+		insights = append(insights, Insight{
+			Kind:     InsightCoordinated,
+			Key:      userID + ":" + day,
+			Summary:  fmt.Sprintf("%d watched accounts followed %s on %s: @%s", len(accounts), userID, day, strings.Join(accounts, ", @")),
+			Accounts: accounts,
+			UserIDs:  []string{userID},
+		})
+	}
+	return insights, rows.Err()
+}
+
+// deriveChurn finds users an account followed and unfollowed, flaps
+// included, at least rules.ChurnChanges times
+func (d *Database) deriveChurn(rules InsightRules) ([]Insight, error) {
+	if rules.ChurnChanges <= 1 {
+		return nil, nil
+	}
+	rows, err := d.db.Query(`
+		SELECT a.id, a.username, e.user_id, COUNT(*) + SUM(e.flaps)
+		FROM follow_events e
+		JOIN watched_accounts a ON a.id = e.watched_account_id
+		WHERE (e.detected_at >= ? OR e.flapped_at >= ?) AND a.archived_at IS NULL
+		GROUP BY a.id, e.user_id
+		HAVING COUNT(*) + SUM(e.flaps) >= ?`,
+		rules.Since, rules.Since, rules.ChurnChanges)
+	if err != nil {
+		return nil, fmt.Errorf("finding churn: %w", err)
+	}
+	defer rows.Close()
+
+	var insights []Insight
+	for rows.Next() {
+		var accountID int64
+		var username, userID string
+		var changes int
+		if err := rows.Scan(&accountID, &username, &userID, &changes); err != nil {
+			return nil, err
+		}
+		insights = append(insights, Insight{
+			Kind:     InsightChurn,
+			Key:      fmt.Sprintf("%d:%s", accountID, userID),
+			Summary:  fmt.Sprintf("@%s followed or unfollowed %s %d times since %s", username, userID, changes, rules.Since.UTC().Format("2006-01-02")),
+			Accounts: []string{username},
+			UserIDs:  []string{userID},
+		})
+	}
+	return insights, rows.Err()
+}
@@ -0,0 +1,50 @@
+package db
+
+import "strings"
+
+// integrityProblemLimit caps the problems integrity_check reports
+const integrityProblemLimit = 20
+
+// IntegrityReport is what CheckIntegrity found
+type IntegrityReport struct {
+	Problems []string       // damage integrity_check found, none when the file is sound
+	Orphans  map[string]int // rows per table belonging to accounts that no longer exist
+}
+
+// CheckIntegrity runs SQLite's integrity check over the whole file and
+// looks for rows left behind by removed accounts: rows violating a foreign
+// key and account following tables without their account. It reads every
+// page, so it takes a while on a large database. Postgres checks its pages
+// itself, so there only the rows left behind are looked for.
+func (d *Database) CheckIntegrity() (IntegrityReport, error) {
+	report := IntegrityReport{Orphans: make(map[string]int)}
+
+	if err := d.dialect.checkPages(d.db, &report); err != nil {
+		return report, err
+	}
+
+	// Account following tables declare no foreign key
+	tables, err := d.Tables()
+	if err != nil {
+		return report, err
+	}
+	for _, table := range tables {
+		id, ok := strings.CutPrefix(table, partitionPrefix)
+		if !ok {
+			continue
+		}
+		var exists bool
+		if err := d.db.QueryRow("SELECT EXISTS (SELECT 1 FROM watched_accounts WHERE id = ?)", id).Scan(&exists); err != nil {
+			return report, err
+		}
+		if exists {
+			continue
+		}
+		count, err := d.CountRows(table)
+		if err != nil {
+			return report, err
+		}
+		report.Orphans[table] = count
+	}
+	return report, nil
+}
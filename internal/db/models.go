@@ -7,7 +7,123 @@ import (
 type WatchedAccount struct {
 	ID       int64  `db:"id"`
 	Username string `db:"username"`
-	UserID   string `db:"user_id"`
+	UserID   string `db:"user_id"` // DID on Bluesky
+	Platform string `db:"platform"` // PlatformX or PlatformBluesky
+	AddedAt  time.Time `db:"added_at"` // zero for accounts added before this was tracked
+	BaselinedAt   time.Time `db:"baselined_at"`    // zero until the first snapshot is stored
+	LastCheckedAt time.Time `db:"last_checked_at"` // zero until the first diff has run
+	Profile       Profile
+	ProfileSeenAt time.Time `db:"profile_seen_at"` // zero until the profile is first captured
+	Status          AccountStatus `db:"status"`
+	StatusChangedAt time.Time     `db:"status_changed_at"` // zero if the status never changed
+	Drift           *FollowingDrift // nil unless the last check found a count mismatch
+	CheckJitter     *time.Duration  // nil uses the configured jitter
+	PausedAt        time.Time       // zero unless checks are paused
+	Alias           string          // empty unless set with the alias command
+	Tags            []string        // sorted
+	Filter   NotificationFilter
+}
+
+// Networks accounts can be watched on
+const (
+	PlatformX       = "x"
+	PlatformBluesky = "bluesky"
+)
+
+// FollowingDrift records a mismatch between the following count the profile
+// reports and the number of IDs pagination returned
+type FollowingDrift struct {
+	Reported   int       `db:"drift_reported"`
+	Fetched    int       `db:"drift_fetched"`
+	DetectedAt time.Time `db:"drift_detected_at"`
+}
+
+// AccountStatus tells whether a watched account can still be viewed
+type AccountStatus string
+
+const (
+	AccountStatusActive      AccountStatus = "active"
+	AccountStatusSuspended   AccountStatus = "suspended"
+	AccountStatusUnavailable AccountStatus = "unavailable" // deactivated, deleted or protected
+)
+
+// Available reports whether the account could be viewed at its last check
+func (a *WatchedAccount) Available() bool {
+	return a.Status != AccountStatusSuspended && a.Status != AccountStatusUnavailable
+}
+
+// Paused reports whether checks of the account are paused
+func (a *WatchedAccount) Paused() bool {
+	return !a.PausedAt.IsZero()
+}
+
+// HasTag reports whether the account is tagged with tag
+func (a *WatchedAccount) HasTag(tag string) bool {
+	for _, t := range a.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Profile holds the tracked profile fields of a watched account
+type Profile struct {
+	Username    string `db:"username"`
+	DisplayName string `db:"display_name"`
+	Bio         string `db:"bio"`
+	AvatarURL   string `db:"avatar_url"`
+}
+
+// ProfileField names a tracked profile field
+type ProfileField string
+
+const (
+	ProfileFieldUsername    ProfileField = "username"
+	ProfileFieldDisplayName ProfileField = "display_name"
+	ProfileFieldBio         ProfileField = "bio"
+	ProfileFieldAvatar      ProfileField = "avatar"
+)
+
+// ProfileEvent records one changed profile field
+type ProfileEvent struct {
+	ID               int64        `db:"id"`
+	WatchedAccountID int64        `db:"watched_account_id"`
+	Field            ProfileField `db:"field"`
+	OldValue         string       `db:"old_value"`
+	NewValue         string       `db:"new_value"`
+	DetectedAt       time.Time    `db:"detected_at"`
+}
+
+// LostFollower is a user who stopped following a watched account. The
+// profile fields are empty if the user couldn't be looked up.
+type LostFollower struct {
+	ID               int64     `db:"id"`
+	WatchedAccountID int64     `db:"watched_account_id"`
+	AccountUsername  string    `db:"account_username"`
+	UserID           string    `db:"user_id"`
+	Username         string    `db:"username"`
+	DisplayName      string    `db:"display_name"`
+	FollowersCount   int       `db:"followers_count"`
+	FirstSeenAt      time.Time `db:"first_seen_at"` // when the follow was first stored, not when it happened
+	LostAt           time.Time `db:"lost_at"`
+}
+
+// FollowedFor is how long the user was seen following the account
+func (f LostFollower) FollowedFor() time.Duration {
+	return f.LostAt.Sub(f.FirstSeenAt)
+}
+
+// NotificationFilter limits which targets of an account trigger notifications
+type NotificationFilter struct {
+	WatchedAccountID int64 `db:"watched_account_id"`
+	MinFollowers     int   `db:"min_followers"`  // only notify for targets with more followers than this
+	VerifiedOnly     bool  `db:"verified_only"`  // only notify for verified or blue accounts
+}
+
+// Active reports whether the filter restricts anything
+func (f NotificationFilter) Active() bool {
+	return f.MinFollowers > 0 || f.VerifiedOnly
 }
 
 type FollowedAccount struct {
@@ -15,6 +131,15 @@ type FollowedAccount struct {
 	UserID          string `db:"followed_user_id"`
 }
 
+// Tombstone remembers a user the account unfollowed (tombstone diff mode)
+type Tombstone struct {
+	WatchedAccountID int64     `db:"watched_account_id"`
+	UserID           string    `db:"followed_user_id"`
+	UnfollowCount    int       `db:"unfollow_count"`
+	RefollowCount    int       `db:"refollow_count"`
+	LastSeenAt       time.Time `db:"last_seen_at"` // when the latest unfollow was detected
+}
+
 type EventType string
 
 const (
@@ -24,8 +149,202 @@ const (
 
 type FollowEvent struct {
 	ID              int64     `db:"id"`
+	UUID            string    `db:"uuid"` // stable ID for other systems to deduplicate and refer to the event by
 	WatchedAccountID int64     `db:"watched_account_id"`
 	UserID          string    `db:"user_id"`
 	EventType       EventType `db:"event_type"`
 	DetectedAt      time.Time `db:"detected_at"`
+	DismissedAt     *time.Time `db:"dismissed_at"` // nil unless hidden from views
+	Flaps           int        `db:"flaps"`      // times the change was reversed or redone within the flap window
+	FlappedAt       *time.Time `db:"flapped_at"` // time of the latest flap, nil if none
+
+	AccountUsername string // watched account's username, filled by joins
+	UnfollowCount   int    // times the account has unfollowed this user, from tombstones
+	Annotations     []string // labels attached to the event, e.g. from chat reactions
+}
+
+// Net returns the change the event amounts to after its flaps: its own
+// type after an even number of them, the opposite after an odd one
+func (e *FollowEvent) Net() EventType {
+	if e.Flaps%2 == 0 {
+		return e.EventType
+	}
+	if e.EventType == EventTypeFollow {
+		return EventTypeUnfollow
+	}
+	return EventTypeFollow
+}
+
+// NotificationMessage links a message posted to a chat channel to the
+// events it announced, so reactions to it can be traced back
+type NotificationMessage struct {
+	MessageID        string    `db:"message_id"`
+	ChannelID        string    `db:"channel_id"`
+	WatchedAccountID int64     `db:"watched_account_id"`
+	EventType        EventType `db:"event_type"`
+	UserIDs          []string  `db:"user_ids"`
+	SentAt           time.Time `db:"sent_at"`
+}
+
+// TargetSnapshot is the first page of followings of a user some watched
+// account followed, captured for context
+type TargetSnapshot struct {
+	ID           int64     `db:"id"`
+	UserID       string    `db:"user_id"`
+	FollowingIDs []string  `db:"following_ids"`
+	TakenAt      time.Time `db:"taken_at"`
+}
+
+// CheckRun summarizes one check cycle over all watched accounts
+type CheckRun struct {
+	ID             int64     `db:"id"`
+	StartedAt      time.Time `db:"started_at"`
+	FinishedAt     time.Time `db:"finished_at"`
+	Accounts       int       `db:"accounts"`        // accounts checked
+	Failures       int       `db:"failures"`        // accounts whose check failed
+	NotifyFailures int       `db:"notify_failures"` // notifications that could not be delivered
+	QuotaRemaining int       `db:"quota_remaining"` // API requests left according to the provider
+	Error          string    `db:"error"`           // set if the cycle could not run at all
+}
+
+// CheckCost is what the check of one account cost in API calls
+type CheckCost struct {
+	CheckRunID       int64     `db:"check_run_id"`
+	WatchedAccountID int64     `db:"watched_account_id"`
+	CheckedAt        time.Time `db:"checked_at"`
+	Pages            int       `db:"pages"`   // following and follower ID pages fetched
+	Lookups          int       `db:"lookups"` // user lookups, e.g. resolving targets for notifications
+}
+
+// Calls returns the API calls the check made
+func (c CheckCost) Calls() int {
+	return c.Pages + c.Lookups
+}
+
+// AccountCost sums up the API calls an account's checks made over a period
+type AccountCost struct {
+	Username string `db:"username"`
+	Checks   int    `db:"checks"`
+	Pages    int    `db:"pages"`
+	Lookups  int    `db:"lookups"`
+}
+
+// Calls returns the API calls the checks made
+func (c AccountCost) Calls() int {
+	return c.Pages + c.Lookups
+}
+
+// Delivery is one attempt to send a notification through a channel
+type Delivery struct {
+	ID          int64         `db:"id"`
+	AttemptedAt time.Time     `db:"attempted_at"`
+	Channel     string        `db:"channel"`     // e.g. "discord", "discord (tag crypto)", "discord ops" or "telegram"
+	Account     string        `db:"account"`     // watched account username, empty for alerts about the tracker
+	Kind        string        `db:"kind"`        // what was sent, e.g. "follows" or "ops alert"
+	Summary     string        `db:"summary"`     // short description of the content
+	StatusCode  int           `db:"status_code"` // HTTP status the channel rejected it with, 0 if accepted or unreachable
+	Latency     time.Duration `db:"latency_ms"`
+	Error       string        `db:"error"` // empty if it was delivered
+}
+
+// AuditEntry records one management action, such as adding an account or
+// switching off a notification channel
+type AuditEntry struct {
+	ID     int64     `db:"id"`
+	At     time.Time `db:"at"`
+	Source string    `db:"source"` // where the action was taken: "tui", "cli", "api" or "telegram"
+	Actor  string    `db:"actor"`  // who took it: the local user, the API client's address or the Telegram sender
+	Action string    `db:"action"` // e.g. "add", "pause" or "disable channel"
+	Target string    `db:"target"` // what it was taken on, e.g. "@elonmusk" or "telegram"
+	Detail string    `db:"detail"` // anything else worth knowing, e.g. the new setting
+}
+
+// Insight kinds
+const (
+	InsightMutual      = "mutual"      // two watched accounts came to follow each other
+	InsightSpree       = "spree"       // an account followed many users in one day
+	InsightCoordinated = "coordinated" // several accounts followed the same user on one day
+	InsightChurn       = "churn"       // an account kept following and unfollowing the same user
+)
+
+// Insight is a fact derived from the recorded events by the insights job.
+// Each one is recorded once per kind and key and kept up to date after.
+type Insight struct {
+	ID         int64     `db:"id"`
+	Kind       string    `db:"kind"`
+	Key        string    `db:"key"` // identifies the insight within its kind, e.g. "12:2024-05-01"
+	Summary    string    `db:"summary"`
+	Accounts   []string  `db:"accounts"` // usernames of the watched accounts involved
+	UserIDs    []string  `db:"user_ids"` // users the insight is about
+	DetectedAt time.Time `db:"detected_at"`
+	UpdatedAt  time.Time `db:"updated_at"`
+}
+
+// RawSnapshot is a following list exactly as one fetch returned it, kept
+// compressed for auditing diffs and rebuilding baselines offline
+type RawSnapshot struct {
+	ID               int64     `db:"id"`
+	WatchedAccountID int64     `db:"watched_account_id"`
+	Username         string    `db:"username"`
+	TakenAt          time.Time `db:"taken_at"`
+	Count            int       `db:"id_count"`
+	Size             int       `db:"size"` // bytes of compressed data
+}
+
+// EventArchive is one run of moving old events to the cold archive
+type EventArchive struct {
+	ID         int64     `db:"id"`
+	ArchivedAt time.Time `db:"archived_at"`
+	Cutoff     time.Time `db:"cutoff"` // events detected before this were moved
+	Events     int       `db:"events"`
+	Path       string    `db:"path"` // the archive database they were moved to
+}
+
+// Suggestion is a user a watched account recently followed, with the other
+// watched accounts that follow the same user
+type Suggestion struct {
+	UserID     string
+	FollowedAt time.Time
+	SharedBy   []string // usernames of the other watched accounts following the user
+}
+
+// TrendingTarget is a user several watched accounts followed recently
+type TrendingTarget struct {
+	UserID         string
+	Score          float64  // follows weighted by the selectivity of the accounts making them
+	FollowedBy     []string // usernames of the watched accounts that followed the user
+	LastFollowedAt time.Time
+}
+
+// EventQuery selects events for listing; zero fields match anything
+type EventQuery struct {
+	AccountID        int64
+	UserID           string
+	EventType        EventType
+	Since            time.Time // inclusive
+	Until            time.Time // exclusive
+	Label            string    // only events carrying this annotation
+	DetectedAt       time.Time // only events from the cycle that ran at this time
+	BatchBelow       int       // leave out cycles that produced this many events of one type for an account
+	IncludeDismissed bool
+	Limit            int // 0 returns every match
+	Offset           int // matches to skip, for paging
+}
+
+// EventBatch counts the events of one type a check cycle produced for an
+// account
+type EventBatch struct {
+	WatchedAccountID int64
+	AccountUsername  string
+	EventType        EventType
+	DetectedAt       time.Time
+	Count            int
+}
+
+// EventRule selects events for bulk dismissal; zero fields match anything
+type EventRule struct {
+	AccountID int64
+	UserID    string
+	EventType EventType
+	Before    time.Time
 } 
\ No newline at end of file
@@ -0,0 +1,111 @@
+package db
+
+import (
+	"fmt"
+	"time"
+)
+
+// MuteStats summarizes the global mute list
+type MuteStats struct {
+	Users      int // muted user IDs
+	Suppressed int // events left out of notifications because their target was muted
+}
+
+// MuteUsers adds user IDs to the global mute list, returning how many
+// weren't muted yet. source records where they came from, e.g. the
+// imported file.
+func (d *Database) MuteUsers(userIDs []string, source string) (int, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO muted_users (user_id, source, muted_at) VALUES (?, ?, ?)
+		ON CONFLICT DO NOTHING`)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	now := time.Now()
+	added := 0
+	for _, id := range userIDs {
+		result, err := stmt.Exec(id, source, now)
+		if err != nil {
+			return 0, fmt.Errorf("muting %s: %w", id, err)
+		}
+		if n, _ := result.RowsAffected(); n > 0 {
+			added++
+		}
+	}
+	return added, tx.Commit()
+}
+
+// UnmuteUsers removes user IDs from the global mute list, returning how
+// many were muted
+func (d *Database) UnmuteUsers(userIDs []string) (int, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	removed := 0
+	for _, id := range userIDs {
+		result, err := tx.Exec("DELETE FROM muted_users WHERE user_id = ?", id)
+		if err != nil {
+			return 0, fmt.Errorf("unmuting %s: %w", id, err)
+		}
+		if n, _ := result.RowsAffected(); n > 0 {
+			removed++
+		}
+	}
+	return removed, tx.Commit()
+}
+
+// SuppressMuted drops muted user IDs from userIDs and counts each one
+// dropped as a suppressed event
+func (d *Database) SuppressMuted(userIDs []string) ([]string, error) {
+	if len(userIDs) == 0 {
+		return userIDs, nil
+	}
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Counting and checking the mute list is the same statement
+	stmt, err := tx.Prepare("UPDATE muted_users SET suppressed = suppressed + 1 WHERE user_id = ?")
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	kept := make([]string, 0, len(userIDs))
+	for _, id := range userIDs {
+		result, err := stmt.Exec(id)
+		if err != nil {
+			return nil, err
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			kept = append(kept, id)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return kept, nil
+}
+
+// GetMuteStats returns the size of the mute list and how many events it
+// has suppressed
+func (d *Database) GetMuteStats() (MuteStats, error) {
+	var stats MuteStats
+	err := d.db.QueryRow(`
+		SELECT COUNT(*), COALESCE(SUM(suppressed), 0) FROM muted_users`).Scan(&stats.Users, &stats.Suppressed)
+	return stats, err
+}
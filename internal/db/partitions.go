@@ -0,0 +1,121 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"x-tracker/internal/logger"
+)
+
+// partitionPrefix names the following table of a partitioned account,
+// followed by its ID
+const partitionPrefix = "following_account_"
+
+// querier is what followingTable needs from a database or transaction
+type querier interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// SetPartitionThreshold moves the followings of accounts storing more than
+// rows of them into a table of their own at their next store, keeping the
+// shared table's index small and making their removal a table drop. 0
+// leaves every account in the shared table. It works the same on SQLite
+// and Postgres; a Store without tables can ignore it.
+func (d *Database) SetPartitionThreshold(rows int) {
+	d.partitionThreshold = rows
+}
+
+func partitionTable(watchedAccountID int64) string {
+	return partitionPrefix + strconv.FormatInt(watchedAccountID, 10)
+}
+
+// followingTable returns the table holding an account's followings: its
+// own if it is partitioned, the shared following table otherwise. Both
+// have the same columns.
+func (d *Database) followingTable(q querier, watchedAccountID int64) (string, error) {
+	table := partitionTable(watchedAccountID)
+	var found string
+	err := q.QueryRow(d.dialect.objects("table"), table).Scan(&found)
+	if err == sql.ErrNoRows {
+		return "following", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("looking up following table: %w", err)
+	}
+	return table, nil
+}
+
+// partitionIfLarge moves an account's followings out of the shared table
+// once it stores more than the partition threshold
+func (d *Database) partitionIfLarge(tx *sql.Tx, watchedAccountID int64, table string, rows int) error {
+	if d.partitionThreshold <= 0 || table != "following" || rows <= d.partitionThreshold {
+		return nil
+	}
+
+	partition := partitionTable(watchedAccountID)
+	if _, err := tx.Exec(d.dialect.partition(partition)); err != nil {
+		return fmt.Errorf("creating %s: %w", partition, err)
+	}
+	if _, err := tx.Exec(`
+		INSERT INTO `+partition+` (watched_account_id, followed_user_id)
+		SELECT watched_account_id, followed_user_id FROM following WHERE watched_account_id = ?`,
+		watchedAccountID); err != nil {
+		return fmt.Errorf("filling %s: %w", partition, err)
+	}
+	if _, err := tx.Exec("DELETE FROM following WHERE watched_account_id = ?", watchedAccountID); err != nil {
+		return fmt.Errorf("clearing moved followings: %w", err)
+	}
+
+	logger.Info("Moved %d followings of account ID %d to %s", rows, watchedAccountID, partition)
+	return nil
+}
+
+// dropPartition drops an account's own following table, if it has one
+func dropPartition(tx *sql.Tx, watchedAccountID int64) error {
+	if _, err := tx.Exec("DROP TABLE IF EXISTS " + partitionTable(watchedAccountID)); err != nil {
+		return fmt.Errorf("dropping following partition: %w", err)
+	}
+	return nil
+}
+
+// partitionedAccounts returns the IDs of the accounts with their own
+// following table
+func (d *Database) partitionedAccounts() ([]int64, error) {
+	rows, err := d.db.Query(d.dialect.objects("table"), partitionPrefix+"%")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		if id, err := strconv.ParseInt(strings.TrimPrefix(name, partitionPrefix), 10, 64); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids, rows.Err()
+}
+
+// allFollowings returns a subquery over the followings of every account,
+// partitioned or not, for queries spanning accounts
+func (d *Database) allFollowings() (string, error) {
+	ids, err := d.partitionedAccounts()
+	if err != nil {
+		return "", err
+	}
+	if len(ids) == 0 {
+		return "following", nil
+	}
+
+	selects := []string{"SELECT watched_account_id, followed_user_id FROM following"}
+	for _, id := range ids {
+		selects = append(selects, "SELECT watched_account_id, followed_user_id FROM "+partitionTable(id))
+	}
+	return "(" + strings.Join(selects, " UNION ALL ") + ")", nil
+}
@@ -0,0 +1,73 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"x-tracker/internal/logger"
+)
+
+// ProfileChanges compares a freshly fetched profile against the stored one
+// and returns an event for every field that differs
+func ProfileChanges(watchedAccountID int64, old, current Profile, at time.Time) []ProfileEvent {
+	fields := []struct {
+		field      ProfileField
+		old, value string
+	}{
+		{ProfileFieldUsername, old.Username, current.Username},
+		{ProfileFieldDisplayName, old.DisplayName, current.DisplayName},
+		{ProfileFieldBio, old.Bio, current.Bio},
+		{ProfileFieldAvatar, old.AvatarURL, current.AvatarURL},
+	}
+
+	var events []ProfileEvent
+	for _, f := range fields {
+		if f.old == f.value {
+			continue
+		}
+		events = append(events, ProfileEvent{
+			WatchedAccountID: watchedAccountID,
+			Field:            f.field,
+			OldValue:         f.old,
+			NewValue:         f.value,
+			DetectedAt:       at,
+		})
+	}
+	return events
+}
+
+// StoreProfile saves the account's current profile along with the events
+// describing how it changed, in one transaction
+func (d *Database) StoreProfile(watchedAccountID int64, profile Profile, events []ProfileEvent) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, event := range events {
+		if _, err := tx.Exec(`
+			INSERT INTO profile_events (watched_account_id, field, old_value, new_value, detected_at)
+			VALUES (?, ?, ?, ?, ?)`,
+			watchedAccountID, event.Field, event.OldValue, event.NewValue, event.DetectedAt); err != nil {
+			return fmt.Errorf("inserting profile event: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(`
+		UPDATE watched_accounts
+		SET username = ?, username_key = ?, display_name = ?, bio = ?, avatar_url = ?, profile_seen_at = ?
+		WHERE id = ?`,
+		profile.Username, UsernameKey(profile.Username), profile.DisplayName, profile.Bio, profile.AvatarURL, time.Now(), watchedAccountID); err != nil {
+		return fmt.Errorf("updating profile: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+
+	if len(events) > 0 {
+		logger.Info("Recorded %d profile changes for account ID %d", len(events), watchedAccountID)
+	}
+	return nil
+}
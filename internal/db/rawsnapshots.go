@@ -0,0 +1,175 @@
+package db
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// rawSnapshotWriter encodes a following list as a gzip-compressed JSON
+// array one ID at a time, so only the compressed list is held in memory
+type rawSnapshotWriter struct {
+	buf   bytes.Buffer
+	gz    *gzip.Writer
+	count int
+}
+
+func newRawSnapshotWriter() *rawSnapshotWriter {
+	w := &rawSnapshotWriter{}
+	w.gz = gzip.NewWriter(&w.buf)
+	w.gz.Write([]byte("["))
+	return w
+}
+
+// add appends an ID to the list
+func (w *rawSnapshotWriter) add(id string) error {
+	encoded, err := json.Marshal(id)
+	if err != nil {
+		return err
+	}
+	if w.count > 0 {
+		w.gz.Write([]byte(","))
+	}
+	w.count++
+	_, err = w.gz.Write(encoded)
+	return err
+}
+
+// finish closes the list and returns the compressed data
+func (w *rawSnapshotWriter) finish() ([]byte, error) {
+	if _, err := w.gz.Write([]byte("]")); err != nil {
+		return nil, err
+	}
+	if err := w.gz.Close(); err != nil {
+		return nil, err
+	}
+	return w.buf.Bytes(), nil
+}
+
+// StoreRawSnapshot stores a fetched following list as a raw snapshot of
+// the account and deletes its oldest ones beyond keep, 0 keeping all
+func (d *Database) StoreRawSnapshot(watchedAccountID int64, ids []string, takenAt time.Time, keep int) error {
+	w := newRawSnapshotWriter()
+	for _, id := range ids {
+		if err := w.add(id); err != nil {
+			return fmt.Errorf("encoding raw snapshot: %w", err)
+		}
+	}
+	return storeRawSnapshot(d.db, watchedAccountID, w, takenAt, keep)
+}
+
+func (s *followingStage) StoreRawSnapshot(takenAt time.Time, keep int) error {
+	rows, err := s.conn.QueryContext(context.Background(), "SELECT user_id FROM staged_followings ORDER BY pos")
+	if err != nil {
+		return fmt.Errorf("reading staged followings: %w", err)
+	}
+	w := newRawSnapshotWriter()
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		if err := w.add(id); err != nil {
+			rows.Close()
+			return fmt.Errorf("encoding raw snapshot: %w", err)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	return storeRawSnapshot(s.conn, s.watchedAccountID, w, takenAt, keep)
+}
+
+func storeRawSnapshot(e execer, watchedAccountID int64, w *rawSnapshotWriter, takenAt time.Time, keep int) error {
+	data, err := w.finish()
+	if err != nil {
+		return fmt.Errorf("compressing raw snapshot: %w", err)
+	}
+	ctx := context.Background()
+	if _, err := e.ExecContext(ctx, `
+		INSERT INTO raw_snapshots (watched_account_id, taken_at, id_count, data)
+		VALUES (?, ?, ?, ?)`, watchedAccountID, takenAt, w.count, data); err != nil {
+		return fmt.Errorf("storing raw snapshot: %w", err)
+	}
+	if keep <= 0 {
+		return nil
+	}
+	if _, err := e.ExecContext(ctx, `
+		DELETE FROM raw_snapshots
+		WHERE watched_account_id = ? AND id NOT IN (
+		    SELECT id FROM raw_snapshots WHERE watched_account_id = ?
+		    ORDER BY taken_at DESC, id DESC LIMIT ?)`,
+		watchedAccountID, watchedAccountID, keep); err != nil {
+		return fmt.Errorf("pruning raw snapshots: %w", err)
+	}
+	return nil
+}
+
+// GetRawSnapshots lists the raw snapshots of an account, or of all accounts
+// if watchedAccountID is 0, newest first
+func (d *Database) GetRawSnapshots(watchedAccountID int64) ([]RawSnapshot, error) {
+	query := `
+		SELECT r.id, r.watched_account_id, COALESCE(a.username, ''), r.taken_at, r.id_count, length(r.data)
+		FROM raw_snapshots r
+		LEFT JOIN watched_accounts a ON a.id = r.watched_account_id`
+	var args []any
+	if watchedAccountID != 0 {
+		query += " WHERE r.watched_account_id = ?"
+		args = append(args, watchedAccountID)
+	}
+	query += " ORDER BY r.taken_at DESC, r.id DESC"
+
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var snapshots []RawSnapshot
+	for rows.Next() {
+		var snapshot RawSnapshot
+		if err := rows.Scan(&snapshot.ID, &snapshot.WatchedAccountID, &snapshot.Username,
+			&snapshot.TakenAt, &snapshot.Count, &snapshot.Size); err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, rows.Err()
+}
+
+// GetRawSnapshot returns a raw snapshot and its IDs in the order they were
+// fetched, or nil if there is no snapshot with that ID
+func (d *Database) GetRawSnapshot(id int64) (*RawSnapshot, []string, error) {
+	var snapshot RawSnapshot
+	var data []byte
+	err := d.db.QueryRow(`
+		SELECT r.id, r.watched_account_id, COALESCE(a.username, ''), r.taken_at, r.id_count, r.data
+		FROM raw_snapshots r
+		LEFT JOIN watched_accounts a ON a.id = r.watched_account_id
+		WHERE r.id = ?`, id).Scan(&snapshot.ID, &snapshot.WatchedAccountID, &snapshot.Username,
+		&snapshot.TakenAt, &snapshot.Count, &data)
+	if err == sql.ErrNoRows {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	snapshot.Size = len(data)
+
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, fmt.Errorf("decompressing raw snapshot %d: %w", id, err)
+	}
+	defer gz.Close()
+	var ids []string
+	if err := json.NewDecoder(gz).Decode(&ids); err != nil {
+		return nil, nil, fmt.Errorf("decoding raw snapshot %d: %w", id, err)
+	}
+	return &snapshot, ids, nil
+}
@@ -0,0 +1,254 @@
+package db
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/mattn/go-sqlite3"
+	"x-tracker/internal/logger"
+)
+
+// IsCorrupt reports whether err is SQLite finding the database file
+// malformed or not a database at all
+func IsCorrupt(err error) bool {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	return sqliteErr.Code == sqlite3.ErrCorrupt || sqliteErr.Code == sqlite3.ErrNotADB
+}
+
+// SalvageReport describes what Salvage got out of a broken database
+type SalvageReport struct {
+	Method  string // "sqlite3 .recover" or "row copy"
+	Tables  int    // tables rows were copied from
+	Rows    int64  // rows copied, counted for the row copy only
+	Skipped []string
+}
+
+// Salvage copies whatever can still be read from the broken database at
+// src into a new database at dst, which must not exist. The sqlite3 command
+// line tool's .recover is used when it is installed, as it also reads
+// pages the table structure no longer reaches; otherwise the tables are
+// copied row by row, stopping at the first unreadable row of each.
+func Salvage(src, dst string) (SalvageReport, error) {
+	if _, err := os.Stat(dst); err == nil {
+		return SalvageReport{}, fmt.Errorf("%s already exists", dst)
+	}
+
+	if cli, err := exec.LookPath("sqlite3"); err == nil {
+		report, err := recoverWithCLI(cli, src, dst)
+		if err == nil {
+			return report, nil
+		}
+		logger.Warn("sqlite3 .recover failed, copying rows instead: %v", err)
+		os.Remove(dst)
+	}
+	return copyRows(src, dst)
+}
+
+// recoverWithCLI pipes the SQL sqlite3 .recover writes for src into a new
+// database at dst
+func recoverWithCLI(cli, src, dst string) (SalvageReport, error) {
+	var script, stderr bytes.Buffer
+	dump := exec.Command(cli, "-readonly", src, ".recover")
+	dump.Stdout = &script
+	dump.Stderr = &stderr
+	if err := dump.Run(); err != nil {
+		return SalvageReport{}, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	stderr.Reset()
+	load := exec.Command(cli, dst)
+	load.Stdin = &script
+	load.Stderr = &stderr
+	if err := load.Run(); err != nil {
+		return SalvageReport{}, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	report := SalvageReport{Method: "sqlite3 .recover"}
+	recovered, err := sql.Open("sqlite3", dst)
+	if err != nil {
+		return report, err
+	}
+	defer recovered.Close()
+	err = recovered.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table'`).Scan(&report.Tables)
+	return report, err
+}
+
+// copyRows creates a database at dst with the current schema and copies
+// every readable row of src into it
+func copyRows(src, dst string) (SalvageReport, error) {
+	report := SalvageReport{Method: "row copy"}
+
+	broken, err := sql.Open("sqlite3", "file:"+src+"?mode=ro")
+	if err != nil {
+		return report, err
+	}
+	defer broken.Close()
+
+	tables, err := salvageTables(broken)
+	if err != nil {
+		return report, fmt.Errorf("reading the table list: %w", err)
+	}
+
+	target, err := NewDatabase(dst)
+	if err != nil {
+		return report, err
+	}
+	defer target.Close()
+
+	for _, table := range tables {
+		rows, err := copyTable(broken, target.db, table)
+		report.Rows += rows
+		if rows > 0 {
+			report.Tables++
+		}
+		if err != nil {
+			logger.Warn("Salvaged %d rows of %s before: %v", rows, table.name, err)
+			report.Skipped = append(report.Skipped, fmt.Sprintf("%s (%d rows copied before: %v)", table.name, rows, err))
+		}
+	}
+	return report, nil
+}
+
+// salvageTable is a table of the broken database and the statements that
+// create it and its indexes
+type salvageTable struct {
+	name    string
+	sql     string
+	indexes []string
+}
+
+func salvageTables(broken *sql.DB) ([]salvageTable, error) {
+	rows, err := broken.Query(`
+		SELECT type, name, tbl_name, COALESCE(sql, '') FROM sqlite_master
+		WHERE type IN ('table', 'index') AND name NOT LIKE 'sqlite_%'
+		ORDER BY type = 'index', rowid`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tables []salvageTable
+	byName := make(map[string]int)
+	for rows.Next() {
+		var kind, name, table, stmt string
+		if err := rows.Scan(&kind, &name, &table, &stmt); err != nil {
+			return nil, err
+		}
+		if kind == "table" {
+			byName[name] = len(tables)
+			tables = append(tables, salvageTable{name: name, sql: stmt})
+		} else if i, ok := byName[table]; ok && stmt != "" {
+			tables[i].indexes = append(tables[i].indexes, stmt)
+		}
+	}
+	return tables, rows.Err()
+}
+
+// copyTable copies the rows of a table the target has the columns for,
+// creating tables only the broken database has, like account partitions
+func copyTable(broken, target *sql.DB, table salvageTable) (int64, error) {
+	targetColumns, err := columnNames(target, table.name)
+	if err != nil {
+		return 0, err
+	}
+	if len(targetColumns) == 0 {
+		if _, err := target.Exec(table.sql); err != nil {
+			return 0, fmt.Errorf("creating table: %w", err)
+		}
+		for _, index := range table.indexes {
+			if _, err := target.Exec(index); err != nil {
+				logger.Warn("Failed to recreate an index of %s: %v", table.name, err)
+			}
+		}
+		if targetColumns, err = columnNames(target, table.name); err != nil {
+			return 0, err
+		}
+	}
+
+	brokenColumns, err := columnNames(broken, table.name)
+	if err != nil {
+		return 0, err
+	}
+	var columns []string
+	for _, column := range brokenColumns {
+		for _, existing := range targetColumns {
+			if column == existing {
+				columns = append(columns, `"`+column+`"`)
+				break
+			}
+		}
+	}
+	if len(columns) == 0 {
+		return 0, nil
+	}
+
+	list := strings.Join(columns, ", ")
+	rows, err := broken.Query(`SELECT ` + list + ` FROM "` + table.name + `"`)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	tx, err := target.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	insert, err := tx.Prepare(`INSERT OR IGNORE INTO "` + table.name + `" (` + list + `) VALUES (?` + strings.Repeat(", ?", len(columns)-1) + `)`)
+	if err != nil {
+		return 0, err
+	}
+	defer insert.Close()
+
+	var copied int64
+	values := make([]interface{}, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	var readErr error
+	for rows.Next() {
+		if readErr = rows.Scan(pointers...); readErr != nil {
+			break
+		}
+		if _, err := insert.Exec(values...); err != nil {
+			return 0, err
+		}
+		copied++
+	}
+	if readErr == nil {
+		readErr = rows.Err()
+	}
+	// Keep what was read before the damage
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return copied, readErr
+}
+
+// columnNames returns the column names of a table, none if it doesn't exist
+func columnNames(db *sql.DB, table string) ([]string, error) {
+	rows, err := db.Query(`SELECT name FROM pragma_table_info(?)`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		columns = append(columns, name)
+	}
+	return columns, rows.Err()
+}
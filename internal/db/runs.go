@@ -0,0 +1,76 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// maxCheckRuns is how many check cycle summaries are kept
+const maxCheckRuns = 1000
+
+// RecordCheckRun stores a check cycle summary and prunes old ones
+func (d *Database) RecordCheckRun(run *CheckRun) error {
+	id, err := d.dialect.insert(context.Background(), d.db, `
+		INSERT INTO check_runs (started_at, finished_at, accounts, failures, notify_failures, quota_remaining, error)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		run.StartedAt, run.FinishedAt, run.Accounts, run.Failures, run.NotifyFailures, run.QuotaRemaining, run.Error)
+	if err != nil {
+		return fmt.Errorf("storing check run: %w", err)
+	}
+	run.ID = id
+
+	if _, err := d.db.Exec(`
+		DELETE FROM check_runs WHERE id NOT IN (
+			SELECT id FROM check_runs ORDER BY id DESC LIMIT ?
+		)`, maxCheckRuns); err != nil {
+		return fmt.Errorf("pruning check runs: %w", err)
+	}
+	return nil
+}
+
+// GetLastCheckRun returns the most recent check cycle, or nil if none ran yet
+func (d *Database) GetLastCheckRun() (*CheckRun, error) {
+	var run CheckRun
+	var runErr sql.NullString
+	err := d.db.QueryRow(`
+		SELECT id, started_at, finished_at, accounts, failures, notify_failures, quota_remaining, error
+		FROM check_runs
+		ORDER BY id DESC
+		LIMIT 1`).Scan(&run.ID, &run.StartedAt, &run.FinishedAt, &run.Accounts,
+		&run.Failures, &run.NotifyFailures, &run.QuotaRemaining, &runErr)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	run.Error = runErr.String
+	return &run, nil
+}
+
+// GetCheckRuns returns the most recent check cycles, newest first
+func (d *Database) GetCheckRuns(limit int) ([]CheckRun, error) {
+	rows, err := d.db.Query(`
+		SELECT id, started_at, finished_at, accounts, failures, notify_failures, quota_remaining, error
+		FROM check_runs
+		ORDER BY id DESC
+		LIMIT ?`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []CheckRun
+	for rows.Next() {
+		var run CheckRun
+		var runErr sql.NullString
+		if err := rows.Scan(&run.ID, &run.StartedAt, &run.FinishedAt, &run.Accounts,
+			&run.Failures, &run.NotifyFailures, &run.QuotaRemaining, &runErr); err != nil {
+			return nil, err
+		}
+		run.Error = runErr.String
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
@@ -0,0 +1,61 @@
+package db
+
+import (
+	"fmt"
+	"time"
+)
+
+// maxCountSamples is how many following-count samples are kept per account
+const maxCountSamples = 500
+
+// RecordFollowingCount stores a following-count sample for an account and
+// prunes samples beyond maxCountSamples
+func (d *Database) RecordFollowingCount(watchedAccountID int64, count int, at time.Time) error {
+	if _, err := d.db.Exec(`
+		INSERT INTO following_count_samples (watched_account_id, following_count, sampled_at)
+		VALUES (?, ?, ?)`, watchedAccountID, count, at); err != nil {
+		return fmt.Errorf("storing following count sample: %w", err)
+	}
+
+	// Samples have no key, so everything older than the oldest kept one goes
+	_, err := d.db.Exec(`
+		DELETE FROM following_count_samples
+		WHERE watched_account_id = ? AND sampled_at < (
+			SELECT sampled_at FROM following_count_samples
+			WHERE watched_account_id = ?
+			ORDER BY sampled_at DESC
+			LIMIT 1 OFFSET ?
+		)`, watchedAccountID, watchedAccountID, maxCountSamples-1)
+	if err != nil {
+		return fmt.Errorf("pruning following count samples: %w", err)
+	}
+	return nil
+}
+
+// GetRecentFollowingCounts returns up to limit of the newest samples for
+// every account, oldest first
+func (d *Database) GetRecentFollowingCounts(limit int) (map[int64][]int, error) {
+	rows, err := d.db.Query(`
+		SELECT watched_account_id, following_count FROM (
+			SELECT watched_account_id, following_count, sampled_at,
+			       ROW_NUMBER() OVER (PARTITION BY watched_account_id ORDER BY sampled_at DESC) AS rn
+			FROM following_count_samples
+		) ranked
+		WHERE rn <= ?
+		ORDER BY watched_account_id, sampled_at`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[int64][]int)
+	for rows.Next() {
+		var id int64
+		var count int
+		if err := rows.Scan(&id, &count); err != nil {
+			return nil, err
+		}
+		counts[id] = append(counts[id], count)
+	}
+	return counts, rows.Err()
+}
@@ -0,0 +1,57 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"x-tracker/internal/logger"
+)
+
+// OpenScratchCopy opens a throwaway copy of the database at dbPath, for
+// runs that must leave it untouched. The original is only read, so it is
+// neither created nor migrated; the copy is removed on Close.
+func OpenScratchCopy(dbPath string) (*Database, error) {
+	if dbPath == MemoryPath {
+		return NewMemoryDatabase()
+	}
+
+	dir, err := os.MkdirTemp("", "x-tracker-scratch")
+	if err != nil {
+		return nil, fmt.Errorf("creating scratch directory: %w", err)
+	}
+	copyPath := filepath.Join(dir, "data.db")
+
+	if _, err := os.Stat(dbPath); err == nil {
+		if err := copyReadOnly(dbPath, copyPath); err != nil {
+			os.RemoveAll(dir)
+			return nil, fmt.Errorf("copying database: %w", err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	database, err := NewDatabase(copyPath)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	database.scratchDir = dir
+	logger.Info("Working on a scratch copy of %s, nothing will be saved", dbPath)
+	return database, nil
+}
+
+// copyReadOnly writes a consistent copy of the database at src to dst
+// without opening src for writing
+func copyReadOnly(src, dst string) error {
+	source, err := sql.Open("sqlite3", "file:"+src+"?mode=ro")
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+	_, err = source.Exec("VACUUM INTO ?", dst)
+	return err
+}
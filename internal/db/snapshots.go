@@ -0,0 +1,53 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// StoreTargetSnapshot saves a one-hop snapshot of a target's followings
+func (d *Database) StoreTargetSnapshot(userID string, followingIDs []string, at time.Time) error {
+	ids, err := json.Marshal(followingIDs)
+	if err != nil {
+		return fmt.Errorf("encoding following IDs: %w", err)
+	}
+
+	if _, err := d.db.Exec(`
+		INSERT INTO target_snapshots (user_id, following_ids, taken_at)
+		VALUES (?, ?, ?)`, userID, string(ids), at); err != nil {
+		return fmt.Errorf("storing target snapshot: %w", err)
+	}
+	return nil
+}
+
+// CountTargetSnapshotsSince returns how many snapshots were taken after since
+func (d *Database) CountTargetSnapshotsSince(since time.Time) (int, error) {
+	var count int
+	err := d.db.QueryRow("SELECT COUNT(*) FROM target_snapshots WHERE taken_at > ?", since).Scan(&count)
+	return count, err
+}
+
+// GetTargetSnapshot returns the newest snapshot of a user, or nil if there is none
+func (d *Database) GetTargetSnapshot(userID string) (*TargetSnapshot, error) {
+	var snapshot TargetSnapshot
+	var ids string
+	err := d.db.QueryRow(`
+		SELECT id, user_id, following_ids, taken_at
+		FROM target_snapshots
+		WHERE user_id = ?
+		ORDER BY taken_at DESC
+		LIMIT 1`, userID).Scan(&snapshot.ID, &snapshot.UserID, &ids, &snapshot.TakenAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(ids), &snapshot.FollowingIDs); err != nil {
+		return nil, fmt.Errorf("decoding following IDs: %w", err)
+	}
+	return &snapshot, nil
+}
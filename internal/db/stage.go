@@ -0,0 +1,138 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// FollowingDiff is how a fetched following list differs from the stored one
+type FollowingDiff struct {
+	Fetched   int      // distinct IDs fetched
+	Stored    int      // followings stored before the change
+	Follows   []string // fetched but not stored, in the order fetched
+	Unfollows []string // stored but not fetched
+}
+
+// FollowingStage collects a fetched following list, page by page, so it
+// can be compared with the stored followings without holding the whole
+// list in memory. It must be closed when done.
+type FollowingStage interface {
+	// Add stages a page of fetched IDs; IDs staged before are ignored
+	Add(ids []string) error
+	// Fetched returns how many distinct IDs have been staged
+	Fetched() int
+	// Diff compares the staged list with the stored followings
+	Diff() (*FollowingDiff, error)
+	// StoreRawSnapshot stores the staged list, in the order it was
+	// fetched, as a raw snapshot of the account and deletes its oldest
+	// ones beyond keep, 0 keeping all
+	StoreRawSnapshot(takenAt time.Time, keep int) error
+	// Close drops the staged list
+	Close() error
+}
+
+// followingStage stages the list in a temporary table, comparing it with
+// the stored followings in SQL. Temporary tables only exist on the
+// connection that made them, so a stage holds a connection of its own
+// until it is closed.
+type followingStage struct {
+	conn             *sql.Conn
+	dialect          dialect
+	table            string // table storing the account's followings
+	watchedAccountID int64
+	fetched          int
+}
+
+// StageFollowings starts collecting a fetched following list of an account
+func (d *Database) StageFollowings(watchedAccountID int64) (FollowingStage, error) {
+	table, err := d.followingTable(d.db, watchedAccountID)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := d.db.Conn(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("opening connection: %w", err)
+	}
+
+	stage := &followingStage{conn: conn, dialect: d.dialect, table: table, watchedAccountID: watchedAccountID}
+	if _, err := conn.ExecContext(context.Background(), d.dialect.stage()); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("creating staging table: %w", err)
+	}
+	if _, err := conn.ExecContext(context.Background(), "DELETE FROM staged_followings"); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("clearing staging table: %w", err)
+	}
+	return stage, nil
+}
+
+func (s *followingStage) Add(ids []string) error {
+	added, err := execBatched(s.conn, ids, func(n int) string {
+		return "INSERT INTO staged_followings (user_id) VALUES " + repeatJoined("(?)", n) + " ON CONFLICT DO NOTHING"
+	}, plainIDs)
+	s.fetched += int(added)
+	if err != nil {
+		return fmt.Errorf("staging followings: %w", err)
+	}
+	return nil
+}
+
+func (s *followingStage) Fetched() int {
+	return s.fetched
+}
+
+func (s *followingStage) Diff() (*FollowingDiff, error) {
+	ctx := context.Background()
+	diff := &FollowingDiff{Fetched: s.fetched}
+	if err := s.conn.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM "+s.table+" WHERE watched_account_id = ?", s.watchedAccountID).Scan(&diff.Stored); err != nil {
+		return nil, fmt.Errorf("counting stored followings: %w", err)
+	}
+
+	var err error
+	if diff.Follows, err = s.userIDs(`
+		SELECT s.user_id FROM staged_followings s
+		WHERE NOT EXISTS (
+		    SELECT 1 FROM ` + s.table + ` f
+		    WHERE f.watched_account_id = ? AND f.followed_user_id = s.user_id)
+		ORDER BY s.pos`); err != nil {
+		return nil, fmt.Errorf("finding follows: %w", err)
+	}
+	if diff.Unfollows, err = s.userIDs(`
+		SELECT f.followed_user_id FROM ` + s.table + ` f
+		WHERE f.watched_account_id = ? AND NOT EXISTS (
+		    SELECT 1 FROM staged_followings s WHERE s.user_id = f.followed_user_id)`); err != nil {
+		return nil, fmt.Errorf("finding unfollows: %w", err)
+	}
+	return diff, nil
+}
+
+// userIDs runs a query about the staged account returning one ID per row
+func (s *followingStage) userIDs(query string) ([]string, error) {
+	rows, err := s.conn.QueryContext(context.Background(), query, s.watchedAccountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// Close drops the staged list and gives the connection back
+func (s *followingStage) Close() error {
+	_, err := s.conn.ExecContext(context.Background(), s.dialect.unstage())
+	if closeErr := s.conn.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,223 @@
+package db
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+const week = 7 * 24 * time.Hour
+
+// Stats aggregates the changes detected across all watched accounts
+type Stats struct {
+	Since          time.Time
+	Follows        int
+	Unfollows      int
+	FollowsPerWeek float64       // new follows per week across all accounts
+	Days           []DayStats    // every local day since Since, oldest first
+	TopTargets     []TargetStats // users followed by the most watched accounts
+	Churn          []AccountChurn
+}
+
+// DayStats counts the changes detected on a local calendar day
+type DayStats struct {
+	Day       time.Time // local midnight
+	Follows   int
+	Unfollows int
+}
+
+// TargetStats is a user and how many watched accounts follow them now
+type TargetStats struct {
+	UserID   string
+	Accounts int
+}
+
+// AccountChurn is how much a watched account's following list changed
+type AccountChurn struct {
+	AccountID      int64
+	Username       string
+	Follows        int
+	Unfollows      int
+	Following      int     // current size of the following list
+	Rate           float64 // changes per followed user, 0 when following no one
+	FollowsPerWeek float64
+}
+
+// Changes is the number of follows and unfollows
+func (c AccountChurn) Changes() int {
+	return c.Follows + c.Unfollows
+}
+
+// GetStats aggregates the events detected since a time: changes per day,
+// the users the most watched accounts currently follow and the accounts
+// whose following list changed the most, busiest first. Archived accounts
+// and dismissed events are left out; events moved to the cold archive
+// count with their day. limit caps both rankings.
+func (d *Database) GetStats(since time.Time, limit int) (*Stats, error) {
+	now := time.Now()
+	stats := &Stats{Since: since}
+
+	accounts, err := d.GetWatchedAccounts()
+	if err != nil {
+		return nil, err
+	}
+	counts, err := d.GetFollowingCounts()
+	if err != nil {
+		return nil, err
+	}
+	churn := make(map[int64]*AccountChurn, len(accounts))
+	for _, account := range accounts {
+		churn[account.ID] = &AccountChurn{
+			AccountID: account.ID,
+			Username:  account.Username,
+			Following: counts[account.ID],
+		}
+	}
+
+	days := make(map[time.Time]*DayStats)
+	start := localDay(since)
+	for day := start; !day.After(now); day = day.AddDate(0, 0, 1) {
+		stats.Days = append(stats.Days, DayStats{Day: day})
+	}
+	for i := range stats.Days {
+		days[stats.Days[i].Day] = &stats.Days[i]
+	}
+
+	// Events moved to the cold archive only left their daily counts behind,
+	// so the window takes in whole days of those
+	rows, err := d.db.Query(`
+		SELECT c.watched_account_id, c.day, SUM(c.follows), SUM(c.unfollows)
+		FROM (
+			SELECT watched_account_id, `+d.dialect.localDay("detected_at")+` AS day,
+			       CASE WHEN event_type = 'follow' THEN 1 ELSE 0 END AS follows,
+			       CASE WHEN event_type = 'unfollow' THEN 1 ELSE 0 END AS unfollows
+			FROM follow_events
+			WHERE detected_at >= ? AND dismissed_at IS NULL
+			UNION ALL
+			SELECT watched_account_id, day, follows, unfollows
+			FROM archived_event_counts
+			WHERE day >= ?
+		) c
+		GROUP BY c.watched_account_id, c.day`, since, start.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var accountID int64
+		var date string
+		var follows, unfollows int
+		if err := rows.Scan(&accountID, &date, &follows, &unfollows); err != nil {
+			return nil, err
+		}
+		account := churn[accountID]
+		if account == nil {
+			continue
+		}
+		stats.Follows += follows
+		stats.Unfollows += unfollows
+		account.Follows += follows
+		account.Unfollows += unfollows
+		if day, err := time.ParseInLocation("2006-01-02", date, time.Local); err == nil && days[day] != nil {
+			days[day].Follows += follows
+			days[day].Unfollows += unfollows
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Rates count from when an account was added if that is later
+	var earliest time.Time
+	for _, account := range accounts {
+		from := since
+		if account.AddedAt.After(from) {
+			from = account.AddedAt
+		}
+		if earliest.IsZero() || from.Before(earliest) {
+			earliest = from
+		}
+		c := churn[account.ID]
+		c.FollowsPerWeek = perWeek(c.Follows, now.Sub(from))
+		if c.Following > 0 {
+			c.Rate = float64(c.Changes()) / float64(c.Following)
+		}
+		if c.Changes() > 0 {
+			stats.Churn = append(stats.Churn, *c)
+		}
+	}
+	if !earliest.IsZero() {
+		stats.FollowsPerWeek = perWeek(stats.Follows, now.Sub(earliest))
+	}
+
+	// Busiest first; ties go to the higher rate, then by username
+	sort.Slice(stats.Churn, func(i, j int) bool {
+		a, b := stats.Churn[i], stats.Churn[j]
+		if a.Changes() != b.Changes() {
+			return a.Changes() > b.Changes()
+		}
+		if a.Rate != b.Rate {
+			return a.Rate > b.Rate
+		}
+		return a.Username < b.Username
+	})
+	if limit > 0 && len(stats.Churn) > limit {
+		stats.Churn = stats.Churn[:limit]
+	}
+
+	if stats.TopTargets, err = d.topTargets(limit); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// topTargets ranks users by how many watched accounts follow them now,
+// leaving out users only one account follows
+func (d *Database) topTargets(limit int) ([]TargetStats, error) {
+	if limit <= 0 {
+		limit = math.MaxInt // no limit
+	}
+	allFollowings, err := d.allFollowings()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := d.db.Query(`
+		SELECT f.followed_user_id, COUNT(DISTINCT f.watched_account_id) AS accounts
+		FROM `+allFollowings+` f
+		JOIN watched_accounts a ON a.id = f.watched_account_id
+		WHERE a.archived_at IS NULL
+		GROUP BY f.followed_user_id
+		HAVING COUNT(DISTINCT f.watched_account_id) > 1
+		ORDER BY accounts DESC, f.followed_user_id
+		LIMIT ?`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var targets []TargetStats
+	for rows.Next() {
+		var target TargetStats
+		if err := rows.Scan(&target.UserID, &target.Accounts); err != nil {
+			return nil, err
+		}
+		targets = append(targets, target)
+	}
+	return targets, rows.Err()
+}
+
+// localDay returns local midnight of the day t falls on
+func localDay(t time.Time) time.Time {
+	year, month, day := t.Local().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+}
+
+// perWeek averages count over a window, counting at least a day so a
+// freshly added account doesn't report a huge rate
+func perWeek(count int, window time.Duration) float64 {
+	if window < 24*time.Hour {
+		window = 24 * time.Hour
+	}
+	return float64(count) / (float64(window) / float64(week))
+}
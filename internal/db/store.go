@@ -0,0 +1,115 @@
+package db
+
+import "time"
+
+// Store is everything the tracker, the UI, the HTTP API and the commands
+// need from the database. Database implements it on a local SQLite file or
+// a shared Postgres database, and embedding programs can substitute their
+// own.
+type Store interface {
+	Close() error
+
+	// Watched accounts
+	AddWatchedAccount(account *WatchedAccount) error
+	GetWatchedAccounts() ([]WatchedAccount, error)
+	GetWatchedAccountByUsername(username string) (*WatchedAccount, error)
+	RemoveWatchedAccount(id int64) error
+	ArchiveWatchedAccount(id int64) error
+	MarkBaselined(id int64) error
+	MarkChecked(id int64, at time.Time) error
+	SetAccountStatus(id int64, status AccountStatus, at time.Time) error
+	SetFollowingDrift(id int64, drift *FollowingDrift) error
+	SetPaused(id int64, paused bool) error
+	SetCheckJitter(id int64, jitter *time.Duration) error
+	SetAlias(id int64, alias string) error
+	SetNotificationFilter(filter *NotificationFilter) error
+	AddTags(id int64, tags []string) error
+	RemoveTags(id int64, tags []string) error
+
+	// Followings and the events diffing them produces
+	SetDiffMode(mode string) error
+	SetPartitionThreshold(rows int)
+	StoreFollowings(watchedAccountID int64, followingIDs []string) error
+	ApplyFollowingChanges(watchedAccountID int64, follows, unfollows []string, total int) error
+	ReplaceFollowings(watchedAccountID int64, followingIDs []string) error
+	StageFollowings(watchedAccountID int64) (FollowingStage, error)
+	GetCurrentFollowings(watchedAccountID int64) (map[string]bool, error)
+	GetFollowingsAsOf(watchedAccountID int64, at time.Time) (map[string]bool, error)
+	GetFollowingCounts() (map[int64]int, error)
+	GetTombstones(watchedAccountID int64, userIDs []string) (map[string]Tombstone, error)
+	StoreFollowEvents(watchedAccountID int64, follows, unfollows []string) ([]FollowEvent, error)
+	ProcessFollowingChanges(account *WatchedAccount, newFollowingIDs []string) error
+	RecordChanges(watchedAccountID int64, follows, unfollows []string, total int, flapSince time.Time) (*RecordedChanges, error)
+
+	// Events
+	GetRecentEvents(limit int, includeDismissed bool) ([]FollowEvent, error)
+	GetEvents(q EventQuery) ([]FollowEvent, error)
+	GetEventByUUID(uuid string) (*FollowEvent, error)
+	GetEventBatches(minSize, limit int, includeDismissed bool) ([]EventBatch, error)
+	GetLastChangeTimes() (map[int64]time.Time, error)
+	GetActivityHours(accountID int64, since time.Time) (ActivityHours, error)
+	DismissEvent(id int64) error
+	RestoreEvent(id int64) error
+	DismissEventsByRule(rule EventRule) (int64, error)
+	AnnotateEvent(uuid, label, source string) error
+	ArchiveEvents(archive string, cutoff time.Time) (int, error)
+	GetEventArchives() ([]EventArchive, error)
+	ArchivedBefore() (time.Time, error)
+
+	// Notifications
+	RecordDelivery(delivery *Delivery) error
+	GetDeliveries(limit int, failedOnly bool) ([]Delivery, error)
+	RecordNotificationMessage(msg NotificationMessage) error
+	GetNotificationMessagesSince(since time.Time) ([]NotificationMessage, error)
+	SyncMessageAnnotations(msg NotificationMessage, labels []string, source string) error
+	MuteUsers(userIDs []string, source string) (int, error)
+	UnmuteUsers(userIDs []string) (int, error)
+	SuppressMuted(userIDs []string) ([]string, error)
+	GetMuteStats() (MuteStats, error)
+
+	// Followers, profiles and snapshots
+	GetFollowers(watchedAccountID int64) (map[string]time.Time, error)
+	StoreFollowers(watchedAccountID int64, gained []string, lost []LostFollower, at time.Time) error
+	GetLostFollowers(watchedAccountID int64, limit int) ([]LostFollower, error)
+	StoreProfile(watchedAccountID int64, profile Profile, events []ProfileEvent) error
+	StoreRawSnapshot(watchedAccountID int64, ids []string, takenAt time.Time, keep int) error
+	GetRawSnapshots(watchedAccountID int64) ([]RawSnapshot, error)
+	GetRawSnapshot(id int64) (*RawSnapshot, []string, error)
+	StoreTargetSnapshot(userID string, followingIDs []string, at time.Time) error
+	CountTargetSnapshotsSince(since time.Time) (int, error)
+	GetTargetSnapshot(userID string) (*TargetSnapshot, error)
+	RecordFollowingCount(watchedAccountID int64, count int, at time.Time) error
+	GetRecentFollowingCounts(limit int) (map[int64][]int, error)
+
+	// Analysis
+	RefreshInsights(rules InsightRules, now time.Time) ([]Insight, error)
+	GetInsights(since time.Time, kind string, limit int) ([]Insight, error)
+	GetSuggestions(watchedAccountID int64, since time.Time, limit int) ([]Suggestion, error)
+	GetTrendingTargets(since time.Time, limit int) ([]TrendingTarget, error)
+	GetStats(since time.Time, limit int) (*Stats, error)
+
+	// Check cycles
+	RecordCheckRun(run *CheckRun) error
+	GetLastCheckRun() (*CheckRun, error)
+	GetCheckRuns(limit int) ([]CheckRun, error)
+	RecordCheckCosts(checkRunID int64, costs []CheckCost) error
+	GetLastCheckCosts() (map[int64]CheckCost, error)
+	GetCostRanking(since time.Time, limit int) ([]AccountCost, error)
+
+	// Audit log
+	Audit(entry AuditEntry)
+	GetAuditLog(limit int) ([]AuditEntry, error)
+
+	// Maintenance and debugging
+	Backup(path string) error
+	Vacuum() error
+	CheckIntegrity() (IntegrityReport, error)
+	InstallViews() error
+	RemoveViews() error
+	SchemaVersion() (current, latest int, err error)
+	Tables() ([]string, error)
+	CountRows(table string) (int, error)
+	SampleRows(table string, limit int) ([]map[string]interface{}, error)
+}
+
+var _ Store = (*Database)(nil)
@@ -0,0 +1,70 @@
+package db
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// GetSuggestions ranks the users an account followed since the given time
+// by how many other watched accounts follow them too. Users the account no
+// longer follows, users already on the watch list and users no other
+// account follows are left out.
+func (d *Database) GetSuggestions(watchedAccountID int64, since time.Time, limit int) ([]Suggestion, error) {
+	allFollowings, err := d.allFollowings()
+	if err != nil {
+		return nil, err
+	}
+	table, err := d.followingTable(d.db, watchedAccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := d.db.Query(`
+		SELECT e.user_id, e.detected_at,
+		       COALESCE((SELECT `+d.dialect.joined("a.username", false)+`
+		                 FROM `+allFollowings+` f
+		                 JOIN watched_accounts a ON a.id = f.watched_account_id
+		                 WHERE f.followed_user_id = e.user_id
+		                   AND f.watched_account_id != e.watched_account_id), '')
+		FROM follow_events e
+		WHERE e.watched_account_id = ? AND e.event_type = ? AND e.detected_at >= ?
+		  AND EXISTS (SELECT 1 FROM `+table+`
+		              WHERE watched_account_id = e.watched_account_id AND followed_user_id = e.user_id)
+		  AND e.user_id NOT IN (SELECT user_id FROM watched_accounts WHERE user_id IS NOT NULL AND archived_at IS NULL)
+		ORDER BY e.detected_at DESC, e.id DESC`,
+		watchedAccountID, EventTypeFollow, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var suggestions []Suggestion
+	seen := make(map[string]bool)
+	for rows.Next() {
+		var suggestion Suggestion
+		var sharedBy string
+		if err := rows.Scan(&suggestion.UserID, &suggestion.FollowedAt, &sharedBy); err != nil {
+			return nil, err
+		}
+		// Re-follows produce several events; the newest one counts
+		if seen[suggestion.UserID] || sharedBy == "" {
+			continue
+		}
+		seen[suggestion.UserID] = true
+		suggestion.SharedBy = strings.Split(sharedBy, ",")
+		suggestions = append(suggestions, suggestion)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Most shared first; ties keep the most recent follow first
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return len(suggestions[i].SharedBy) > len(suggestions[j].SharedBy)
+	})
+	if limit > 0 && len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions, nil
+}
@@ -0,0 +1,70 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NormalizeTag lowercases a tag and trims surrounding whitespace and a
+// leading '#', so "#Crypto" and "crypto" are the same tag
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+}
+
+// AddTags tags an account; tags it already has are left as they are
+func (d *Database) AddTags(id int64, tags []string) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, tag := range tags {
+		if _, err := tx.Exec(`
+			INSERT INTO account_tags (watched_account_id, tag) VALUES (?, ?)
+			ON CONFLICT DO NOTHING`,
+			id, tag); err != nil {
+			return fmt.Errorf("adding tag %q: %w", tag, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// RemoveTags removes tags from an account
+func (d *Database) RemoveTags(id int64, tags []string) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, tag := range tags {
+		if _, err := tx.Exec(`
+			DELETE FROM account_tags WHERE watched_account_id = ? AND tag = ?`,
+			id, tag); err != nil {
+			return fmt.Errorf("removing tag %q: %w", tag, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// getTags returns the sorted tags of every account, keyed by account ID
+func (d *Database) getTags() (map[int64][]string, error) {
+	rows, err := d.db.Query(`
+		SELECT watched_account_id, tag FROM account_tags ORDER BY watched_account_id, tag`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := make(map[int64][]string)
+	for rows.Next() {
+		var id int64
+		var tag string
+		if err := rows.Scan(&id, &tag); err != nil {
+			return nil, err
+		}
+		tags[id] = append(tags[id], tag)
+	}
+	return tags, rows.Err()
+}
@@ -0,0 +1,132 @@
+package db
+
+import (
+	"sort"
+	"time"
+)
+
+const (
+	// burstGap separates two bursts of activity: changes detected closer
+	// together than this, like the ones a single check finds, count once
+	burstGap = time.Minute
+	// quietHours is the length of the daily window with the least activity,
+	// taken to be the night
+	quietHours = 6
+	// quietLocalStart is the local hour the night is assumed to start at
+	quietLocalStart = 1
+)
+
+// ActivityHours counts an account's bursts of follows, unfollows and
+// flaps by UTC hour of the day
+type ActivityHours struct {
+	Hours  [24]int // bursts starting in each UTC hour
+	Bursts int
+	Events int // changes the bursts are made of
+}
+
+// TimezoneGuess is the timezone an account's activity suggests
+type TimezoneGuess struct {
+	Offset     int     // hours east of UTC
+	QuietStart int     // UTC hour the quietest window starts at
+	QuietHours int     // length of the window
+	QuietShare float64 // share of the bursts falling in the quiet window
+	PeakHour   int     // UTC hour with the most bursts
+	Confidence string  // low, medium or high
+}
+
+// GetActivityHours counts the bursts of changes of a watched account
+// detected since a time by UTC hour. Changes are timed by the check that
+// noticed them, so they lag the actual follow by up to the check interval.
+func (d *Database) GetActivityHours(accountID int64, since time.Time) (ActivityHours, error) {
+	rows, err := d.db.Query(`
+		SELECT detected_at FROM follow_events
+		WHERE watched_account_id = ? AND detected_at >= ?
+		UNION ALL
+		SELECT flapped_at FROM follow_events
+		WHERE watched_account_id = ? AND flapped_at >= ?`,
+		accountID, since, accountID, since)
+	if err != nil {
+		return ActivityHours{}, err
+	}
+	defer rows.Close()
+
+	var times []time.Time
+	for rows.Next() {
+		var at time.Time
+		if err := rows.Scan(&at); err != nil {
+			return ActivityHours{}, err
+		}
+		times = append(times, at.UTC())
+	}
+	if err := rows.Err(); err != nil {
+		return ActivityHours{}, err
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	activity := ActivityHours{Events: len(times)}
+	var last time.Time
+	for _, at := range times {
+		if last.IsZero() || at.Sub(last) >= burstGap {
+			activity.Hours[at.Hour()]++
+			activity.Bursts++
+		}
+		last = at
+	}
+	return activity, nil
+}
+
+// InferTimezone guesses the UTC offset of an account from when it is
+// active, assuming the six quietest hours of its day are the night,
+// starting around 1am local time. The confidence rises with the number of
+// bursts and with how quiet that window is compared to the rest of the day.
+func InferTimezone(activity ActivityHours) TimezoneGuess {
+	guess := TimezoneGuess{QuietHours: quietHours}
+	var sums [24]int
+	quietest := -1
+	for start := range sums {
+		for h := start; h < start+quietHours; h++ {
+			sums[start] += activity.Hours[h%24]
+		}
+		if quietest < 0 || sums[start] < quietest {
+			quietest = sums[start]
+		}
+	}
+	// A night longer than the window leaves several windows as quiet;
+	// the one in the middle of them is taken
+	for start := range sums {
+		if sums[start] != quietest || sums[(start+23)%24] == quietest {
+			continue
+		}
+		run := 1
+		for run < 24 && sums[(start+run)%24] == quietest {
+			run++
+		}
+		guess.QuietStart = (start + run/2) % 24
+		break
+	}
+	for h := range activity.Hours {
+		if activity.Hours[h] > activity.Hours[guess.PeakHour] {
+			guess.PeakHour = h
+		}
+	}
+
+	guess.Offset = ((quietLocalStart-guess.QuietStart)%24 + 24) % 24
+	if guess.Offset > 12 {
+		guess.Offset -= 24
+	}
+	if activity.Bursts > 0 {
+		guess.QuietShare = float64(quietest) / float64(activity.Bursts)
+	}
+
+	// Activity spread evenly over the day puts a quarter of it in any six
+	// hours
+	switch {
+	case activity.Bursts >= 100 && guess.QuietShare < 0.05:
+		guess.Confidence = "high"
+	case activity.Bursts >= 40 && guess.QuietShare < 0.12:
+		guess.Confidence = "medium"
+	default:
+		guess.Confidence = "low"
+	}
+	return guess
+}
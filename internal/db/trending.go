@@ -0,0 +1,88 @@
+package db
+
+import (
+	"database/sql"
+	"math"
+	"sort"
+	"time"
+)
+
+// GetTrendingTargets ranks the users followed since the given time by how
+// many watched accounts followed them, each follow weighted by how
+// selective the account is: one from an account following 200 users
+// counts for more than one from an account following 20,000. Users
+// followed by a single account and users already watched are left out.
+func (d *Database) GetTrendingTargets(since time.Time, limit int) ([]TrendingTarget, error) {
+	// Each account's newest follow of each user
+	rows, err := d.db.Query(`
+		SELECT e.user_id, a.username, e.detected_at,
+		       (SELECT s.following_count FROM following_count_samples s
+		        WHERE s.watched_account_id = a.id
+		        ORDER BY s.sampled_at DESC LIMIT 1)
+		FROM follow_events e
+		JOIN watched_accounts a ON a.id = e.watched_account_id
+		WHERE e.id IN (
+		    SELECT MAX(id) FROM follow_events
+		    WHERE event_type = ? AND detected_at >= ?
+		    GROUP BY user_id, watched_account_id)
+		  AND a.archived_at IS NULL
+		  AND e.user_id NOT IN (SELECT user_id FROM watched_accounts WHERE user_id IS NOT NULL AND archived_at IS NULL)`,
+		EventTypeFollow, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	targets := make(map[string]*TrendingTarget)
+	for rows.Next() {
+		var userID, username string
+		var followedAt time.Time
+		var following sql.NullInt64
+		if err := rows.Scan(&userID, &username, &followedAt, &following); err != nil {
+			return nil, err
+		}
+
+		target := targets[userID]
+		if target == nil {
+			target = &TrendingTarget{UserID: userID}
+			targets[userID] = target
+		}
+		target.FollowedBy = append(target.FollowedBy, username)
+		target.Score += selectivity(int(following.Int64))
+		if followedAt.After(target.LastFollowedAt) {
+			target.LastFollowedAt = followedAt
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var trending []TrendingTarget
+	for _, target := range targets {
+		if len(target.FollowedBy) > 1 {
+			sort.Strings(target.FollowedBy)
+			trending = append(trending, *target)
+		}
+	}
+	// Highest score first; ties keep the most recent follow first
+	sort.Slice(trending, func(i, j int) bool {
+		if trending[i].Score != trending[j].Score {
+			return trending[i].Score > trending[j].Score
+		}
+		return trending[i].LastFollowedAt.After(trending[j].LastFollowedAt)
+	})
+	if limit > 0 && len(trending) > limit {
+		trending = trending[:limit]
+	}
+	return trending, nil
+}
+
+// selectivity weighs a follow by the following count of the account that
+// made it: 1 for an account following 10 users, 0.25 for one following
+// 10,000. Accounts without a recorded count weigh as following 1,000.
+func selectivity(following int) float64 {
+	if following <= 0 {
+		following = 1000
+	}
+	return 1 / math.Log10(float64(max(following, 10)))
+}
@@ -0,0 +1,160 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// View is a SQL view shaped for Grafana's SQLite and Postgres
+// datasources: a "time" column of Unix seconds and one row per series
+// point, so panels can query it without custom SQL
+type View struct {
+	Name        string
+	Description string
+	Columns     []ViewColumn
+	query       func(d dialect) string
+}
+
+// ViewColumn documents one column of a view
+type ViewColumn struct {
+	Name        string
+	Description string
+}
+
+// Views are the dashboard views installed by InstallViews
+var Views = []View{
+	{
+		Name:        "events_daily",
+		Description: "Follows and unfollows per watched account and UTC day, leaving out dismissed events and including archived ones",
+		Columns: []ViewColumn{
+			{"time", "start of the day, Unix seconds"},
+			{"day", "the day as YYYY-MM-DD"},
+			{"account", "watched account username"},
+			{"follows", "follows recorded that day"},
+			{"unfollows", "unfollows recorded that day"},
+		},
+		query: func(d dialect) string {
+			return `
+			SELECT ` + d.unixTime("c.day", true) + ` AS time,
+			       c.day AS day,
+			       a.username AS account,
+			       SUM(c.follows) AS follows,
+			       SUM(c.unfollows) AS unfollows
+			FROM (
+				SELECT watched_account_id, ` + d.utcDay("detected_at") + ` AS day,
+				       CASE WHEN event_type = 'follow' THEN 1 ELSE 0 END AS follows,
+				       CASE WHEN event_type = 'unfollow' THEN 1 ELSE 0 END AS unfollows
+				FROM follow_events
+				WHERE dismissed_at IS NULL
+				UNION ALL
+				SELECT watched_account_id, day, follows, unfollows
+				FROM archived_event_counts
+			) c
+			JOIN watched_accounts a ON a.id = c.watched_account_id
+			GROUP BY c.day, a.id`
+		},
+	},
+	{
+		Name:        "account_growth",
+		Description: "Following count of every watched account at each check",
+		Columns: []ViewColumn{
+			{"time", "when the count was sampled, Unix seconds"},
+			{"account", "watched account username"},
+			{"following", "number of accounts it followed"},
+		},
+		query: func(d dialect) string {
+			return `
+			SELECT ` + d.unixTime("s.sampled_at", false) + ` AS time,
+			       a.username AS account,
+			       s.following_count AS following
+			FROM following_count_samples s
+			JOIN watched_accounts a ON a.id = s.watched_account_id`
+		},
+	},
+	{
+		Name:        "quota_usage",
+		Description: "API quota left after every check cycle and what the cycle used",
+		Columns: []ViewColumn{
+			{"time", "when the cycle started, Unix seconds"},
+			{"quota_remaining", "requests left in the plan's period"},
+			{"quota_used", "requests used since the previous cycle, NULL after the quota was reset"},
+			{"accounts", "accounts checked"},
+			{"failures", "accounts whose check failed"},
+			{"duration_seconds", "how long the cycle took"},
+		},
+		query: func(d dialect) string {
+			return `
+			SELECT time, quota_remaining,
+			       CASE WHEN previous >= quota_remaining THEN previous - quota_remaining END AS quota_used,
+			       accounts, failures, duration_seconds
+			FROM (
+				SELECT ` + d.unixTime("started_at", false) + ` AS time,
+				       quota_remaining,
+				       LAG(quota_remaining) OVER (ORDER BY id) AS previous,
+				       accounts, failures,
+				       ` + d.seconds("started_at", "finished_at") + ` AS duration_seconds
+				FROM check_runs
+			) cycles`
+		},
+	},
+	{
+		Name:        "insight_log",
+		Description: "Insights found by the insights job: new mutuals, follow sprees, coordinated follows and churn",
+		Columns: []ViewColumn{
+			{"time", "when the insight was first found, Unix seconds"},
+			{"kind", "mutual, spree, coordinated or churn"},
+			{"summary", "the insight in one sentence"},
+			{"accounts", "watched accounts involved, as a JSON array of usernames"},
+		},
+		query: func(d dialect) string {
+			return `
+			SELECT ` + d.unixTime("detected_at", false) + ` AS time,
+			       kind, summary, accounts
+			FROM insights`
+		},
+	},
+}
+
+// InstallViews creates the dashboard views, replacing earlier versions of
+// them
+func (d *Database) InstallViews() error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, view := range Views {
+		if _, err := tx.Exec("DROP VIEW IF EXISTS " + view.Name); err != nil {
+			return fmt.Errorf("dropping view %s: %w", view.Name, err)
+		}
+		if _, err := tx.Exec("CREATE VIEW " + view.Name + " AS " + view.query(d.dialect)); err != nil {
+			return fmt.Errorf("creating view %s: %w", view.Name, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// RemoveViews drops the dashboard views
+func (d *Database) RemoveViews() error {
+	for _, view := range Views {
+		if _, err := d.db.Exec("DROP VIEW IF EXISTS " + view.Name); err != nil {
+			return fmt.Errorf("dropping view %s: %w", view.Name, err)
+		}
+	}
+	return nil
+}
+
+// refreshViews recreates the dashboard views if they were installed, so
+// they keep up with schema changes
+func (d *Database) refreshViews() error {
+	var name string
+	err := d.db.QueryRow(d.dialect.objects("view"), Views[0].Name).Scan(&name)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return d.InstallViews()
+}
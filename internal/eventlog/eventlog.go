@@ -0,0 +1,114 @@
+// Package eventlog appends follow events to a JSON Lines file, one object
+// per line, for tools that tail it instead of querying the database
+package eventlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Record is one line of the event log. A flap rewrites the event it was
+// collapsed into, so a UUID seen again carries the event's latest state.
+type Record struct {
+	UUID       string     `json:"uuid"`
+	ID         int64      `json:"id"`
+	Account    string     `json:"account"`
+	AccountID  int64      `json:"account_id"`
+	UserID     string     `json:"user_id"`
+	EventType  string     `json:"event_type"`
+	DetectedAt time.Time  `json:"detected_at"`
+	Flaps      int        `json:"flaps"`
+	FlappedAt  *time.Time `json:"flapped_at,omitempty"`
+}
+
+// Options is where the log is written and when it rotates
+type Options struct {
+	Path     string
+	MaxSize  int64 // bytes before the file is rotated, 0 never rotates
+	MaxFiles int   // rotated files kept, 0 keeps all
+}
+
+// mu keeps the appends and rotations of one process in order
+var mu sync.Mutex
+
+// Append writes records to the end of the log, one line each, rotating it
+// first if they would take it past MaxSize. The file is opened for each
+// batch, so a rotation by another process or a logrotate run is picked up.
+func Append(opts Options, records []Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	var lines bytes.Buffer
+	encoder := json.NewEncoder(&lines)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(opts.Path), 0755); err != nil {
+		return fmt.Errorf("creating event log directory: %w", err)
+	}
+	if opts.MaxSize > 0 {
+		if info, err := os.Stat(opts.Path); err == nil && info.Size() > 0 && info.Size()+int64(lines.Len()) > opts.MaxSize {
+			if err := rotate(opts); err != nil {
+				return fmt.Errorf("rotating event log: %w", err)
+			}
+		}
+	}
+
+	file, err := os.OpenFile(opts.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("opening event log: %w", err)
+	}
+	// One write per batch, so a reader tailing the file gets whole lines
+	if _, err := file.Write(lines.Bytes()); err != nil {
+		file.Close()
+		return fmt.Errorf("writing event log: %w", err)
+	}
+	return file.Close()
+}
+
+// rotate renames the log to path.20060102-150405.000000 and deletes the
+// oldest rotated files beyond MaxFiles
+func rotate(opts Options) error {
+	rotated := opts.Path + "." + time.Now().Format("20060102-150405.000000")
+	if err := os.Rename(opts.Path, rotated); err != nil {
+		return err
+	}
+	if opts.MaxFiles <= 0 {
+		return nil
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(opts.Path))
+	if err != nil {
+		return err
+	}
+	prefix := filepath.Base(opts.Path) + "."
+	var names []string
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), prefix) && !entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	// Timestamps sort oldest first
+	sort.Strings(names)
+	for len(names) > opts.MaxFiles {
+		if err := os.Remove(filepath.Join(filepath.Dir(opts.Path), names[0])); err != nil {
+			return err
+		}
+		names = names[1:]
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package format
+
+import (
+	"fmt"
+	"time"
+)
+
+// Age describes a long duration in its largest whole unit, e.g. "3 months"
+// or "12 days"
+func Age(d time.Duration) string {
+	const day = 24 * time.Hour
+	switch {
+	case d >= 365*day:
+		return unit(int(d/(365*day)), "year")
+	case d >= 30*day:
+		return unit(int(d/(30*day)), "month")
+	case d >= day:
+		return unit(int(d/day), "day")
+	case d >= time.Hour:
+		return unit(int(d/time.Hour), "hour")
+	default:
+		return "less than an hour"
+	}
+}
+
+func unit(n int, name string) string {
+	if n == 1 {
+		return "1 " + name
+	}
+	return fmt.Sprintf("%d %ss", n, name)
+}
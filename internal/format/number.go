@@ -0,0 +1,150 @@
+package format
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Number styles
+const (
+	StylePlain   = "plain"   // 1234567
+	StyleGrouped = "grouped" // 1,234,567
+	StyleCompact = "compact" // 1.2M
+)
+
+// separators holds the thousands and decimal separators of a locale
+type separators struct {
+	thousands string
+	decimal   string
+	lakh      bool // digits above the thousands go in pairs, e.g. 12,34,567
+}
+
+var locales = map[string]separators{
+	"en": {thousands: ",", decimal: "."},
+	"de": {thousands: ".", decimal: ","},
+	"fr": {thousands: " ", decimal: ","}, // narrow no-break space
+	"ch": {thousands: "'", decimal: "."},
+	"in": {thousands: ",", decimal: ".", lakh: true},
+}
+
+var (
+	mu     sync.RWMutex
+	style  = StylePlain
+	locale = locales["en"]
+)
+
+// Configure sets the number style and locale used by Number
+func Configure(numberStyle, localeName string) error {
+	seps, ok := locales[strings.ToLower(localeName)]
+	if !ok {
+		return fmt.Errorf("unsupported number locale %q", localeName)
+	}
+	switch numberStyle {
+	case StylePlain, StyleGrouped, StyleCompact:
+	default:
+		return fmt.Errorf("unsupported number format %q, expected %s, %s or %s",
+			numberStyle, StylePlain, StyleGrouped, StyleCompact)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	style = numberStyle
+	locale = seps
+	return nil
+}
+
+// Number formats a count in the configured style
+func Number(n int) string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	switch style {
+	case StyleGrouped:
+		return group(n, locale)
+	case StyleCompact:
+		return compact(n, locale)
+	}
+	return strconv.Itoa(n)
+}
+
+// group inserts the locale's separator between every three digits, or
+// after the last three and then every two with lakh grouping
+func group(n int, seps separators) string {
+	digits := strconv.Itoa(n)
+	sign := ""
+	if n < 0 {
+		sign, digits = "-", digits[1:]
+	}
+
+	var s strings.Builder
+	for i, d := range digits {
+		if i > 0 && seps.groupsAt(len(digits)-i) {
+			s.WriteString(seps.thousands)
+		}
+		s.WriteRune(d)
+	}
+	return sign + s.String()
+}
+
+// groupsAt reports whether a separator goes before the digit that has left
+// digits from it to the end, itself included
+func (seps separators) groupsAt(left int) bool {
+	if seps.lakh && left > 3 {
+		return (left-3)%2 == 0
+	}
+	return left%3 == 0
+}
+
+// Compact abbreviates a count to one decimal, e.g. 1.2M, whatever the
+// configured style, with the configured locale's decimal separator
+func Compact(n int) string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return compact(n, locale)
+}
+
+// compact abbreviates large numbers to one decimal, e.g. 1.2M or 45K
+func compact(n int, seps separators) string {
+	abs := n
+	if abs < 0 {
+		abs = -abs
+	}
+
+	units := []struct {
+		size   float64
+		suffix string
+	}{
+		{1e9, "B"},
+		{1e6, "M"},
+		{1e3, "K"},
+	}
+	for i, unit := range units {
+		if float64(abs) < unit.size {
+			continue
+		}
+		value := float64(n) / unit.size
+		// 999,950 would round to 1000K; the next unit up shows it as 1M
+		if i > 0 && math.Abs(roundCompact(value)) >= 1000 {
+			unit = units[i-1]
+			value = float64(n) / unit.size
+		}
+		text := strconv.FormatFloat(value, 'f', 1, 64)
+		// Drop a trailing ".0" and values of 10 and above don't need a decimal
+		if strings.HasSuffix(text, ".0") || value >= 10 || value <= -10 {
+			text = strconv.FormatFloat(value, 'f', 0, 64)
+		}
+		return strings.Replace(text, ".", seps.decimal, 1) + unit.suffix
+	}
+	return strconv.Itoa(n)
+}
+
+// roundCompact rounds value to the precision compact shows it with
+func roundCompact(value float64) float64 {
+	if math.Abs(value) < 10 {
+		return math.Round(value*10) / 10
+	}
+	return math.Round(value)
+}
@@ -0,0 +1,484 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Request is a GraphQL request as sent over HTTP
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// Response is the result of a request. Data is nil if the request failed
+// before anything was resolved; otherwise fields that failed are null and
+// their errors listed.
+type Response struct {
+	Data   any     `json:"data,omitempty"`
+	Errors []Error `json:"errors,omitempty"`
+}
+
+// Error describes what went wrong, and where for field errors
+type Error struct {
+	Message string `json:"message"`
+	Path    []any  `json:"path,omitempty"`
+}
+
+// Execute runs the query in req
+func (s *Schema) Execute(ctx context.Context, req Request) Response {
+	fail := func(err error) Response {
+		return Response{Errors: []Error{{Message: err.Error()}}}
+	}
+
+	doc, err := parse(req.Query)
+	if err != nil {
+		return fail(err)
+	}
+	op, err := doc.operation(req.OperationName)
+	if err != nil {
+		return fail(err)
+	}
+	if op.kind != "query" {
+		return fail(fmt.Errorf("only queries are supported, not %ss", op.kind))
+	}
+	if err := doc.validate(op); err != nil {
+		return fail(err)
+	}
+
+	e := &executor{schema: s, fragments: doc.fragments, variables: map[string]any{}}
+	for _, def := range op.variables {
+		value, given := req.Variables[def.name]
+		if !given && def.hasDefault {
+			value, given = def.defaultValue, true
+		}
+		if !given && !strings.HasSuffix(def.typ, "!") {
+			continue
+		}
+		// Checked here, coerced to each argument's type where it is used
+		if _, err := coerce(value, def.typ); err != nil {
+			return fail(fmt.Errorf("variable $%s: %w", def.name, err))
+		}
+		e.variables[def.name] = value
+	}
+
+	data := e.selectObject(ctx, s.query, nil, op.selections, nil)
+	if data == nil {
+		return Response{Errors: e.errors}
+	}
+	return Response{Data: data, Errors: e.errors}
+}
+
+// operation picks the operation to run: the one called name, or the only one
+func (d *document) operation(name string) (*operation, error) {
+	if name == "" {
+		if len(d.operations) > 1 {
+			return nil, fmt.Errorf("the document has several operations; choose one with operationName")
+		}
+		return d.operations[0], nil
+	}
+	for _, op := range d.operations {
+		if op.name == name {
+			return op, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown operation %q", name)
+}
+
+type executor struct {
+	schema    *Schema
+	fragments map[string]*fragment
+	variables map[string]any
+	errors    []Error
+}
+
+func (e *executor) fail(path []any, format string, args ...any) {
+	e.errors = append(e.errors, Error{Message: fmt.Sprintf(format, args...), Path: path})
+}
+
+// fieldGroup is the fields selected under one response key, whose
+// selections are merged
+type fieldGroup struct {
+	key    string
+	fields []*fieldNode
+}
+
+// selectObject resolves the selected fields of an object
+func (e *executor) selectObject(ctx context.Context, object *Object, source any, selections []selection, path []any) *orderedObject {
+	groups, err := e.collectFields(object, selections, map[string]bool{}, nil)
+	if err != nil {
+		e.fail(path, "%v", err)
+		return nil
+	}
+
+	result := &orderedObject{}
+	for _, group := range groups {
+		fieldPath := append(path[:len(path):len(path)], group.key)
+		result.keys = append(result.keys, group.key)
+		result.values = append(result.values, e.resolveField(ctx, object, source, group, fieldPath))
+	}
+	return result
+}
+
+// collectFields flattens fragments and groups the fields that remain by
+// response key, in the order they are first selected
+func (e *executor) collectFields(object *Object, selections []selection, visited map[string]bool, groups []*fieldGroup) ([]*fieldGroup, error) {
+	for _, sel := range selections {
+		switch sel := sel.(type) {
+		case *fieldNode:
+			include, err := e.included(sel.directives)
+			if err != nil {
+				return nil, err
+			}
+			if !include {
+				continue
+			}
+			found := false
+			for _, group := range groups {
+				if group.key == sel.key() {
+					if group.fields[0].name != sel.name {
+						return nil, fmt.Errorf("%q selects both %s and %s", sel.key(), group.fields[0].name, sel.name)
+					}
+					group.fields = append(group.fields, sel)
+					found = true
+					break
+				}
+			}
+			if !found {
+				groups = append(groups, &fieldGroup{key: sel.key(), fields: []*fieldNode{sel}})
+			}
+
+		case *fragmentSpread:
+			include, err := e.included(sel.directives)
+			if err != nil {
+				return nil, err
+			}
+			if !include || visited[sel.name] {
+				continue
+			}
+			visited[sel.name] = true
+			frag := e.fragments[sel.name]
+			if frag == nil {
+				return nil, fmt.Errorf("unknown fragment %q", sel.name)
+			}
+			if err := e.checkTypeCondition(object, frag.typeCondition); err != nil {
+				return nil, err
+			}
+			if groups, err = e.collectFields(object, frag.selections, visited, groups); err != nil {
+				return nil, err
+			}
+
+		case *inlineFragment:
+			include, err := e.included(sel.directives)
+			if err != nil {
+				return nil, err
+			}
+			if !include {
+				continue
+			}
+			if sel.typeCondition != "" {
+				if err := e.checkTypeCondition(object, sel.typeCondition); err != nil {
+					return nil, err
+				}
+			}
+			if groups, err = e.collectFields(object, sel.selections, visited, groups); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return groups, nil
+}
+
+// checkTypeCondition rejects fragments on another type; without interfaces
+// or unions a fragment can only apply to the object it is spread in
+func (e *executor) checkTypeCondition(object *Object, typeCondition string) error {
+	if typeCondition != object.Name {
+		return fmt.Errorf("a fragment on %s can't be spread in %s", typeCondition, object.Name)
+	}
+	return nil
+}
+
+// included evaluates the @include and @skip directives
+func (e *executor) included(directives []directive) (bool, error) {
+	for _, d := range directives {
+		if d.name != "include" && d.name != "skip" {
+			return false, fmt.Errorf("unknown directive @%s", d.name)
+		}
+		value, err := e.resolveValue(d.arguments["if"])
+		if err != nil {
+			return false, err
+		}
+		condition, ok := value.(bool)
+		if !ok {
+			return false, fmt.Errorf("@%s needs a Boolean if argument", d.name)
+		}
+		if condition == (d.name == "skip") {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// resolveField resolves one field and its selections, recording an error
+// and returning null if it fails
+func (e *executor) resolveField(ctx context.Context, object *Object, source any, group *fieldGroup, path []any) any {
+	// A client that went away or a server shutting down stops the query
+	if err := ctx.Err(); err != nil {
+		e.fail(path, "%v", err)
+		return nil
+	}
+	node := group.fields[0]
+	if node.name == "__typename" {
+		return object.Name
+	}
+	field := object.field(node.name)
+	if field == nil {
+		e.fail(path, "%s has no field %q", object.Name, node.name)
+		return nil
+	}
+
+	args, err := e.arguments(field, node.arguments)
+	if err != nil {
+		e.fail(path, "%v", err)
+		return nil
+	}
+	value, err := field.Resolve(ctx, source, args)
+	if err != nil {
+		e.fail(path, "%v", err)
+		return nil
+	}
+
+	var selections []selection
+	for _, f := range group.fields {
+		selections = append(selections, f.selections...)
+	}
+	return e.complete(ctx, field.Type, value, selections, path)
+}
+
+// arguments coerces the arguments given to a field to their declared types
+func (e *executor) arguments(field *Field, given map[string]any) (Args, error) {
+	for name := range given {
+		known := false
+		for _, arg := range field.Args {
+			known = known || arg.Name == name
+		}
+		if !known {
+			return nil, fmt.Errorf("%s has no argument %q", field.Name, name)
+		}
+	}
+
+	args := Args{}
+	for _, arg := range field.Args {
+		literal, ok := given[arg.Name]
+		if v, isVariable := literal.(variable); ok && isVariable {
+			_, ok = e.variables[string(v)]
+		}
+		if !ok {
+			if arg.Default != nil {
+				args[arg.Name] = arg.Default
+			} else if strings.HasSuffix(arg.Type, "!") {
+				return nil, fmt.Errorf("argument %q of %s is required", arg.Name, field.Name)
+			}
+			continue
+		}
+
+		value, err := e.resolveValue(literal)
+		if err != nil {
+			return nil, err
+		}
+		if args[arg.Name], err = coerce(value, arg.Type); err != nil {
+			return nil, fmt.Errorf("argument %q: %w", arg.Name, err)
+		}
+	}
+	return args, nil
+}
+
+// resolveValue replaces the variables in a literal by their values
+func (e *executor) resolveValue(literal any) (any, error) {
+	switch literal := literal.(type) {
+	case variable:
+		return e.variables[string(literal)], nil
+	case []any:
+		list := make([]any, len(literal))
+		for i, item := range literal {
+			value, err := e.resolveValue(item)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = value
+		}
+		return list, nil
+	case map[string]any:
+		object := make(map[string]any, len(literal))
+		for key, item := range literal {
+			value, err := e.resolveValue(item)
+			if err != nil {
+				return nil, err
+			}
+			object[key] = value
+		}
+		return object, nil
+	}
+	return literal, nil
+}
+
+// coerce converts an input value, from the document or the JSON variables,
+// to the Go type of the GraphQL type typ
+func coerce(value any, typ string) (any, error) {
+	nonNull := strings.HasSuffix(typ, "!")
+	typ = strings.TrimSuffix(typ, "!")
+	if value == nil {
+		if nonNull {
+			return nil, fmt.Errorf("expected %s!, got null", typ)
+		}
+		return nil, nil
+	}
+
+	if strings.HasPrefix(typ, "[") {
+		inner := typ[1 : len(typ)-1]
+		items, ok := value.([]any)
+		if !ok {
+			items = []any{value}
+		}
+		list := make([]any, len(items))
+		for i, item := range items {
+			var err error
+			if list[i], err = coerce(item, inner); err != nil {
+				return nil, err
+			}
+		}
+		return list, nil
+	}
+
+	switch typ {
+	case "Int":
+		switch n := value.(type) {
+		case int64:
+			if n >= math.MinInt32 && n <= math.MaxInt32 {
+				return int(n), nil
+			}
+		case float64:
+			if n == math.Trunc(n) && n >= math.MinInt32 && n <= math.MaxInt32 {
+				return int(n), nil
+			}
+		}
+	case "Float":
+		switch n := value.(type) {
+		case int64:
+			return float64(n), nil
+		case float64:
+			return n, nil
+		}
+	case "Boolean":
+		if b, ok := value.(bool); ok {
+			return b, nil
+		}
+	case "ID":
+		switch id := value.(type) {
+		case string:
+			return id, nil
+		case int64:
+			return fmt.Sprint(id), nil
+		}
+	default:
+		// String and custom scalars
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+	}
+	return nil, fmt.Errorf("expected %s, got %s", typ, describe(value))
+}
+
+// describe names an input value in error messages
+func describe(value any) string {
+	switch value := value.(type) {
+	case enumValue:
+		return string(value)
+	case string:
+		return fmt.Sprintf("%q", value)
+	case []any:
+		return "a list"
+	case map[string]any:
+		return "an object"
+	}
+	return fmt.Sprint(value)
+}
+
+// complete turns a resolved value into its response shape: lists item by
+// item, objects by resolving their selections and scalars as they are
+func (e *executor) complete(ctx context.Context, typ string, value any, selections []selection, path []any) any {
+	typ = strings.TrimSuffix(typ, "!")
+	if value == nil {
+		return nil
+	}
+
+	if strings.HasPrefix(typ, "[") {
+		items := reflect.ValueOf(value)
+		if items.Kind() != reflect.Slice {
+			e.fail(path, "expected a list")
+			return nil
+		}
+		list := make([]any, items.Len())
+		for i := range list {
+			list[i] = e.complete(ctx, typ[1:len(typ)-1], items.Index(i).Interface(), selections, append(path[:len(path):len(path)], i))
+		}
+		return list
+	}
+
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Pointer && v.IsNil() {
+		return nil
+	}
+	if object := e.schema.objects[typ]; object != nil {
+		if len(selections) == 0 {
+			e.fail(path, "a field of type %s needs a selection of subfields", typ)
+			return nil
+		}
+		return e.selectObject(ctx, object, value, selections, path)
+	}
+	if len(selections) > 0 {
+		e.fail(path, "%s has no subfields", typ)
+		return nil
+	}
+
+	switch value := value.(type) {
+	case time.Time:
+		return value.Format(time.RFC3339)
+	case *time.Time:
+		return value.Format(time.RFC3339)
+	}
+	return value
+}
+
+// orderedObject is a response object that keeps its fields in the order
+// they were selected
+type orderedObject struct {
+	keys   []string
+	values []any
+}
+
+func (o *orderedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(key)
+		value, err := json.Marshal(o.values[i])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
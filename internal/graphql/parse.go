@@ -0,0 +1,657 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunct
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+// token is a lexical token; the text of a string token is its decoded value
+type token struct {
+	kind      tokenKind
+	text      string
+	line, col int
+}
+
+// lex splits a query document into tokens, dropping whitespace, commas and
+// comments
+func lex(src string) ([]token, error) {
+	var tokens []token
+	line, col := 1, 1
+	advance := func(n int) {
+		for _, r := range src[:n] {
+			if r == '\n' {
+				line, col = line+1, 1
+			} else {
+				col++
+			}
+		}
+		src = src[n:]
+	}
+
+	for {
+		// Insignificant characters
+		for len(src) > 0 {
+			switch c := src[0]; {
+			case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+				advance(1)
+				continue
+			case strings.HasPrefix(src, "\uFEFF"):
+				advance(len("\uFEFF"))
+				continue
+			case c == '#':
+				end := strings.IndexByte(src, '\n')
+				if end < 0 {
+					end = len(src)
+				}
+				advance(end)
+				continue
+			}
+			break
+		}
+		if len(src) == 0 {
+			return append(tokens, token{kind: tokenEOF, line: line, col: col}), nil
+		}
+
+		start := token{line: line, col: col}
+		c := src[0]
+		switch {
+		case strings.HasPrefix(src, "..."):
+			start.kind, start.text = tokenPunct, "..."
+			advance(3)
+		case strings.IndexByte("!$&()[]{}:=@|", c) >= 0:
+			start.kind, start.text = tokenPunct, src[:1]
+			advance(1)
+		case c == '_' || isLetter(c):
+			n := 1
+			for n < len(src) && (src[n] == '_' || isLetter(src[n]) || isDigit(src[n])) {
+				n++
+			}
+			start.kind, start.text = tokenName, src[:n]
+			advance(n)
+		case c == '-' || isDigit(c):
+			n, float := scanNumber(src)
+			if n == 0 {
+				return nil, fmt.Errorf("%d:%d: invalid number", line, col)
+			}
+			start.kind, start.text = tokenInt, src[:n]
+			if float {
+				start.kind = tokenFloat
+			}
+			advance(n)
+		case strings.HasPrefix(src, `"""`):
+			end := strings.Index(src[3:], `"""`)
+			if end < 0 {
+				return nil, fmt.Errorf("%d:%d: unterminated string", line, col)
+			}
+			start.kind, start.text = tokenString, blockString(src[3:3+end])
+			advance(end + 6)
+		case c == '"':
+			value, n, err := scanString(src)
+			if err != nil {
+				return nil, fmt.Errorf("%d:%d: %w", line, col, err)
+			}
+			start.kind, start.text = tokenString, value
+			advance(n)
+		default:
+			r, _ := utf8.DecodeRuneInString(src)
+			return nil, fmt.Errorf("%d:%d: unexpected character %q", line, col, r)
+		}
+		tokens = append(tokens, start)
+	}
+}
+
+func isLetter(c byte) bool { return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' }
+func isDigit(c byte) bool  { return c >= '0' && c <= '9' }
+
+// scanNumber returns the length of the number src starts with and whether
+// it has a fraction or exponent, or 0 if it isn't a valid number
+func scanNumber(src string) (int, bool) {
+	n := 0
+	if src[0] == '-' {
+		n++
+	}
+	digits := func() int {
+		start := n
+		for n < len(src) && isDigit(src[n]) {
+			n++
+		}
+		return n - start
+	}
+	if digits() == 0 {
+		return 0, false
+	}
+	float := false
+	if n < len(src) && src[n] == '.' {
+		n++
+		if digits() == 0 {
+			return 0, false
+		}
+		float = true
+	}
+	if n < len(src) && (src[n] == 'e' || src[n] == 'E') {
+		n++
+		if n < len(src) && (src[n] == '+' || src[n] == '-') {
+			n++
+		}
+		if digits() == 0 {
+			return 0, false
+		}
+		float = true
+	}
+	return n, float
+}
+
+// scanString decodes the quoted string src starts with, returning its value
+// and length in src
+func scanString(src string) (string, int, error) {
+	var value strings.Builder
+	for i := 1; i < len(src); i++ {
+		switch c := src[i]; c {
+		case '"':
+			return value.String(), i + 1, nil
+		case '\n', '\r':
+			return "", 0, fmt.Errorf("unterminated string")
+		case '\\':
+			if i+1 >= len(src) {
+				return "", 0, fmt.Errorf("unterminated string")
+			}
+			i++
+			switch src[i] {
+			case '"', '\\', '/':
+				value.WriteByte(src[i])
+			case 'b':
+				value.WriteByte('\b')
+			case 'f':
+				value.WriteByte('\f')
+			case 'n':
+				value.WriteByte('\n')
+			case 'r':
+				value.WriteByte('\r')
+			case 't':
+				value.WriteByte('\t')
+			case 'u':
+				if i+5 > len(src) {
+					return "", 0, fmt.Errorf("invalid unicode escape")
+				}
+				code, err := strconv.ParseUint(src[i+1:i+5], 16, 32)
+				if err != nil {
+					return "", 0, fmt.Errorf("invalid unicode escape")
+				}
+				value.WriteRune(rune(code))
+				i += 4
+			default:
+				return "", 0, fmt.Errorf("invalid escape \\%c", src[i])
+			}
+		default:
+			value.WriteByte(c)
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string")
+}
+
+// blockString strips the common indentation and blank first and last lines
+// of a """block string"""
+func blockString(raw string) string {
+	lines := strings.Split(strings.ReplaceAll(strings.ReplaceAll(raw, "\r\n", "\n"), `\"""`, `"""`), "\n")
+	indent := -1
+	for _, line := range lines[1:] {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed != "" && (indent < 0 || len(line)-len(trimmed) < indent) {
+			indent = len(line) - len(trimmed)
+		}
+	}
+	for i := 1; i < len(lines) && indent > 0; i++ {
+		if len(lines[i]) >= indent {
+			lines[i] = lines[i][indent:]
+		} else {
+			lines[i] = ""
+		}
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// document is a parsed query document
+type document struct {
+	operations []*operation
+	fragments  map[string]*fragment
+}
+
+type operation struct {
+	kind       string // "query", "mutation" or "subscription"
+	name       string
+	variables  []variableDefinition
+	selections []selection
+}
+
+type variableDefinition struct {
+	name         string
+	typ          string
+	defaultValue any
+	hasDefault   bool
+}
+
+type fragment struct {
+	name          string
+	typeCondition string
+	selections    []selection
+}
+
+// selection is a *fieldNode, *fragmentSpread or *inlineFragment
+type selection any
+
+type fieldNode struct {
+	alias, name string
+	arguments   map[string]any
+	directives  []directive
+	selections  []selection
+}
+
+// key is the name the field's value is returned under
+func (f *fieldNode) key() string {
+	if f.alias != "" {
+		return f.alias
+	}
+	return f.name
+}
+
+type fragmentSpread struct {
+	name       string
+	directives []directive
+}
+
+type inlineFragment struct {
+	typeCondition string // "" applies to any type
+	directives    []directive
+	selections    []selection
+}
+
+type directive struct {
+	name      string
+	arguments map[string]any
+}
+
+// Values in the document are int64, float64, string, bool, nil, enumValue,
+// variable, []any and map[string]any
+type (
+	enumValue string
+	variable  string
+)
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// parse parses a query document
+func parse(src string) (*document, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	doc := &document{fragments: map[string]*fragment{}}
+	for p.peek().kind != tokenEOF {
+		switch {
+		case p.is("{"):
+			selections, err := p.selectionSet()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, &operation{kind: "query", selections: selections})
+		case p.is("query") || p.is("mutation") || p.is("subscription"):
+			op, err := p.operation()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, op)
+		case p.is("fragment"):
+			frag, err := p.fragment()
+			if err != nil {
+				return nil, err
+			}
+			if doc.fragments[frag.name] != nil {
+				return nil, fmt.Errorf("fragment %q is defined twice", frag.name)
+			}
+			doc.fragments[frag.name] = frag
+		default:
+			return nil, p.unexpected()
+		}
+	}
+	if len(doc.operations) == 0 {
+		return nil, fmt.Errorf("the document has no operation")
+	}
+	return doc, nil
+}
+
+func (p *parser) peek() token { return p.tokens[p.pos] }
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// is reports whether the next token is the punctuator or name text
+func (p *parser) is(text string) bool {
+	t := p.peek()
+	return (t.kind == tokenPunct || t.kind == tokenName) && t.text == text
+}
+
+func (p *parser) expect(text string) error {
+	if !p.is(text) {
+		return p.unexpected()
+	}
+	p.next()
+	return nil
+}
+
+func (p *parser) unexpected() error {
+	t := p.peek()
+	if t.kind == tokenEOF {
+		return fmt.Errorf("%d:%d: unexpected end of document", t.line, t.col)
+	}
+	return fmt.Errorf("%d:%d: unexpected %q", t.line, t.col, t.text)
+}
+
+func (p *parser) name() (string, error) {
+	if p.peek().kind != tokenName {
+		return "", p.unexpected()
+	}
+	return p.next().text, nil
+}
+
+func (p *parser) operation() (*operation, error) {
+	op := &operation{kind: p.next().text}
+	if p.peek().kind == tokenName {
+		op.name = p.next().text
+	}
+	if p.is("(") {
+		p.next()
+		for !p.is(")") {
+			if err := p.expect("$"); err != nil {
+				return nil, err
+			}
+			def := variableDefinition{}
+			var err error
+			if def.name, err = p.name(); err != nil {
+				return nil, err
+			}
+			if err := p.expect(":"); err != nil {
+				return nil, err
+			}
+			if def.typ, err = p.typeRef(); err != nil {
+				return nil, err
+			}
+			if p.is("=") {
+				p.next()
+				if def.defaultValue, err = p.value(true); err != nil {
+					return nil, err
+				}
+				def.hasDefault = true
+			}
+			op.variables = append(op.variables, def)
+		}
+		p.next()
+	}
+	if _, err := p.directives(); err != nil {
+		return nil, err
+	}
+	var err error
+	op.selections, err = p.selectionSet()
+	return op, err
+}
+
+// typeRef parses a type reference such as [String!]! into its text
+func (p *parser) typeRef() (string, error) {
+	var typ string
+	if p.is("[") {
+		p.next()
+		inner, err := p.typeRef()
+		if err != nil {
+			return "", err
+		}
+		if err := p.expect("]"); err != nil {
+			return "", err
+		}
+		typ = "[" + inner + "]"
+	} else {
+		name, err := p.name()
+		if err != nil {
+			return "", err
+		}
+		typ = name
+	}
+	if p.is("!") {
+		p.next()
+		typ += "!"
+	}
+	return typ, nil
+}
+
+func (p *parser) fragment() (*fragment, error) {
+	p.next()
+	frag := &fragment{}
+	var err error
+	if frag.name, err = p.name(); err != nil {
+		return nil, err
+	}
+	if frag.name == "on" {
+		return nil, fmt.Errorf("a fragment can't be named \"on\"")
+	}
+	if err := p.expect("on"); err != nil {
+		return nil, err
+	}
+	if frag.typeCondition, err = p.name(); err != nil {
+		return nil, err
+	}
+	if _, err := p.directives(); err != nil {
+		return nil, err
+	}
+	frag.selections, err = p.selectionSet()
+	return frag, err
+}
+
+func (p *parser) selectionSet() ([]selection, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	var selections []selection
+	for !p.is("}") {
+		var sel selection
+		var err error
+		if p.is("...") {
+			sel, err = p.spread()
+		} else {
+			sel, err = p.field()
+		}
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, sel)
+	}
+	p.next()
+	if len(selections) == 0 {
+		return nil, fmt.Errorf("empty selection set")
+	}
+	return selections, nil
+}
+
+func (p *parser) spread() (selection, error) {
+	p.next()
+	if p.peek().kind == tokenName && !p.is("on") {
+		spread := &fragmentSpread{name: p.next().text}
+		var err error
+		spread.directives, err = p.directives()
+		return spread, err
+	}
+
+	inline := &inlineFragment{}
+	var err error
+	if p.is("on") {
+		p.next()
+		if inline.typeCondition, err = p.name(); err != nil {
+			return nil, err
+		}
+	}
+	if inline.directives, err = p.directives(); err != nil {
+		return nil, err
+	}
+	inline.selections, err = p.selectionSet()
+	return inline, err
+}
+
+func (p *parser) field() (*fieldNode, error) {
+	field := &fieldNode{}
+	var err error
+	if field.name, err = p.name(); err != nil {
+		return nil, err
+	}
+	if p.is(":") {
+		p.next()
+		field.alias = field.name
+		if field.name, err = p.name(); err != nil {
+			return nil, err
+		}
+	}
+	if field.arguments, err = p.arguments(); err != nil {
+		return nil, err
+	}
+	if field.directives, err = p.directives(); err != nil {
+		return nil, err
+	}
+	if p.is("{") {
+		if field.selections, err = p.selectionSet(); err != nil {
+			return nil, err
+		}
+	}
+	return field, nil
+}
+
+func (p *parser) arguments() (map[string]any, error) {
+	if !p.is("(") {
+		return nil, nil
+	}
+	p.next()
+	arguments := map[string]any{}
+	for !p.is(")") {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		if _, ok := arguments[name]; ok {
+			return nil, fmt.Errorf("argument %q is given twice", name)
+		}
+		if arguments[name], err = p.value(false); err != nil {
+			return nil, err
+		}
+	}
+	p.next()
+	return arguments, nil
+}
+
+func (p *parser) directives() ([]directive, error) {
+	var directives []directive
+	for p.is("@") {
+		p.next()
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		arguments, err := p.arguments()
+		if err != nil {
+			return nil, err
+		}
+		directives = append(directives, directive{name: name, arguments: arguments})
+	}
+	return directives, nil
+}
+
+// value parses a literal; variables aren't allowed in constant positions
+// such as default values
+func (p *parser) value(constant bool) (any, error) {
+	t := p.peek()
+	switch {
+	case t.kind == tokenPunct && t.text == "$" && !constant:
+		p.next()
+		name, err := p.name()
+		return variable(name), err
+	case t.kind == tokenInt:
+		p.next()
+		n, err := strconv.ParseInt(t.text, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%d:%d: integer %s out of range", t.line, t.col, t.text)
+		}
+		return n, nil
+	case t.kind == tokenFloat:
+		p.next()
+		return strconv.ParseFloat(t.text, 64)
+	case t.kind == tokenString:
+		p.next()
+		return t.text, nil
+	case t.kind == tokenName:
+		p.next()
+		switch t.text {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+		return enumValue(t.text), nil
+	case p.is("["):
+		p.next()
+		list := []any{}
+		for !p.is("]") {
+			item, err := p.value(constant)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, item)
+		}
+		p.next()
+		return list, nil
+	case p.is("{"):
+		p.next()
+		object := map[string]any{}
+		for !p.is("}") {
+			name, err := p.name()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(":"); err != nil {
+				return nil, err
+			}
+			if object[name], err = p.value(constant); err != nil {
+				return nil, err
+			}
+		}
+		p.next()
+		return object, nil
+	}
+	return nil, p.unexpected()
+}
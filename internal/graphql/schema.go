@@ -0,0 +1,164 @@
+// Package graphql runs GraphQL queries against a schema of Go resolvers.
+// It covers the parts of the query language dashboards use: fields,
+// aliases, arguments, variables, fragments and the @include and @skip
+// directives. Mutations, subscriptions, interfaces and introspection are
+// not supported; Schema.SDL describes the schema instead.
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// builtinScalars are the scalar types every schema has
+var builtinScalars = []string{"Int", "Float", "String", "Boolean", "ID"}
+
+// Schema is the root query type and the object types reachable from it.
+// Types that are neither objects nor built-in scalars are custom scalars,
+// serialized as strings; time.Time values are written in RFC 3339.
+type Schema struct {
+	query   *Object
+	objects map[string]*Object
+	order   []*Object
+}
+
+// NewSchema creates a schema answering queries with the fields of query
+func NewSchema(query *Object, objects ...*Object) *Schema {
+	s := &Schema{query: query, objects: map[string]*Object{}}
+	for _, object := range append([]*Object{query}, objects...) {
+		s.objects[object.Name] = object
+		s.order = append(s.order, object)
+	}
+	return s
+}
+
+// Object is an object type
+type Object struct {
+	Name        string
+	Description string
+	Fields      []*Field
+}
+
+// field returns the field called name, or nil if there is none
+func (o *Object) field(name string) *Field {
+	for _, field := range o.Fields {
+		if field.Name == name {
+			return field
+		}
+	}
+	return nil
+}
+
+// Field is a field of an object type
+type Field struct {
+	Name        string
+	Description string
+	Type        string // e.g. "String", "Account" or "[Event!]!"
+	Args        []Arg
+	// Resolve returns the field's value for source, the Go value the
+	// parent object was resolved to. Lists are returned as slices.
+	Resolve func(ctx context.Context, source any, args Args) (any, error)
+}
+
+// Arg is an argument of a field
+type Arg struct {
+	Name        string
+	Description string
+	Type        string
+	Default     any // used when the argument is left out, nil for none
+}
+
+// Args holds the arguments of a field, coerced to their declared types:
+// int, float64, string and bool. Arguments left out without a default are
+// missing.
+type Args map[string]any
+
+// String returns a string argument, "" if it is missing or null
+func (a Args) String(name string) string {
+	s, _ := a[name].(string)
+	return s
+}
+
+// Int returns an integer argument, 0 if it is missing or null
+func (a Args) Int(name string) int {
+	n, _ := a[name].(int)
+	return n
+}
+
+// Float returns a float argument, 0 if it is missing or null
+func (a Args) Float(name string) float64 {
+	f, _ := a[name].(float64)
+	return f
+}
+
+// Bool returns a boolean argument, false if it is missing or null
+func (a Args) Bool(name string) bool {
+	b, _ := a[name].(bool)
+	return b
+}
+
+// Has reports whether an argument was given a value other than null
+func (a Args) Has(name string) bool {
+	return a[name] != nil
+}
+
+// SDL describes the schema in the GraphQL schema definition language
+func (s *Schema) SDL() string {
+	var sdl strings.Builder
+	var scalars []string
+	for _, object := range s.order {
+		for _, field := range object.Fields {
+			types := []string{field.Type}
+			for _, arg := range field.Args {
+				types = append(types, arg.Type)
+			}
+			for _, typ := range types {
+				name := strings.Trim(typ, "[]!")
+				if s.objects[name] == nil && !slices.Contains(builtinScalars, name) && !slices.Contains(scalars, name) {
+					scalars = append(scalars, name)
+				}
+			}
+		}
+	}
+	for _, scalar := range scalars {
+		fmt.Fprintf(&sdl, "scalar %s\n\n", scalar)
+	}
+
+	for i, object := range s.order {
+		if i > 0 {
+			sdl.WriteString("\n")
+		}
+		writeDescription(&sdl, "", object.Description)
+		fmt.Fprintf(&sdl, "type %s {\n", object.Name)
+		for _, field := range object.Fields {
+			writeDescription(&sdl, "  ", field.Description)
+			sdl.WriteString("  " + field.Name)
+			if len(field.Args) > 0 {
+				args := make([]string, 0, len(field.Args))
+				for _, arg := range field.Args {
+					text := arg.Name + ": " + arg.Type
+					if arg.Default != nil {
+						value, _ := json.Marshal(arg.Default)
+						text += " = " + string(value)
+					}
+					args = append(args, text)
+				}
+				sdl.WriteString("(" + strings.Join(args, ", ") + ")")
+			}
+			sdl.WriteString(": " + field.Type + "\n")
+		}
+		sdl.WriteString("}\n")
+	}
+	return sdl.String()
+}
+
+func writeDescription(sdl *strings.Builder, indent, description string) {
+	if description == "" {
+		return
+	}
+	quoted, _ := json.Marshal(description)
+	sdl.WriteString(indent + string(quoted) + "\n")
+}
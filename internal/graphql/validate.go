@@ -0,0 +1,125 @@
+package graphql
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// maxDepth is how deeply the fields of a query may nest
+	maxDepth = 10
+	// maxFields is how many fields a query may select, counting the fields
+	// of a fragment once for every place it is spread
+	maxFields = 1000
+)
+
+// validate rejects documents that would not finish: fragments spreading
+// themselves, directly or through others, and operations selecting more
+// fields or nesting deeper than the limits allow
+func (d *document) validate(op *operation) error {
+	if err := d.checkFragmentCycles(); err != nil {
+		return err
+	}
+	m := &measure{fragments: d.fragments}
+	return m.selections(op.selections, 1)
+}
+
+// checkFragmentCycles is the NoFragmentCycles rule of the specification
+func (d *document) checkFragmentCycles() error {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := map[string]int{}
+	var stack []string
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		frag := d.fragments[name]
+		if frag == nil {
+			// Reported where the fragment is spread
+			return nil
+		}
+		switch state[name] {
+		case visiting:
+			start := 0
+			for stack[start] != name {
+				start++
+			}
+			cycle := append(stack[start:len(stack):len(stack)], name)
+			return fmt.Errorf("fragment %q spreads itself (%s)", name, strings.Join(cycle, " -> "))
+		case done:
+			return nil
+		}
+
+		state[name] = visiting
+		stack = append(stack, name)
+		for _, spread := range spreads(frag.selections, nil) {
+			if err := visit(spread); err != nil {
+				return err
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[name] = done
+		return nil
+	}
+
+	for name := range d.fragments {
+		if err := visit(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// spreads appends the names of the fragments spread anywhere in selections
+func spreads(selections []selection, names []string) []string {
+	for _, sel := range selections {
+		switch sel := sel.(type) {
+		case *fieldNode:
+			names = spreads(sel.selections, names)
+		case *fragmentSpread:
+			names = append(names, sel.name)
+		case *inlineFragment:
+			names = spreads(sel.selections, names)
+		}
+	}
+	return names
+}
+
+// measure counts the fields an operation selects with its fragments
+// expanded, stopping as soon as a limit is exceeded. It must only run once
+// fragment cycles are ruled out.
+type measure struct {
+	fragments map[string]*fragment
+	fields    int
+}
+
+func (m *measure) selections(selections []selection, depth int) error {
+	for _, sel := range selections {
+		switch sel := sel.(type) {
+		case *fieldNode:
+			if depth > maxDepth {
+				return fmt.Errorf("the query nests fields more than %d levels deep", maxDepth)
+			}
+			if m.fields++; m.fields > maxFields {
+				return fmt.Errorf("the query selects more than %d fields", maxFields)
+			}
+			if err := m.selections(sel.selections, depth+1); err != nil {
+				return err
+			}
+		case *fragmentSpread:
+			if frag := m.fragments[sel.name]; frag != nil {
+				if err := m.selections(frag.selections, depth); err != nil {
+					return err
+				}
+			}
+		case *inlineFragment:
+			if err := m.selections(sel.selections, depth); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
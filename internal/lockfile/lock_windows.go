@@ -0,0 +1,37 @@
+//go:build windows
+
+package lockfile
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// Windows locks are mandatory, so the locked byte sits far past any content
+// to keep the file readable by other processes
+const lockOffset = ^uint32(0)
+
+// Lock takes an exclusive lock, blocking until it is free
+func Lock(f *os.File) error {
+	overlapped := windows.Overlapped{Offset: lockOffset}
+	return windows.LockFileEx(windows.Handle(f.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK, 0, 1, 0, &overlapped)
+}
+
+// TryLock takes an exclusive lock, returning ErrLocked instead of blocking
+// when it is held elsewhere
+func TryLock(f *os.File) error {
+	overlapped := windows.Overlapped{Offset: lockOffset}
+	err := windows.LockFileEx(windows.Handle(f.Fd()),
+		windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY, 0, 1, 0, &overlapped)
+	if errors.Is(err, windows.ERROR_LOCK_VIOLATION) {
+		return ErrLocked
+	}
+	return err
+}
+
+func Unlock(f *os.File) error {
+	overlapped := windows.Overlapped{Offset: lockOffset}
+	return windows.UnlockFileEx(windows.Handle(f.Fd()), 0, 1, 0, &overlapped)
+}
@@ -0,0 +1,7 @@
+// Package lockfile provides advisory file locks shared between processes
+package lockfile
+
+import "errors"
+
+// ErrLocked is returned by TryLock when another process holds the lock
+var ErrLocked = errors.New("file is locked by another process")
@@ -1,96 +1,198 @@
 package logger
 
 import (
+	"context"
 	"fmt"
-	"os"
-	"path/filepath"
+	"log/slog"
+	"strings"
 	"sync"
 	"time"
 )
 
-type Logger struct {
-	enabled  bool
-	logDir   string
-	mu       sync.Mutex
-	file     *os.File
-	filename string
+// Options configures the logger
+type Options struct {
+	Enabled bool
+	Dir     string
+	Level   slog.Level
+	JSON    bool // one JSON object per line instead of key=value text
+
+	MaxSize  int64         // rotate the day's file when it would grow past this many bytes, 0 disables
+	MaxFiles int           // rotated files to keep, 0 keeps all
+	MaxAge   time.Duration // delete rotated files older than this, 0 keeps them forever
 }
 
 var (
-	instance *Logger
+	level    = new(slog.LevelVar)
+	root     = &fanout{level: level}
+	instance = slog.New(root)
+	output   *rotatingFile
 	once     sync.Once
 )
 
-// Initialize creates a new logger instance
-func Initialize(enabled bool, logDir string) error {
+// Initialize sets up logging to a file per day in opts.Dir, rotated and
+// pruned according to opts
+func Initialize(opts Options) error {
 	var err error
 	once.Do(func() {
-		instance = &Logger{
-			enabled: enabled,
-			logDir:  logDir,
+		if !opts.Enabled {
+			return
+		}
+
+		output = &rotatingFile{
+			dir:      opts.Dir,
+			maxSize:  opts.MaxSize,
+			maxFiles: opts.MaxFiles,
+			maxAge:   opts.MaxAge,
+		}
+		if err = output.open(time.Now()); err != nil {
+			return
+		}
+		// Compress and prune whatever earlier runs left behind
+		output.archive(output.filename)
+
+		level.Set(opts.Level)
+		handlerOptions := &slog.HandlerOptions{Level: level}
+		if opts.JSON {
+			root.add(slog.NewJSONHandler(output, handlerOptions))
+		} else {
+			root.add(slog.NewTextHandler(output, handlerOptions))
 		}
-		err = instance.rotateFile()
 	})
 	return err
 }
 
-// Info logs an info level message
-func Info(format string, args ...interface{}) {
-	if instance == nil || !instance.enabled {
-		return
+// AddSink sends every message at or above the configured level to h as
+// well, whether or not file logging is enabled
+func AddSink(h slog.Handler) {
+	root.add(h)
+}
+
+// ParseLevel parses a level name: debug, info, warn or error
+func ParseLevel(name string) (slog.Level, error) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(strings.TrimSpace(name))); err != nil {
+		return 0, fmt.Errorf("invalid log level %q, expected debug, info, warn or error", name)
 	}
+	return l, nil
+}
 
-	instance.mu.Lock()
-	defer instance.mu.Unlock()
+// SetLevel changes the minimum level of messages written from now on
+func SetLevel(l slog.Level) {
+	level.Set(l)
+}
 
-	// Check if we need to rotate to a new day's file
-	currentFile := time.Now().Format("2006-01-02") + ".log"
-	if currentFile != instance.filename {
-		if err := instance.rotateFile(); err != nil {
-			fmt.Fprintf(os.Stderr, "Error rotating log file: %v\n", err)
-			return
-		}
-	}
+// Debug logs a printf-style message useful when diagnosing problems
+func Debug(format string, args ...interface{}) {
+	logf(slog.LevelDebug, format, args...)
+}
 
-	// Format the log message
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
-	msg := fmt.Sprintf(format, args...)
-	logLine := fmt.Sprintf("[%s] [INFO] %s\n", timestamp, msg)
+// Info logs a printf-style message about normal operation
+func Info(format string, args ...interface{}) {
+	logf(slog.LevelInfo, format, args...)
+}
 
-	// Write to file
-	if _, err := instance.file.WriteString(logLine); err != nil {
-		fmt.Fprintf(os.Stderr, "Error writing to log file: %v\n", err)
-	}
+// Warn logs a printf-style message about something that needs attention
+func Warn(format string, args ...interface{}) {
+	logf(slog.LevelWarn, format, args...)
 }
 
-// rotateFile creates a new log file for the current day
-func (l *Logger) rotateFile() error {
-	// Close existing file if open
-	if l.file != nil {
-		l.file.Close()
-	}
+// Error logs a printf-style message about a failed operation
+func Error(format string, args ...interface{}) {
+	logf(slog.LevelError, format, args...)
+}
 
-	// Create logs directory if it doesn't exist
-	if err := os.MkdirAll(l.logDir, 0755); err != nil {
-		return fmt.Errorf("creating log directory: %w", err)
-	}
+// With returns a structured logger that adds the given key-value pairs to
+// every message, e.g. logger.With("account", name).Info("checked", "follows", 3)
+func With(args ...any) *slog.Logger {
+	return instance.With(args...)
+}
 
-	// Open new file
-	l.filename = time.Now().Format("2006-01-02") + ".log"
-	filepath := filepath.Join(l.logDir, l.filename)
-	file, err := os.OpenFile(filepath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		return fmt.Errorf("opening log file: %w", err)
+func logf(l slog.Level, format string, args ...interface{}) {
+	// Skip formatting messages that would be dropped anyway
+	if !instance.Enabled(context.Background(), l) {
+		return
 	}
-
-	l.file = file
-	return nil
+	instance.Log(context.Background(), l, fmt.Sprintf(format, args...))
 }
 
 // Close closes the current log file
 func Close() error {
-	if instance != nil && instance.file != nil {
-		return instance.file.Close()
+	if output == nil {
+		return nil
 	}
-	return nil
-} 
\ No newline at end of file
+	return output.Close()
+}
+
+// fanout passes records to every registered handler; with none registered
+// logging is disabled
+type fanout struct {
+	level    *slog.LevelVar
+	mu       sync.RWMutex
+	handlers []slog.Handler
+}
+
+func (f *fanout) add(h slog.Handler) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.handlers = append(f.handlers, h)
+}
+
+func (f *fanout) current() []slog.Handler {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.handlers
+}
+
+func (f *fanout) Enabled(_ context.Context, l slog.Level) bool {
+	return l >= f.level.Level() && len(f.current()) > 0
+}
+
+func (f *fanout) Handle(ctx context.Context, r slog.Record) error {
+	var firstErr error
+	for _, h := range f.current() {
+		if !h.Enabled(ctx, r.Level) {
+			continue
+		}
+		if err := h.Handle(ctx, r.Clone()); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// WithAttrs keeps the attributes on the record rather than on each handler,
+// so sinks added later still receive them
+func (f *fanout) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &fanoutAttrs{fanout: f, attrs: attrs}
+}
+
+// WithGroup isn't used by this package; groups are flattened
+func (f *fanout) WithGroup(string) slog.Handler {
+	return f
+}
+
+// fanoutAttrs is a fanout with attributes from With
+type fanoutAttrs struct {
+	*fanout
+	attrs []slog.Attr
+}
+
+func (f *fanoutAttrs) Handle(ctx context.Context, r slog.Record) error {
+	// Attributes from With come before the ones passed with the message
+	combined := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	combined.AddAttrs(f.attrs...)
+	r.Attrs(func(attr slog.Attr) bool {
+		combined.AddAttrs(attr)
+		return true
+	})
+	return f.fanout.Handle(ctx, combined)
+}
+
+func (f *fanoutAttrs) WithAttrs(attrs []slog.Attr) slog.Handler {
+	combined := append(append([]slog.Attr{}, f.attrs...), attrs...)
+	return &fanoutAttrs{fanout: f.fanout, attrs: combined}
+}
+
+func (f *fanoutAttrs) WithGroup(string) slog.Handler {
+	return f
+}
@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Entry is one message kept by a Ring
+type Entry struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string // including key=value attributes
+}
+
+// Ring is a sink that keeps the most recent messages in memory, e.g. for
+// showing them in the UI
+type Ring struct {
+	mu      sync.Mutex
+	entries []Entry
+	next    int  // where the next entry goes
+	full    bool // whether entries has wrapped around
+}
+
+// NewRing creates a sink holding up to size messages
+func NewRing(size int) *Ring {
+	return &Ring{entries: make([]Entry, size)}
+}
+
+// Last returns up to n of the newest messages, oldest first
+func (r *Ring) Last(n int) []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := r.next
+	if r.full {
+		count = len(r.entries)
+	}
+	n = min(n, count)
+
+	last := make([]Entry, 0, n)
+	for i := r.next - n; i < r.next; i++ {
+		last = append(last, r.entries[(i+len(r.entries))%len(r.entries)])
+	}
+	return last
+}
+
+func (r *Ring) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+func (r *Ring) Handle(_ context.Context, record slog.Record) error {
+	var message strings.Builder
+	message.WriteString(record.Message)
+	record.Attrs(func(attr slog.Attr) bool {
+		fmt.Fprintf(&message, " %s=%v", attr.Key, attr.Value)
+		return true
+	})
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[r.next] = Entry{Time: record.Time, Level: record.Level, Message: message.String()}
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+	return nil
+}
+
+// WithAttrs isn't needed: the logger passes attributes on the record
+func (r *Ring) WithAttrs([]slog.Attr) slog.Handler {
+	return r
+}
+
+func (r *Ring) WithGroup(string) slog.Handler {
+	return r
+}
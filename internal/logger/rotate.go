@@ -0,0 +1,253 @@
+package logger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// logFilePattern matches the files this package writes: the active
+// 2006-01-02.log and rotated 2006-01-02.N.log, possibly gzipped
+var logFilePattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}(\.\d+)?\.log(\.gz)?$`)
+
+// rotatingFile writes to a file named after the current day. It switches
+// to a new file at midnight and when the file reaches maxSize; rotated
+// files are gzipped and pruned in the background.
+type rotatingFile struct {
+	dir      string
+	maxSize  int64
+	maxFiles int
+	maxAge   time.Duration
+
+	mu       sync.Mutex
+	file     *os.File
+	filename string
+	day      string
+	size     int64
+
+	archiveMu sync.Mutex // one archive pass at a time
+	archiving sync.WaitGroup
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	dayChanged := now.Format("2006-01-02") != r.day
+	full := r.maxSize > 0 && r.size > 0 && r.size+int64(len(p)) > r.maxSize
+	if dayChanged || full {
+		if err := r.rotate(now, full && !dayChanged); err != nil {
+			fmt.Fprintf(os.Stderr, "Error rotating log file: %v\n", err)
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing to log file: %v\n", err)
+	}
+	return n, err
+}
+
+// open opens (or continues) the log file for the day of now
+func (r *rotatingFile) open(now time.Time) error {
+	// Create logs directory if it doesn't exist
+	if err := os.MkdirAll(r.dir, 0755); err != nil {
+		return fmt.Errorf("creating log directory: %w", err)
+	}
+
+	r.day = now.Format("2006-01-02")
+	r.filename = r.day + ".log"
+	file, err := os.OpenFile(filepath.Join(r.dir, r.filename), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("opening log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("reading log file size: %w", err)
+	}
+
+	r.file = file
+	r.size = info.Size()
+	return nil
+}
+
+// rotate closes the current file and opens a new one; callers hold the
+// lock. A file that filled up is renamed to the day's next free number
+// first, so the active file is always 2006-01-02.log.
+func (r *rotatingFile) rotate(now time.Time, full bool) error {
+	r.file.Close()
+
+	if full {
+		numbered := r.nextNumbered()
+		if err := os.Rename(filepath.Join(r.dir, r.filename), filepath.Join(r.dir, numbered)); err != nil {
+			// Keep appending to the full file rather than losing messages
+			fmt.Fprintf(os.Stderr, "Error renaming full log file: %v\n", err)
+		}
+	}
+
+	if err := r.open(now); err != nil {
+		return err
+	}
+
+	// The file just closed is archived along with anything else not active
+	active := r.filename
+	r.archiving.Add(1)
+	go func() {
+		defer r.archiving.Done()
+		r.archive(active)
+	}()
+	return nil
+}
+
+// nextNumbered returns the 2006-01-02.N.log name after the highest number
+// used so far for the current day, so numbers keep increasing even after
+// older files were pruned
+func (r *rotatingFile) nextNumbered() string {
+	highest := 0
+	if entries, err := os.ReadDir(r.dir); err == nil {
+		for _, entry := range entries {
+			rest, ok := strings.CutPrefix(entry.Name(), r.day+".")
+			if !ok {
+				continue
+			}
+			number, _, _ := strings.Cut(rest, ".")
+			if n, err := strconv.Atoi(number); err == nil && n > highest {
+				highest = n
+			}
+		}
+	}
+	return fmt.Sprintf("%s.%d.log", r.day, highest+1)
+}
+
+// archive gzips every uncompressed log file except active, then deletes
+// rotated files beyond the age and count limits
+func (r *rotatingFile) archive(active string) {
+	r.archiveMu.Lock()
+	defer r.archiveMu.Unlock()
+
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading log directory: %v\n", err)
+		return
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if name == active || !logFilePattern.MatchString(name) || strings.HasSuffix(name, ".gz") {
+			continue
+		}
+		if err := compress(filepath.Join(r.dir, name)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error compressing log file %s: %v\n", name, err)
+		}
+	}
+
+	r.prune(active)
+}
+
+// prune deletes rotated files older than maxAge, then the oldest ones
+// beyond maxFiles
+func (r *rotatingFile) prune(active string) {
+	if r.maxAge <= 0 && r.maxFiles <= 0 {
+		return
+	}
+
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading log directory: %v\n", err)
+		return
+	}
+
+	type rotatedFile struct {
+		name    string
+		modTime time.Time
+	}
+	var rotated []rotatedFile
+	for _, entry := range entries {
+		name := entry.Name()
+		if name == active || !logFilePattern.MatchString(name) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		rotated = append(rotated, rotatedFile{name, info.ModTime()})
+	}
+
+	// Newest first
+	sort.Slice(rotated, func(i, j int) bool {
+		return rotated[i].modTime.After(rotated[j].modTime)
+	})
+
+	for i, file := range rotated {
+		expired := r.maxAge > 0 && time.Since(file.modTime) > r.maxAge
+		excess := r.maxFiles > 0 && i >= r.maxFiles
+		if expired || excess {
+			if err := os.Remove(filepath.Join(r.dir, file.name)); err != nil {
+				fmt.Fprintf(os.Stderr, "Error removing old log file %s: %v\n", file.name, err)
+			}
+		}
+	}
+}
+
+// compress replaces path with path.gz, keeping its modification time so
+// age-based pruning still works
+func compress(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(path + ".gz")
+		return err
+	}
+
+	os.Chtimes(path+".gz", info.ModTime(), info.ModTime())
+	return os.Remove(path)
+}
+
+// Close closes the current file after pending archiving has finished
+func (r *rotatingFile) Close() error {
+	r.archiving.Wait()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	return r.file.Close()
+}
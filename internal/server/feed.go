@@ -0,0 +1,179 @@
+package server
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"x-tracker/internal/db"
+	"x-tracker/internal/logger"
+)
+
+// feedPath serves recent events as an Atom feed for feed readers
+const feedPath = "/feed.atom"
+
+// atomFeed and the types below are the parts of RFC 4287 the feed uses
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomPerson  `xml:"author"`
+	Link    []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	ID        string         `xml:"id"`
+	Title     string         `xml:"title"`
+	Updated   string         `xml:"updated"`
+	Published string         `xml:"published"`
+	Author    atomPerson     `xml:"author"`
+	Link      atomLink       `xml:"link"`
+	Category  []atomCategory `xml:"category"`
+	Content   string         `xml:"content"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// GET returns the events /api/events would as an Atom feed, newest first.
+// Feed readers rarely send headers, so the API token may also be passed as
+// the token query parameter.
+func (s *Server) handleFeed(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		methodNotAllowed(w, http.MethodGet, http.MethodHead)
+		return
+	}
+
+	query, err := s.parseEventQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	events, err := s.db.GetEvents(query)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	self := feedURL(r)
+	feed := atomFeed{
+		ID:      self,
+		Title:   feedTitle(r),
+		Updated: time.Now().UTC().Format(time.RFC3339),
+		Author:  atomPerson{Name: "x-tracker"},
+		Link:    []atomLink{{Href: self, Rel: "self", Type: "application/atom+xml"}},
+	}
+	if len(events) > 0 {
+		feed.Updated = entryUpdated(&events[0]).UTC().Format(time.RFC3339)
+	}
+	for i := range events {
+		feed.Entries = append(feed.Entries, newAtomEntry(&events[i]))
+	}
+
+	w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodHead {
+		return
+	}
+	w.Write([]byte(xml.Header))
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(feed); err != nil {
+		logger.Warn("Writing the Atom feed failed: %v", err)
+	}
+}
+
+func newAtomEntry(event *db.FollowEvent) atomEntry {
+	verb := "followed"
+	if event.EventType == db.EventTypeUnfollow {
+		verb = "unfollowed"
+	}
+	profile := "https://x.com/i/user/" + event.UserID
+	author := "https://x.com/" + event.AccountUsername
+	if strings.HasPrefix(event.UserID, "did:") {
+		profile = "https://bsky.app/profile/" + event.UserID
+		author = "https://bsky.app/profile/" + event.AccountUsername
+	}
+
+	content := fmt.Sprintf("@%s %s user %s, detected %s.",
+		event.AccountUsername, verb, event.UserID, event.DetectedAt.Local().Format("2006-01-02 15:04 MST"))
+	if event.Flaps > 0 && event.Net() != event.EventType {
+		content += fmt.Sprintf(" Since reversed (%d flaps).", event.Flaps)
+	} else if event.Flaps > 0 {
+		content += fmt.Sprintf(" Since reversed and redone (%d flaps).", event.Flaps)
+	}
+	if len(event.Annotations) > 0 {
+		content += " Labels: " + strings.Join(event.Annotations, ", ") + "."
+	}
+
+	entry := atomEntry{
+		ID:        "urn:uuid:" + event.UUID,
+		Title:     fmt.Sprintf("@%s %s %s", event.AccountUsername, verb, event.UserID),
+		Updated:   entryUpdated(event).UTC().Format(time.RFC3339),
+		Published: event.DetectedAt.UTC().Format(time.RFC3339),
+		Author:    atomPerson{Name: "@" + event.AccountUsername, URI: author},
+		Link:      atomLink{Href: profile, Rel: "alternate"},
+		Category:  []atomCategory{{Term: string(event.EventType)}},
+		Content:   content,
+	}
+	for _, label := range event.Annotations {
+		entry.Category = append(entry.Category, atomCategory{Term: label})
+	}
+	return entry
+}
+
+// entryUpdated is when an event last changed: its latest flap, if any
+func entryUpdated(event *db.FollowEvent) time.Time {
+	if event.FlappedAt != nil && event.FlappedAt.After(event.DetectedAt) {
+		return *event.FlappedAt
+	}
+	return event.DetectedAt
+}
+
+// feedURL is the address the feed was requested at, without the token,
+// which serves as its ID and self link
+func feedURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	params := r.URL.Query()
+	params.Del("token")
+	url := scheme + "://" + r.Host + feedPath
+	if encoded := params.Encode(); encoded != "" {
+		url += "?" + encoded
+	}
+	return url
+}
+
+// feedTitle names the feed after its filters
+func feedTitle(r *http.Request) string {
+	params := r.URL.Query()
+	title := "x-tracker events"
+	switch params.Get("type") {
+	case string(db.EventTypeFollow):
+		title = "x-tracker follows"
+	case string(db.EventTypeUnfollow):
+		title = "x-tracker unfollows"
+	}
+	if account := strings.TrimPrefix(params.Get("account"), "@"); account != "" {
+		title += " of @" + account
+	}
+	return title
+}
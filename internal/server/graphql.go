@@ -0,0 +1,432 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"x-tracker/internal/db"
+	"x-tracker/internal/graphql"
+)
+
+// maxGraphQLBody caps the size of a GraphQL request
+const maxGraphQLBody = 1 << 20
+
+// loaderKey holds the per-request graphqlLoader in the context
+type loaderKey struct{}
+
+// graphqlLoader loads the watch list and following counts at most once per
+// request, however many events or targets refer to them
+type graphqlLoader struct {
+	db       db.Store
+	accounts []db.WatchedAccount
+	counts   map[int64]int
+}
+
+func loaderFrom(ctx context.Context) *graphqlLoader {
+	return ctx.Value(loaderKey{}).(*graphqlLoader)
+}
+
+func (l *graphqlLoader) watchList() ([]db.WatchedAccount, error) {
+	if l.accounts == nil {
+		accounts, err := l.db.GetWatchedAccounts()
+		if err != nil {
+			return nil, err
+		}
+		l.accounts = append([]db.WatchedAccount{}, accounts...)
+	}
+	return l.accounts, nil
+}
+
+func (l *graphqlLoader) followingCount(accountID int64) (int, error) {
+	if l.counts == nil {
+		counts, err := l.db.GetFollowingCounts()
+		if err != nil {
+			return 0, err
+		}
+		l.counts = counts
+	}
+	return l.counts[accountID], nil
+}
+
+// account finds a watched account by ID, or by username if id is 0; nil if
+// it isn't watched (any more)
+func (l *graphqlLoader) account(id int64, username string) (*db.WatchedAccount, error) {
+	accounts, err := l.watchList()
+	if err != nil {
+		return nil, err
+	}
+	for i := range accounts {
+		if accounts[i].ID == id || id == 0 && strings.EqualFold(accounts[i].Username, username) {
+			return &accounts[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// pageArgs are the paging arguments of list fields
+func pageArgs(defaultFirst int) []graphql.Arg {
+	return []graphql.Arg{
+		{Name: "first", Type: "Int", Default: defaultFirst, Description: fmt.Sprintf("at most this many, up to %d", maxEventLimit)},
+		{Name: "offset", Type: "Int", Default: 0, Description: "skip this many first"},
+	}
+}
+
+// page checks the paging arguments and returns them
+func page(args graphql.Args) (first, offset int, err error) {
+	first, offset = args.Int("first"), args.Int("offset")
+	if first < 1 || first > maxEventLimit {
+		return 0, 0, fmt.Errorf("first must be between 1 and %d", maxEventLimit)
+	}
+	if offset < 0 {
+		return 0, 0, errors.New("offset can't be negative")
+	}
+	return first, offset, nil
+}
+
+// pageOf returns one page of items
+func pageOf[T any](items []T, args graphql.Args) ([]T, error) {
+	first, offset, err := page(args)
+	if err != nil {
+		return nil, err
+	}
+	if offset >= len(items) {
+		return nil, nil
+	}
+	return items[offset:min(offset+first, len(items))], nil
+}
+
+// eventArgs filter events, leaving out the filters in omit that the parent
+// object already fixes
+func eventArgs(omit ...string) []graphql.Arg {
+	args := []graphql.Arg{
+		{Name: "account", Type: "String", Description: "username of the watched account"},
+		{Name: "userId", Type: "String", Description: "only events about this user"},
+		{Name: "type", Type: "String", Description: "follow or unfollow"},
+		{Name: "since", Type: "Time", Description: "YYYY-MM-DD or RFC 3339, inclusive"},
+		{Name: "until", Type: "Time", Description: "YYYY-MM-DD or RFC 3339, exclusive"},
+		{Name: "label", Type: "String", Description: "only events with this annotation"},
+		{Name: "dismissed", Type: "Boolean", Default: false, Description: "include dismissed events"},
+	}
+	args = slices.DeleteFunc(args, func(arg graphql.Arg) bool { return slices.Contains(omit, arg.Name) })
+	return append(args, pageArgs(100)...)
+}
+
+// events runs an event query built from the arguments of an events field
+func (s *Server) events(ctx context.Context, args graphql.Args, query db.EventQuery) ([]*db.FollowEvent, error) {
+	first, offset, err := page(args)
+	if err != nil {
+		return nil, err
+	}
+	query.Limit, query.Offset = first, offset
+	query.IncludeDismissed = args.Bool("dismissed")
+	query.Label = args.String("label")
+	if args.Has("userId") {
+		query.UserID = args.String("userId")
+	}
+
+	if username := strings.TrimPrefix(args.String("account"), "@"); username != "" {
+		account, err := loaderFrom(ctx).account(0, username)
+		if err != nil {
+			return nil, err
+		}
+		if account == nil {
+			return nil, fmt.Errorf("account @%s not found", username)
+		}
+		query.AccountID = account.ID
+	}
+
+	switch eventType := args.String("type"); eventType {
+	case "":
+	case string(db.EventTypeFollow), string(db.EventTypeUnfollow):
+		query.EventType = db.EventType(eventType)
+	default:
+		return nil, errors.New("type must be follow or unfollow")
+	}
+
+	if query.Since, err = parseTime(args.String("since")); err != nil {
+		return nil, fmt.Errorf("invalid since: %w", err)
+	}
+	if query.Until, err = parseTime(args.String("until")); err != nil {
+		return nil, fmt.Errorf("invalid until: %w", err)
+	}
+
+	events, err := s.db.GetEvents(query)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*db.FollowEvent, len(events))
+	for i := range events {
+		result[i] = &events[i]
+	}
+	return result, nil
+}
+
+// field builds a field without arguments read off its source
+func field[T any](name, typ, description string, get func(source T) any) *graphql.Field {
+	return &graphql.Field{
+		Name:        name,
+		Type:        typ,
+		Description: description,
+		Resolve: func(ctx context.Context, source any, args graphql.Args) (any, error) {
+			return get(source.(T)), nil
+		},
+	}
+}
+
+// graphqlSchema describes the watch list, events and trending targets
+func (s *Server) graphqlSchema() *graphql.Schema {
+	account := &graphql.Object{
+		Name:        "Account",
+		Description: "A watched account",
+		Fields: []*graphql.Field{
+			field("id", "Int!", "", func(a *db.WatchedAccount) any { return a.ID }),
+			field("username", "String!", "", func(a *db.WatchedAccount) any { return a.Username }),
+			field("userId", "String!", "", func(a *db.WatchedAccount) any { return a.UserID }),
+			field("status", "String!", "active, suspended or unavailable", func(a *db.WatchedAccount) any { return string(a.Status) }),
+			field("paused", "Boolean!", "", func(a *db.WatchedAccount) any { return a.Paused() }),
+			field("tags", "[String!]!", "", func(a *db.WatchedAccount) any { return a.Tags }),
+			field("addedAt", "Time", "", func(a *db.WatchedAccount) any { return optionalTime(a.AddedAt) }),
+			field("lastCheckedAt", "Time", "", func(a *db.WatchedAccount) any { return optionalTime(a.LastCheckedAt) }),
+			{
+				Name:        "followingCount",
+				Type:        "Int!",
+				Description: "size of the stored following snapshot",
+				Resolve: func(ctx context.Context, source any, args graphql.Args) (any, error) {
+					return loaderFrom(ctx).followingCount(source.(*db.WatchedAccount).ID)
+				},
+			},
+			{
+				Name:        "following",
+				Type:        "[String!]!",
+				Description: "user IDs in the stored following snapshot, sorted",
+				Args:        pageArgs(maxEventLimit),
+				Resolve: func(ctx context.Context, source any, args graphql.Args) (any, error) {
+					followings, err := s.db.GetCurrentFollowings(source.(*db.WatchedAccount).ID)
+					if err != nil {
+						return nil, err
+					}
+					ids := make([]string, 0, len(followings))
+					for id := range followings {
+						ids = append(ids, id)
+					}
+					slices.Sort(ids)
+					return pageOf(ids, args)
+				},
+			},
+			{
+				Name:        "events",
+				Type:        "[Event!]!",
+				Description: "the account's events, newest first",
+				Args:        eventArgs("account"),
+				Resolve: func(ctx context.Context, source any, args graphql.Args) (any, error) {
+					return s.events(ctx, args, db.EventQuery{AccountID: source.(*db.WatchedAccount).ID})
+				},
+			},
+		},
+	}
+
+	event := &graphql.Object{
+		Name:        "Event",
+		Description: "A follow or unfollow by a watched account",
+		Fields: []*graphql.Field{
+			field("id", "Int!", "", func(e *db.FollowEvent) any { return e.ID }),
+			field("uuid", "String!", "", func(e *db.FollowEvent) any { return e.UUID }),
+			field("userId", "String!", "the user followed or unfollowed", func(e *db.FollowEvent) any { return e.UserID }),
+			field("type", "String!", "follow or unfollow", func(e *db.FollowEvent) any { return string(e.EventType) }),
+			field("detectedAt", "Time!", "", func(e *db.FollowEvent) any { return e.DetectedAt }),
+			field("dismissedAt", "Time", "", func(e *db.FollowEvent) any { return e.DismissedAt }),
+			field("flaps", "Int!", "times the change was undone or redone within the flap window", func(e *db.FollowEvent) any { return e.Flaps }),
+			field("flappedAt", "Time", "", func(e *db.FollowEvent) any { return e.FlappedAt }),
+			field("annotations", "[String!]!", "", func(e *db.FollowEvent) any { return e.Annotations }),
+			{
+				Name:        "account",
+				Type:        "Account",
+				Description: "the watched account, null if it was removed since",
+				Resolve: func(ctx context.Context, source any, args graphql.Args) (any, error) {
+					return loaderFrom(ctx).account(source.(*db.FollowEvent).WatchedAccountID, "")
+				},
+			},
+		},
+	}
+
+	target := &graphql.Object{
+		Name:        "Target",
+		Description: "A user followed by several watched accounts lately",
+		Fields: []*graphql.Field{
+			field("userId", "String!", "", func(t *db.TrendingTarget) any { return t.UserID }),
+			field("score", "Float!", "follows weighted by how selective the following accounts are", func(t *db.TrendingTarget) any { return t.Score }),
+			field("lastFollowedAt", "Time!", "", func(t *db.TrendingTarget) any { return t.LastFollowedAt }),
+			{
+				Name:        "followedBy",
+				Type:        "[Account!]!",
+				Description: "the watched accounts that followed the user",
+				Resolve: func(ctx context.Context, source any, args graphql.Args) (any, error) {
+					var accounts []*db.WatchedAccount
+					for _, username := range source.(*db.TrendingTarget).FollowedBy {
+						account, err := loaderFrom(ctx).account(0, username)
+						if err != nil {
+							return nil, err
+						}
+						if account != nil {
+							accounts = append(accounts, account)
+						}
+					}
+					return accounts, nil
+				},
+			},
+			{
+				Name:        "events",
+				Type:        "[Event!]!",
+				Description: "events about the user, newest first",
+				Args:        eventArgs("userId"),
+				Resolve: func(ctx context.Context, source any, args graphql.Args) (any, error) {
+					return s.events(ctx, args, db.EventQuery{UserID: source.(*db.TrendingTarget).UserID})
+				},
+			},
+		},
+	}
+
+	query := &graphql.Object{
+		Name: "Query",
+		Fields: []*graphql.Field{
+			{
+				Name:        "accounts",
+				Type:        "[Account!]!",
+				Description: "the watch list, by username",
+				Args: append([]graphql.Arg{
+					{Name: "tag", Type: "String", Description: "only accounts with this tag"},
+					{Name: "status", Type: "String", Description: "only accounts with this status"},
+					{Name: "paused", Type: "Boolean", Description: "only paused or only active accounts"},
+				}, pageArgs(maxEventLimit)...),
+				Resolve: func(ctx context.Context, source any, args graphql.Args) (any, error) {
+					watchList, err := loaderFrom(ctx).watchList()
+					if err != nil {
+						return nil, err
+					}
+					var accounts []*db.WatchedAccount
+					for i := range watchList {
+						account := &watchList[i]
+						switch {
+						case args.Has("tag") && !account.HasTag(db.NormalizeTag(args.String("tag"))):
+						case args.Has("status") && string(account.Status) != args.String("status"):
+						case args.Has("paused") && account.Paused() != args.Bool("paused"):
+						default:
+							accounts = append(accounts, account)
+						}
+					}
+					return pageOf(accounts, args)
+				},
+			},
+			{
+				Name: "account",
+				Type: "Account",
+				Args: []graphql.Arg{{Name: "username", Type: "String!"}},
+				Resolve: func(ctx context.Context, source any, args graphql.Args) (any, error) {
+					return loaderFrom(ctx).account(0, strings.TrimPrefix(args.String("username"), "@"))
+				},
+			},
+			{
+				Name:        "events",
+				Type:        "[Event!]!",
+				Description: "events of all watched accounts, newest first",
+				Args:        eventArgs(),
+				Resolve: func(ctx context.Context, source any, args graphql.Args) (any, error) {
+					return s.events(ctx, args, db.EventQuery{})
+				},
+			},
+			{
+				Name: "event",
+				Type: "Event",
+				Args: []graphql.Arg{{Name: "uuid", Type: "String!"}},
+				Resolve: func(ctx context.Context, source any, args graphql.Args) (any, error) {
+					return s.db.GetEventByUUID(args.String("uuid"))
+				},
+			},
+			{
+				Name:        "targets",
+				Type:        "[Target!]!",
+				Description: "users followed by more than one watched account lately, highest score first",
+				Args: []graphql.Arg{
+					{Name: "days", Type: "Int", Default: 7, Description: "how far back to look"},
+					{Name: "first", Type: "Int", Default: 50, Description: fmt.Sprintf("at most this many, up to %d", maxEventLimit)},
+				},
+				Resolve: func(ctx context.Context, source any, args graphql.Args) (any, error) {
+					days, first := args.Int("days"), args.Int("first")
+					if days < 1 {
+						return nil, errors.New("days must be at least 1")
+					}
+					if first < 1 || first > maxEventLimit {
+						return nil, fmt.Errorf("first must be between 1 and %d", maxEventLimit)
+					}
+					targets, err := s.db.GetTrendingTargets(time.Now().AddDate(0, 0, -days), first)
+					if err != nil {
+						return nil, err
+					}
+					result := make([]*db.TrendingTarget, len(targets))
+					for i := range targets {
+						result[i] = &targets[i]
+					}
+					return result, nil
+				},
+			},
+		},
+	}
+
+	return graphql.NewSchema(query, account, event, target)
+}
+
+// GET ?query=... or POST {"query": ..., "variables": ...} runs a GraphQL
+// query. Malformed requests get 400; errors of single fields come back
+// with 200 next to the data that could be resolved, as GraphQL clients
+// expect.
+func (s *Server) handleGraphQL(w http.ResponseWriter, r *http.Request) {
+	var req graphql.Request
+	switch r.Method {
+	case http.MethodGet:
+		params := r.URL.Query()
+		req.Query = params.Get("query")
+		req.OperationName = params.Get("operationName")
+		if variables := params.Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				writeError(w, http.StatusBadRequest, fmt.Errorf("invalid variables: %w", err))
+				return
+			}
+		}
+	case http.MethodPost:
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxGraphQLBody)).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+			return
+		}
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPost)
+		return
+	}
+	if req.Query == "" {
+		writeError(w, http.StatusBadRequest, errors.New("query is required"))
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), loaderKey{}, &graphqlLoader{db: s.db})
+	resp := s.graphql.Execute(ctx, req)
+	status := http.StatusOK
+	if resp.Data == nil {
+		status = http.StatusBadRequest
+	}
+	writeJSON(w, status, resp)
+}
+
+// GET returns the GraphQL schema in the schema definition language
+func (s *Server) handleGraphQLSchema(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprint(w, s.graphql.SDL())
+}
@@ -0,0 +1,467 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"x-tracker/internal/db"
+	"x-tracker/internal/graphql"
+	"x-tracker/internal/logger"
+	"x-tracker/internal/tracker"
+)
+
+// maxEventLimit caps how many events one request can return
+const maxEventLimit = 1000
+
+// Server exposes the watch list, events and checks over a small REST API
+// so dashboards and bots can be built on top of the tracker, and the events
+// as an Atom feed
+type Server struct {
+	db       db.Store
+	checker  *tracker.Tracker
+	token    string              // required as a bearer token when set
+	onChange func(notice string) // called after the watch list or events changed
+	mux      *http.ServeMux
+	http     *http.Server
+	graphql  *graphql.Schema // nil unless EnableGraphQL was called
+}
+
+// New creates a server for addr. onChange may be nil.
+func New(addr, token string, database db.Store, checker *tracker.Tracker, onChange func(string)) *Server {
+	s := &Server{
+		db:       database,
+		checker:  checker,
+		token:    token,
+		onChange: onChange,
+	}
+
+	s.mux = http.NewServeMux()
+	s.mux.HandleFunc("/api/accounts", s.handleAccounts)
+	s.mux.HandleFunc("/api/accounts/", s.handleAccount)
+	s.mux.HandleFunc("/api/events", s.handleEvents)
+	s.mux.HandleFunc("/api/events/", s.handleEvent)
+	s.mux.HandleFunc("/api/check", s.handleCheck)
+	s.mux.HandleFunc(feedPath, s.handleFeed)
+
+	s.http = &http.Server{
+		Addr:              addr,
+		Handler:           s.authenticate(s.mux),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return s
+}
+
+// EnableGraphQL also serves read-only GraphQL queries over accounts,
+// events and trending targets at /api/graphql. Call it before Start.
+func (s *Server) EnableGraphQL() {
+	s.graphql = s.graphqlSchema()
+	s.mux.HandleFunc("/api/graphql", s.handleGraphQL)
+	s.mux.HandleFunc("/api/graphql/schema", s.handleGraphQLSchema)
+}
+
+// Start binds the address and serves requests in the background. Without
+// a token it refuses any address but a loopback one, as anyone reaching it
+// could change the watch list.
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.http.Addr)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", s.http.Addr, err)
+	}
+	if s.token == "" {
+		if addr, ok := listener.Addr().(*net.TCPAddr); !ok || !addr.IP.IsLoopback() {
+			listener.Close()
+			return fmt.Errorf("refusing to serve the HTTP API on %s without authentication, set API_TOKEN or listen on 127.0.0.1", s.http.Addr)
+		}
+		logger.Info("HTTP API listening on %s without authentication", listener.Addr())
+	} else {
+		logger.Info("HTTP API listening on %s", listener.Addr())
+	}
+
+	go func() {
+		if err := s.http.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Info("HTTP API stopped: %v", err)
+		}
+	}()
+	return nil
+}
+
+// Close stops accepting requests and waits briefly for running ones
+func (s *Server) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return s.http.Shutdown(ctx)
+}
+
+// authenticate rejects requests without the configured bearer token
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.token != "" {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok && r.URL.Path == feedPath {
+				token, ok = r.URL.Query().Get("token"), true
+			}
+			if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+				writeError(w, http.StatusUnauthorized, errors.New("missing or invalid API token"))
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// changed tells the owner that data shown elsewhere is out of date
+func (s *Server) changed(notice string) {
+	if s.onChange != nil {
+		s.onChange(notice)
+	}
+}
+
+// audit records an action taken through the API, with the client's address
+// as the actor
+func (s *Server) audit(r *http.Request, action, target string) {
+	actor, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		actor = r.RemoteAddr
+	}
+	s.db.Audit(db.AuditEntry{
+		Source: db.AuditSourceAPI,
+		Actor:  actor,
+		Action: action,
+		Target: target,
+	})
+}
+
+// accountJSON is a watched account as returned by the API
+type accountJSON struct {
+	ID             int64      `json:"id"`
+	Username       string     `json:"username"`
+	UserID         string     `json:"user_id"`
+	Status         string     `json:"status"`
+	Paused         bool       `json:"paused"`
+	Tags           []string   `json:"tags"`
+	FollowingCount int        `json:"following_count"`
+	AddedAt        *time.Time `json:"added_at,omitempty"`
+	LastCheckedAt  *time.Time `json:"last_checked_at,omitempty"`
+}
+
+func newAccountJSON(account *db.WatchedAccount, followingCount int) accountJSON {
+	tags := account.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return accountJSON{
+		ID:             account.ID,
+		Username:       account.Username,
+		UserID:         account.UserID,
+		Status:         string(account.Status),
+		Paused:         account.Paused(),
+		Tags:           tags,
+		FollowingCount: followingCount,
+		AddedAt:        optionalTime(account.AddedAt),
+		LastCheckedAt:  optionalTime(account.LastCheckedAt),
+	}
+}
+
+// eventJSON is a follow event as returned by the API
+type eventJSON struct {
+	ID          int64      `json:"id"`
+	UUID        string     `json:"uuid"`
+	Account     string     `json:"account"`
+	UserID      string     `json:"user_id"`
+	EventType   string     `json:"event_type"`
+	DetectedAt  time.Time  `json:"detected_at"`
+	DismissedAt *time.Time `json:"dismissed_at,omitempty"`
+	Flaps       int        `json:"flaps"`
+	FlappedAt   *time.Time `json:"flapped_at,omitempty"`
+	Annotations []string   `json:"annotations"`
+}
+
+func newEventJSON(event *db.FollowEvent) eventJSON {
+	annotations := event.Annotations
+	if annotations == nil {
+		annotations = []string{}
+	}
+	return eventJSON{
+		ID:          event.ID,
+		UUID:        event.UUID,
+		Account:     event.AccountUsername,
+		UserID:      event.UserID,
+		EventType:   string(event.EventType),
+		DetectedAt:  event.DetectedAt,
+		DismissedAt: event.DismissedAt,
+		Flaps:       event.Flaps,
+		FlappedAt:   event.FlappedAt,
+		Annotations: annotations,
+	}
+}
+
+// GET lists the watch list, POST {"username": "..."} adds an account
+func (s *Server) handleAccounts(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		accounts, err := s.db.GetWatchedAccounts()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		counts, err := s.db.GetFollowingCounts()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		result := make([]accountJSON, 0, len(accounts))
+		for i := range accounts {
+			result = append(result, newAccountJSON(&accounts[i], counts[accounts[i].ID]))
+		}
+		writeJSON(w, http.StatusOK, result)
+
+	case http.MethodPost:
+		var body struct {
+			Username string `json:"username"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+			return
+		}
+		username := strings.TrimPrefix(strings.TrimSpace(body.Username), "@")
+		if username == "" {
+			writeError(w, http.StatusBadRequest, errors.New("username is required"))
+			return
+		}
+		if existing, err := s.db.GetWatchedAccountByUsername(username); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		} else if existing != nil {
+			writeError(w, http.StatusConflict, fmt.Errorf("@%s is already watched", existing.Username))
+			return
+		}
+
+		account, err := s.checker.AddAccount(username)
+		var watched *tracker.AlreadyWatchedError
+		switch {
+		case errors.Is(err, tracker.ErrInvalidUsername):
+			writeError(w, http.StatusBadRequest, err)
+			return
+		case errors.As(err, &watched):
+			writeError(w, http.StatusConflict, err)
+			return
+		case err != nil:
+			writeError(w, http.StatusBadGateway, err)
+			return
+		}
+		counts, err := s.db.GetFollowingCounts()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		s.audit(r, "add", "@"+account.Username)
+		s.changed(fmt.Sprintf("Added @%s", account.Username))
+		writeJSON(w, http.StatusCreated, newAccountJSON(account, counts[account.ID]))
+
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPost)
+	}
+}
+
+// GET returns one account, DELETE removes it from the watch list
+func (s *Server) handleAccount(w http.ResponseWriter, r *http.Request) {
+	username := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/api/accounts/"), "@")
+	if username == "" || strings.Contains(username, "/") {
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodDelete {
+		methodNotAllowed(w, http.MethodGet, http.MethodDelete)
+		return
+	}
+
+	account, err := s.db.GetWatchedAccountByUsername(username)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if account == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("account @%s not found", username))
+		return
+	}
+
+	if r.Method == http.MethodDelete {
+		if err := s.checker.RemoveAccount(account); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		s.audit(r, "remove", "@"+account.Username)
+		s.changed(fmt.Sprintf("Removed @%s", account.Username))
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	counts, err := s.db.GetFollowingCounts()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newAccountJSON(account, counts[account.ID]))
+}
+
+// GET lists events, filtered by the account, user_id, type, since, until,
+// label, dismissed and limit query parameters
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	query, err := s.parseEventQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	events, err := s.db.GetEvents(query)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	result := make([]eventJSON, 0, len(events))
+	for i := range events {
+		result = append(result, newEventJSON(&events[i]))
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// GET returns one event by its UUID
+func (s *Server) handleEvent(w http.ResponseWriter, r *http.Request) {
+	uuid := strings.TrimPrefix(r.URL.Path, "/api/events/")
+	if uuid == "" || strings.Contains(uuid, "/") {
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	event, err := s.db.GetEventByUUID(uuid)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if event == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("event %s not found", uuid))
+		return
+	}
+	writeJSON(w, http.StatusOK, newEventJSON(event))
+}
+
+// parseEventQuery turns query parameters into an event query
+func (s *Server) parseEventQuery(r *http.Request) (db.EventQuery, error) {
+	params := r.URL.Query()
+	query := db.EventQuery{Limit: 100}
+
+	if username := strings.TrimPrefix(params.Get("account"), "@"); username != "" {
+		account, err := s.db.GetWatchedAccountByUsername(username)
+		if err != nil {
+			return query, err
+		}
+		if account == nil {
+			return query, fmt.Errorf("account @%s not found", username)
+		}
+		query.AccountID = account.ID
+	}
+	query.UserID = params.Get("user_id")
+	query.Label = params.Get("label")
+
+	switch eventType := params.Get("type"); eventType {
+	case "":
+	case string(db.EventTypeFollow), string(db.EventTypeUnfollow):
+		query.EventType = db.EventType(eventType)
+	default:
+		return query, errors.New("type must be follow or unfollow")
+	}
+
+	var err error
+	if query.Since, err = parseTime(params.Get("since")); err != nil {
+		return query, fmt.Errorf("invalid since: %w", err)
+	}
+	if query.Until, err = parseTime(params.Get("until")); err != nil {
+		return query, fmt.Errorf("invalid until: %w", err)
+	}
+
+	if value := params.Get("dismissed"); value != "" {
+		if query.IncludeDismissed, err = strconv.ParseBool(value); err != nil {
+			return query, errors.New("dismissed must be true or false")
+		}
+	}
+	if value := params.Get("limit"); value != "" {
+		if query.Limit, err = strconv.Atoi(value); err != nil || query.Limit < 1 || query.Limit > maxEventLimit {
+			return query, fmt.Errorf("limit must be between 1 and %d", maxEventLimit)
+		}
+	}
+	return query, nil
+}
+
+// POST runs a check of every account and returns when it has finished
+func (s *Server) handleCheck(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	if err := s.checker.CheckAll(); err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	message := fmt.Sprintf("Check completed at %s", time.Now().Format("15:04:05"))
+	s.changed(message)
+	writeJSON(w, http.StatusOK, map[string]string{"message": message})
+}
+
+// parseTime accepts RFC 3339 timestamps and plain YYYY-MM-DD dates (local
+// midnight); an empty value is the zero time
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	// Stored timestamps are local, and compared as text
+	return t.Local(), nil
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logger.Warn("Writing HTTP API response failed: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func methodNotAllowed(w http.ResponseWriter, allowed ...string) {
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+}
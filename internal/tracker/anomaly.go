@@ -0,0 +1,102 @@
+package tracker
+
+import (
+	"fmt"
+	"strings"
+
+	"x-tracker/internal/logger"
+)
+
+// emptyListGuard is how many stored followings an account needs before an
+// empty following list from the API is treated as a bad response rather
+// than the account unfollowing everyone
+const emptyListGuard = 5
+
+// checkSchema looks at what the API decoded during the cycle and raises an
+// ops alert when key fields came back empty across the board, which usually
+// means the provider changed its response format. The alert is sent once
+// per distinct problem, and again when it clears.
+func (t *Tracker) checkSchema(emptyLists int) {
+	var problems []string
+	if fields := t.api.SuspiciousFields(); len(fields) > 0 {
+		problems = append(problems, fmt.Sprintf("user fields always empty: %s", strings.Join(fields, ", ")))
+	}
+	if emptyLists > 0 {
+		problems = append(problems, fmt.Sprintf("%d following lists came back empty", emptyLists))
+	}
+	summary := strings.Join(problems, "; ")
+
+	t.mu.Lock()
+	previous := t.schemaAlert
+	t.schemaAlert = summary
+	t.mu.Unlock()
+
+	if summary == previous {
+		return
+	}
+
+	if summary == "" {
+		logger.Info("API responses look normal again")
+		if t.notifications != nil {
+			t.notifications.NotifyOps("API responses back to normal", "The anomalies reported earlier were not seen in the last check cycle.")
+		}
+		return
+	}
+
+	logger.Warn("suspicious API responses this cycle: %s", summary)
+	if t.notifications != nil {
+		t.notifications.NotifyOps("Suspicious API responses",
+			fmt.Sprintf("%s. The API provider may have changed its response format; baselines are postponed until this clears.", summary))
+	}
+}
+
+// schemaSuspect reports whether the last cycle saw malformed API responses
+func (t *Tracker) schemaSuspect() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.schemaAlert != ""
+}
+
+// checkKey sends a single ops alert when the provider rejects the API key,
+// and another once a cycle gets past it again
+func (t *Tracker) checkKey(err error) {
+	rejected := err != nil
+
+	t.mu.Lock()
+	previous := t.keyRejected
+	t.keyRejected = rejected
+	t.mu.Unlock()
+
+	if rejected == previous || t.notifications == nil {
+		return
+	}
+	if rejected {
+		t.notifications.NotifyOps("API key rejected",
+			fmt.Sprintf("The provider refused the API key (%v). Checks stop at the first refusal until the key or its subscription is fixed; `x-tracker probe` tests it.", err))
+		return
+	}
+	t.notifications.NotifyOps("API key accepted again", "The provider accepts the API key again and checks have resumed.")
+}
+
+// checkBreaker sends a single ops alert when the API circuit breaker opens,
+// and another once requests succeed again
+func (t *Tracker) checkBreaker() {
+	status := t.api.Breaker()
+	degraded := !status.Until.IsZero()
+
+	t.mu.Lock()
+	previous := t.degraded
+	t.degraded = degraded
+	t.mu.Unlock()
+
+	if degraded == previous || t.notifications == nil {
+		return
+	}
+	if degraded {
+		t.notifications.NotifyOps("API degraded",
+			fmt.Sprintf("%d API requests in a row failed (last: %s). Checks are skipped until %s, then the API is tried again.",
+				status.Failures, status.Reason, status.Until.Local().Format("15:04")))
+		return
+	}
+	t.notifications.NotifyOps("API back to normal", "API requests succeed again and checks have resumed.")
+}
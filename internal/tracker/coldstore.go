@@ -0,0 +1,38 @@
+package tracker
+
+import (
+	"fmt"
+	"time"
+
+	"x-tracker/internal/format"
+	"x-tracker/internal/logger"
+)
+
+// archiveEvents moves the events older than EVENT_ARCHIVE_AFTER to the
+// cold archive, at most once a day
+func (t *Tracker) archiveEvents() {
+	cfg := t.Config()
+	if cfg.EventArchiveAfter <= 0 {
+		return
+	}
+
+	archives, err := t.db.GetEventArchives()
+	if err != nil {
+		logger.Error("Error reading event archive runs: %v", err)
+		return
+	}
+	if len(archives) > 0 && time.Since(archives[0].ArchivedAt) < 24*time.Hour {
+		return
+	}
+
+	started := time.Now()
+	moved, err := t.db.ArchiveEvents(cfg.EventArchivePath, started.Add(-cfg.EventArchiveAfter))
+	t.Complete("Event archiving", started, fmt.Sprintf("moved %s events to %s", format.Number(moved), cfg.EventArchivePath), err)
+	if err != nil {
+		logger.Error("Error archiving events: %v", err)
+		return
+	}
+	if moved > 0 {
+		logger.With("events", moved, "path", cfg.EventArchivePath).Info("Archived old events")
+	}
+}
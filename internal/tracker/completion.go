@@ -0,0 +1,62 @@
+package tracker
+
+import (
+	"fmt"
+	"time"
+
+	"x-tracker/internal/logger"
+)
+
+// Completion describes a finished long-running operation, for users who
+// started it and walked away
+type Completion struct {
+	Operation string // e.g. "Baseline of @foo"
+	Summary   string // what it did, e.g. "stored 48,301 followings"
+	Duration  time.Duration
+	Err       error
+}
+
+// String describes the completion in one line, e.g. "Baseline of @foo
+// finished in 4m12s: stored 48,301 followings"
+func (c Completion) String() string {
+	duration := c.Duration.Round(time.Second)
+	if c.Err != nil {
+		return fmt.Sprintf("%s failed after %s: %v", c.Operation, duration, c.Err)
+	}
+	return fmt.Sprintf("%s finished in %s: %s", c.Operation, duration, c.Summary)
+}
+
+// SetCompletionFunc sets a function called with every reported completion
+func (t *Tracker) SetCompletionFunc(fn func(Completion)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.completionFn = fn
+}
+
+// Complete reports an operation started at started that has finished,
+// with err if it failed. With COMPLETION_NOTIFY it goes to the ops channel
+// and the completion function, unless it took less than
+// COMPLETION_MIN_DURATION.
+func (t *Tracker) Complete(operation string, started time.Time, summary string, err error) {
+	cfg := t.Config()
+	c := Completion{Operation: operation, Summary: summary, Duration: time.Since(started), Err: err}
+	if !cfg.CompletionNotify || c.Duration < cfg.CompletionMinDuration {
+		return
+	}
+	logger.Info("%s", c)
+
+	title := operation + " finished"
+	if err != nil {
+		title = operation + " failed"
+	}
+	if t.notifications != nil {
+		t.notifications.NotifyOps(title, c.String())
+	}
+
+	t.mu.RLock()
+	fn := t.completionFn
+	t.mu.RUnlock()
+	if fn != nil {
+		fn(c)
+	}
+}
@@ -0,0 +1,49 @@
+package tracker
+
+import (
+	"time"
+
+	"x-tracker/internal/db"
+	"x-tracker/internal/logger"
+)
+
+// diffFollowings compares the account's followings against the stored ones
+// while they are fetched: each page is staged in the database as it
+// arrives and the comparison runs there, so neither list is ever held in
+// memory, only the changes.
+func (t *Tracker) diffFollowings(account *db.WatchedAccount) (*db.FollowingDiff, error) {
+	stage, err := t.db.StageFollowings(account.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer stage.Close()
+
+	err = t.providerFor(account).ForEachFollowingPage(account.UserID, func(ids []string) error {
+		if err := stage.Add(ids); err != nil {
+			return err
+		}
+		t.reportFetched(account, stage.Fetched())
+		return nil
+	})
+	if err := t.fetched(account, err); err != nil {
+		return nil, err
+	}
+	if cfg := t.Config(); cfg.RawSnapshots {
+		if err := stage.StoreRawSnapshot(time.Now(), cfg.RawSnapshotKeep); err != nil {
+			logger.Warn("Could not store raw snapshot of @%s: %v", account.Username, err)
+		}
+	}
+	return stage.Diff()
+}
+
+// storeRawSnapshot keeps a fully fetched following list as a raw snapshot
+// of the account if RAW_SNAPSHOTS is on. Failing to is only logged.
+func (t *Tracker) storeRawSnapshot(account *db.WatchedAccount, ids []string) {
+	cfg := t.Config()
+	if !cfg.RawSnapshots {
+		return
+	}
+	if err := t.db.StoreRawSnapshot(account.ID, ids, time.Now(), cfg.RawSnapshotKeep); err != nil {
+		logger.Warn("Could not store raw snapshot of @%s: %v", account.Username, err)
+	}
+}
@@ -0,0 +1,110 @@
+package tracker
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"x-tracker/internal/db"
+	"x-tracker/internal/format"
+	"x-tracker/internal/logger"
+)
+
+// driftTolerance absorbs follows and unfollows that happen between the user
+// lookup and the ID fetch, so small accounts aren't flagged for them
+const driftTolerance = 2
+
+// checkDrift compares the following count the user endpoint reports with
+// the number of IDs pagination returned and flags the account when they
+// differ by more than DriftThreshold percent. It reports whether the
+// account is drifting.
+func (t *Tracker) checkDrift(account *db.WatchedAccount, reported, fetched int) bool {
+	diff := reported - fetched
+	if diff < 0 {
+		diff = -diff
+	}
+
+	threshold := math.Max(float64(reported)*t.Config().DriftThreshold/100, driftTolerance)
+	if float64(diff) <= threshold {
+		if account.Drift != nil {
+			logger.Info("Following count drift for %s resolved (%d reported, %d fetched)", account.Username, reported, fetched)
+			if err := t.db.SetFollowingDrift(account.ID, nil); err != nil {
+				logger.Error("Error clearing drift flag of %s: %v", account.Username, err)
+			}
+			account.Drift = nil
+		}
+		return false
+	}
+
+	logger.Warn("following count drift for %s: profile reports %d, pagination returned %d IDs",
+		account.Username, reported, fetched)
+	drift := &db.FollowingDrift{Reported: reported, Fetched: fetched, DetectedAt: time.Now()}
+	if err := t.db.SetFollowingDrift(account.ID, drift); err != nil {
+		logger.Error("Error flagging drift of %s: %v", account.Username, err)
+	}
+	account.Drift = drift
+	return true
+}
+
+// ResyncAccount re-syncs the watched account with this username
+func (t *Tracker) ResyncAccount(username string) (*db.WatchedAccount, error) {
+	username = strings.TrimPrefix(username, "@")
+
+	account, err := t.db.GetWatchedAccountByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, fmt.Errorf("account @%s not found", username)
+	}
+
+	if err := t.Resync(account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// Resync wipes the account's stored following snapshot and rebuilds it from
+// a full fetch, without recording follow or unfollow events for the
+// differences. It waits for a running check cycle to finish, so the two
+// never write the account's snapshot at the same time.
+func (t *Tracker) Resync(account *db.WatchedAccount) error {
+	t.checkMu.Lock()
+	defer t.checkMu.Unlock()
+	return t.resync(account)
+}
+
+// resync is Resync for callers already holding checkMu
+func (t *Tracker) resync(account *db.WatchedAccount) (err error) {
+	started := time.Now()
+	stored := 0
+	defer func() {
+		t.Complete("Re-sync of @"+account.Username, started, fmt.Sprintf("stored %s followings", format.Number(stored)), err)
+	}()
+
+	followings, err := t.fetchFollowingIDs(account)
+	if err != nil {
+		return fmt.Errorf("getting followings: %w", err)
+	}
+	t.storeRawSnapshot(account, followings.IDs)
+	if len(followings.IDs) == 0 {
+		stored, err := t.db.GetCurrentFollowings(account.ID)
+		if err != nil {
+			return fmt.Errorf("getting current followings: %w", err)
+		}
+		if len(stored) >= emptyListGuard {
+			return fmt.Errorf("%w while %d are stored, keeping the snapshot", errEmptyFollowingList, len(stored))
+		}
+	}
+
+	if err := t.db.ReplaceFollowings(account.ID, followings.IDs); err != nil {
+		return fmt.Errorf("replacing followings: %w", err)
+	}
+	account.BaselinedAt = time.Now()
+	stored = len(followings.IDs)
+	t.recordCount(account, len(followings.IDs))
+
+	logger.Info("Re-synced %d followings for @%s", len(followings.IDs), account.Username)
+	return t.markChecked(account)
+}
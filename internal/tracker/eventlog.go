@@ -0,0 +1,43 @@
+package tracker
+
+import (
+	"x-tracker/config"
+	"x-tracker/internal/db"
+	"x-tracker/internal/eventlog"
+	"x-tracker/internal/logger"
+)
+
+// logEvents appends new and flapped events to the event log when
+// EVENT_LOG is on. The events are stored already, so a failure is only
+// logged.
+func logEvents(cfg *config.Config, account *db.WatchedAccount, events ...[]db.FollowEvent) {
+	if !cfg.EventLog {
+		return
+	}
+
+	var records []eventlog.Record
+	for _, batch := range events {
+		for _, event := range batch {
+			records = append(records, eventlog.Record{
+				UUID:       event.UUID,
+				ID:         event.ID,
+				Account:    account.Username,
+				AccountID:  account.ID,
+				UserID:     event.UserID,
+				EventType:  string(event.EventType),
+				DetectedAt: event.DetectedAt,
+				Flaps:      event.Flaps,
+				FlappedAt:  event.FlappedAt,
+			})
+		}
+	}
+
+	err := eventlog.Append(eventlog.Options{
+		Path:     cfg.EventLogPath,
+		MaxSize:  cfg.EventLogMaxSize,
+		MaxFiles: cfg.EventLogMaxFiles,
+	}, records)
+	if err != nil {
+		logger.Warn("Failed to write %d events of %s to the event log: %v", len(records), account.Username, err)
+	}
+}
@@ -0,0 +1,119 @@
+package tracker
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"x-tracker/internal/db"
+	"x-tracker/internal/logger"
+)
+
+// maxLostFollowerLookups caps the profile lookups made for lost followers
+// per check; the rest are recorded by ID only
+const maxLostFollowerLookups = 25
+
+// checkFollowers diffs the account's followers against the stored list,
+// records everyone who stopped following it with their profile details and
+// notifies about them. The first fetch only stores the followers.
+func (t *Tracker) checkFollowers(account *db.WatchedAccount) error {
+	response, err := t.providerFor(account).GetFollowerIDs(account.UserID)
+	if err != nil {
+		return fmt.Errorf("getting follower IDs: %w", err)
+	}
+
+	stored, err := t.db.GetFollowers(account.ID)
+	if err != nil {
+		return fmt.Errorf("getting stored followers: %w", err)
+	}
+
+	// Same guard as for followings: losing everyone at once is far more
+	// likely a bad response
+	if len(response.IDs) == 0 && len(stored) >= emptyListGuard {
+		return fmt.Errorf("follower list came back empty while %d are stored, skipping diff", len(stored))
+	}
+
+	now := time.Now()
+	current := make(map[string]bool, len(response.IDs))
+	var gained []string
+	for _, id := range response.IDs {
+		current[id] = true
+		if _, ok := stored[id]; !ok {
+			gained = append(gained, id)
+		}
+	}
+
+	var lost []db.LostFollower
+	for id, firstSeen := range stored {
+		if current[id] {
+			continue
+		}
+		lost = append(lost, db.LostFollower{
+			WatchedAccountID: account.ID,
+			AccountUsername:  account.Username,
+			UserID:           id,
+			FirstSeenAt:      firstSeen,
+			LostAt:           now,
+		})
+	}
+
+	// Longest-standing followers first, they're the ones worth looking up
+	sort.Slice(lost, func(i, j int) bool {
+		return lost[i].FirstSeenAt.Before(lost[j].FirstSeenAt)
+	})
+	for i := range lost {
+		if i >= maxLostFollowerLookups {
+			break
+		}
+		// Deactivated and suspended users can't be looked up, which is
+		// often why they stopped following
+		user, err := t.providerFor(account).GetUserByID(lost[i].UserID)
+		if err != nil {
+			continue
+		}
+		lost[i].Username = user.Legacy.ScreenName
+		lost[i].DisplayName = user.Legacy.Name
+		lost[i].FollowersCount = user.Legacy.FollowersCount
+	}
+
+	if err := t.db.StoreFollowers(account.ID, gained, lost, now); err != nil {
+		return fmt.Errorf("storing followers: %w", err)
+	}
+
+	if len(stored) == 0 {
+		logger.Info("Stored %d followers of %s", len(gained), account.Username)
+		return nil
+	}
+	if len(lost) == 0 {
+		return nil
+	}
+	logger.With("account", account.Username).Info("Lost followers",
+		"lost", len(lost), "gained", len(gained))
+
+	if t.notifications != nil && t.Config().EnableLostFollowerNotifications {
+		if notify := t.withoutMutedFollowers(account, lost); len(notify) > 0 {
+			t.notifications.NotifyLostFollowers(account, notify)
+		}
+	}
+	return nil
+}
+
+// withoutMutedFollowers drops lost followers on the global mute list
+func (t *Tracker) withoutMutedFollowers(account *db.WatchedAccount, lost []db.LostFollower) []db.LostFollower {
+	ids := make([]string, len(lost))
+	for i, follower := range lost {
+		ids[i] = follower.UserID
+	}
+	kept := make(map[string]bool, len(lost))
+	for _, id := range t.withoutMuted(account, ids) {
+		kept[id] = true
+	}
+
+	notify := make([]db.LostFollower, 0, len(kept))
+	for _, follower := range lost {
+		if kept[follower.UserID] {
+			notify = append(notify, follower)
+		}
+	}
+	return notify
+}
@@ -0,0 +1,48 @@
+package tracker
+
+import (
+	"strings"
+	"time"
+
+	"x-tracker/internal/db"
+	"x-tracker/internal/logger"
+)
+
+// refreshInsights runs the insights job over the events of the insights
+// window, storing what it finds in the insights table
+func (t *Tracker) refreshInsights() {
+	cfg := t.Config()
+	if cfg.InsightsWindow <= 0 {
+		return
+	}
+
+	now := time.Now()
+	fresh, err := t.db.RefreshInsights(db.InsightRules{
+		Since:               now.Add(-cfg.InsightsWindow),
+		SpreeFollows:        cfg.InsightsSpreeFollows,
+		CoordinatedAccounts: cfg.InsightsCoordinatedAccounts,
+		ChurnChanges:        cfg.InsightsChurnChanges,
+	}, now)
+	if err != nil {
+		logger.Error("Error refreshing insights: %v", err)
+		return
+	}
+	for _, insight := range fresh {
+		logger.With("kind", insight.Kind).Info("New insight: " + insight.Summary)
+	}
+}
+
+// insightLines lists the insights first found since a time for the cycle
+// summary, one per line, or returns "" if there are none
+func (t *Tracker) insightLines(since time.Time) string {
+	insights, err := t.db.GetInsights(since, "", 0)
+	if err != nil {
+		logger.Warn("Failed to load insights for the cycle summary: %v", err)
+		return ""
+	}
+	lines := make([]string, 0, len(insights))
+	for _, insight := range insights {
+		lines = append(lines, "• "+insight.Summary)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,73 @@
+package tracker
+
+import "x-tracker/internal/db"
+
+// ProgressState is where an account stands in a check cycle
+type ProgressState int
+
+const (
+	ProgressChecking ProgressState = iota // the account's check is running
+	ProgressChecked                       // the account's check succeeded
+	ProgressFailed                        // the account's check failed, see Err
+	ProgressFinished                      // the whole cycle is over
+)
+
+// Progress reports a step of a check cycle to a live display, e.g.
+// "checking @foo 3/12, fetched 45k IDs"
+type Progress struct {
+	State   ProgressState
+	Account string // username of the account the step is about
+	Index   int    // 1-based position of Account in the cycle
+	Total   int    // accounts checked by the cycle
+	Fetched int    // following IDs fetched for Account so far
+	Err     error
+}
+
+// SetProgressFunc sets a function called with every step of a check cycle.
+// It runs on the checking goroutine, so it shouldn't block for long.
+func (t *Tracker) SetProgressFunc(fn func(Progress)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.progressFn = fn
+}
+
+// report sends a step to the progress function and remembers it as the
+// current one, so pages fetched deep inside the check can be reported
+// against it
+func (t *Tracker) report(p Progress) {
+	t.mu.Lock()
+	fn := t.progressFn
+	if current := t.progress; current != nil && current.Account == p.Account && p.Fetched == 0 {
+		p.Fetched = current.Fetched
+	}
+	if p.State == ProgressChecking {
+		t.progress = &p
+	} else {
+		t.progress = nil
+	}
+	t.mu.Unlock()
+
+	if fn != nil {
+		fn(p)
+	}
+}
+
+// reportFetched reports the IDs fetched so far for an account being
+// checked by a cycle. Fetches outside a cycle, like adding an account,
+// aren't reported.
+func (t *Tracker) reportFetched(account *db.WatchedAccount, fetched int) {
+	t.mu.Lock()
+	fn := t.progressFn
+	current := t.progress
+	if current == nil || current.Account != account.Username {
+		t.mu.Unlock()
+		return
+	}
+	current.Fetched = fetched
+	p := *current
+	t.mu.Unlock()
+
+	if fn != nil {
+		fn(p)
+	}
+}
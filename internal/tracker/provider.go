@@ -0,0 +1,51 @@
+package tracker
+
+import (
+	"x-tracker/internal/api"
+	"x-tracker/internal/bluesky"
+	"x-tracker/internal/db"
+	"x-tracker/internal/webhook"
+)
+
+// Provider is the source of users and following lists the tracker checks
+// against. *api.Client is the one the tracker ships with for X and
+// *bluesky.Client the one for Bluesky; tests and embedding programs can
+// substitute their own.
+type Provider interface {
+	GetUser(username string) (*api.UserResponse, error)
+	GetUserByID(userID string) (*api.UserByIDResponse, error)
+	ForEachFollowingPage(userID string, fn func(ids []string) error) error
+	GetFollowingIDsWithProgress(userID string, progress func(fetched int)) (*api.FollowingIDsResponse, error)
+	GetFirstFollowingIDs(userID string, count int) (*api.FollowingIDsResponse, error)
+	GetFollowerIDs(userID string) (*api.FollowingIDsResponse, error)
+
+	// Quota and health, consulted to pace and skip checks
+	Usage() api.Usage
+	RemainingRequests() int
+	RateLimitStatus() []api.EndpointQuota
+	Breaker() api.BreakerStatus
+
+	// Response format monitoring
+	SuspiciousFields() []string
+	ResetSchemaStats()
+}
+
+// Notifier delivers what the tracker finds. *webhook.NotificationManager
+// sends it to the configured channels.
+type Notifier interface {
+	NotifyNewFollows(account *db.WatchedAccount, follows []db.FollowEvent, users webhook.UserLookup)
+	NotifyUnfollows(account *db.WatchedAccount, unfollows []db.FollowEvent, users webhook.UserLookup)
+	NotifyProfileChanges(account *db.WatchedAccount, changes []db.ProfileEvent)
+	NotifyLostFollowers(account *db.WatchedAccount, lost []db.LostFollower)
+	NotifyAccountStatus(account *db.WatchedAccount, previous db.AccountStatus)
+	NotifyOps(title, message string)
+	NotifyCycleSummary(summary string)
+	// Failures counts the notifications that could not be delivered
+	Failures() int64
+}
+
+var (
+	_ Provider = (*api.Client)(nil)
+	_ Provider = (*bluesky.Client)(nil)
+	_ Notifier = (*webhook.NotificationManager)(nil)
+)
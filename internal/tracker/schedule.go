@@ -0,0 +1,151 @@
+package tracker
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"x-tracker/internal/db"
+)
+
+// spreadCycle is a periodic check cycle whose account checks are spread
+// over part of the check interval instead of running back to back
+type spreadCycle struct {
+	start     time.Time
+	hurry     chan struct{} // closed to run the remaining checks right away
+	hurryOnce sync.Once
+	done      chan struct{} // closed when the cycle has finished
+	err       error
+	plan      []PlannedCheck // when each account's check was drawn to run
+}
+
+func newSpreadCycle() *spreadCycle {
+	return &spreadCycle{
+		start: time.Now(),
+		hurry: make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+}
+
+// wait blocks until delay after the start of the cycle, or until hurried
+func (c *spreadCycle) wait(delay time.Duration) {
+	timer := time.NewTimer(time.Until(c.start.Add(delay)))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-c.hurry:
+	}
+}
+
+// finish hurries the cycle along; it is safe to call more than once
+func (c *spreadCycle) finish() {
+	c.hurryOnce.Do(func() { close(c.hurry) })
+}
+
+// planChecks orders accounts by when they should be checked within a cycle.
+// Accounts get evenly spaced slots over window, each pushed back by a random
+// delay of up to its jitter. Delays are returned in the new account order.
+func planChecks(accounts []db.WatchedAccount, window, jitter time.Duration) []time.Duration {
+	delays := make([]time.Duration, len(accounts))
+	for i := range accounts {
+		delays[i] = window * time.Duration(i) / time.Duration(len(accounts))
+
+		accountJitter := jitter
+		if accounts[i].CheckJitter != nil {
+			accountJitter = *accounts[i].CheckJitter
+		}
+		if accountJitter > 0 {
+			delays[i] += time.Duration(rand.Int63n(int64(accountJitter) + 1))
+		}
+	}
+
+	sort.Sort(byDelay{accounts, delays})
+	return delays
+}
+
+// byDelay sorts accounts and their planned delays together
+type byDelay struct {
+	accounts []db.WatchedAccount
+	delays   []time.Duration
+}
+
+func (b byDelay) Len() int           { return len(b.accounts) }
+func (b byDelay) Less(i, j int) bool { return b.delays[i] < b.delays[j] }
+func (b byDelay) Swap(i, j int) {
+	b.accounts[i], b.accounts[j] = b.accounts[j], b.accounts[i]
+	b.delays[i], b.delays[j] = b.delays[j], b.delays[i]
+}
+
+// PlannedCheck is when an account is due within a check cycle
+type PlannedCheck struct {
+	Account string
+	At      time.Time     // earliest time the check runs
+	Jitter  time.Duration // how much later than At it may run, 0 once drawn
+}
+
+// Plan returns when each active account is due in a periodic cycle starting
+// at start, in slot order. While a cycle is spreading its checks, its drawn
+// times are returned instead, with running set.
+func (t *Tracker) Plan(start time.Time) (plan []PlannedCheck, running bool, err error) {
+	t.mu.RLock()
+	if t.cycle != nil && t.cycle.plan != nil {
+		plan = t.cycle.plan
+	}
+	t.mu.RUnlock()
+	if plan != nil {
+		return plan, true, nil
+	}
+
+	accounts, err := t.db.GetWatchedAccounts()
+	if err != nil {
+		return nil, false, fmt.Errorf("getting watched accounts: %w", err)
+	}
+	var active []db.WatchedAccount
+	for _, account := range accounts {
+		if !account.Paused() {
+			active = append(active, account)
+		}
+	}
+
+	cfg := t.Config()
+	window := time.Duration(float64(cfg.CheckInterval) * cfg.CheckSpread)
+	plan = make([]PlannedCheck, len(active))
+	for i, account := range active {
+		jitter := cfg.CheckJitter
+		if account.CheckJitter != nil {
+			jitter = *account.CheckJitter
+		}
+		plan[i] = PlannedCheck{
+			Account: account.Username,
+			At:      start.Add(window * time.Duration(i) / time.Duration(len(active))),
+			Jitter:  jitter,
+		}
+	}
+	return plan, false, nil
+}
+
+// setPlan records the times a spread cycle drew for its accounts
+func (t *Tracker) setPlan(c *spreadCycle, accounts []db.WatchedAccount, delays []time.Duration) {
+	plan := make([]PlannedCheck, len(accounts))
+	for i := range accounts {
+		plan[i] = PlannedCheck{Account: accounts[i].Username, At: c.start.Add(delays[i])}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	c.plan = plan
+}
+
+// spreading returns the cycle currently waiting between checks, if any
+func (t *Tracker) spreading() *spreadCycle {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.cycle
+}
+
+func (t *Tracker) setSpreading(c *spreadCycle) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.cycle = c
+}
@@ -0,0 +1,151 @@
+package tracker
+
+import (
+	"fmt"
+	"time"
+
+	"x-tracker/config"
+	"x-tracker/internal/api"
+	"x-tracker/internal/db"
+	"x-tracker/internal/format"
+	"x-tracker/internal/logger"
+)
+
+// quotaInterval returns the interval to space periodic checks out to with
+// remaining requests of quota left, or 0 to keep CHECK_INTERVAL. Below
+// QUOTA_LOW_THRESHOLD the interval grows with how far the quota is below
+// it, up to QUOTA_MAX_STRETCH times, but not past the next known reset.
+func quotaInterval(cfg *config.Config, remaining int, reset time.Time, now time.Time) time.Duration {
+	if cfg.QuotaLowThreshold <= 0 || remaining >= cfg.QuotaLowThreshold {
+		return 0
+	}
+	factor := cfg.QuotaMaxStretch
+	if remaining > 0 {
+		factor = min(factor, (cfg.QuotaLowThreshold+remaining-1)/remaining)
+	}
+	interval := cfg.CheckInterval * time.Duration(factor)
+	if !reset.IsZero() {
+		interval = min(interval, reset.Sub(now))
+	}
+	if interval <= cfg.CheckInterval {
+		return 0
+	}
+	return interval
+}
+
+// updateThrottle works out after a cycle whether the quota left on each
+// platform calls for spacing its checks out, and sends an ops notification
+// when that starts or ends. Periodic cycles run at the shortest interval
+// among the watched platforms; withoutThrottled holds back the accounts of
+// platforms needing a longer one.
+func (t *Tracker) updateThrottle(watched map[string]bool) {
+	cfg := t.Config()
+	now := time.Now()
+	for _, platform := range []string{db.PlatformX, db.PlatformBluesky} {
+		provider, ok := t.providers[platform]
+		if !ok {
+			continue
+		}
+		status := provider.RateLimitStatus()
+		if len(status) == 0 {
+			continue
+		}
+		remaining := provider.RemainingRequests()
+		var reset time.Time
+		if next, ok := api.NextReset(status, now); ok {
+			reset = next.Reset
+		}
+		interval := quotaInterval(cfg, remaining, reset, now)
+
+		t.mu.Lock()
+		if t.stretches == nil {
+			t.stretches = map[string]time.Duration{}
+		}
+		previous := t.stretches[platform]
+		t.stretches[platform] = interval
+		t.mu.Unlock()
+		t.reportThrottle(platform, remaining, reset, interval, previous)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stretched = 0
+	for platform := range watched {
+		stretch := t.stretches[platform]
+		if stretch == 0 {
+			t.stretched = 0
+			return
+		}
+		if t.stretched == 0 || stretch < t.stretched {
+			t.stretched = stretch
+		}
+	}
+}
+
+// reportThrottle logs a change in the interval a platform's quota calls for
+func (t *Tracker) reportThrottle(platform string, remaining int, reset time.Time, interval, previous time.Duration) {
+	cfg := t.Config()
+	name := platformName(platform)
+	switch {
+	case interval > 0 && previous == 0:
+		message := fmt.Sprintf("Only %s %s requests left, checking its accounts every %s instead of every %s",
+			format.Number(remaining), name, interval.Round(time.Second), cfg.CheckInterval)
+		if !reset.IsZero() {
+			message += fmt.Sprintf(" until the quota resets at %s", reset.Local().Format("15:04"))
+		}
+		logger.Warn("%s", message)
+		if t.notifications != nil {
+			t.notifications.NotifyOps(name+" quota running low", message+".")
+		}
+	case interval == 0 && previous > 0:
+		message := fmt.Sprintf("%s %s requests left, back to checking its accounts every %s", format.Number(remaining), name, cfg.CheckInterval)
+		logger.Info("%s", message)
+		if t.notifications != nil {
+			t.notifications.NotifyOps(name+" quota recovered", message+".")
+		}
+	case interval != previous:
+		logger.Info("Checking %s accounts every %s while %s requests are left", name, interval.Round(time.Second), format.Number(remaining))
+	}
+}
+
+// withoutThrottled leaves out the accounts whose platform is low on quota
+// and that were checked more recently than its stretched interval allows.
+// Half a check interval of slack keeps the spread and jitter of cycles from
+// pushing an account back a whole extra cycle.
+func (t *Tracker) withoutThrottled(accounts []db.WatchedAccount) []db.WatchedAccount {
+	cfg := t.Config()
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	kept := accounts[:0:0]
+	held := 0
+	for _, account := range accounts {
+		stretch := t.stretches[account.Platform]
+		if stretch > 0 && !account.LastCheckedAt.IsZero() && time.Since(account.LastCheckedAt) < stretch-cfg.CheckInterval/2 {
+			held++
+			continue
+		}
+		kept = append(kept, account)
+	}
+	if held > 0 {
+		logger.Info("Leaving %s for a later cycle while their quota is low", plural(held, "account"))
+	}
+	return kept
+}
+
+// platformName is how the platform is named in logs and notifications. X is
+// the API, as it was before other platforms were supported.
+func platformName(platform string) string {
+	if platform == db.PlatformBluesky {
+		return "Bluesky"
+	}
+	return "API"
+}
+
+// StretchedInterval returns the interval periodic checks are spaced out to
+// while the quota of every watched platform is low, or 0 while
+// CHECK_INTERVAL applies
+func (t *Tracker) StretchedInterval() time.Duration {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.stretched
+}
@@ -0,0 +1,836 @@
+package tracker
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"x-tracker/config"
+	"x-tracker/internal/api"
+	"x-tracker/internal/bluesky"
+	"x-tracker/internal/db"
+	"x-tracker/internal/format"
+	"x-tracker/internal/logger"
+)
+
+// ErrAccountUnavailable is returned when a watched account is suspended or
+// otherwise can't be viewed; the status change has already been recorded
+var ErrAccountUnavailable = errors.New("account unavailable")
+
+// errEmptyFollowingList is returned instead of diffing an empty following
+// list against a populated snapshot
+var errEmptyFollowingList = errors.New("following list came back empty")
+
+// Tracker runs the fetch, diff and notify pipeline for watched accounts.
+// It is shared by the TUI and the CLI commands.
+type Tracker struct {
+	db            db.Store
+	api           Provider            // X, also consulted for the quota and the circuit breaker
+	providers     map[string]Provider // by platform, for everything specific to an account
+	notifications Notifier            // nil sends nothing
+
+	mu           sync.RWMutex // guards config, which can be swapped on reload, schemaAlert, stretched, stretches, degraded, cycle, asleep and progress
+	config       *config.Config
+	schemaAlert  string                   // anomalies reported by the last cycle, empty if none
+	stretched    time.Duration            // interval checks are spaced out to while the quota is low, 0 otherwise
+	stretches    map[string]time.Duration // by platform, the interval its quota calls for, absent or 0 while it has plenty left
+	degraded     bool                     // an API degraded alert was sent and the API hasn't recovered since
+	keyRejected  bool                     // an API key alert was sent and no cycle has got past the key since
+	cycle        *spreadCycle             // set while a periodic cycle is spreading its checks
+	asleep       time.Duration            // set while a catch-up cycle runs, how long the machine slept
+	progressFn   func(Progress)
+	progress     *Progress // the account a cycle is checking, nil between checks
+	completionFn func(Completion)
+
+	checkMu sync.Mutex // keeps cycles started by the timer, the UI and the control socket, and re-syncs, from overlapping
+
+	checks    atomic.Int64 // account checks run since startup
+	follows   atomic.Int64 // follow events recorded since startup
+	unfollows atomic.Int64 // unfollow events recorded since startup
+}
+
+// New returns a tracker checking X accounts through provider and Bluesky
+// accounts through the AppView at BLUESKY_HOST, storing them in database.
+// notifications may be nil to send none.
+func New(database db.Store, provider Provider, notifications Notifier, cfg *config.Config) *Tracker {
+	return &Tracker{
+		db:  database,
+		api: provider,
+		providers: map[string]Provider{
+			db.PlatformX:       provider,
+			db.PlatformBluesky: bluesky.NewClient(cfg),
+		},
+		notifications: notifications,
+		config:        cfg,
+	}
+}
+
+// SetProvider replaces the provider accounts on platform are checked
+// through. Call it before the tracker is used.
+func (t *Tracker) SetProvider(platform string, provider Provider) {
+	if platform == db.PlatformX {
+		t.api = provider
+	}
+	t.providers[platform] = provider
+}
+
+// providerFor returns the provider of the account's platform
+func (t *Tracker) providerFor(account *db.WatchedAccount) Provider {
+	return t.ProviderFor(account.Platform)
+}
+
+// ProviderFor returns the provider accounts on platform are checked
+// through, X's for platforms it doesn't know
+func (t *Tracker) ProviderFor(platform string) Provider {
+	if provider, ok := t.providers[platform]; ok {
+		return provider
+	}
+	return t.api
+}
+
+// Config returns the configuration currently in effect. While a catch-up
+// cycle runs, that is the digest variant of the configuration.
+func (t *Tracker) Config() *config.Config {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.asleep > 0 {
+		return digestConfig(t.config)
+	}
+	return t.config
+}
+
+// SetConfig swaps in a reloaded configuration; it applies from the next check
+func (t *Tracker) SetConfig(cfg *config.Config) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.config = cfg
+}
+
+// Totals counts what the tracker did since it was created
+type Totals struct {
+	Checks    int64
+	Follows   int64
+	Unfollows int64
+}
+
+// Totals returns the checks run and events recorded since startup
+func (t *Tracker) Totals() Totals {
+	return Totals{Checks: t.checks.Load(), Follows: t.follows.Load(), Unfollows: t.unfollows.Load()}
+}
+
+// AddAccount looks up a user, adds it to the watch list and stores its
+// current followings as the baseline, waiting for a running check cycle to
+// finish first
+func (t *Tracker) AddAccount(username string) (*db.WatchedAccount, error) {
+	account, err := t.addAccount(username)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.Config().BaselineMode == config.BaselineDeferred {
+		logger.Info("Deferring baseline for @%s to the next check", account.Username)
+		return account, nil
+	}
+
+	// Like a re-sync, the baseline waits for a running check cycle, which
+	// could be taking a deferred baseline of the same account
+	t.checkMu.Lock()
+	defer t.checkMu.Unlock()
+	if err := t.baseline(account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// AddAccountByID adds the user with this ID or DID to the watch list, for
+// users only known from events. It costs a lookup by ID on top of
+// AddAccount's.
+func (t *Tracker) AddAccountByID(userID string) (*db.WatchedAccount, error) {
+	provider := t.api
+	if strings.HasPrefix(userID, "did:") {
+		provider = t.providers[db.PlatformBluesky]
+	}
+	user, err := provider.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Legacy.ScreenName == "" {
+		return nil, fmt.Errorf("lookup of user %s returned no username, the API response format may have changed", userID)
+	}
+	return t.AddAccount(user.Legacy.ScreenName)
+}
+
+// RemoveAccount stops watching an account. Depending on REMOVE_MODE its
+// history is deleted with it or kept in the archive.
+func (t *Tracker) RemoveAccount(account *db.WatchedAccount) error {
+	if t.Config().RemoveMode == config.RemoveArchive {
+		return t.db.ArchiveWatchedAccount(account.ID)
+	}
+	return t.db.RemoveWatchedAccount(account.ID)
+}
+
+// ImportBaseline uses a following list loaded from a file as the account's
+// baseline, adding the account first if it isn't watched yet. An existing
+// baseline is only overwritten when replace is set. Like a re-sync, it
+// waits for a running check cycle to finish before storing it.
+func (t *Tracker) ImportBaseline(username string, followingIDs []string, replace bool) (*db.WatchedAccount, error) {
+	username = strings.TrimPrefix(username, "@")
+
+	account, err := t.db.GetWatchedAccountByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		if account, err = t.addAccount(username); err != nil {
+			return nil, err
+		}
+	} else if !account.BaselinedAt.IsZero() && !replace {
+		return nil, fmt.Errorf("@%s already has a baseline, use --replace to overwrite it", account.Username)
+	}
+
+	t.checkMu.Lock()
+	defer t.checkMu.Unlock()
+	if err := t.db.ReplaceFollowings(account.ID, followingIDs); err != nil {
+		return nil, fmt.Errorf("storing imported followings: %w", err)
+	}
+	account.BaselinedAt = time.Now()
+	t.recordCount(account, len(followingIDs))
+
+	logger.Info("Imported %d followings for @%s as baseline", len(followingIDs), account.Username)
+	return account, nil
+}
+
+// addAccount looks up a user and adds it to the watch list without a
+// baseline. Malformed handles and accounts already watched are turned away
+// before the lookup spends an API request.
+func (t *Tracker) addAccount(username string) (*db.WatchedAccount, error) {
+	username, err := NormalizeUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.checkNotWatched(username); err != nil {
+		return nil, err
+	}
+
+	// Get user details from API
+	platform := PlatformOf(username)
+	user, err := t.providers[platform].GetUser(username)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Got user details - ID: %s, Username: %s, Following: %d",
+		user.RestID,
+		user.Legacy.ScreenName,
+		user.Legacy.FriendsCount)
+
+	if user.RestID == "" || user.Legacy.ScreenName == "" {
+		return nil, fmt.Errorf("lookup of %s returned no user ID or username, the API response format may have changed", username)
+	}
+
+	// Add to database
+	account := &db.WatchedAccount{
+		Username: user.Legacy.ScreenName,
+		UserID:   user.RestID,
+		Platform: platform,
+	}
+
+	if err := t.db.AddWatchedAccount(account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// baseline fetches and stores the account's followings without recording
+// events, so later checks have something to diff against
+func (t *Tracker) baseline(account *db.WatchedAccount) (err error) {
+	started := time.Now()
+	stored := 0
+	defer func() {
+		t.Complete("Baseline of @"+account.Username, started, fmt.Sprintf("stored %s followings", format.Number(stored)), err)
+	}()
+
+	if t.schemaSuspect() {
+		return fmt.Errorf("API responses look malformed, postponing baseline for %s", account.Username)
+	}
+
+	// Get and store initial following list
+	followings, err := t.fetchFollowingIDs(account)
+	if err != nil {
+		return fmt.Errorf("getting initial followings: %w", err)
+	}
+	t.storeRawSnapshot(account, followings.IDs)
+
+	if err := t.db.StoreFollowings(account.ID, followings.IDs); err != nil {
+		return fmt.Errorf("storing initial followings: %w", err)
+	}
+	stored = len(followings.IDs)
+	t.recordCount(account, len(followings.IDs))
+
+	if err := t.db.MarkBaselined(account.ID); err != nil {
+		return fmt.Errorf("marking baseline: %w", err)
+	}
+	account.BaselinedAt = time.Now()
+
+	logger.Info("Initialized %d followings for @%s", len(followings.IDs), account.Username)
+	return nil
+}
+
+// CheckAll checks every watched account once, right away. Per-account
+// failures are logged and skipped; only failing to load the watch list is
+// returned. If a periodic cycle is spreading its checks, the remaining ones
+// run immediately instead of starting another cycle.
+func (t *Tracker) CheckAll() error {
+	if cycle := t.spreading(); cycle != nil {
+		cycle.finish()
+		<-cycle.done
+		return cycle.err
+	}
+	return t.checkAll(0, 0, 0, false)
+}
+
+// CheckAllScheduled runs a periodic check cycle, spreading the account
+// checks over CHECK_SPREAD of the interval and adding per-account jitter
+func (t *Tracker) CheckAllScheduled() error {
+	cfg := t.Config()
+	window := time.Duration(float64(cfg.CheckInterval) * cfg.CheckSpread)
+	return t.checkAll(window, cfg.CheckJitter, 0, true)
+}
+
+// cycleSummary describes a finished check run in one line, e.g. "Checked 14
+// accounts in 38s: 3 changes, 1 error, quota 1,241 left"
+func (t *Tracker) cycleSummary(run *db.CheckRun) string {
+	duration := run.FinishedAt.Sub(run.StartedAt).Round(time.Second)
+	if run.Error != "" {
+		return fmt.Sprintf("Check cycle failed after %s: %s", duration, run.Error)
+	}
+
+	changes := "? changes"
+	events, err := t.db.GetEvents(db.EventQuery{Since: run.StartedAt, IncludeDismissed: true})
+	if err != nil {
+		logger.Warn("Failed to count changes for the cycle summary: %v", err)
+	} else {
+		changes = plural(len(events), "change")
+	}
+
+	return fmt.Sprintf("Checked %s in %s: %s, %s, quota %s left",
+		plural(run.Accounts, "account"),
+		duration,
+		changes,
+		plural(run.Failures+run.NotifyFailures, "error"),
+		format.Number(run.QuotaRemaining))
+}
+
+// plural formats a count with a noun that takes a plain "s" in the plural
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%s %s", format.Number(n), noun)
+	}
+	return fmt.Sprintf("%s %ss", format.Number(n), noun)
+}
+
+// checkAll runs a check cycle. Checks are spaced out over window with up to
+// jitter extra delay each; both zero checks the accounts back to back. A
+// non-zero asleep makes it a catch-up cycle after the machine slept that
+// long. A scheduled cycle leaves out accounts whose platform is low on
+// quota until their stretched interval is up. Every cycle is recorded as a
+// check run for the status command.
+func (t *Tracker) checkAll(window, jitter, asleep time.Duration, scheduled bool) (err error) {
+	t.checkMu.Lock()
+	defer t.checkMu.Unlock()
+
+	if asleep > 0 {
+		t.setAsleep(asleep)
+		defer t.setAsleep(0)
+	}
+
+	var cycle *spreadCycle
+	if window > 0 || jitter > 0 {
+		cycle = newSpreadCycle()
+		t.setSpreading(cycle)
+		defer func() {
+			t.setSpreading(nil)
+			cycle.err = err
+			close(cycle.done)
+		}()
+	}
+
+	run := &db.CheckRun{StartedAt: time.Now()}
+	var costs []db.CheckCost
+	var keyErr error
+	watched := map[string]bool{}
+	var notifyFailures int64
+	if t.notifications != nil {
+		notifyFailures = t.notifications.Failures()
+	}
+	defer func() {
+		t.report(Progress{State: ProgressFinished, Index: run.Accounts, Total: run.Accounts})
+		run.FinishedAt = time.Now()
+		run.QuotaRemaining = t.api.RemainingRequests()
+		if t.notifications != nil {
+			run.NotifyFailures = int(t.notifications.Failures() - notifyFailures)
+		}
+		if err := t.db.RecordCheckRun(run); err != nil {
+			logger.Error("Error recording check run: %v", err)
+		} else if len(costs) > 0 {
+			if err := t.db.RecordCheckCosts(run.ID, costs); err != nil {
+				logger.Error("Error recording check costs: %v", err)
+			}
+		}
+		t.checkBreaker()
+		if run.Accounts > 0 {
+			t.checkKey(keyErr)
+		}
+		t.updateThrottle(watched)
+		t.refreshInsights()
+		t.archiveEvents()
+		logger.With("accounts", run.Accounts, "failures", run.Failures,
+			"notify_failures", run.NotifyFailures, "quota_remaining", run.QuotaRemaining,
+			"duration", run.FinishedAt.Sub(run.StartedAt).Round(time.Millisecond)).Info("Check cycle finished")
+		if t.notifications != nil && t.Config().CycleSummary {
+			summary := t.cycleSummary(run)
+			if asleep > 0 {
+				summary = fmt.Sprintf("Caught up after %s asleep. %s", asleep.Round(time.Minute), summary)
+			}
+			if insights := t.insightLines(run.StartedAt); insights != "" {
+				summary += "\n" + insights
+			}
+			t.notifications.NotifyCycleSummary(summary)
+		}
+	}()
+
+	accounts, err := t.db.GetWatchedAccounts()
+	if err != nil {
+		run.Error = err.Error()
+		return fmt.Errorf("getting watched accounts: %w", err)
+	}
+	accounts = withoutPaused(accounts)
+	for _, account := range accounts {
+		watched[account.Platform] = true
+	}
+	if scheduled {
+		accounts = t.withoutThrottled(accounts)
+	}
+
+	var delays []time.Duration
+	if cycle != nil && len(accounts) > 0 {
+		delays = planChecks(accounts, window, jitter)
+		t.setPlan(cycle, accounts, delays)
+		logger.Info("Spreading checks of %d accounts over %s", len(accounts), delays[len(delays)-1].Round(time.Second))
+	}
+
+	t.api.ResetSchemaStats()
+	emptyLists := 0
+	// Checks skipped because their platform's circuit is open or its key
+	// was refused; the other platforms are checked as usual
+	degraded, refused := 0, 0
+	rejected := map[string]bool{}
+	for i := range accounts {
+		if cycle != nil {
+			cycle.wait(delays[i])
+		}
+		provider := t.providerFor(&accounts[i])
+		if rejected[accounts[i].Platform] {
+			refused++
+			continue
+		}
+		if breaker := provider.Breaker(); breaker.Open {
+			if degraded == 0 {
+				logger.Warn("API degraded, skipping checks of %s accounts until %s", platformName(accounts[i].Platform), breaker.Until.Local().Format("15:04:05"))
+			}
+			degraded++
+			continue
+		}
+		run.Accounts++
+		progress := Progress{State: ProgressChecking, Account: accounts[i].Username, Index: i + 1, Total: len(accounts)}
+		t.report(progress)
+		before := provider.Usage()
+		err := t.CheckAccount(&accounts[i])
+		usage := provider.Usage().Sub(before)
+		costs = append(costs, db.CheckCost{
+			WatchedAccountID: accounts[i].ID,
+			CheckedAt:        time.Now(),
+			Pages:            int(usage.Pages),
+			Lookups:          int(usage.Lookups),
+		})
+		progress.State, progress.Err = ProgressChecked, err
+		if err != nil {
+			progress.State = ProgressFailed
+		}
+		t.report(progress)
+		if errors.Is(err, ErrAccountUnavailable) {
+			// Already reported when the status changed
+			continue
+		}
+		if errors.Is(err, errEmptyFollowingList) {
+			emptyLists++
+		}
+		if errors.Is(err, api.ErrInvalidKey) {
+			// Every other check on the platform would be refused the same way
+			if provider == t.api {
+				keyErr = err
+			}
+			rejected[accounts[i].Platform] = true
+			run.Failures++
+			logger.Error("API key rejected, skipping the remaining checks of %s accounts: %v", platformName(accounts[i].Platform), err)
+			continue
+		}
+		if err != nil {
+			run.Failures++
+			logger.With("account", accounts[i].Username, "error", err).Error("Check failed")
+		}
+	}
+	switch {
+	case len(rejected) > 0:
+		run.Error = fmt.Sprintf("API key rejected, skipped %s", plural(refused, "check"))
+	case degraded > 0:
+		run.Error = fmt.Sprintf("API degraded, skipped %s", plural(degraded, "check"))
+	}
+	t.checkSchema(emptyLists)
+	return nil
+}
+
+// withoutPaused drops the accounts whose checks are paused
+func withoutPaused(accounts []db.WatchedAccount) []db.WatchedAccount {
+	active := accounts[:0]
+	for _, account := range accounts {
+		if account.Paused() {
+			logger.Debug("Skipping paused account %s", account.Username)
+			continue
+		}
+		active = append(active, account)
+	}
+	return active
+}
+
+// CheckAccount fetches an account's followings, records the differences
+// against the stored snapshot and sends notifications for them
+func (t *Tracker) CheckAccount(account *db.WatchedAccount) error {
+	cfg := t.Config()
+	t.checks.Add(1)
+
+	// One user lookup serves both the profile and the drift checks. They are
+	// independent of the following diff, so a failure here shouldn't hold it up.
+	var user *api.UserByIDResponse
+	if account.Available() && (cfg.TrackProfileChanges || cfg.DriftThreshold > 0) {
+		var err error
+		if user, err = t.providerFor(account).GetUserByID(account.UserID); err != nil {
+			logger.Error("Error looking up %s: %v", account.Username, err)
+		}
+	}
+	if user != nil && cfg.TrackProfileChanges {
+		if err := t.checkProfile(account, user); err != nil {
+			logger.Error("Error checking profile of %s: %v", account.Username, err)
+		}
+	}
+
+	if account.Available() && cfg.TracksFollowers(account.Username) {
+		if err := t.checkFollowers(account); err != nil {
+			logger.Error("Error checking followers of %s: %v", account.Username, err)
+		}
+	}
+
+	// Accounts added with a deferred baseline get it on their first check
+	if account.BaselinedAt.IsZero() {
+		return t.baseline(account)
+	}
+
+	// Diff the followings from the API page by page
+	diff, err := t.diffFollowings(account)
+	if err != nil {
+		return fmt.Errorf("diffing followings: %w", err)
+	}
+
+	// Dropping every following at once is far more likely a bad response
+	// than a real mass unfollow
+	if diff.Fetched == 0 && diff.Stored >= emptyListGuard {
+		return fmt.Errorf("%w while %d are stored, skipping diff", errEmptyFollowingList, diff.Stored)
+	}
+
+	t.recordCount(account, diff.Fetched)
+
+	if user != nil && cfg.DriftThreshold > 0 {
+		drifting := t.checkDrift(account, user.Legacy.FriendsCount, diff.Fetched)
+		if drifting && cfg.DriftResync {
+			logger.Info("Re-syncing %s instead of diffing an incomplete following list", account.Username)
+			return t.resync(account)
+		}
+	}
+
+	newFollows, unfollows := diff.Follows, diff.Unfollows
+
+	firstCheck := account.LastCheckedAt.IsZero()
+
+	if len(newFollows) == 0 && len(unfollows) == 0 {
+		logger.Info("No changes detected for %s", account.Username)
+		return t.markChecked(account)
+	}
+
+	logger.With("account", account.Username).Info("Processing changes",
+		"follows", len(newFollows), "unfollows", len(unfollows))
+
+	// Changes undoing a recent event are counted as flaps of it instead of
+	// being recorded again. The flaps, the events and the followings are
+	// stored together, so a failure leaves the changes to the next check.
+	var flapSince time.Time
+	if cfg.FlapWindow > 0 {
+		flapSince = time.Now().Add(-cfg.FlapWindow)
+	}
+	recorded, err := t.db.RecordChanges(account.ID, newFollows, unfollows, diff.Fetched, flapSince)
+	if err != nil {
+		return err
+	}
+	events, flapped := recorded.Events, recorded.Flapped
+	storedFollows, storedUnfollows := recorded.Follows, recorded.Unfollows
+	if len(flapped) > 0 {
+		logger.Info("Collapsed %d flapping changes of %s", len(flapped), account.Username)
+	}
+	t.follows.Add(int64(len(storedFollows)))
+	t.unfollows.Add(int64(len(storedUnfollows)))
+	logEvents(cfg, account, events, flapped)
+
+	if err := t.markChecked(account); err != nil {
+		return err
+	}
+
+	if firstCheck && !cfg.FirstCheckNotify {
+		logger.Info("First check for %s after baseline, not notifying %d follows and %d unfollows",
+			account.Username, len(newFollows), len(unfollows))
+		return nil
+	}
+
+	// Flaps are only notified on request, under the event they were
+	// collapsed into
+	notifyFollows, notifyUnfollows := storedFollows, storedUnfollows
+	if cfg.FlapNotify {
+		notifyFollows, notifyUnfollows = newFollows, unfollows
+		for _, event := range flapped {
+			event.EventType = event.Net()
+			events = append(events, event)
+		}
+	} else if len(flapped) > 0 {
+		logger.Info("Not notifying %d flapping changes of %s", len(flapped), account.Username)
+	}
+
+	// Send webhook notifications if configured
+	if t.notifications != nil {
+		// Handle follow notifications
+		if cfg.EnableFollowNotifications && len(notifyFollows) > 0 {
+			if follows := t.withoutMuted(account, notifyFollows); len(follows) > 0 {
+				logger.Info("Sending follow notifications for %s: %d new follows",
+					account.Username, len(follows))
+				t.notifications.NotifyNewFollows(account, eventsOf(events, db.EventTypeFollow, follows), t.providerFor(account))
+			}
+		} else if len(notifyFollows) > 0 {
+			logger.Info("Follow notifications disabled, skipping %d new follows", len(notifyFollows))
+		}
+
+		// Handle unfollow notifications
+		if cfg.EnableUnfollowNotifications && len(notifyUnfollows) > 0 {
+			if unfollowed := t.withoutMuted(account, notifyUnfollows); len(unfollowed) > 0 {
+				logger.Info("Sending unfollow notifications for %s: %d unfollows",
+					account.Username, len(unfollowed))
+				t.notifications.NotifyUnfollows(account, eventsOf(events, db.EventTypeUnfollow, unfollowed), t.providerFor(account))
+			}
+		} else if len(notifyUnfollows) > 0 {
+			logger.Info("Unfollow notifications disabled, skipping %d unfollows", len(notifyUnfollows))
+		}
+	}
+
+	t.snapshotTargets(t.providerFor(account), storedFollows)
+
+	logger.Info("Successfully processed all changes for account %s", account.Username)
+	return nil
+}
+
+// withoutMuted drops targets on the global mute list before they reach
+// the notifications. Their events are still recorded. If the mute list
+// can't be read every target is kept.
+func (t *Tracker) withoutMuted(account *db.WatchedAccount, userIDs []string) []string {
+	kept, err := t.db.SuppressMuted(userIDs)
+	if err != nil {
+		logger.Warn("Failed to check the mute list for %s: %v", account.Username, err)
+		return userIDs
+	}
+	if muted := len(userIDs) - len(kept); muted > 0 {
+		logger.Info("Suppressed %d muted targets of %s", muted, account.Username)
+	}
+	return kept
+}
+
+// eventsOf picks the events of one type about the given users, in their order
+func eventsOf(events []db.FollowEvent, eventType db.EventType, userIDs []string) []db.FollowEvent {
+	byUser := make(map[string]db.FollowEvent, len(events))
+	for _, event := range events {
+		if event.EventType == eventType {
+			byUser[event.UserID] = event
+		}
+	}
+	picked := make([]db.FollowEvent, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if event, ok := byUser[userID]; ok {
+			picked = append(picked, event)
+		}
+	}
+	return picked
+}
+
+// snapshotTargets stores the first page of followings of newly followed
+// users through the provider of the account following them, spending at
+// most TargetSnapshotBudget requests per 24 hours. Users snapshotted within
+// that window are skipped.
+func (t *Tracker) snapshotTargets(provider Provider, userIDs []string) {
+	cfg := t.Config()
+	if cfg.TargetSnapshotBudget <= 0 || len(userIDs) == 0 {
+		return
+	}
+
+	since := time.Now().Add(-24 * time.Hour)
+	used, err := t.db.CountTargetSnapshotsSince(since)
+	if err != nil {
+		logger.Error("Error counting target snapshots: %v", err)
+		return
+	}
+
+	for i, userID := range userIDs {
+		if used >= cfg.TargetSnapshotBudget {
+			logger.Info("Target snapshot budget of %d per day used up, skipping %d targets",
+				cfg.TargetSnapshotBudget, len(userIDs)-i)
+			return
+		}
+
+		existing, err := t.db.GetTargetSnapshot(userID)
+		if err != nil {
+			logger.Error("Error loading target snapshot for %s: %v", userID, err)
+			continue
+		}
+		if existing != nil && existing.TakenAt.After(since) {
+			continue
+		}
+
+		// Failed requests still cost quota, so they count against this run's budget
+		used++
+		page, err := provider.GetFirstFollowingIDs(userID, cfg.TargetSnapshotSize)
+		if err != nil {
+			logger.Error("Error snapshotting followings of %s: %v", userID, err)
+			continue
+		}
+		if err := t.db.StoreTargetSnapshot(userID, page.IDs, time.Now()); err != nil {
+			logger.Error("Error storing target snapshot for %s: %v", userID, err)
+			continue
+		}
+		logger.Info("Stored snapshot of %d followings for target %s", len(page.IDs), userID)
+	}
+}
+
+// fetchFollowingIDs gets the account's followings and keeps its status in
+// step with the API: an account that can't be viewed is marked suspended or
+// unavailable, and marked active again once it can
+func (t *Tracker) fetchFollowingIDs(account *db.WatchedAccount) (*api.FollowingIDsResponse, error) {
+	followings, err := t.providerFor(account).GetFollowingIDsWithProgress(account.UserID, func(fetched int) {
+		t.reportFetched(account, fetched)
+	})
+	if err := t.fetched(account, err); err != nil {
+		return nil, err
+	}
+	return followings, nil
+}
+
+// fetched updates the account's status from the outcome of fetching its
+// followings and returns the error to report, if any
+func (t *Tracker) fetched(account *db.WatchedAccount, err error) error {
+	var apiErr *api.APIError
+	switch {
+	case err == nil:
+		t.setStatus(account, db.AccountStatusActive)
+		return nil
+	case errors.As(err, &apiErr) && apiErr.Unavailable():
+		status := db.AccountStatusUnavailable
+		if apiErr.Suspended() {
+			status = db.AccountStatusSuspended
+		}
+		t.setStatus(account, status)
+		return fmt.Errorf("@%s is %s: %w", account.Username, status, ErrAccountUnavailable)
+	}
+	return err
+}
+
+// setStatus records a status change and sends a one-time notification for it
+func (t *Tracker) setStatus(account *db.WatchedAccount, status db.AccountStatus) {
+	previous := account.Status
+	if previous == status || (previous == "" && status == db.AccountStatusActive) {
+		return
+	}
+
+	now := time.Now()
+	if err := t.db.SetAccountStatus(account.ID, status, now); err != nil {
+		logger.Error("Error saving status of %s: %v", account.Username, err)
+		return
+	}
+	account.Status = status
+	account.StatusChangedAt = now
+	logger.Info("Account %s is now %s (was %s)", account.Username, status, previous)
+
+	if t.notifications != nil {
+		t.notifications.NotifyAccountStatus(account, previous)
+	}
+}
+
+// checkProfile compares the account's profile against the stored one,
+// records what changed and notifies about it. The first capture only
+// stores the profile.
+func (t *Tracker) checkProfile(account *db.WatchedAccount, user *api.UserByIDResponse) error {
+	if user.Legacy.ScreenName == "" {
+		return fmt.Errorf("profile lookup returned no username")
+	}
+
+	current := db.Profile{
+		Username:    user.Legacy.ScreenName,
+		DisplayName: user.Legacy.Name,
+		Bio:         user.Legacy.Description,
+		AvatarURL:   user.Legacy.ProfileImageURLHTTPS,
+	}
+
+	var changes []db.ProfileEvent
+	if !account.ProfileSeenAt.IsZero() {
+		changes = db.ProfileChanges(account.ID, account.Profile, current, time.Now())
+	}
+
+	if err := t.db.StoreProfile(account.ID, current, changes); err != nil {
+		return fmt.Errorf("storing profile: %w", err)
+	}
+	account.Username = current.Username
+	account.Profile = current
+	account.ProfileSeenAt = time.Now()
+
+	if len(changes) == 0 {
+		return nil
+	}
+	logger.Info("Detected %d profile changes for %s", len(changes), account.Username)
+
+	if t.notifications != nil && t.Config().EnableProfileNotifications {
+		t.notifications.NotifyProfileChanges(account, changes)
+	}
+	return nil
+}
+
+// markChecked records a completed diff for the account
+func (t *Tracker) markChecked(account *db.WatchedAccount) error {
+	checkedAt := time.Now()
+	if err := t.db.MarkChecked(account.ID, checkedAt); err != nil {
+		return fmt.Errorf("marking check: %w", err)
+	}
+	account.LastCheckedAt = checkedAt
+	return nil
+}
+
+// recordCount stores a following-count sample; failures only cost a point
+// on the sparkline, so they are logged rather than returned
+func (t *Tracker) recordCount(account *db.WatchedAccount, count int) {
+	if err := t.db.RecordFollowingCount(account.ID, count, time.Now()); err != nil {
+		logger.Error("Error recording following count for %s: %v", account.Username, err)
+	}
+}
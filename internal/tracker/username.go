@@ -0,0 +1,116 @@
+package tracker
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"x-tracker/internal/db"
+)
+
+// ErrInvalidUsername is returned for input that can't be an X or Bluesky
+// handle, before any API request is spent on it
+var ErrInvalidUsername = errors.New("invalid username")
+
+// usernamePattern matches X handles: 1 to 15 letters, digits or underscores
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_]{1,15}$`)
+
+// handlePattern matches Bluesky handles, which are domain names such as
+// alice.bsky.social
+var handlePattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// maxHandleLength is the longest domain name, and so Bluesky handle
+const maxHandleLength = 253
+
+// AlreadyWatchedError is returned when adding an account that is already
+// on the watch list
+type AlreadyWatchedError struct {
+	Account *db.WatchedAccount
+}
+
+func (e *AlreadyWatchedError) Error() string {
+	return fmt.Sprintf("@%s is already watched", e.Account.Username)
+}
+
+// NormalizeUsername trims whitespace and a leading @ from a typed handle
+// and checks that what's left is a valid X username, or a Bluesky handle
+// if it contains a dot. Bluesky handles are lowercased.
+func NormalizeUsername(input string) (string, error) {
+	username := strings.TrimPrefix(strings.TrimSpace(input), "@")
+	if PlatformOf(username) == db.PlatformBluesky {
+		return normalizeHandle(username)
+	}
+	switch {
+	case username == "":
+		return "", fmt.Errorf("%w: nothing entered", ErrInvalidUsername)
+	case len(username) > 15:
+		return "", fmt.Errorf("%w @%s: longer than 15 characters", ErrInvalidUsername, username)
+	case !usernamePattern.MatchString(username):
+		return "", fmt.Errorf("%w @%s: only letters, digits and underscores are allowed", ErrInvalidUsername, username)
+	}
+	return username, nil
+}
+
+func normalizeHandle(handle string) (string, error) {
+	handle = strings.ToLower(handle)
+	switch {
+	case len(handle) > maxHandleLength:
+		return "", fmt.Errorf("%w @%s: longer than %d characters", ErrInvalidUsername, handle, maxHandleLength)
+	case !handlePattern.MatchString(handle):
+		return "", fmt.Errorf("%w @%s: not a valid Bluesky handle", ErrInvalidUsername, handle)
+	}
+	return handle, nil
+}
+
+// PlatformOf returns the platform a handle is on. X usernames can't contain
+// a dot and Bluesky handles always do.
+func PlatformOf(username string) string {
+	if strings.Contains(username, ".") {
+		return db.PlatformBluesky
+	}
+	return db.PlatformX
+}
+
+// PlatformOfUserID returns the platform a user ID is on. Bluesky IDs are
+// DIDs and X IDs are numbers.
+func PlatformOfUserID(id string) string {
+	if strings.HasPrefix(id, "did:") {
+		return db.PlatformBluesky
+	}
+	return db.PlatformX
+}
+
+// checkNotWatched returns an AlreadyWatchedError if username is on the
+// watch list
+func (t *Tracker) checkNotWatched(username string) error {
+	existing, err := t.db.GetWatchedAccountByUsername(username)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return &AlreadyWatchedError{Account: existing}
+	}
+	return nil
+}
+
+// SplitUsernames splits a list of handles separated by commas or whitespace,
+// dropping repeats in any casing. The handles aren't validated.
+func SplitUsernames(input string) []string {
+	fields := strings.FieldsFunc(input, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+
+	var usernames []string
+	seen := make(map[string]bool)
+	for _, field := range fields {
+		key := db.UsernameKey(strings.TrimPrefix(field, "@"))
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		usernames = append(usernames, field)
+	}
+	return usernames
+}
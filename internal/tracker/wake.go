@@ -0,0 +1,43 @@
+package tracker
+
+import (
+	"time"
+
+	"x-tracker/config"
+	"x-tracker/internal/logger"
+)
+
+// CatchUp runs one check cycle right away after the machine slept through
+// at least one scheduled cycle. Rather than a notification per change,
+// everything the cycle finds is announced in a single cycle summary, like
+// the digest notification preset. A periodic cycle whose spread checks were
+// held up by the sleep is hurried along instead.
+func (t *Tracker) CatchUp(asleep time.Duration) error {
+	if cycle := t.spreading(); cycle != nil {
+		logger.Info("Resumed after %s asleep, finishing the interrupted check cycle", asleep.Round(time.Second))
+		cycle.finish()
+		<-cycle.done
+		return cycle.err
+	}
+	logger.Info("Resumed after %s asleep, running a catch-up check cycle", asleep.Round(time.Second))
+	return t.checkAll(0, 0, asleep, false)
+}
+
+// setAsleep marks a catch-up cycle as running, or over with 0
+func (t *Tracker) setAsleep(asleep time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.asleep = asleep
+}
+
+// digestConfig is cfg with the notification per change turned off and the
+// cycle summary turned on
+func digestConfig(cfg *config.Config) *config.Config {
+	digest := *cfg
+	digest.EnableFollowNotifications = false
+	digest.EnableUnfollowNotifications = false
+	digest.EnableProfileNotifications = false
+	digest.EnableLostFollowerNotifications = false
+	digest.CycleSummary = true
+	return &digest
+}
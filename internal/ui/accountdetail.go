@@ -0,0 +1,275 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"x-tracker/internal/db"
+	"x-tracker/internal/format"
+)
+
+const (
+	// detailEvents is how many recent events the account detail lists
+	detailEvents = 8
+	// detailDays is how many days the event chart of the account detail covers
+	detailDays = 14
+	// detailProblems is how many recent errors the account detail lists
+	detailProblems = 4
+	// detailDeliveries is how many failed deliveries are searched for ones
+	// about the account
+	detailDeliveries = 200
+	// detailLogLines is how many activity log lines are searched for
+	// problems with the account
+	detailLogLines = 500
+)
+
+// accountDetailLoadedMsg carries what the account detail shows besides
+// the account itself
+type accountDetailLoadedMsg struct {
+	accountID  int64
+	recent     []db.FollowEvent // newest first
+	daily      []int            // events per day, oldest first, ending today
+	follows    int              // follows in the charted days
+	unfollows  int              // unfollows in the charted days
+	deliveries []db.Delivery    // failed deliveries about the account, newest first
+}
+
+// openAccountDetail shows the account selected in the list
+func (m *Model) openAccountDetail() tea.Cmd {
+	accounts := m.visibleAccounts()
+	if m.selected < 0 || m.selected >= len(accounts) {
+		return nil
+	}
+	m.mode = ModeAccountDetail
+	m.detailAccount = accounts[m.selected].ID
+	m.accountDetail = nil
+	return m.loadAccountDetail
+}
+
+// detailedAccount returns the account the detail screen is about, nil if
+// it is no longer watched
+func (m *Model) detailedAccount() *db.WatchedAccount {
+	for i := range m.accounts {
+		if m.accounts[i].ID == m.detailAccount {
+			return &m.accounts[i]
+		}
+	}
+	return nil
+}
+
+func (m *Model) loadAccountDetail() tea.Msg {
+	id := m.detailAccount
+	detail := accountDetailLoadedMsg{accountID: id, daily: make([]int, detailDays)}
+
+	recent, err := m.db.GetEvents(db.EventQuery{AccountID: id, IncludeDismissed: true, Limit: detailEvents})
+	if err != nil {
+		return err
+	}
+	detail.recent = recent
+
+	now := time.Now()
+	since := time.Date(now.Year(), now.Month(), now.Day()+1-detailDays, 0, 0, 0, 0, time.Local)
+	charted, err := m.db.GetEvents(db.EventQuery{AccountID: id, Since: since, IncludeDismissed: true})
+	if err != nil {
+		return err
+	}
+	for _, event := range charted {
+		day := int(event.DetectedAt.Local().Sub(since) / (24 * time.Hour))
+		if day >= 0 && day < detailDays {
+			detail.daily[day]++
+		}
+		if event.EventType == db.EventTypeUnfollow {
+			detail.unfollows++
+		} else {
+			detail.follows++
+		}
+	}
+
+	deliveries, err := m.db.GetDeliveries(detailDeliveries, true)
+	if err != nil {
+		return err
+	}
+	if account := m.detailedAccount(); account != nil {
+		for _, delivery := range deliveries {
+			if delivery.Account == account.Username && len(detail.deliveries) < detailProblems {
+				detail.deliveries = append(detail.deliveries, delivery)
+			}
+		}
+	}
+	return detail
+}
+
+// updateAccountDetail runs the shortcuts of the account detail. Actions
+// asking for input open their prompt with the username filled in.
+func (m *Model) updateAccountDetail(msg tea.KeyMsg) tea.Cmd {
+	account := m.detailedAccount()
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.mode = ModeListAccounts
+		m.error = nil
+		return nil
+	case account == nil:
+		return nil
+	case key.Matches(msg, m.keys.Followings):
+		return m.openFollowings()
+	case key.Matches(msg, m.keys.Pause):
+		return m.setPaused(*account, !account.Paused())
+	case key.Matches(msg, m.keys.Resync):
+		return m.enterInputModeFor(ModeResyncAccount, account.Username)
+	case key.Matches(msg, m.keys.Filter):
+		return m.enterInputModeFor(ModeFilterAccount, account.Username+" ")
+	case key.Matches(msg, m.keys.Remove):
+		return m.enterInputModeFor(ModeRemoveAccount, account.Username)
+	}
+	return nil
+}
+
+// enterInputModeFor opens a prompt with value already typed
+func (m *Model) enterInputModeFor(mode Mode, value string) tea.Cmd {
+	cmd := m.enterInputMode(mode)
+	m.textInput.SetValue(value)
+	m.textInput.CursorEnd()
+	return cmd
+}
+
+// renderAccountDetail shows everything known about one account: profile,
+// settings, trends, recent events and recent problems
+func (m *Model) renderAccountDetail() string {
+	account := m.detailedAccount()
+	if account == nil {
+		return m.box().Render("This account is no longer watched")
+	}
+	width := m.itemWidth()
+	line := func(s *strings.Builder, layout string, args ...any) {
+		text := fmt.Sprintf(layout, args...)
+		if width > 0 {
+			text = truncate(text, width)
+		}
+		s.WriteString(text + "\n")
+	}
+
+	var s strings.Builder
+	line(&s, "@%s%s (ID %s)", account.Username, tagSuffix(*account), account.UserID)
+	if account.Profile.DisplayName != "" {
+		line(&s, "%s", account.Profile.DisplayName)
+	}
+	if account.Profile.Bio != "" {
+		line(&s, "%s", strings.Join(strings.Fields(account.Profile.Bio), " "))
+	}
+	line(&s, "Status: %s", accountStatusText(*account))
+	line(&s, "Added %s, last checked %s, last change %s",
+		dateOrUnknown(account.AddedAt), shortAge(account.LastCheckedAt), shortAge(m.lastChanges[account.ID]))
+
+	s.WriteString("\nSettings:\n")
+	jitter := "default"
+	if account.CheckJitter != nil {
+		jitter = account.CheckJitter.String()
+	}
+	line(&s, "  Check interval %s, jitter %s", m.checkInterval, jitter)
+	tags := "none"
+	if len(account.Tags) > 0 {
+		tags = strings.Join(account.Tags, ", ")
+	}
+	line(&s, "  Tags: %s", tags)
+	filter := "none"
+	if account.Filter.Active() {
+		filter = filterSummary(account.Filter)
+	}
+	line(&s, "  Notification filter: %s", filter)
+	if m.config.TracksFollowers(account.Username) {
+		line(&s, "  Followers are tracked")
+	}
+
+	s.WriteString("\nTrends:\n")
+	if samples := m.countHistory[account.ID]; len(samples) > 0 {
+		latest := samples[len(samples)-1]
+		line(&s, "  Following  %s %s (%+d over %d checks)",
+			sparkline(samples), format.Number(latest), latest-samples[0], len(samples))
+	} else {
+		line(&s, "  Following  no counts recorded yet")
+	}
+	detail := m.accountDetail
+	if detail != nil && detail.accountID == account.ID {
+		line(&s, "  Events     %s %d follows, %d unfollows in %d days",
+			sparkline(detail.daily), detail.follows, detail.unfollows, detailDays)
+	}
+
+	s.WriteString("\nRecent events:\n")
+	switch {
+	case detail == nil || detail.accountID != account.ID:
+		s.WriteString("  Loading…\n")
+	case len(detail.recent) == 0:
+		s.WriteString("  None yet\n")
+	default:
+		for _, event := range detail.recent {
+			line(&s, "  %s", eventItem(event))
+		}
+	}
+
+	s.WriteString("\nRecent problems:\n")
+	problems := m.accountProblems(*account)
+	if detail != nil && detail.accountID == account.ID {
+		for _, delivery := range detail.deliveries {
+			problems = append(problems, fmt.Sprintf("%s  %s notification failed: %s",
+				delivery.AttemptedAt.Local().Format("Jan 2 15:04"), delivery.Channel, delivery.Error))
+		}
+	}
+	if len(problems) == 0 {
+		s.WriteString("  None\n")
+	}
+	for _, problem := range problems {
+		line(&s, "  %s", problem)
+	}
+
+	return m.box().Render(strings.TrimSuffix(s.String(), "\n"))
+}
+
+// accountProblems returns the newest warnings and errors about account in
+// the activity log, newest first
+func (m *Model) accountProblems(account db.WatchedAccount) []string {
+	if m.activity == nil {
+		return nil
+	}
+	entries := m.activity.Last(detailLogLines)
+	var problems []string
+	for i := len(entries) - 1; i >= 0 && len(problems) < detailProblems; i-- {
+		entry := entries[i]
+		if entry.Level >= slog.LevelWarn && strings.Contains(entry.Message, account.Username) {
+			problems = append(problems, entry.Time.Format("Jan 2 15:04")+"  "+entry.Message)
+		}
+	}
+	return problems
+}
+
+// accountStatusText describes whether and how an account is being checked
+func accountStatusText(account db.WatchedAccount) string {
+	var status []string
+	if account.Available() {
+		status = append(status, "active")
+	} else {
+		status = append(status, fmt.Sprintf("%s since %s", account.Status, dateOrUnknown(account.StatusChangedAt)))
+	}
+	if account.Paused() {
+		status = append(status, "paused since "+dateOrUnknown(account.PausedAt))
+	}
+	if account.BaselinedAt.IsZero() {
+		status = append(status, "awaiting baseline")
+	}
+	if account.Drift != nil {
+		status = append(status, fmt.Sprintf("drift: %s reported, %s fetched",
+			format.Number(account.Drift.Reported), format.Number(account.Drift.Fetched)))
+	}
+	return strings.Join(status, ", ")
+}
+
+func dateOrUnknown(t time.Time) string {
+	if t.IsZero() {
+		return "unknown"
+	}
+	return t.Local().Format("2006-01-02")
+}
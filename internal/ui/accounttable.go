@@ -0,0 +1,305 @@
+package ui
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/lipgloss"
+
+	"x-tracker/internal/db"
+	"x-tracker/internal/format"
+)
+
+// accountSort is the column the account table is sorted by
+type accountSort int
+
+const (
+	sortByUsername accountSort = iota
+	sortByUserID
+	sortByFollowing
+	sortByLastChange
+	sortByLastCheck
+	sortByCost
+	accountSorts // number of sort orders, for cycling through them
+)
+
+func (s accountSort) String() string {
+	switch s {
+	case sortByUserID:
+		return "user ID"
+	case sortByFollowing:
+		return "following count"
+	case sortByLastChange:
+		return "last change"
+	case sortByLastCheck:
+		return "last check"
+	case sortByCost:
+		return "check cost"
+	default:
+		return "username"
+	}
+}
+
+const (
+	// tableSparkline is how many of the loaded samples the following
+	// column draws
+	tableSparkline = 10
+	// tableHeaderLines is the header row and the border under it
+	tableHeaderLines = 2
+	// tableChrome is the header and the page line under the table
+	tableChrome = tableHeaderLines + 1
+)
+
+// sortAccounts returns a copy of accounts in the table's sort order. Text
+// columns sort ascending, counts and times newest or largest first.
+func (m *Model) sortAccounts(accounts []db.WatchedAccount) []db.WatchedAccount {
+	sorted := slices.Clone(accounts)
+	slices.SortStableFunc(sorted, func(a, b db.WatchedAccount) int {
+		switch m.accountSort {
+		case sortByUserID:
+			// IDs are numeric strings of varying length
+			if len(a.UserID) != len(b.UserID) {
+				return cmp.Compare(len(a.UserID), len(b.UserID))
+			}
+			return strings.Compare(a.UserID, b.UserID)
+		case sortByFollowing:
+			return cmp.Compare(m.followingCount(b), m.followingCount(a))
+		case sortByLastChange:
+			return m.lastChanges[b.ID].Compare(m.lastChanges[a.ID])
+		case sortByLastCheck:
+			return b.LastCheckedAt.Compare(a.LastCheckedAt)
+		case sortByCost:
+			return cmp.Compare(m.checkCost(b), m.checkCost(a))
+		default:
+			return strings.Compare(strings.ToLower(a.Username), strings.ToLower(b.Username))
+		}
+	})
+	return sorted
+}
+
+// followingCount returns an account's latest sampled following count, -1
+// if it has none yet so it sorts last
+func (m *Model) followingCount(account db.WatchedAccount) int {
+	samples := m.countHistory[account.ID]
+	if len(samples) == 0 {
+		return -1
+	}
+	return samples[len(samples)-1]
+}
+
+// checkCost returns the API calls of an account's last check, -1 if none
+// was recorded so it sorts last
+func (m *Model) checkCost(account db.WatchedAccount) int {
+	cost, ok := m.lastCosts[account.ID]
+	if !ok {
+		return -1
+	}
+	return cost.Calls()
+}
+
+// cycleAccountSort sorts the table by the next column, keeping the
+// selected account selected
+func (m *Model) cycleAccountSort() {
+	accounts := m.visibleAccounts()
+	var selectedID int64
+	if m.selected < len(accounts) {
+		selectedID = accounts[m.selected].ID
+	}
+
+	m.accountSort = (m.accountSort + 1) % accountSorts
+	m.selected = 0
+	for i, account := range m.visibleAccounts() {
+		if account.ID == selectedID {
+			m.selected = i
+		}
+	}
+	m.notice = fmt.Sprintf("Sorted by %s", m.accountSort)
+}
+
+// accountPageRows is how many accounts one page of the table shows
+func (m *Model) accountPageRows() int {
+	if m.listRows() == 0 {
+		return 0
+	}
+	return max(m.listRows()-tableChrome, minListRows)
+}
+
+// movePage moves the selection a page of the table up or down
+func (m *Model) movePage(pages int) {
+	rows := m.accountPageRows()
+	if rows == 0 {
+		return
+	}
+	m.selected = min(max(m.selected+pages*rows, 0), max(len(m.visibleAccounts())-1, 0))
+}
+
+// accountColumns lays out the table for the terminal width. Columns that
+// don't fit are left out, the status first and then the user ID; the
+// status takes whatever width is left over.
+func (m *Model) accountColumns() []table.Column {
+	titles := []string{"Username", "User ID", "Following", "Changed", "Checked", "Cost", "Status"}
+	widths := []int{18, 20, 18, 10, 10, 6, 30}
+	titles[m.accountSort] += " " + m.sortArrow()
+
+	if width := m.itemWidth(); width > 0 {
+		// Cells are padded by a space on either side
+		used := 0
+		for _, w := range widths[:6] {
+			used += w + 2
+		}
+		widths[6] = width - used - 2
+		if widths[6] < 10 {
+			widths[6] = 0
+			if used > width {
+				widths[1] = 0
+			}
+		}
+	}
+
+	columns := make([]table.Column, len(titles))
+	for i := range titles {
+		columns[i] = table.Column{Title: titles[i], Width: widths[i]}
+	}
+	return columns
+}
+
+func (m *Model) sortArrow() string {
+	if m.accountSort == sortByUsername || m.accountSort == sortByUserID {
+		return "▲"
+	}
+	return "▼"
+}
+
+// accountRow renders an account's cells, in the order of accountColumns
+func (m *Model) accountRow(account db.WatchedAccount) table.Row {
+	following := "-"
+	if samples := m.countHistory[account.ID]; len(samples) > 0 {
+		following = fmt.Sprintf("%s %s", sparkline(samples[max(len(samples)-tableSparkline, 0):]), format.Number(samples[len(samples)-1]))
+	}
+
+	var status []string
+	if account.BaselinedAt.IsZero() {
+		status = append(status, "awaiting baseline")
+	}
+	if account.Drift != nil {
+		status = append(status, fmt.Sprintf("drift: %s reported, %s fetched",
+			format.Number(account.Drift.Reported), format.Number(account.Drift.Fetched)))
+	}
+	if !account.Available() {
+		status = append(status, fmt.Sprintf("%s since %s", account.Status, account.StatusChangedAt.Local().Format("2006-01-02")))
+	}
+	if account.Filter.Active() {
+		status = append(status, filterSummary(account.Filter))
+	}
+	if account.Paused() {
+		status = append(status, "paused")
+	}
+
+	cost := "-"
+	if calls := m.checkCost(account); calls >= 0 {
+		cost = format.Number(calls)
+	}
+
+	return table.Row{
+		"@" + account.Username + tagSuffix(account),
+		account.UserID,
+		following,
+		shortAge(m.lastChanges[account.ID]),
+		shortAge(account.LastCheckedAt),
+		cost,
+		strings.Join(status, ", "),
+	}
+}
+
+// shortAge tells how long ago t was in a few characters, e.g. "3h ago"
+func shortAge(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d/time.Minute))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d/time.Hour))
+	default:
+		return fmt.Sprintf("%dd ago", int(d/(24*time.Hour)))
+	}
+}
+
+// renderAccountList shows the watched accounts as a table, a page at a
+// time. The selected row is highlighted only while the list has focus.
+func (m *Model) renderAccountList() string {
+	if len(m.accounts) == 0 {
+		return "No accounts being watched"
+	}
+
+	var s strings.Builder
+	accounts := m.visibleAccounts()
+	if m.tagFilter != "" {
+		s.WriteString(fmt.Sprintf("Watched accounts tagged %s:\n\n", m.tagFilter))
+		if len(accounts) == 0 {
+			s.WriteString("None\n")
+			return m.box().Render(s.String())
+		}
+	} else {
+		s.WriteString("Watched accounts:\n\n")
+	}
+
+	selected := -1
+	if m.mode == ModeListAccounts {
+		selected = m.selected
+	}
+	start, end := 0, len(accounts)
+	page, pages := 1, 1
+	if pageRows := m.accountPageRows(); pageRows > 0 && len(accounts) > pageRows {
+		page = max(selected, 0)/pageRows + 1
+		pages = (len(accounts) + pageRows - 1) / pageRows
+		start = (page - 1) * pageRows
+		end = min(start+pageRows, len(accounts))
+	}
+
+	rows := make([]table.Row, 0, end-start)
+	for i, account := range accounts[start:end] {
+		row := m.accountRow(account)
+		if start+i == selected {
+			row[0] = "→ " + row[0]
+		}
+		rows = append(rows, row)
+	}
+	styles := table.Styles{Header: tableHeaderStyle, Cell: tableCellStyle, Selected: tableSelectedStyle}
+	if selected < 0 {
+		styles.Selected = lipgloss.NewStyle()
+	}
+
+	columns := m.accountColumns()
+	width := 0
+	for _, column := range columns {
+		if column.Width > 0 {
+			width += column.Width + 2
+		}
+	}
+	// The height counts the header, so the styles that border it go first
+	t := table.New(
+		table.WithStyles(styles),
+		table.WithColumns(columns),
+		table.WithRows(rows),
+		table.WithHeight(len(rows)+tableHeaderLines),
+		table.WithWidth(width),
+	)
+	if selected >= 0 {
+		t.SetCursor(selected - start)
+	}
+	s.WriteString(t.View() + "\n")
+
+	if pages > 1 {
+		s.WriteString(helpStyle.Render(fmt.Sprintf("Page %d of %d, %d accounts", page, pages, len(accounts))) + "\n")
+	}
+	return m.box().Render(s.String())
+}
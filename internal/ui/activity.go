@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"log/slog"
+	"strings"
+)
+
+const (
+	activityLines = 12  // log lines shown in the activity pane
+	activityWidth = 120 // longer lines are cut off
+)
+
+func (m *Model) toggleActivity() {
+	if m.activity == nil {
+		m.notice = "Activity log is not available"
+		return
+	}
+	m.showActivity = !m.showActivity
+}
+
+// renderActivity shows the newest log lines; the every-second tick keeps it live
+func (m *Model) renderActivity() string {
+	entries := m.activity.Last(activityLines)
+	if len(entries) == 0 {
+		return m.box().Render("No activity yet")
+	}
+
+	var s strings.Builder
+	s.WriteString("Activity:\n\n")
+	for i, entry := range entries {
+		width := activityWidth
+		if w := m.itemWidth(); w > 0 {
+			// Activity lines aren't indented like list items
+			width = min(w+4, activityWidth)
+		}
+		line := truncate(entry.Time.Format("15:04:05")+" "+entry.Message, width)
+
+		switch {
+		case entry.Level >= slog.LevelError:
+			line = errorStyle.Render(line)
+		case entry.Level >= slog.LevelWarn:
+			line = warnStyle.Render(line)
+		default:
+			line = activityStyle.Render(line)
+		}
+		s.WriteString(line)
+		if i < len(entries)-1 {
+			s.WriteString("\n")
+		}
+	}
+	return m.box().Render(s.String())
+}
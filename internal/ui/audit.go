@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"x-tracker/internal/db"
+)
+
+// maxAuditShown is how many management actions the audit log view loads
+const maxAuditShown = 200
+
+// auditLoadedMsg carries the most recent management actions
+type auditLoadedMsg []db.AuditEntry
+
+// audit records an action taken in the TUI
+func (m *Model) audit(action, target, detail string) {
+	m.db.Audit(db.AuditEntry{
+		Source: db.AuditSourceTUI,
+		Actor:  db.LocalActor(),
+		Action: action,
+		Target: target,
+		Detail: detail,
+	})
+}
+
+func (m *Model) openAudit() tea.Cmd {
+	m.mode = ModeAudit
+	m.selected = 0
+	return m.loadAudit
+}
+
+func (m *Model) loadAudit() tea.Msg {
+	entries, err := m.db.GetAuditLog(maxAuditShown)
+	if err != nil {
+		return err
+	}
+	return auditLoadedMsg(entries)
+}
+
+func (m *Model) updateAudit(msg tea.KeyMsg) {
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.mode = ModeNormal
+		m.error = nil
+	case key.Matches(msg, m.keys.Up):
+		if m.selected > 0 {
+			m.selected--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.selected < len(m.auditLog)-1 {
+			m.selected++
+		}
+	}
+}
+
+// renderAudit lists recent management actions, newest first
+func (m *Model) renderAudit() string {
+	var s strings.Builder
+	s.WriteString(fmt.Sprintf("%d recent actions\n\n", len(m.auditLog)))
+	if len(m.auditLog) == 0 {
+		s.WriteString("No management actions recorded yet\n")
+		return m.box().Render(s.String())
+	}
+
+	rows := max(m.listRows()-4, minListRows)
+	start, stop := scrollWindow(m.selected, len(m.auditLog), rows)
+	s.WriteString(moreMarker("↑", start))
+	for i := start; i < stop; i++ {
+		entry := m.auditLog[i]
+		item := fmt.Sprintf("%s  %-3s  %s  %s %s",
+			entry.At.Local().Format("Jan 2 15:04:05"), entry.Source, entry.Actor, entry.Action, entry.Target)
+		if entry.Detail != "" {
+			item += " (" + entry.Detail + ")"
+		}
+		item = truncate(item, m.itemWidth())
+		if i == m.selected {
+			s.WriteString(selectedItemStyle.Render(item) + "\n")
+		} else {
+			s.WriteString(itemStyle.Render(item) + "\n")
+		}
+	}
+	s.WriteString(moreMarker("↓", len(m.auditLog)-stop))
+	return m.box().Render(s.String())
+}
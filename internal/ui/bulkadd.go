@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"x-tracker/internal/logger"
+)
+
+// bulkAdd tracks a list of accounts typed into add mode, which are added
+// one at a time so the screen can show how far along it is
+type bulkAdd struct {
+	pending  []string
+	total    int
+	added    int
+	failures []string // "@username: reason" for every account that failed
+	started  time.Time
+}
+
+// bulkAddedMsg reports the outcome of adding one account of a bulk add
+type bulkAddedMsg struct {
+	username string
+	err      error
+}
+
+func (m *Model) startBulkAdd(usernames []string) tea.Cmd {
+	m.bulk = &bulkAdd{pending: usernames, total: len(usernames), started: time.Now()}
+	m.error = nil
+	return m.addNext()
+}
+
+// addNext adds the next pending account of the bulk add
+func (m *Model) addNext() tea.Cmd {
+	username := strings.TrimPrefix(m.bulk.pending[0], "@")
+	return func() tea.Msg {
+		account, err := m.tracker.AddAccount(username)
+		if err == nil {
+			m.audit("add", "@"+account.Username, "bulk add")
+		}
+		return bulkAddedMsg{username: username, err: err}
+	}
+}
+
+// updateBulkAdd records an added account and moves on to the next one,
+// summing up once the list is done or was cancelled
+func (m *Model) updateBulkAdd(msg bulkAddedMsg) tea.Cmd {
+	if m.bulk == nil {
+		return nil
+	}
+	bulk := m.bulk
+	if msg.err != nil {
+		logger.Warn("Bulk add of @%s failed: %v", msg.username, msg.err)
+		bulk.failures = append(bulk.failures, fmt.Sprintf("@%s: %v", msg.username, msg.err))
+	} else {
+		bulk.added++
+	}
+	if len(bulk.pending) > 0 {
+		bulk.pending = bulk.pending[1:]
+	}
+	if len(bulk.pending) > 0 {
+		return m.addNext()
+	}
+
+	m.bulk = nil
+	m.notice = fmt.Sprintf("Added %d of %d accounts", bulk.added, bulk.total)
+	summary := fmt.Sprintf("added %d of %d accounts", bulk.added, bulk.total)
+	if n := len(bulk.failures); n > 0 {
+		m.error = fmt.Errorf("could not add %s", strings.Join(bulk.failures, "; "))
+		summary += fmt.Sprintf(", %d failed", n)
+	}
+	if m.mode == ModeAddAccount {
+		m.mode = ModeNormal
+		m.textInput.Reset()
+		m.textInput.Blur()
+	}
+	complete := func() tea.Msg {
+		m.tracker.Complete(fmt.Sprintf("Adding %d accounts", bulk.total), bulk.started, summary, nil)
+		return nil
+	}
+	return tea.Batch(m.loadAccounts, complete)
+}
+
+// cancelBulkAdd stops a bulk add after the account being added now
+func (m *Model) cancelBulkAdd() {
+	if m.bulk != nil && len(m.bulk.pending) > 1 {
+		m.bulk.total -= len(m.bulk.pending) - 1
+		m.bulk.pending = m.bulk.pending[:1]
+	}
+}
+
+// renderBulkAdd shows the account being added and how many are done
+func (m *Model) renderBulkAdd() string {
+	done := m.bulk.total - len(m.bulk.pending)
+	status := fmt.Sprintf("%s Adding @%s (%d/%d)", m.spinner.View(), strings.TrimPrefix(m.bulk.pending[0], "@"), done+1, m.bulk.total)
+	if n := len(m.bulk.failures); n > 0 {
+		status += " " + warnStyle.Render(fmt.Sprintf("%d failed", n))
+	}
+	return status + "\n" + m.renderHelp("\nPress esc to stop after this account")
+}
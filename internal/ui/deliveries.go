@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"x-tracker/internal/db"
+)
+
+// maxDeliveriesShown is how many notification attempts the delivery log loads
+const maxDeliveriesShown = 200
+
+// deliveriesLoadedMsg carries the most recent notification attempts
+type deliveriesLoadedMsg []db.Delivery
+
+func (m *Model) openDeliveries() tea.Cmd {
+	m.mode = ModeDeliveries
+	m.selected = 0
+	return m.loadDeliveries
+}
+
+func (m *Model) loadDeliveries() tea.Msg {
+	deliveries, err := m.db.GetDeliveries(maxDeliveriesShown, m.failedOnly)
+	if err != nil {
+		return err
+	}
+	return deliveriesLoadedMsg(deliveries)
+}
+
+func (m *Model) updateDeliveries(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.mode = ModeNormal
+		m.error = nil
+	case key.Matches(msg, m.keys.Up):
+		if m.selected > 0 {
+			m.selected--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.selected < len(m.deliveries)-1 {
+			m.selected++
+		}
+	case key.Matches(msg, m.keys.FailedOnly):
+		m.failedOnly = !m.failedOnly
+		m.selected = 0
+		return m.loadDeliveries
+	}
+	return nil
+}
+
+// renderDeliveries lists recent notification attempts, newest first, with
+// the error of the selected one spelled out below
+func (m *Model) renderDeliveries() string {
+	var s strings.Builder
+	failed := 0
+	for _, delivery := range m.deliveries {
+		if delivery.Error != "" {
+			failed++
+		}
+	}
+	if m.failedOnly {
+		s.WriteString(fmt.Sprintf("%d failed deliveries\n\n", len(m.deliveries)))
+	} else {
+		s.WriteString(fmt.Sprintf("%d recent deliveries, %d failed\n\n", len(m.deliveries), failed))
+	}
+	if len(m.deliveries) == 0 {
+		s.WriteString("No notifications sent yet\n")
+		return m.box().Render(s.String())
+	}
+
+	rows := max(m.listRows()-4, minListRows)
+	start, stop := scrollWindow(m.selected, len(m.deliveries), rows)
+	s.WriteString(moreMarker("↑", start))
+	for i := start; i < stop; i++ {
+		delivery := m.deliveries[i]
+		result := "ok"
+		if delivery.Error != "" {
+			result = "failed"
+			if delivery.StatusCode != 0 {
+				result += fmt.Sprintf(" (%d)", delivery.StatusCode)
+			}
+		}
+		account := ""
+		if delivery.Account != "" {
+			account = "  @" + delivery.Account
+		}
+		item := truncate(fmt.Sprintf("%s  %-6s  %s%s  %s  %s",
+			delivery.AttemptedAt.Local().Format("Jan 2 15:04:05"), result, delivery.Channel, account,
+			delivery.Kind, delivery.Latency), m.itemWidth())
+		switch {
+		case i == m.selected:
+			s.WriteString(selectedItemStyle.Render(item) + "\n")
+		case delivery.Error != "":
+			s.WriteString(errorStyle.Render(item) + "\n")
+		default:
+			s.WriteString(itemStyle.Render(item) + "\n")
+		}
+	}
+	s.WriteString(moreMarker("↓", len(m.deliveries)-stop))
+
+	if m.selected < len(m.deliveries) {
+		selected := m.deliveries[m.selected]
+		s.WriteString("\n" + truncate(selected.Summary, m.itemWidth()) + "\n")
+		if selected.Error != "" {
+			s.WriteString(errorStyle.Render(truncate(selected.Error, m.itemWidth())) + "\n")
+		}
+	}
+	return m.box().Render(s.String())
+}
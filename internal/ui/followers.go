@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"x-tracker/internal/db"
+	"x-tracker/internal/format"
+)
+
+// lostFollowersLimit is how many lost followers the view loads
+const lostFollowersLimit = 50
+
+// lostFollowersLoadedMsg carries the most recently lost followers
+type lostFollowersLoadedMsg []db.LostFollower
+
+func (m *Model) loadLostFollowers() tea.Msg {
+	lost, err := m.db.GetLostFollowers(0, lostFollowersLimit)
+	if err != nil {
+		return err
+	}
+	return lostFollowersLoadedMsg(lost)
+}
+
+func (m *Model) renderLostFollowers() string {
+	if len(m.config.TrackFollowers) == 0 {
+		return m.box().Render("Follower tracking is off. Set TRACK_FOLLOWERS to the accounts whose followers to track.")
+	}
+	if len(m.lostFollowers) == 0 {
+		return m.box().Render("No lost followers recorded yet")
+	}
+
+	var s strings.Builder
+	s.WriteString("Who unfollowed:\n\n")
+	_, end := scrollWindow(0, len(m.lostFollowers), m.listRows())
+	for _, follower := range m.lostFollowers[:end] {
+		who := follower.UserID + " (profile unavailable)"
+		if follower.Username != "" {
+			who = fmt.Sprintf("@%s (%s, %s followers)",
+				follower.Username, follower.DisplayName, format.Number(follower.FollowersCount))
+		}
+		item := fmt.Sprintf("%s  @%s lost %s, followed for %s",
+			follower.LostAt.Local().Format("2006-01-02 15:04"),
+			follower.AccountUsername,
+			who,
+			format.Age(follower.FollowedFor()))
+		s.WriteString(itemStyle.Render(truncate(item, m.itemWidth())) + "\n")
+	}
+	s.WriteString(moreMarker("↓", len(m.lostFollowers)-end))
+
+	return m.box().Render(s.String())
+}
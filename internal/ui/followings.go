@@ -0,0 +1,216 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"x-tracker/internal/format"
+)
+
+// followingChange tells how a listed following differs between the browsed
+// date and today
+type followingChange int
+
+const (
+	followingKept    followingChange = iota
+	followingAdded                   // followed since the browsed date
+	followingRemoved                 // unfollowed since the browsed date
+)
+
+// followingRow is one user in the followings browser
+type followingRow struct {
+	userID string
+	change followingChange
+}
+
+// followingsLoadedMsg carries an account's followings as of a date
+type followingsLoadedMsg struct {
+	accountID int64
+	asOf      time.Time // zero for today
+	rows      []followingRow
+	count     int // followings at the browsed date
+	added     int
+	removed   int
+}
+
+// openFollowings browses the followings of the account the detail screen
+// is about, starting today
+func (m *Model) openFollowings() tea.Cmd {
+	m.mode = ModeFollowings
+	m.selected = 0
+	m.followingsAsOf = time.Time{}
+	m.followings = nil
+	return m.loadFollowings
+}
+
+func (m *Model) loadFollowings() tea.Msg {
+	id, asOf := m.detailAccount, m.followingsAsOf
+	current, err := m.db.GetCurrentFollowings(id)
+	if err != nil {
+		return err
+	}
+	then := current
+	if !asOf.IsZero() {
+		if then, err = m.db.GetFollowingsAsOf(id, asOf); err != nil {
+			return err
+		}
+	}
+
+	loaded := followingsLoadedMsg{accountID: id, asOf: asOf, count: len(then)}
+	for userID := range then {
+		row := followingRow{userID: userID}
+		if !current[userID] {
+			row.change = followingRemoved
+			loaded.removed++
+		}
+		loaded.rows = append(loaded.rows, row)
+	}
+	for userID := range current {
+		if !then[userID] {
+			loaded.rows = append(loaded.rows, followingRow{userID: userID, change: followingAdded})
+			loaded.added++
+		}
+	}
+	sort.Slice(loaded.rows, func(i, j int) bool {
+		a, b := loaded.rows[i].userID, loaded.rows[j].userID
+		if len(a) != len(b) {
+			return len(a) < len(b)
+		}
+		return a < b
+	})
+	return loaded
+}
+
+func (m *Model) updateFollowings(msg tea.KeyMsg) tea.Cmd {
+	rows := 0
+	if m.followings != nil {
+		rows = len(m.followings.rows)
+	}
+	page := max(m.listRows()-4, minListRows)
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.mode = ModeAccountDetail
+		m.error = nil
+	case key.Matches(msg, m.keys.Up):
+		if m.selected > 0 {
+			m.selected--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.selected < rows-1 {
+			m.selected++
+		}
+	case key.Matches(msg, m.keys.PrevPage):
+		m.selected = max(m.selected-page, 0)
+	case key.Matches(msg, m.keys.NextPage):
+		m.selected = max(min(m.selected+page, rows-1), 0)
+	case key.Matches(msg, m.keys.AsOf):
+		value := ""
+		if !m.followingsAsOf.IsZero() {
+			value = m.followingsAsOf.Add(-time.Second).Format("2006-01-02")
+		}
+		return m.enterInputModeFor(ModeFollowingsAsOf, value)
+	}
+	return nil
+}
+
+// handleFollowingsAsOf browses the followings as of the typed date
+func (m *Model) handleFollowingsAsOf(input string) tea.Cmd {
+	asOf, err := parseAsOf(input, time.Now())
+	if err != nil {
+		return func() tea.Msg { return err }
+	}
+	m.mode = ModeFollowings
+	m.selected = 0
+	m.error = nil
+	m.textInput.Blur()
+	m.followingsAsOf = asOf
+	return m.loadFollowings
+}
+
+// parseAsOf reads a date to browse followings at: YYYY-MM-DD for the end of
+// that day, a number of days ago such as 30d, or today (or nothing) for the
+// current followings, returned as the zero time
+func parseAsOf(input string, now time.Time) (time.Time, error) {
+	input = strings.TrimSpace(input)
+	if input == "" || strings.EqualFold(input, "today") {
+		return time.Time{}, nil
+	}
+	if days, ok := strings.CutSuffix(input, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return time.Time{}, fmt.Errorf("%q is not a number of days", input)
+		}
+		if n == 0 {
+			return time.Time{}, nil
+		}
+		return now.AddDate(0, 0, -n), nil
+	}
+	day, err := time.ParseInLocation("2006-01-02", input, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not a date like 2024-05-01 or 30d", input)
+	}
+	end := day.AddDate(0, 0, 1)
+	if !end.Before(now) {
+		return time.Time{}, nil
+	}
+	return end, nil
+}
+
+// renderFollowings lists the account's followings at the browsed date,
+// marking the ones that changed since
+func (m *Model) renderFollowings() string {
+	account := m.detailedAccount()
+	if account == nil {
+		return m.box().Render("This account is no longer watched")
+	}
+	loaded := m.followings
+	if loaded == nil || loaded.accountID != account.ID {
+		return m.box().Render("Loading followings…")
+	}
+
+	var s strings.Builder
+	if loaded.asOf.IsZero() {
+		s.WriteString(fmt.Sprintf("@%s follows %s accounts today\n", account.Username, format.Number(loaded.count)))
+	} else {
+		s.WriteString(fmt.Sprintf("@%s followed %s accounts as of %s (since then +%s followed, −%s unfollowed)\n",
+			account.Username, format.Number(loaded.count), loaded.asOf.Add(-time.Second).Format("2006-01-02 15:04"),
+			format.Number(loaded.added), format.Number(loaded.removed)))
+		if !account.BaselinedAt.IsZero() && loaded.asOf.Before(account.BaselinedAt) {
+			s.WriteString(warnStyle.Render(fmt.Sprintf("Tracking started %s; earlier changes are unknown, so this is the first list fetched",
+				dateOrUnknown(account.BaselinedAt))) + "\n")
+		}
+	}
+	s.WriteString("\n")
+	if len(loaded.rows) == 0 {
+		s.WriteString("No followings stored\n")
+		return m.box().Render(s.String())
+	}
+
+	rows := max(m.listRows()-4, minListRows)
+	start, stop := scrollWindow(m.selected, len(loaded.rows), rows)
+	s.WriteString(moreMarker("↑", start))
+	for i := start; i < stop; i++ {
+		row := loaded.rows[i]
+		item := "  " + row.userID
+		switch row.change {
+		case followingAdded:
+			item = "+ " + row.userID + "  followed since"
+		case followingRemoved:
+			item = "− " + row.userID + "  unfollowed since"
+		}
+		item = truncate(item, m.itemWidth())
+		if i == m.selected {
+			s.WriteString(selectedItemStyle.Render(item) + "\n")
+		} else {
+			s.WriteString(itemStyle.Render(item) + "\n")
+		}
+	}
+	s.WriteString(moreMarker("↓", len(loaded.rows)-stop))
+	return m.box().Render(s.String())
+}
@@ -0,0 +1,461 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"x-tracker/internal/db"
+	"x-tracker/internal/format"
+)
+
+// historyLimit is how many rows the history view loads
+const historyLimit = 50
+
+// collapseThreshold is how many events of one type a single cycle must
+// produce for an account before the history shows them as one summary row
+const collapseThreshold = 20
+
+// historyRow is a line of the history view: a single event, or a summary
+// of a cycle's events that expands into a sub-list
+type historyRow struct {
+	event *db.FollowEvent
+	batch *db.EventBatch
+}
+
+// eventsLoadedMsg carries fresh rows for the history view
+type eventsLoadedMsg []historyRow
+
+// batchEventsLoadedMsg carries the events of the expanded summary row
+type batchEventsLoadedMsg []db.FollowEvent
+
+// openHistory switches to the history view and loads it
+func (m *Model) openHistory() tea.Cmd {
+	m.mode = ModeHistory
+	m.selected = 0
+	m.batch = nil
+	return m.loadEvents
+}
+
+func (m *Model) loadEvents() tea.Msg {
+	events, err := m.db.GetEvents(db.EventQuery{
+		BatchBelow:       collapseThreshold,
+		IncludeDismissed: m.showDismissed,
+		Limit:            historyLimit,
+	})
+	if err != nil {
+		return err
+	}
+	batches, err := m.db.GetEventBatches(collapseThreshold, historyLimit, m.showDismissed)
+	if err != nil {
+		return err
+	}
+	return eventsLoadedMsg(mergeHistory(events, batches))
+}
+
+// mergeHistory interleaves single events and summary rows, both newest
+// first, keeping the newest historyLimit rows
+func mergeHistory(events []db.FollowEvent, batches []db.EventBatch) []historyRow {
+	var rows []historyRow
+	for len(rows) < historyLimit && (len(events) > 0 || len(batches) > 0) {
+		if len(batches) == 0 || (len(events) > 0 && !events[0].DetectedAt.Before(batches[0].DetectedAt)) {
+			rows = append(rows, historyRow{event: &events[0]})
+			events = events[1:]
+		} else {
+			rows = append(rows, historyRow{batch: &batches[0]})
+			batches = batches[1:]
+		}
+	}
+	return rows
+}
+
+func (m *Model) loadBatchEvents() tea.Msg {
+	batch := m.batch
+	if batch == nil {
+		return nil
+	}
+	events, err := m.db.GetEvents(db.EventQuery{
+		AccountID:        batch.WatchedAccountID,
+		EventType:        batch.EventType,
+		DetectedAt:       batch.DetectedAt,
+		IncludeDismissed: m.showDismissed,
+	})
+	if err != nil {
+		return err
+	}
+	return batchEventsLoadedMsg(events)
+}
+
+// eventsMode is the list an event detail or preview returns to
+func (m *Model) eventsMode() Mode {
+	if m.batch != nil {
+		return ModeEventBatch
+	}
+	return ModeHistory
+}
+
+// batchPageSize is the most events a page of an expanded summary row shows
+const batchPageSize = 25
+
+// snapshotLimit is how many snapshot IDs the event detail lists
+const snapshotLimit = 20
+
+// eventSnapshotMsg carries the target snapshot for the event detail view
+type eventSnapshotMsg struct {
+	snapshot *db.TargetSnapshot
+}
+
+// updateHistory handles keys while the history view is open
+func (m *Model) updateHistory(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.mode = ModeNormal
+		m.error = nil
+	case key.Matches(msg, m.keys.Up):
+		if m.selected > 0 {
+			m.selected--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.selected < len(m.history)-1 {
+			m.selected++
+		}
+	case key.Matches(msg, m.keys.Dismiss):
+		if event := m.selectedEvent(); event != nil {
+			id := event.ID
+			return func() tea.Msg {
+				if err := m.db.DismissEvent(id); err != nil {
+					return err
+				}
+				return m.loadEvents()
+			}
+		}
+	case key.Matches(msg, m.keys.Restore):
+		if event := m.selectedEvent(); event != nil {
+			id := event.ID
+			return func() tea.Msg {
+				if err := m.db.RestoreEvent(id); err != nil {
+					return err
+				}
+				return m.loadEvents()
+			}
+		}
+	case key.Matches(msg, m.keys.Open):
+		if m.selected < len(m.history) && m.history[m.selected].batch != nil {
+			m.batch = m.history[m.selected].batch
+			m.batchEvents = nil
+			m.batchSelected = 0
+			m.mode = ModeEventBatch
+			return m.loadBatchEvents
+		}
+		return m.openEventDetail()
+	case key.Matches(msg, m.keys.Preview):
+		return m.openPreview()
+	case key.Matches(msg, m.keys.ShowDismissed):
+		m.showDismissed = !m.showDismissed
+		return m.loadEvents
+	case key.Matches(msg, m.keys.DismissRule):
+		m.mode = ModeDismissRule
+		m.textInput.Reset()
+		m.textInput.Focus()
+	}
+	return nil
+}
+
+// updateEventBatch handles keys while a summary row is expanded
+func (m *Model) updateEventBatch(msg tea.KeyMsg) tea.Cmd {
+	pageSize := m.batchPageSize()
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.mode = ModeHistory
+		m.batch = nil
+		m.error = nil
+		return m.loadEvents
+	case key.Matches(msg, m.keys.Up):
+		if m.batchSelected > 0 {
+			m.batchSelected--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.batchSelected < len(m.batchEvents)-1 {
+			m.batchSelected++
+		}
+	case key.Matches(msg, m.keys.PrevPage):
+		m.batchSelected = max(m.batchSelected/pageSize*pageSize-pageSize, 0)
+	case key.Matches(msg, m.keys.NextPage):
+		if next := (m.batchSelected/pageSize + 1) * pageSize; next < len(m.batchEvents) {
+			m.batchSelected = next
+		}
+	case key.Matches(msg, m.keys.Dismiss):
+		if event := m.selectedEvent(); event != nil {
+			id := event.ID
+			return func() tea.Msg {
+				if err := m.db.DismissEvent(id); err != nil {
+					return err
+				}
+				return m.loadBatchEvents()
+			}
+		}
+	case key.Matches(msg, m.keys.Restore):
+		if event := m.selectedEvent(); event != nil {
+			id := event.ID
+			return func() tea.Msg {
+				if err := m.db.RestoreEvent(id); err != nil {
+					return err
+				}
+				return m.loadBatchEvents()
+			}
+		}
+	case key.Matches(msg, m.keys.Open):
+		return m.openEventDetail()
+	case key.Matches(msg, m.keys.Preview):
+		return m.openPreview()
+	}
+	return nil
+}
+
+// openEventDetail shows the selected event with its target's snapshot
+func (m *Model) openEventDetail() tea.Cmd {
+	event := m.selectedEvent()
+	if event == nil {
+		return nil
+	}
+	userID := event.UserID
+	m.mode = ModeEventDetail
+	m.eventSnapshot = nil
+	return func() tea.Msg {
+		snapshot, err := m.db.GetTargetSnapshot(userID)
+		if err != nil {
+			return err
+		}
+		return eventSnapshotMsg{snapshot}
+	}
+}
+
+// selectedEvent is the event under the cursor in the expanded summary row
+// or the history, nil on a summary row
+func (m *Model) selectedEvent() *db.FollowEvent {
+	if m.batch != nil {
+		if m.batchSelected < 0 || m.batchSelected >= len(m.batchEvents) {
+			return nil
+		}
+		return &m.batchEvents[m.batchSelected]
+	}
+	if m.selected < 0 || m.selected >= len(m.history) {
+		return nil
+	}
+	return m.history[m.selected].event
+}
+
+// batchPageSize is how many events a page of the expanded summary row
+// shows
+func (m *Model) batchPageSize() int {
+	if rows := m.listRows(); rows > 0 {
+		return min(rows, batchPageSize)
+	}
+	return batchPageSize
+}
+
+// handleDismissRule parses a rule such as "account:foo type:unfollow
+// target:123 before:2024-01-31" and dismisses every matching event
+func (m *Model) handleDismissRule(input string) tea.Cmd {
+	return func() tea.Msg {
+		var rule db.EventRule
+		for _, field := range strings.Fields(input) {
+			key, value, ok := strings.Cut(field, ":")
+			if !ok || value == "" {
+				return fmt.Errorf("invalid rule term %q, expected key:value", field)
+			}
+			switch key {
+			case "account":
+				username := strings.TrimPrefix(value, "@")
+				for _, account := range m.accounts {
+					if strings.EqualFold(account.Username, username) {
+						rule.AccountID = account.ID
+					}
+				}
+				if rule.AccountID == 0 {
+					return fmt.Errorf("account @%s not found", username)
+				}
+			case "target":
+				rule.UserID = value
+			case "type":
+				if value != string(db.EventTypeFollow) && value != string(db.EventTypeUnfollow) {
+					return fmt.Errorf("type must be follow or unfollow")
+				}
+				rule.EventType = db.EventType(value)
+			case "before":
+				before, err := time.ParseInLocation("2006-01-02", value, time.Local)
+				if err != nil {
+					return fmt.Errorf("invalid date %q, expected YYYY-MM-DD", value)
+				}
+				rule.Before = before
+			default:
+				return fmt.Errorf("unknown rule term %q", key)
+			}
+		}
+
+		if _, err := m.db.DismissEventsByRule(rule); err != nil {
+			return err
+		}
+		m.mode = ModeHistory
+		m.textInput.Reset()
+		m.textInput.Blur()
+		return m.loadEvents()
+	}
+}
+
+func (m *Model) renderHistory() string {
+	if len(m.history) == 0 {
+		return m.box().Render("No events recorded")
+	}
+
+	var s strings.Builder
+	title := "Recent events:"
+	if m.showDismissed {
+		title = "Recent events (including dismissed):"
+	}
+	s.WriteString(title + "\n\n")
+
+	start, end := scrollWindow(m.selected, len(m.history), m.listRows())
+	s.WriteString(moreMarker("↑", start))
+
+	for i := start; i < end; i++ {
+		var item string
+		if batch := m.history[i].batch; batch != nil {
+			item = fmt.Sprintf("%s  @%s %s %s accounts ▸",
+				batch.DetectedAt.Local().Format("2006-01-02 15:04"),
+				batch.AccountUsername,
+				eventVerb(batch.EventType),
+				format.Number(batch.Count))
+		} else {
+			item = eventItem(*m.history[i].event)
+		}
+
+		item = truncate(item, m.itemWidth())
+		if i == m.selected {
+			s.WriteString(selectedItemStyle.Render(item) + "\n")
+		} else {
+			s.WriteString(itemStyle.Render(item) + "\n")
+		}
+	}
+	s.WriteString(moreMarker("↓", len(m.history)-end))
+
+	return m.box().Render(s.String())
+}
+
+// renderEventBatch renders the current page of the expanded summary row
+func (m *Model) renderEventBatch() string {
+	batch := m.batch
+	if batch == nil {
+		return m.box().Render("No summary selected")
+	}
+
+	var s strings.Builder
+	pageSize := m.batchPageSize()
+	pages := max((len(m.batchEvents)+pageSize-1)/pageSize, 1)
+	page := m.batchSelected / pageSize
+	count := batch.Count
+	if len(m.batchEvents) > 0 {
+		// Dismissing from the sub-list shrinks it
+		count = len(m.batchEvents)
+	}
+	fmt.Fprintf(&s, "@%s %s %s accounts at %s (page %d/%d):\n\n",
+		batch.AccountUsername,
+		eventVerb(batch.EventType),
+		format.Number(count),
+		batch.DetectedAt.Local().Format("2006-01-02 15:04"),
+		page+1, pages)
+
+	if len(m.batchEvents) == 0 {
+		s.WriteString("Loading events...")
+		return m.box().Render(s.String())
+	}
+
+	start := page * pageSize
+	end := min(start+pageSize, len(m.batchEvents))
+	for i := start; i < end; i++ {
+		item := truncate(eventItem(m.batchEvents[i]), m.itemWidth())
+		if i == m.batchSelected {
+			s.WriteString(selectedItemStyle.Render(item) + "\n")
+		} else {
+			s.WriteString(itemStyle.Render(item) + "\n")
+		}
+	}
+
+	return m.box().Render(s.String())
+}
+
+// eventVerb describes an event type in the past tense
+func eventVerb(eventType db.EventType) string {
+	if eventType == db.EventTypeUnfollow {
+		return "unfollowed"
+	}
+	return "followed"
+}
+
+// eventItem renders a single event as a list line
+func eventItem(event db.FollowEvent) string {
+	item := fmt.Sprintf("%s  @%s %s %s",
+		event.DetectedAt.Local().Format("2006-01-02 15:04"),
+		event.AccountUsername,
+		eventVerb(event.EventType),
+		event.UserID)
+	if event.UnfollowCount > 0 {
+		item += fmt.Sprintf(" [unfollowed %d×]", event.UnfollowCount)
+	}
+	if event.Flaps > 0 {
+		item += fmt.Sprintf(" [flapped %d×]", event.Flaps)
+	}
+	if len(event.Annotations) > 0 {
+		item += " {" + strings.Join(event.Annotations, ", ") + "}"
+	}
+	if event.DismissedAt != nil {
+		item += " (dismissed)"
+	}
+	return item
+}
+
+func (m *Model) renderEventDetail() string {
+	event := m.selectedEvent()
+	if event == nil {
+		return m.box().Render("No event selected")
+	}
+
+	var s strings.Builder
+	fmt.Fprintf(&s, "@%s %s %s\n", event.AccountUsername, eventVerb(event.EventType), event.UserID)
+	fmt.Fprintf(&s, "Detected: %s\n", event.DetectedAt.Local().Format("2006-01-02 15:04:05"))
+	if event.UnfollowCount > 0 {
+		fmt.Fprintf(&s, "Unfollowed %d× so far\n", event.UnfollowCount)
+	}
+	if event.FlappedAt != nil {
+		fmt.Fprintf(&s, "Flapped %d×, last at %s (net: %s)\n",
+			event.Flaps, event.FlappedAt.Local().Format("2006-01-02 15:04"), eventVerb(event.Net()))
+	}
+	if len(event.Annotations) > 0 {
+		fmt.Fprintf(&s, "Annotations: %s\n", strings.Join(event.Annotations, ", "))
+	}
+	if event.DismissedAt != nil {
+		fmt.Fprintf(&s, "Dismissed: %s\n", event.DismissedAt.Local().Format("2006-01-02 15:04"))
+	}
+
+	s.WriteString("\n")
+	if m.eventSnapshot == nil {
+		s.WriteString("No following snapshot for this user\n")
+		return m.box().Render(s.String())
+	}
+
+	snapshot := m.eventSnapshot
+	fmt.Fprintf(&s, "Following snapshot (%s IDs, taken %s):\n",
+		format.Number(len(snapshot.FollowingIDs)),
+		snapshot.TakenAt.Local().Format("2006-01-02 15:04"))
+	for i, id := range snapshot.FollowingIDs {
+		if i == snapshotLimit {
+			fmt.Fprintf(&s, "  … and %s more\n", format.Number(len(snapshot.FollowingIDs)-snapshotLimit))
+			break
+		}
+		s.WriteString(itemStyle.Render(id) + "\n")
+	}
+
+	return m.box().Render(s.String())
+}
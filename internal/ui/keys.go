@@ -0,0 +1,244 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// keyMap holds every key binding of the UI. The handlers, the help lines,
+// the palette shortcuts and the help overlay all read from it, so a
+// binding changed here changes everywhere.
+type keyMap struct {
+	// Global
+	Palette key.Binding
+	Help    key.Binding
+	Back    key.Binding
+	Quit    key.Binding
+
+	// Normal mode
+	Add        key.Binding
+	List       key.Binding
+	Remove     key.Binding
+	Filter     key.Binding
+	Resync     key.Binding
+	History    key.Binding
+	Unfollowed key.Binding
+	Activity   key.Binding
+	Trending   key.Binding
+	Schedule   key.Binding
+	Stats      key.Binding
+	Theme      key.Binding
+	TestNotify key.Binding
+	Deliveries key.Binding
+	Audit      key.Binding
+
+	// Lists
+	Up       key.Binding
+	Down     key.Binding
+	PrevPage key.Binding
+	NextPage key.Binding
+	Open     key.Binding
+
+	// Account list
+	Sort      key.Binding
+	Pause     key.Binding
+	TagFilter key.Binding
+
+	// Account detail
+	Followings key.Binding
+
+	// Followings browser
+	AsOf key.Binding
+
+	// Trending targets
+	Promote key.Binding
+
+	// Delivery log
+	FailedOnly key.Binding
+
+	// History
+	Dismiss       key.Binding
+	Restore       key.Binding
+	Preview       key.Binding
+	ShowDismissed key.Binding
+	DismissRule   key.Binding
+
+	// Notification preview
+	NextChannel key.Binding
+	PrevChannel key.Binding
+
+	// Text inputs and the palette, where letters are typed
+	Submit      key.Binding
+	PaletteUp   key.Binding
+	PaletteDown key.Binding
+	Complete    key.Binding
+}
+
+func defaultKeyMap() keyMap {
+	return keyMap{
+		Palette: key.NewBinding(key.WithKeys("ctrl+k"), key.WithHelp("ctrl+k", "commands")),
+		Help:    key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "all keys")),
+		Back:    key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "back")),
+		Quit:    key.NewBinding(key.WithKeys("q", "ctrl+c"), key.WithHelp("q", "quit")),
+
+		Add:        key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "add")),
+		List:       key.NewBinding(key.WithKeys("l"), key.WithHelp("l", "list")),
+		Remove:     key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "remove")),
+		Filter:     key.NewBinding(key.WithKeys("f"), key.WithHelp("f", "filter")),
+		Resync:     key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "resync")),
+		History:    key.NewBinding(key.WithKeys("h"), key.WithHelp("h", "history")),
+		Unfollowed: key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "unfollowed")),
+		Activity:   key.NewBinding(key.WithKeys("L"), key.WithHelp("L", "activity")),
+		Trending:   key.NewBinding(key.WithKeys("T"), key.WithHelp("T", "trending")),
+		Schedule:   key.NewBinding(key.WithKeys("S"), key.WithHelp("S", "schedule")),
+		Stats:      key.NewBinding(key.WithKeys("i"), key.WithHelp("i", "stats")),
+		Theme:      key.NewBinding(key.WithKeys("ctrl+t"), key.WithHelp("ctrl+t", "next theme")),
+		TestNotify: key.NewBinding(key.WithKeys("N"), key.WithHelp("N", "test notification")),
+		Deliveries: key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "deliveries")),
+		Audit:      key.NewBinding(key.WithKeys("A"), key.WithHelp("A", "audit log")),
+
+		Up:       key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "up")),
+		Down:     key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "down")),
+		PrevPage: key.NewBinding(key.WithKeys("left", "h", "pgup"), key.WithHelp("←/h", "previous page")),
+		NextPage: key.NewBinding(key.WithKeys("right", "l", "pgdown"), key.WithHelp("→/l", "next page")),
+		Open:     key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "open")),
+
+		Sort:      key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "sort")),
+		Pause:     key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "pause/resume checks")),
+		TagFilter: key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "filter by tag")),
+
+		Followings: key.NewBinding(key.WithKeys("o"), key.WithHelp("o", "followings")),
+
+		AsOf: key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "as of date")),
+
+		Promote: key.NewBinding(key.WithKeys("w"), key.WithHelp("w", "watch")),
+
+		FailedOnly: key.NewBinding(key.WithKeys("f"), key.WithHelp("f", "failed only")),
+
+		Dismiss:       key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "dismiss")),
+		Restore:       key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "restore")),
+		Preview:       key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "preview notification")),
+		ShowDismissed: key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "toggle dismissed")),
+		DismissRule:   key.NewBinding(key.WithKeys("D"), key.WithHelp("D", "dismiss by rule")),
+
+		NextChannel: key.NewBinding(key.WithKeys("tab", "right", "l"), key.WithHelp("tab/→", "next channel")),
+		PrevChannel: key.NewBinding(key.WithKeys("shift+tab", "left", "h"), key.WithHelp("shift+tab/←", "previous channel")),
+
+		Submit:      key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "confirm")),
+		PaletteUp:   key.NewBinding(key.WithKeys("up", "ctrl+p"), key.WithHelp("↑/ctrl+p", "up")),
+		PaletteDown: key.NewBinding(key.WithKeys("down", "ctrl+n"), key.WithHelp("↓/ctrl+n", "down")),
+		Complete:    key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "use suggestion")),
+	}
+}
+
+// keySection is a group of bindings shown together in the help overlay
+type keySection struct {
+	title    string
+	bindings []key.Binding
+}
+
+// sections lists the bindings of every mode for the help overlay
+func (k keyMap) sections() []keySection {
+	return []keySection{
+		{"Everywhere", []key.Binding{k.Palette, k.Help, k.Back}},
+		{"Main screen", []key.Binding{k.Add, k.List, k.Remove, k.Filter, k.Resync, k.History, k.Unfollowed, k.Activity, k.Trending, k.Schedule, k.Stats, k.Theme, k.TestNotify, k.Deliveries, k.Audit, k.Quit}},
+		{"Account list", []key.Binding{k.Up, k.Down, k.PrevPage, k.NextPage, k.Open, k.Sort, k.Pause, k.TagFilter}},
+		{"Account detail", k.detailKeys()},
+		{"Followings", k.followingsKeys()},
+		{"Trending targets", []key.Binding{k.Up, k.Down, k.Promote}},
+		{"Delivery log", []key.Binding{k.Up, k.Down, k.FailedOnly}},
+		{"History", k.historyKeys()},
+		{"Expanded summary", k.batchKeys()},
+		{"Adding accounts", []key.Binding{k.PaletteUp, k.PaletteDown, k.Complete, k.Submit}},
+		{"Notification preview", []key.Binding{k.NextChannel, k.PrevChannel}},
+		{"Command palette", []key.Binding{k.PaletteUp, k.PaletteDown, k.Submit}},
+	}
+}
+
+func (k keyMap) historyKeys() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.Open, k.Dismiss, k.Restore, k.Preview, k.ShowDismissed, k.DismissRule}
+}
+
+func (k keyMap) detailKeys() []key.Binding {
+	return []key.Binding{k.Followings, k.Pause, k.Resync, k.Filter, k.Remove}
+}
+
+func (k keyMap) followingsKeys() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.PrevPage, k.NextPage, k.AsOf}
+}
+
+func (k keyMap) batchKeys() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.PrevPage, k.NextPage, k.Open, k.Dismiss, k.Restore, k.Preview}
+}
+
+// helpColumns is how many bindings a column of the help overlay holds
+const helpColumns = 4
+
+// renderKeys renders a help line for bindings, wrapped on narrow terminals
+func (m *Model) renderKeys(bindings ...key.Binding) string {
+	return m.renderHelp("\n" + m.help.ShortHelpView(bindings))
+}
+
+// footerKeys are the bindings shown at the bottom of the screen; the
+// help overlay lists the rest
+func (m *Model) footerKeys() []key.Binding {
+	switch {
+	case m.mode == ModeNormal:
+		return []key.Binding{m.keys.Add, m.keys.List, m.keys.History, m.keys.Palette, m.keys.Help, m.keys.Quit}
+	case m.typing():
+		return []key.Binding{m.keys.Palette, m.keys.Back}
+	}
+	return []key.Binding{m.keys.Palette, m.keys.Help, m.keys.Back}
+}
+
+// typing reports whether keys go to a text input, so letters and ? must
+// not trigger bindings
+func (m *Model) typing() bool {
+	switch m.mode {
+	case ModeAddAccount, ModeRemoveAccount, ModeFilterAccount, ModeResyncAccount, ModeDismissRule, ModeFollowingsAsOf, ModePalette:
+		return true
+	}
+	return false
+}
+
+func (m *Model) openHelp() {
+	m.helpReturn = m.mode
+	m.mode = ModeHelp
+}
+
+// updateHelp closes the help overlay
+func (m *Model) updateHelp(msg tea.KeyMsg) {
+	if key.Matches(msg, m.keys.Help, m.keys.Back) {
+		m.mode = m.helpReturn
+	}
+}
+
+// renderKeyHelp renders the help overlay with the bindings of every mode
+func (m *Model) renderKeyHelp() string {
+	var s strings.Builder
+	s.WriteString("Keys:\n")
+	for _, section := range m.keys.sections() {
+		var columns [][]key.Binding
+		for i := 0; i < len(section.bindings); i += helpColumns {
+			columns = append(columns, section.bindings[i:min(i+helpColumns, len(section.bindings))])
+		}
+		s.WriteString("\n" + titleStyle.Render(section.title) + "\n")
+		s.WriteString(m.help.FullHelpView(columns) + "\n")
+	}
+	return m.box().Render(strings.TrimSuffix(s.String(), "\n"))
+}
+
+// newHelp returns the help renderer styled like the rest of the UI
+func newHelp() help.Model {
+	h := help.New()
+	h.Styles.ShortKey = helpKeyStyle
+	h.Styles.ShortDesc = helpDescStyle
+	h.Styles.ShortSeparator = helpDescStyle
+	h.Styles.FullKey = helpKeyStyle
+	h.Styles.FullDesc = helpDescStyle
+	h.Styles.FullSeparator = helpDescStyle
+	return h
+}
@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// listChrome is the number of lines around a list: status bar, list
+	// border and padding, title, scroll markers and the help lines as they
+	// wrap on a narrow terminal
+	listChrome = 21
+	// minListRows is shown even on very short terminals
+	minListRows = 3
+)
+
+// box returns the bordered list style, spanning the terminal width once
+// it is known. Longer lines wrap inside it.
+func (m *Model) box() lipgloss.Style {
+	if m.width == 0 {
+		return listStyle
+	}
+	// Width covers the padding but not the border
+	return listStyle.Width(max(m.width-2, 10))
+}
+
+// renderHelp renders a help line, wrapped on narrow terminals
+func (m *Model) renderHelp(text string) string {
+	if m.width == 0 {
+		return helpStyle.Render(text)
+	}
+	return helpStyle.Width(m.width).Render(text)
+}
+
+// itemWidth is the width available to a list item's text, 0 until the
+// terminal size is known
+func (m *Model) itemWidth() int {
+	if m.width == 0 {
+		return 0
+	}
+	// Border, padding and the item indent, which is one wider for the
+	// selected item's arrow
+	return max(m.width-9, 10)
+}
+
+// listRows is how many list items fit on screen, 0 until the terminal
+// size is known
+func (m *Model) listRows() int {
+	if m.height == 0 {
+		return 0
+	}
+	chrome := listChrome
+	if m.checking() {
+		chrome += progressLines
+	}
+	return max(m.height-chrome, minListRows)
+}
+
+// scrollWindow returns the range of n items to show in rows lines, keeping
+// selected in view. rows of 0 shows everything.
+func scrollWindow(selected, n, rows int) (int, int) {
+	if rows <= 0 || n <= rows {
+		return 0, n
+	}
+	start := max(0, min(selected-rows/2, n-rows))
+	return start, start + rows
+}
+
+// moreMarker tells how many items are scrolled out of view, "" if none
+func moreMarker(arrow string, hidden int) string {
+	if hidden <= 0 {
+		return ""
+	}
+	return helpStyle.MarginTop(0).Render(fmt.Sprintf("  %s %d more", arrow, hidden)) + "\n"
+}
+
+// truncate cuts plain text to width cells, marking the cut with "…". A
+// width of 0 leaves it alone.
+func truncate(s string, width int) string {
+	if width <= 0 || lipgloss.Width(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && lipgloss.Width(string(runes))+1 > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
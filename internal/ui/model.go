@@ -1,10 +1,15 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
@@ -12,6 +17,8 @@ import (
 	"x-tracker/config"
 	"x-tracker/internal/api"
 	"x-tracker/internal/db"
+	"x-tracker/internal/format"
+	"x-tracker/internal/tracker"
 	"x-tracker/internal/webhook"
 	"x-tracker/internal/logger"
 )
@@ -26,8 +33,28 @@ type checkTimerMsg time.Time
 type (
 	errMsg error
 	CheckAccountsMsg time.Time
+	manualCheckDoneMsg time.Time
+	accountWatchedMsg string // username typed in add mode that is already watched
 )
 
+// ConfigReloadedMsg is sent into the program when the configuration is reloaded
+type ConfigReloadedMsg struct {
+	Config *config.Config
+}
+
+// AccountsChangedMsg is sent into the program when another command changed
+// the watch list or ran a check through the control socket
+type AccountsChangedMsg struct {
+	Notice string
+}
+
+// CheckProgressMsg is sent into the program at every step of a check cycle
+type CheckProgressMsg tracker.Progress
+
+// CompletionMsg is sent into the program when a long-running operation
+// finishes
+type CompletionMsg tracker.Completion
+
 type Mode int
 
 const (
@@ -35,6 +62,24 @@ const (
 	ModeAddAccount
 	ModeListAccounts
 	ModeRemoveAccount
+	ModeFilterAccount
+	ModeHistory
+	ModeDismissRule
+	ModePalette
+	ModeEventDetail
+	ModeResyncAccount
+	ModeLostFollowers
+	ModeNotificationPreview
+	ModeEventBatch
+	ModeHelp
+	ModeTrending
+	ModeSchedule
+	ModeDeliveries
+	ModeAudit
+	ModeAccountDetail
+	ModeFollowings
+	ModeFollowingsAsOf
+	ModeStats
 
 	// Braille spinner characters
 	brailleSpinnerFrames = `⠋⠙⠹⠸⠼⠴⠦⠧⠇⠏`
@@ -51,6 +96,42 @@ func (m Mode) String() string {
 		return "List"
 	case ModeRemoveAccount:
 		return "Remove"
+	case ModeFilterAccount:
+		return "Filter"
+	case ModeHistory:
+		return "History"
+	case ModeDismissRule:
+		return "Dismiss"
+	case ModePalette:
+		return "Palette"
+	case ModeEventDetail:
+		return "Event"
+	case ModeResyncAccount:
+		return "Resync"
+	case ModeLostFollowers:
+		return "Lost"
+	case ModeNotificationPreview:
+		return "Preview"
+	case ModeEventBatch:
+		return "Batch"
+	case ModeHelp:
+		return "Help"
+	case ModeTrending:
+		return "Trending"
+	case ModeSchedule:
+		return "Schedule"
+	case ModeDeliveries:
+		return "Deliveries"
+	case ModeAudit:
+		return "Audit"
+	case ModeAccountDetail:
+		return "Account"
+	case ModeFollowings:
+		return "Followings"
+	case ModeFollowingsAsOf:
+		return "As Of"
+	case ModeStats:
+		return "Stats"
 	default:
 		return "Unknown"
 	}
@@ -58,9 +139,10 @@ func (m Mode) String() string {
 
 type Model struct {
 	mode           Mode
-	db             *db.Database
+	db             db.Store
 	api            *api.Client
 	notifications  *webhook.NotificationManager
+	tracker        *tracker.Tracker
 	config         *config.Config
 	accounts       []db.WatchedAccount
 	spinner        spinner.Model
@@ -68,15 +150,61 @@ type Model struct {
 	error          error
 	input          string
 	selected       int
+	tagFilter      string // list only accounts with this tag, "" for all
 	uptime         time.Duration
 	startTime      time.Time
 	textInput      textinput.Model
 	lastCheckTime  time.Time
+	lastClockTick  time.Time    // previous check timer tick, to notice the machine sleeping
+	checkGeneration atomic.Int64 // bumped to drop periodic checks scheduled before
 	checkInterval  time.Duration
 	lastTick       time.Time
+	countHistory   map[int64][]int
+	lastChanges    map[int64]time.Time // last follow event per account ID
+	lastCosts      map[int64]db.CheckCost // cost of the last check per account ID
+	accountSort    accountSort
+	history        []historyRow
+	batch          *db.EventBatch // expanded summary row, nil while none is open
+	batchEvents    []db.FollowEvent
+	batchSelected  int
+	showDismissed  bool
+	eventSnapshot  *db.TargetSnapshot
+	lostFollowers  []db.LostFollower
+	trending       []db.TrendingTarget
+	schedule       *scheduleLoadedMsg // nil until the schedule view has loaded
+	stats          *statsLoadedMsg    // nil until the stats view has loaded
+	deliveries     []db.Delivery
+	failedOnly     bool // the delivery log lists failed deliveries only
+	auditLog       []db.AuditEntry
+	detailAccount  int64 // ID of the account the detail screen is about
+	accountDetail  *accountDetailLoadedMsg // nil until the detail screen has loaded
+	followings     *followingsLoadedMsg // nil until the followings browser has loaded
+	followingsAsOf time.Time // date the followings browser shows, zero for today
+	preview        *webhook.Preview
+	previewChannel int
+	notice         string
+	addDuplicate   string // watched account add mode offers to re-sync, "" if none
+	bulk           *bulkAdd // accounts being added from a typed list, nil if none
+	suggestFor      string   // handle the suggestions are for
+	suggestions     []api.UserResponse
+	suggestSelected int
+	suggestCache    map[string][]api.UserResponse // search results by lowercase query
+	checkProgress  []tracker.Progress // the running cycle's accounts in check order, nil between cycles
+	paletteInput    textinput.Model
+	paletteSelected int
+	paletteReturn   Mode
+	keys            keyMap
+	help            help.Model
+	helpReturn      Mode
+	activity        *logger.Ring
+	showActivity    bool
+	theme           string // name of the theme in use
+	dryRun          bool   // changes land in a scratch copy of the database
+	width           int // terminal size, 0 until the first resize message
+	height          int
 }
 
-func NewModel(database *db.Database, apiClient *api.Client, notifications *webhook.NotificationManager, cfg *config.Config) *Model {
+func NewModel(database db.Store, apiClient *api.Client, notifications *webhook.NotificationManager, tr *tracker.Tracker, cfg *config.Config) *Model {
 	// Initialize text input with styling
 	ti := textinput.New()
 	ti.Placeholder = "username (without @)"
@@ -84,17 +212,25 @@ func NewModel(database *db.Database, apiClient *api.Client, notifications *webho
 	ti.PromptStyle = inputPromptStyle
 	ti.TextStyle = inputStyle
 	ti.Cursor.Style = cursorStyle
-	ti.CharLimit = 50
+	ti.CharLimit = 500 // room for a list of accounts to add
 	ti.Width = 30
 	ti.Prompt = "@ "
 
+	pi := textinput.New()
+	pi.Placeholder = "type to search actions"
+	pi.PlaceholderStyle = placeholderStyle
+	pi.TextStyle = inputStyle
+	pi.Cursor.Style = cursorStyle
+	pi.Width = 30
+	pi.Prompt = "> "
+
 	// Initialize spinners with proper timing
 	s := spinner.New(
 		spinner.WithSpinner(spinner.Spinner{
 			Frames: []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"},
 			FPS:    time.Second / 8, // Medium speed
 		}),
-		spinner.WithStyle(lipgloss.NewStyle().Foreground(special)),
+		spinner.WithStyle(lipgloss.NewStyle()),
 	)
 
 	bs := spinner.New(
@@ -102,23 +238,39 @@ func NewModel(database *db.Database, apiClient *api.Client, notifications *webho
 			Frames: strings.Split(brailleSpinnerFrames, ""),
 			FPS:    time.Second / 8, // Match the first spinner's speed
 		}),
-		spinner.WithStyle(lipgloss.NewStyle().Foreground(highlight)),
+		spinner.WithStyle(lipgloss.NewStyle()),
 	)
 
-	return &Model{
+	m := &Model{
 		mode:           ModeNormal,
 		db:             database,
 		api:            apiClient,
 		notifications: notifications,
+		tracker:        tr,
 		config:         cfg,
 		spinner:        s,
 		brailleSpinner: bs,
 		textInput:      ti,
+		paletteInput:   pi,
+		keys:           defaultKeyMap(),
+		help:           newHelp(),
 		startTime:      time.Now(),
 		lastCheckTime:  time.Now(),
 		checkInterval:  cfg.CheckInterval,
 		lastTick:       time.Now(),
 	}
+	m.setTheme(m.configuredTheme())
+	return m
+}
+
+// SetActivityLog sets the log sink shown in the activity pane
+func (m *Model) SetActivityLog(ring *logger.Ring) {
+	m.activity = ring
+}
+
+// SetDryRun marks the session as a dry run in the status bar
+func (m *Model) SetDryRun() {
+	m.dryRun = true
 }
 
 func (m *Model) Init() tea.Cmd {
@@ -151,51 +303,233 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		// Add debug logging
 		//logger.Info("Key pressed in mode %d: %s", m.mode, msg.String())
+		m.notice = ""
+
+		// The command palette is reachable from every mode
+		if key.Matches(msg, m.keys.Palette) && m.mode != ModePalette {
+			return m, m.openPalette()
+		}
+		if key.Matches(msg, m.keys.Help) && !m.typing() && m.mode != ModeHelp {
+			m.openHelp()
+			return m, nil
+		}
 
 		switch m.mode {
+		case ModePalette:
+			return m, m.updatePalette(msg)
+
+		case ModeHelp:
+			m.updateHelp(msg)
+
 		case ModeNormal:
 			// Only process mode-switching keys in normal mode
-			switch msg.String() {
-			case "q", "ctrl+c":
+			switch {
+			case key.Matches(msg, m.keys.Quit):
 				return m, tea.Quit
-			case "a":
+			case key.Matches(msg, m.keys.Add):
 				m.mode = ModeAddAccount
 				m.textInput.Focus()
 				return m, textinput.Blink
-			case "l":
+			case key.Matches(msg, m.keys.List):
 				m.mode = ModeListAccounts
-			case "r":
+				m.selected = 0
+			case key.Matches(msg, m.keys.Remove):
 				m.mode = ModeRemoveAccount
 				m.textInput.Focus()
 				m.textInput.Reset()
 				return m, textinput.Blink
+			case key.Matches(msg, m.keys.Filter):
+				m.mode = ModeFilterAccount
+				m.textInput.Focus()
+				m.textInput.Reset()
+				return m, textinput.Blink
+			case key.Matches(msg, m.keys.History):
+				return m, m.openHistory()
+			case key.Matches(msg, m.keys.Resync):
+				m.mode = ModeResyncAccount
+				m.textInput.Focus()
+				m.textInput.Reset()
+				return m, textinput.Blink
+			case key.Matches(msg, m.keys.Unfollowed):
+				m.mode = ModeLostFollowers
+				return m, m.loadLostFollowers
+			case key.Matches(msg, m.keys.Activity):
+				m.toggleActivity()
+			case key.Matches(msg, m.keys.Trending):
+				return m, m.openTrending()
+			case key.Matches(msg, m.keys.Schedule):
+				return m, m.openSchedule()
+			case key.Matches(msg, m.keys.Stats):
+				return m, m.openStats()
+			case key.Matches(msg, m.keys.Theme):
+				m.cycleTheme()
+			case key.Matches(msg, m.keys.TestNotify):
+				return m, m.sendTestNotification()
+			case key.Matches(msg, m.keys.Deliveries):
+				return m, m.openDeliveries()
+			case key.Matches(msg, m.keys.Audit):
+				return m, m.openAudit()
 			}
 
 		case ModeAddAccount:
 			// In add mode, only handle enter and escape
-			switch msg.String() {
-			case "enter":
+			switch {
+			case key.Matches(msg, m.keys.Submit):
+				m.clearSuggestions()
 				return m, m.handleAddAccount(m.textInput.Value())
-			case "esc":
+			case key.Matches(msg, m.keys.Back):
 				m.mode = ModeNormal
 				m.error = nil
+				m.addDuplicate = ""
+				m.cancelBulkAdd()
+				m.clearSuggestions()
 				m.textInput.Blur()
+			case key.Matches(msg, m.keys.PaletteUp):
+				if m.suggestSelected > 0 {
+					m.suggestSelected--
+				}
+				return m, nil
+			case key.Matches(msg, m.keys.PaletteDown):
+				if m.suggestSelected < len(m.suggestions)-1 {
+					m.suggestSelected++
+				}
+				return m, nil
+			case key.Matches(msg, m.keys.Complete):
+				m.completeSuggestion()
+				return m, nil
 			}
 
 		case ModeRemoveAccount:
 			// In remove mode, handle navigation and selection
-			switch msg.String() {
-			case "enter":
+			switch {
+			case key.Matches(msg, m.keys.Submit):
 				return m, m.handleRemoveByUsername(m.textInput.Value())
-			case "esc":
+			case key.Matches(msg, m.keys.Back):
+				m.mode = ModeNormal
+				m.error = nil
+				m.textInput.Blur()
+			}
+
+		case ModeFilterAccount:
+			switch {
+			case key.Matches(msg, m.keys.Submit):
+				return m, m.handleSetFilter(m.textInput.Value())
+			case key.Matches(msg, m.keys.Back):
+				m.mode = ModeNormal
+				m.error = nil
+				m.textInput.Blur()
+			}
+
+		case ModeHistory:
+			if cmd := m.updateHistory(msg); cmd != nil {
+				return m, cmd
+			}
+			if m.mode == ModeDismissRule {
+				return m, textinput.Blink
+			}
+
+		case ModeResyncAccount:
+			switch {
+			case key.Matches(msg, m.keys.Submit):
+				return m, m.handleResync(m.textInput.Value())
+			case key.Matches(msg, m.keys.Back):
 				m.mode = ModeNormal
 				m.error = nil
 				m.textInput.Blur()
 			}
 
+		case ModeEventBatch:
+			if cmd := m.updateEventBatch(msg); cmd != nil {
+				return m, cmd
+			}
+
+		case ModeEventDetail:
+			if key.Matches(msg, m.keys.Back) {
+				m.mode = m.eventsMode()
+				m.error = nil
+			}
+
+		case ModeNotificationPreview:
+			m.updatePreview(msg)
+
+		case ModeDismissRule:
+			switch {
+			case key.Matches(msg, m.keys.Submit):
+				return m, m.handleDismissRule(m.textInput.Value())
+			case key.Matches(msg, m.keys.Back):
+				m.mode = ModeHistory
+				m.error = nil
+				m.textInput.Blur()
+			}
+
 		case ModeListAccounts:
-			// In list mode, only handle escape
-			if msg.String() == "esc" {
+			switch {
+			case key.Matches(msg, m.keys.Back):
+				m.mode = ModeNormal
+				m.error = nil
+			case key.Matches(msg, m.keys.Up):
+				if m.selected > 0 {
+					m.selected--
+				}
+			case key.Matches(msg, m.keys.Down):
+				if m.selected < len(m.visibleAccounts())-1 {
+					m.selected++
+				}
+			case key.Matches(msg, m.keys.PrevPage):
+				m.movePage(-1)
+			case key.Matches(msg, m.keys.NextPage):
+				m.movePage(1)
+			case key.Matches(msg, m.keys.Sort):
+				m.cycleAccountSort()
+			case key.Matches(msg, m.keys.Pause):
+				return m, m.togglePaused()
+			case key.Matches(msg, m.keys.TagFilter):
+				m.cycleTagFilter()
+			case key.Matches(msg, m.keys.Open):
+				return m, m.openAccountDetail()
+			}
+
+		case ModeAccountDetail:
+			if cmd := m.updateAccountDetail(msg); cmd != nil {
+				return m, cmd
+			}
+
+		case ModeFollowings:
+			if cmd := m.updateFollowings(msg); cmd != nil {
+				return m, cmd
+			}
+
+		case ModeFollowingsAsOf:
+			switch {
+			case key.Matches(msg, m.keys.Submit):
+				return m, m.handleFollowingsAsOf(m.textInput.Value())
+			case key.Matches(msg, m.keys.Back):
+				m.mode = ModeFollowings
+				m.error = nil
+				m.textInput.Blur()
+			}
+
+		case ModeSchedule:
+			m.updateSchedule(msg)
+
+		case ModeDeliveries:
+			if cmd := m.updateDeliveries(msg); cmd != nil {
+				return m, cmd
+			}
+
+		case ModeAudit:
+			m.updateAudit(msg)
+
+		case ModeStats:
+			m.updateStats(msg)
+
+		case ModeTrending:
+			if cmd := m.updateTrending(msg); cmd != nil {
+				return m, cmd
+			}
+
+		case ModeLostFollowers:
+			if key.Matches(msg, m.keys.Back) {
 				m.mode = ModeNormal
 				m.error = nil
 			}
@@ -203,18 +537,156 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case checkTimerMsg:
 		now := time.Now()
+		if asleep := m.clockGap(now); asleep >= wakeThreshold {
+			cmds = append(cmds, m.resume(now, asleep), m.tickCheckTimer())
+			break
+		}
 		elapsed := now.Sub(m.lastCheckTime)
-		if elapsed >= m.checkInterval {
-			logger.Info("Starting periodic check (interval: %s)", m.checkInterval)
+		interval := m.effectiveInterval()
+		if elapsed >= interval {
+			logger.Info("Starting periodic check (interval: %s)", interval)
 			cmds = append(cmds, m.CheckAccounts())
 			m.lastCheckTime = now
 		}
 		cmds = append(cmds, m.tickCheckTimer())
 
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		// Leave room for the prompt and label next to the inputs
+		m.textInput.Width = max(min(msg.Width-40, 50), 10)
+		m.paletteInput.Width = max(min(msg.Width-20, 50), 10)
+
 	case tickMsg:
 		m.uptime = time.Since(m.startTime)
 		cmds = append(cmds, m.tickUptime())
 
+	case ConfigReloadedMsg:
+		m.config = msg.Config
+		if m.checkInterval != msg.Config.CheckInterval {
+			logger.Info("Check interval changed from %s to %s", m.checkInterval, msg.Config.CheckInterval)
+			m.checkInterval = msg.Config.CheckInterval
+		}
+		if theme := m.configuredTheme(); theme.Name != m.theme {
+			m.setTheme(theme)
+		}
+		m.notice = "Configuration reloaded"
+
+	case caughtUpMsg:
+		if msg.err != nil {
+			m.error = fmt.Errorf("catch-up check after sleeping failed: %w", msg.err)
+		} else {
+			m.notice = fmt.Sprintf("Caught up after %s asleep", msg.asleep.Round(time.Minute))
+		}
+		cmds = append(cmds, m.loadAccounts)
+
+	case CheckAccountsMsg:
+		// Refresh baselines and sparklines after a periodic check
+		cmds = append(cmds, m.loadAccounts)
+		if m.mode == ModeDeliveries {
+			cmds = append(cmds, m.loadDeliveries)
+		}
+		if m.mode == ModeAccountDetail {
+			cmds = append(cmds, m.loadAccountDetail)
+		}
+		if m.mode == ModeFollowings {
+			cmds = append(cmds, m.loadFollowings)
+		}
+
+	case AccountsChangedMsg:
+		m.notice = msg.Notice
+		cmds = append(cmds, m.loadAccounts)
+
+	case CompletionMsg:
+		m.notice = tracker.Completion(msg).String()
+
+	case CheckProgressMsg:
+		m.updateCheckProgress(tracker.Progress(msg))
+		if m.mode == ModeSchedule {
+			cmds = append(cmds, m.loadSchedule)
+		}
+
+	case suggestTickMsg:
+		if cmd := m.searchSuggestions(string(msg)); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+
+	case suggestionsMsg:
+		m.setSuggestions(msg)
+
+	case bulkAddedMsg:
+		if cmd := m.updateBulkAdd(msg); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+
+	case accountWatchedMsg:
+		m.addDuplicate = string(msg)
+		m.error = nil
+
+	case manualCheckDoneMsg:
+		m.notice = fmt.Sprintf("Check completed at %s", time.Time(msg).Format("15:04:05"))
+		cmds = append(cmds, m.loadAccounts)
+
+	case eventsLoadedMsg:
+		m.history = msg
+		if m.selected >= len(m.history) {
+			m.selected = max(len(m.history)-1, 0)
+		}
+
+	case batchEventsLoadedMsg:
+		m.batchEvents = msg
+		if m.batchSelected >= len(m.batchEvents) {
+			m.batchSelected = max(len(m.batchEvents)-1, 0)
+		}
+
+	case eventSnapshotMsg:
+		m.eventSnapshot = msg.snapshot
+
+	case lostFollowersLoadedMsg:
+		m.lostFollowers = msg
+
+	case testNotifiedMsg:
+		m.notice, m.error = testNotifiedSummary(msg)
+
+	case accountDetailLoadedMsg:
+		m.accountDetail = &msg
+
+	case followingsLoadedMsg:
+		m.followings = &msg
+		if m.selected >= len(msg.rows) {
+			m.selected = max(len(msg.rows)-1, 0)
+		}
+
+	case auditLoadedMsg:
+		m.auditLog = msg
+		if m.selected >= len(m.auditLog) {
+			m.selected = max(len(m.auditLog)-1, 0)
+		}
+
+	case deliveriesLoadedMsg:
+		m.deliveries = msg
+		if m.selected >= len(m.deliveries) {
+			m.selected = max(len(m.deliveries)-1, 0)
+		}
+
+	case scheduleLoadedMsg:
+		m.schedule = &msg
+		if m.selected >= len(msg.plan) {
+			m.selected = max(len(msg.plan)-1, 0)
+		}
+
+	case statsLoadedMsg:
+		m.stats = &msg
+
+	case trendingLoadedMsg:
+		m.trending = msg
+		if m.selected >= len(m.trending) {
+			m.selected = max(len(m.trending)-1, 0)
+		}
+
+	case previewLoadedMsg:
+		preview := webhook.Preview(msg)
+		m.preview = &preview
+
 	case error:
 		m.error = msg
 		return m, nil
@@ -228,11 +700,14 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	}
 
-	// Handle text input updates only in add mode
-	if m.mode == ModeAddAccount || m.mode == ModeRemoveAccount {
+	// Handle text input updates only in input modes
+	if m.mode == ModeAddAccount || m.mode == ModeRemoveAccount || m.mode == ModeFilterAccount || m.mode == ModeResyncAccount || m.mode == ModeDismissRule || m.mode == ModeFollowingsAsOf {
 		var cmd tea.Cmd
 		m.textInput, cmd = m.textInput.Update(msg)
 		cmds = append(cmds, cmd)
+		if m.mode == ModeAddAccount {
+			cmds = append(cmds, m.updateSuggestions())
+		}
 	}
 
 	return m, tea.Batch(cmds...)
@@ -243,33 +718,117 @@ func (m *Model) View() string {
 
 	//s.WriteString(titleStyle.Render("X Track") + "\n\n")
 	// Add status bar with spinner at the top
-	s.WriteString(m.renderStatusBar() + "\n\n")
+	s.WriteString(m.renderStatusBar() + "\n")
+	s.WriteString(m.renderCheckProgress() + "\n")
 
 
 	// Main content area
 	switch m.mode {
 	case ModeAddAccount:
+		if m.bulk != nil {
+			s.WriteString(m.renderBulkAdd())
+			break
+		}
 		prompt := inputPromptStyle.Render("Enter username to watch:")
 		s.WriteString(prompt + " " + m.textInput.View() + "\n")
-		s.WriteString(helpStyle.Render("\nPress enter to add, esc to cancel"))
+		s.WriteString(m.renderSuggestions())
+		if m.offeringResync() {
+			s.WriteString("\n" + warnStyle.Render(fmt.Sprintf("@%s is already watched.", m.addDuplicate)))
+			s.WriteString(m.renderHelp("Press enter to re-sync it instead, esc to cancel"))
+		} else {
+			help := "\nSeparate several usernames with spaces or commas • enter to add, esc to cancel"
+			if len(m.suggestions) > 0 {
+				help = "\n↑/↓ to pick a suggestion, tab to use it • enter to add, esc to cancel"
+			}
+			s.WriteString(m.renderHelp(help))
+		}
 	case ModeRemoveAccount:
 		prompt := removePromptStyle.Render("Enter username to remove:")
 		s.WriteString(prompt + " " + m.textInput.View() + "\n")
-		s.WriteString(helpStyle.Render("\nPress enter to remove, esc to cancel"))
+		s.WriteString(m.renderHelp("\nPress enter to remove, esc to cancel"))
+		s.WriteString(m.renderAccountList())
+	case ModeFilterAccount:
+		prompt := inputPromptStyle.Render("Filter notifications:")
+		s.WriteString(prompt + " " + m.textInput.View() + "\n")
+		s.WriteString(m.renderHelp("\nFormat: username [>followers] [verified] or username off • enter to save, esc to cancel"))
+		s.WriteString(m.renderAccountList())
+	case ModeResyncAccount:
+		prompt := removePromptStyle.Render("Enter username to re-sync:")
+		s.WriteString(prompt + " " + m.textInput.View() + "\n")
+		s.WriteString(m.renderHelp("\nRebuilds the stored followings from the API without recording events • enter to re-sync, esc to cancel"))
 		s.WriteString(m.renderAccountList())
 	case ModeListAccounts:
 		s.WriteString(m.renderAccountList())
+		s.WriteString(m.renderKeys(m.keys.Up, m.keys.Down, m.keys.Open, m.keys.NextPage, m.keys.Sort, m.keys.Pause, m.keys.TagFilter))
+	case ModeAccountDetail:
+		s.WriteString(m.renderAccountDetail())
+		s.WriteString(m.renderKeys(m.keys.detailKeys()...))
+	case ModeFollowings:
+		s.WriteString(m.renderFollowings())
+		s.WriteString(m.renderKeys(m.keys.followingsKeys()...))
+	case ModeFollowingsAsOf:
+		prompt := inputPromptStyle.Render("Browse followings as of:")
+		s.WriteString(prompt + " " + m.textInput.View() + "\n")
+		s.WriteString(m.renderHelp("\nYYYY-MM-DD, a number of days ago like 30d, or today • enter to browse, esc to cancel"))
+		s.WriteString(m.renderFollowings())
+	case ModeLostFollowers:
+		s.WriteString(m.renderLostFollowers())
+	case ModeTrending:
+		s.WriteString(m.renderTrending())
+		s.WriteString(m.renderKeys(m.keys.Up, m.keys.Down, m.keys.Promote))
+	case ModeSchedule:
+		s.WriteString(m.renderSchedule())
+		s.WriteString(m.renderKeys(m.keys.Up, m.keys.Down))
+	case ModeDeliveries:
+		s.WriteString(m.renderDeliveries())
+		s.WriteString(m.renderKeys(m.keys.Up, m.keys.Down, m.keys.FailedOnly))
+	case ModeAudit:
+		s.WriteString(m.renderAudit())
+		s.WriteString(m.renderKeys(m.keys.Up, m.keys.Down))
+	case ModeStats:
+		s.WriteString(m.renderStats())
+	case ModeHistory:
+		s.WriteString(m.renderHistory())
+		s.WriteString(m.renderKeys(m.keys.historyKeys()...))
+	case ModeEventBatch:
+		s.WriteString(m.renderEventBatch())
+		s.WriteString(m.renderKeys(m.keys.batchKeys()...))
+	case ModeEventDetail:
+		s.WriteString(m.renderEventDetail())
+	case ModeNotificationPreview:
+		s.WriteString(m.renderPreview())
+		s.WriteString(m.renderKeys(m.keys.NextChannel, m.keys.PrevChannel))
+	case ModePalette:
+		s.WriteString(m.renderPalette())
+		s.WriteString(m.renderKeys(m.keys.PaletteUp, m.keys.PaletteDown, m.keys.Submit))
+	case ModeHelp:
+		s.WriteString(m.renderKeyHelp())
+	case ModeDismissRule:
+		prompt := removePromptStyle.Render("Dismiss events matching:")
+		s.WriteString(prompt + " " + m.textInput.View() + "\n")
+		s.WriteString(m.renderHelp("\nTerms: account:<username> target:<user id> type:follow|unfollow before:YYYY-MM-DD • enter to dismiss, esc to cancel"))
+	}
+
+	if m.showActivity {
+		s.WriteString(m.renderActivity())
 	}
 
 	// Error display
 	if m.error != nil {
 		s.WriteString("\n" + errorStyle.Render(m.error.Error()))
 	}
+	if m.notice != "" {
+		s.WriteString("\n" + noticeStyle.Render(m.notice))
+	}
 
 	// Help text
-	s.WriteString("\n\n" + helpStyle.Render("a: add • l: list • r: remove • q: quit • esc: cancel"))
+	s.WriteString("\n\n" + m.renderHelp(m.help.ShortHelpView(m.footerKeys())))
 
-	return s.String()
+	if m.width == 0 {
+		return s.String()
+	}
+	// Lines the terminal would wrap itself throw off the redraw
+	return lipgloss.NewStyle().MaxWidth(m.width).Render(s.String())
 }
 
 func (m *Model) getModeString() string {
@@ -282,65 +841,102 @@ func (m *Model) getModeString() string {
 		return "List Accounts"
 	case ModeRemoveAccount:
 		return "Remove Account"
+	case ModeFilterAccount:
+		return "Filter Account"
+	case ModeHistory:
+		return "History"
+	case ModeDismissRule:
+		return "Dismiss Events"
+	case ModePalette:
+		return "Command Palette"
+	case ModeEventDetail:
+		return "Event Detail"
+	case ModeResyncAccount:
+		return "Resync Account"
+	case ModeLostFollowers:
+		return "Lost Followers"
+	case ModeNotificationPreview:
+		return "Notification Preview"
+	case ModeEventBatch:
+		return "Event Summary"
+	case ModeHelp:
+		return "Help"
+	case ModeTrending:
+		return "Trending Targets"
+	case ModeSchedule:
+		return "Check Schedule"
+	case ModeDeliveries:
+		return "Notification Deliveries"
+	case ModeAudit:
+		return "Audit Log"
+	case ModeAccountDetail:
+		return "Account Detail"
+	case ModeFollowings:
+		return "Followings"
+	case ModeFollowingsAsOf:
+		return "Browse As Of"
+	case ModeStats:
+		return "Statistics"
 	default:
 		return "Unknown"
 	}
 }
 
-func (m *Model) renderAccountList() string {
-	if len(m.accounts) == 0 {
-		return "No accounts being watched"
-	}
-
-	var s strings.Builder
-	s.WriteString("Watched accounts:\n\n")
-	
-	for _, account := range m.accounts {
-		item := fmt.Sprintf("@%s",
-			account.Username)
-		s.WriteString(itemStyle.Render(item) + "\n")
+// togglePaused pauses or resumes checks of the selected account
+func (m *Model) togglePaused() tea.Cmd {
+	accounts := m.visibleAccounts()
+	if m.selected < 0 || m.selected >= len(accounts) {
+		return nil
 	}
-	
-	return listStyle.Render(s.String())
+	account := accounts[m.selected]
+	return m.setPaused(account, !account.Paused())
 }
 
-func (m *Model) handleAddAccount(username string) tea.Cmd {
+// setPaused pauses or resumes checks of account
+func (m *Model) setPaused(account db.WatchedAccount, paused bool) tea.Cmd {
 	return func() tea.Msg {
-		// Remove @ if user added it anyway
-		username = strings.TrimPrefix(username, "@")
-		
-		// Get user details from API
-		user, err := m.api.GetUser(username)
-		if err != nil {
+		if err := m.db.SetPaused(account.ID, paused); err != nil {
 			return err
 		}
-
-		logger.Info("Got user details - ID: %s, Username: %s, Following: %d", 
-			user.RestID, 
-			user.Legacy.ScreenName, 
-			user.Legacy.FriendsCount)
-
-		// Add to database
-		account := &db.WatchedAccount{
-			Username:        user.Legacy.ScreenName,
-			UserID:         user.RestID,
+		if paused {
+			m.audit("pause", "@"+account.Username, "")
+		} else {
+			m.audit("resume", "@"+account.Username, "")
 		}
+		return m.loadAccounts()
+	}
+}
 
-		if err := m.db.AddWatchedAccount(account); err != nil {
-			return err
-		}
+// handleAddAccount adds the typed account. A malformed handle is rejected
+// right away; an account that is already watched is offered for a re-sync,
+// which a second enter runs. A list of accounts is added one by one.
+func (m *Model) handleAddAccount(input string) tea.Cmd {
+	if m.bulk != nil {
+		return nil
+	}
+	if usernames := tracker.SplitUsernames(input); len(usernames) > 1 {
+		return m.startBulkAdd(usernames)
+	}
+	if m.offeringResync() {
+		username := m.addDuplicate
+		m.addDuplicate = ""
+		return m.handleResync(username)
+	}
 
-		// Get and store initial following list
-		followings, err := m.api.GetFollowingIDs(account.UserID)
-		if err != nil {
-			return fmt.Errorf("getting initial followings: %w", err)
+	username, err := tracker.NormalizeUsername(input)
+	if err != nil {
+		return func() tea.Msg { return err }
+	}
+	return func() tea.Msg {
+		account, err := m.tracker.AddAccount(username)
+		var watched *tracker.AlreadyWatchedError
+		if errors.As(err, &watched) {
+			return accountWatchedMsg(watched.Account.Username)
 		}
-
-		if err := m.db.StoreFollowings(account.ID, followings.IDs); err != nil {
-			return fmt.Errorf("storing initial followings: %w", err)
+		if err != nil {
+			return err
 		}
-
-		logger.Info("Initialized %d followings for @%s", len(followings.IDs), account.Username)
+		m.audit("add", "@"+account.Username, "")
 
 		m.mode = ModeNormal
 		m.textInput.Reset()
@@ -348,6 +944,16 @@ func (m *Model) handleAddAccount(username string) tea.Cmd {
 	}
 }
 
+// offeringResync reports whether add mode still holds the name of an
+// account found to be watched already
+func (m *Model) offeringResync() bool {
+	if m.addDuplicate == "" {
+		return false
+	}
+	username, err := tracker.NormalizeUsername(m.textInput.Value())
+	return err == nil && strings.EqualFold(username, m.addDuplicate)
+}
+
 func (m *Model) handleRemoveByUsername(username string) tea.Cmd {
 	return func() tea.Msg {
 		// Remove @ if user added it anyway
@@ -358,11 +964,12 @@ func (m *Model) handleRemoveByUsername(username string) tea.Cmd {
 		
 		// Find the account ID by username
 		for _, account := range m.accounts {
-			if account.Username == username {
+			if strings.EqualFold(account.Username, username) {
 				logger.Info("Removing account @%s (ID: %d)", username, account.ID)
-				if err := m.db.RemoveWatchedAccount(account.ID); err != nil {
+				if err := m.tracker.RemoveAccount(&account); err != nil {
 					return err
 				}
+				m.audit("remove", "@"+account.Username, "")
 				m.mode = ModeNormal
 				m.textInput.Reset()
 				m.textInput.Blur()
@@ -373,109 +980,159 @@ func (m *Model) handleRemoveByUsername(username string) tea.Cmd {
 	}
 }
 
-func (m *Model) loadAccounts() tea.Msg {
-	accounts, err := m.db.GetWatchedAccounts()
-	if err != nil {
-		return err
-	}
-	m.accounts = accounts
-	return nil
-}
+// handleResync rebuilds an account's following snapshot without events
+func (m *Model) handleResync(username string) tea.Cmd {
+	return func() tea.Msg {
+		username = strings.TrimPrefix(username, "@")
+		if username == "" {
+			return fmt.Errorf("please enter a username")
+		}
 
-// CheckAccounts periodically checks all watched accounts for changes
-func (m *Model) CheckAccounts() tea.Cmd {
-	return tea.Tick(m.config.CheckInterval, func(t time.Time) tea.Msg {
-		logger.Info("Starting periodic check of watched accounts...")
-		
-		accounts, err := m.db.GetWatchedAccounts()
+		account, err := m.tracker.ResyncAccount(username)
 		if err != nil {
-			logger.Info("Error getting watched accounts: %v", err)
-			return nil
+			return err
 		}
+		m.audit("resync", "@"+account.Username, "")
+		m.mode = ModeNormal
+		m.notice = fmt.Sprintf("Re-synced @%s", account.Username)
+		m.textInput.Reset()
+		m.textInput.Blur()
+		return m.loadAccounts()
+	}
+}
 
-		for _, account := range accounts {
-			// Get current following IDs from API
-			followings, err := m.api.GetFollowingIDs(account.UserID)
-			if err != nil {
-				logger.Info("Error getting following IDs for %s: %v", account.Username, err)
-				continue
-			}
-
-			// Get current followings from database
-			currentFollowings, err := m.db.GetCurrentFollowings(account.ID)
-			if err != nil {
-				logger.Info("Error getting current followings for %s: %v", account.Username, err)
-				continue
-			}
-
-			// Create map of new followings for efficient lookup
-			newFollowingsMap := make(map[string]bool)
-			var newFollows []string
-
-			// Find new follows
-			for _, id := range followings.IDs {
-				newFollowingsMap[id] = true
-				if !currentFollowings[id] {
-					newFollows = append(newFollows, id)
+// handleSetFilter parses "username [>followers] [verified]" (or "username off")
+// and saves it as that account's notification filter
+func (m *Model) handleSetFilter(input string) tea.Cmd {
+	return func() tea.Msg {
+		fields := strings.Fields(input)
+		if len(fields) == 0 {
+			return fmt.Errorf("please enter a username")
+		}
+		username := strings.TrimPrefix(fields[0], "@")
+
+		var filter db.NotificationFilter
+		for _, field := range fields[1:] {
+			switch {
+			case field == "off":
+				filter = db.NotificationFilter{}
+			case field == "verified" || field == "blue":
+				filter.VerifiedOnly = true
+			case strings.HasPrefix(field, ">"):
+				n, err := strconv.Atoi(strings.TrimPrefix(field, ">"))
+				if err != nil || n < 0 {
+					return fmt.Errorf("invalid follower threshold %q", field)
 				}
+				filter.MinFollowers = n
+			default:
+				return fmt.Errorf("unknown filter option %q", field)
 			}
+		}
 
-			// Find unfollows
-			var unfollows []string
-			for id := range currentFollowings {
-				if !newFollowingsMap[id] {
-					unfollows = append(unfollows, id)
+		for _, account := range m.accounts {
+			if strings.EqualFold(account.Username, username) {
+				filter.WatchedAccountID = account.ID
+				if err := m.db.SetNotificationFilter(&filter); err != nil {
+					return err
 				}
+				m.audit("filter", "@"+account.Username, strings.Join(fields[1:], " "))
+				m.mode = ModeNormal
+				m.textInput.Reset()
+				m.textInput.Blur()
+				return m.loadAccounts()
 			}
+		}
+		return fmt.Errorf("account @%s not found", username)
+	}
+}
 
-			// If there are changes, store them
-			if len(newFollows) > 0 || len(unfollows) > 0 {
-				logger.Info("Processing changes for %s: +%d new follows, -%d unfollows", 
-					account.Username, len(newFollows), len(unfollows))
+// filterSummary renders a short description of an active filter
+func filterSummary(filter db.NotificationFilter) string {
+	var parts []string
+	if filter.MinFollowers > 0 {
+		parts = append(parts, fmt.Sprintf(">%s followers", format.Number(filter.MinFollowers)))
+	}
+	if filter.VerifiedOnly {
+		parts = append(parts, "verified only")
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
 
-				// First store the events
-				if err := m.db.StoreFollowEvents(account.ID, newFollows, unfollows); err != nil {
-					logger.Info("Error storing follow events for %s: %v", account.Username, err)
-					continue
-				}
+func (m *Model) loadAccounts() tea.Msg {
+	accounts, err := m.db.GetWatchedAccounts()
+	if err != nil {
+		return err
+	}
+	counts, err := m.db.GetRecentFollowingCounts(sparklineWidth)
+	if err != nil {
+		return err
+	}
+	changes, err := m.db.GetLastChangeTimes()
+	if err != nil {
+		return err
+	}
+	costs, err := m.db.GetLastCheckCosts()
+	if err != nil {
+		return err
+	}
+	m.accounts = accounts
+	m.countHistory = counts
+	m.lastChanges = changes
+	m.lastCosts = costs
+	return nil
+}
 
-				// Then update the following relationships
-				if err := m.db.StoreFollowings(account.ID, followings.IDs); err != nil {
-					logger.Info("Error updating followings for %s: %v", account.Username, err)
-					continue
-				}
+// effectiveInterval returns the check interval, or the longer one checks
+// are spaced out to while the API quota is low
+func (m *Model) effectiveInterval() time.Duration {
+	if stretched := m.tracker.StretchedInterval(); stretched > m.checkInterval {
+		return stretched
+	}
+	return m.checkInterval
+}
 
-				// Send webhook notifications if configured
-				if m.notifications != nil {
-					// Handle follow notifications
-					if m.config.EnableFollowNotifications && len(newFollows) > 0 {
-						logger.Info("Sending follow notifications for %s: %d new follows", 
-							account.Username, len(newFollows))
-						m.notifications.NotifyNewFollows(&account, newFollows, m.api)
-					} else if len(newFollows) > 0 {
-						logger.Info("Follow notifications disabled, skipping %d new follows", len(newFollows))
-					}
-
-					// Handle unfollow notifications
-					if m.config.EnableUnfollowNotifications && len(unfollows) > 0 {
-						logger.Info("Sending unfollow notifications for %s: %d unfollows", 
-							account.Username, len(unfollows))
-						m.notifications.NotifyUnfollows(&account, unfollows, m.api)
-					} else if len(unfollows) > 0 {
-						logger.Info("Unfollow notifications disabled, skipping %d unfollows", len(unfollows))
-					}
-				}
+// CheckAccounts periodically checks all watched accounts for changes
+func (m *Model) CheckAccounts() tea.Cmd {
+	return m.scheduleCheck(m.config.CheckInterval)
+}
 
-				logger.Info("Successfully processed all changes for account %s", account.Username)
-			} else {
-				logger.Info("No changes detected for %s", account.Username)
-			}
+// scheduleCheck runs a periodic check cycle after delay, unless the checks
+// are rescheduled before then
+func (m *Model) scheduleCheck(delay time.Duration) tea.Cmd {
+	generation := m.checkGeneration.Load()
+	return tea.Tick(delay, func(t time.Time) tea.Msg {
+		if m.checkGeneration.Load() != generation {
+			return nil
+		}
+		logger.Info("Starting periodic check of watched accounts...")
+		if err := m.tracker.CheckAllScheduled(); err != nil {
+			logger.Error("Error checking accounts: %v", err)
+			return nil
 		}
-
 		return CheckAccountsMsg(t)
 	})
 }
 
+// CheckNow checks all watched accounts right away
+func (m *Model) CheckNow() tea.Cmd {
+	return func() tea.Msg {
+		logger.Info("Starting manual check of watched accounts...")
+		if msg := m.checkAllAccounts(time.Now()); msg == nil {
+			return fmt.Errorf("check failed, see logs for details")
+		}
+		return manualCheckDoneMsg(time.Now())
+	}
+}
+
+// checkAllAccounts fetches and diffs the followings of every watched account
+func (m *Model) checkAllAccounts(t time.Time) tea.Msg {
+	if err := m.tracker.CheckAll(); err != nil {
+		logger.Error("Error checking accounts: %v", err)
+		return nil
+	}
+	return CheckAccountsMsg(t)
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
@@ -518,9 +1175,25 @@ func (m *Model) renderStatusBar() string {
 	uptime := time.Since(m.startTime).Round(time.Second)
 	spinnerView := m.spinner.View()
 	
-	return statusBarStyle.Render(
-		fmt.Sprintf("X Track | API Left: %d | Uptime: %s %s", 
-			m.api.RemainingRequests(), 
+	style := statusBarStyle
+	if m.width > 0 {
+		style = style.Width(m.width)
+	}
+	reset := ""
+	if breaker := m.api.Breaker(); breaker.Open {
+		reset = fmt.Sprintf(" | API degraded, checks resume at %s", breaker.Until.Local().Format("15:04:05"))
+	} else if next, ok := api.NextReset(m.api.RateLimitStatus(), time.Now()); ok {
+		reset = fmt.Sprintf(" | %s resets in %s", next.Endpoint, formatDuration(time.Until(next.Reset)))
+	}
+	title := "X Track"
+	if m.dryRun {
+		title += " | DRY RUN, nothing is saved or sent"
+	}
+	return style.Render(
+		fmt.Sprintf("%s | API Left: %s%s | Uptime: %s %s", 
+			title,
+			format.Number(m.api.RemainingRequests()), 
+			reset,
 			uptime, 
 			spinnerView,
 		),
@@ -0,0 +1,250 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"x-tracker/internal/webhook"
+)
+
+// paletteAction is one entry in the command palette
+type paletteAction struct {
+	name string
+	key  key.Binding // shortcut from normal mode, unset if none
+	run  func(m *Model) tea.Cmd
+}
+
+// paletteActions lists everything reachable from the command palette.
+// Register new features here so they stay discoverable.
+func paletteActions(keys keyMap) []paletteAction {
+	return []paletteAction{
+		{name: "Add account", key: keys.Add, run: func(m *Model) tea.Cmd { return m.enterInputMode(ModeAddAccount) }},
+		{name: "List accounts", key: keys.List, run: func(m *Model) tea.Cmd { m.mode = ModeListAccounts; m.selected = 0; return nil }},
+		{name: "Filter account list by tag", run: func(m *Model) tea.Cmd {
+			m.mode = ModeListAccounts
+			m.cycleTagFilter()
+			return nil
+		}},
+		{name: "Remove account", key: keys.Remove, run: func(m *Model) tea.Cmd { return m.enterInputMode(ModeRemoveAccount) }},
+		{name: "Filter account notifications", key: keys.Filter, run: func(m *Model) tea.Cmd { return m.enterInputMode(ModeFilterAccount) }},
+		{name: "Re-sync account", key: keys.Resync, run: func(m *Model) tea.Cmd { return m.enterInputMode(ModeResyncAccount) }},
+		{name: "Open event history", key: keys.History, run: func(m *Model) tea.Cmd { return m.openHistory() }},
+		{name: "Show who unfollowed", key: keys.Unfollowed, run: func(m *Model) tea.Cmd {
+			m.mode = ModeLostFollowers
+			return m.loadLostFollowers
+		}},
+		{name: "Show trending targets", key: keys.Trending, run: func(m *Model) tea.Cmd { return m.openTrending() }},
+		{name: "Show check schedule", key: keys.Schedule, run: func(m *Model) tea.Cmd { return m.openSchedule() }},
+		{name: "Show statistics", key: keys.Stats, run: func(m *Model) tea.Cmd { return m.openStats() }},
+		{name: "Switch color theme", key: keys.Theme, run: func(m *Model) tea.Cmd { m.cycleTheme(); return nil }},
+		{name: "Toggle activity log", key: keys.Activity, run: func(m *Model) tea.Cmd {
+			m.mode = ModeNormal
+			m.toggleActivity()
+			return nil
+		}},
+		{name: "Run check now", run: func(m *Model) tea.Cmd {
+			m.mode = ModeNormal
+			m.notice = "Checking all accounts..."
+			return m.CheckNow()
+		}},
+		{name: "Toggle Discord notifications", run: func(m *Model) tea.Cmd { return m.toggleChannel(webhook.ChannelDiscord) }},
+		{name: "Toggle Telegram notifications", run: func(m *Model) tea.Cmd { return m.toggleChannel(webhook.ChannelTelegram) }},
+		{name: "Toggle Signal notifications", run: func(m *Model) tea.Cmd { return m.toggleChannel(webhook.ChannelSignal) }},
+		{name: "Toggle desktop notifications", run: func(m *Model) tea.Cmd { return m.toggleChannel(webhook.ChannelDesktop) }},
+		{name: "Send test notification", key: keys.TestNotify, run: func(m *Model) tea.Cmd { return m.sendTestNotification() }},
+		{name: "Show notification deliveries", key: keys.Deliveries, run: func(m *Model) tea.Cmd { return m.openDeliveries() }},
+		{name: "Show audit log", key: keys.Audit, run: func(m *Model) tea.Cmd { return m.openAudit() }},
+		{name: "Quit", key: keys.Quit, run: func(m *Model) tea.Cmd { return tea.Quit }},
+	}
+}
+
+// enterInputMode switches to a mode driven by the text input
+func (m *Model) enterInputMode(mode Mode) tea.Cmd {
+	m.mode = mode
+	m.textInput.Reset()
+	m.textInput.Focus()
+	return textinput.Blink
+}
+
+func (m *Model) toggleChannel(channel string) tea.Cmd {
+	m.mode = ModeNormal
+	enabled := !m.notifications.ChannelEnabled(channel)
+	if err := m.notifications.SetChannelEnabled(channel, enabled); err != nil {
+		m.error = err
+		return nil
+	}
+	state, action := "disabled", "disable channel"
+	if enabled {
+		state, action = "enabled", "enable channel"
+	}
+	m.audit(action, channel, "")
+	m.notice = fmt.Sprintf("%s notifications %s", strings.ToUpper(channel[:1])+channel[1:], state)
+	return nil
+}
+
+// testNotifiedMsg carries the per-channel outcome of a test notification
+type testNotifiedMsg []webhook.TestResult
+
+// sendTestNotification sends the sample notification through every enabled
+// channel in the background
+func (m *Model) sendTestNotification() tea.Cmd {
+	m.mode = ModeNormal
+	m.notice = "Sending test notification..."
+	return func() tea.Msg {
+		return testNotifiedMsg(m.notifications.SendTest())
+	}
+}
+
+// testNotifiedSummary reports which channels took the test notification
+func testNotifiedSummary(results testNotifiedMsg) (string, error) {
+	if len(results) == 0 {
+		return "", fmt.Errorf("no notification channel is enabled")
+	}
+	var ok, failed []string
+	for _, result := range results {
+		if result.Err != nil {
+			failed = append(failed, fmt.Sprintf("%s (%v)", result.Channel, result.Err))
+		} else {
+			ok = append(ok, result.Channel)
+		}
+	}
+	if len(failed) > 0 {
+		return "", fmt.Errorf("test notification failed for %s", strings.Join(failed, ", "))
+	}
+	return fmt.Sprintf("Test notification sent to %s", strings.Join(ok, ", ")), nil
+}
+
+func (m *Model) openPalette() tea.Cmd {
+	m.paletteReturn = m.mode
+	m.mode = ModePalette
+	m.paletteSelected = 0
+	m.paletteInput.Reset()
+	m.paletteInput.Focus()
+	m.textInput.Blur()
+	return textinput.Blink
+}
+
+// updatePalette handles keys while the palette is open
+func (m *Model) updatePalette(msg tea.KeyMsg) tea.Cmd {
+	matches := m.paletteMatches()
+
+	switch {
+	case key.Matches(msg, m.keys.Back, m.keys.Palette):
+		m.mode = m.paletteReturn
+		m.paletteInput.Blur()
+		return nil
+	case key.Matches(msg, m.keys.PaletteUp):
+		if m.paletteSelected > 0 {
+			m.paletteSelected--
+		}
+		return nil
+	case key.Matches(msg, m.keys.PaletteDown):
+		if m.paletteSelected < len(matches)-1 {
+			m.paletteSelected++
+		}
+		return nil
+	case key.Matches(msg, m.keys.Submit):
+		m.paletteInput.Blur()
+		if len(matches) == 0 {
+			m.mode = m.paletteReturn
+			return nil
+		}
+		m.mode = ModeNormal
+		m.error = nil
+		return matches[m.paletteSelected].run(m)
+	}
+
+	var cmd tea.Cmd
+	m.paletteInput, cmd = m.paletteInput.Update(msg)
+	m.paletteSelected = 0
+	return cmd
+}
+
+// paletteMatches returns the actions matching the query, best first
+func (m *Model) paletteMatches() []paletteAction {
+	query := m.paletteInput.Value()
+	actions := paletteActions(m.keys)
+	if strings.TrimSpace(query) == "" {
+		return actions
+	}
+
+	type scored struct {
+		action paletteAction
+		score  int
+	}
+	var results []scored
+	for _, action := range actions {
+		if score, ok := fuzzyScore(query, action.name); ok {
+			results = append(results, scored{action, score})
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].score > results[j].score
+	})
+
+	matches := make([]paletteAction, len(results))
+	for i, r := range results {
+		matches[i] = r.action
+	}
+	return matches
+}
+
+// fuzzyScore matches query as a case-insensitive subsequence of target.
+// Consecutive characters and word starts score higher.
+func fuzzyScore(query, target string) (int, bool) {
+	q := []rune(strings.ToLower(strings.ReplaceAll(query, " ", "")))
+	t := []rune(strings.ToLower(target))
+
+	score, qi, prev := 0, 0, -2
+	for ti := 0; ti < len(t) && qi < len(q); ti++ {
+		if t[ti] != q[qi] {
+			continue
+		}
+		score++
+		if ti == prev+1 {
+			score += 3
+		}
+		if ti == 0 || unicode.IsSpace(t[ti-1]) {
+			score += 5
+		}
+		prev = ti
+		qi++
+	}
+	if qi < len(q) {
+		return 0, false
+	}
+	// Prefer shorter names when scores tie
+	return score*100 - len(t), true
+}
+
+func (m *Model) renderPalette() string {
+	var s strings.Builder
+	s.WriteString(inputPromptStyle.Render("Command:") + " " + m.paletteInput.View() + "\n\n")
+
+	matches := m.paletteMatches()
+	if len(matches) == 0 {
+		s.WriteString(itemStyle.Render("No matching commands") + "\n")
+	}
+	start, end := scrollWindow(m.paletteSelected, len(matches), m.listRows())
+	s.WriteString(moreMarker("↑", start))
+	for i := start; i < end; i++ {
+		action := matches[i]
+		item := action.name
+		if len(action.key.Keys()) > 0 {
+			item += fmt.Sprintf(" (%s)", action.key.Help().Key)
+		}
+		if i == m.paletteSelected {
+			s.WriteString(selectedItemStyle.Render(item) + "\n")
+		} else {
+			s.WriteString(itemStyle.Render(item) + "\n")
+		}
+	}
+	s.WriteString(moreMarker("↓", len(matches)-end))
+
+	return m.box().Render(s.String())
+}
@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"x-tracker/internal/db"
+	"x-tracker/internal/webhook"
+)
+
+// previewChannels are the renderings the preview cycles through
+var previewChannels = []string{"Discord", "Telegram", "Signal", "JSON"}
+
+// previewLoadedMsg carries a rendered notification for the preview screen
+type previewLoadedMsg webhook.Preview
+
+// openPreview renders the notification the selected event would have sent
+func (m *Model) openPreview() tea.Cmd {
+	event := m.selectedEvent()
+	if event == nil {
+		return nil
+	}
+	m.mode = ModeNotificationPreview
+	m.preview = nil
+
+	account := &db.WatchedAccount{ID: event.WatchedAccountID, Username: event.AccountUsername}
+	events := []db.FollowEvent{*event}
+	return func() tea.Msg {
+		return previewLoadedMsg(m.notifications.Preview(account, events, m.api))
+	}
+}
+
+// updatePreview handles keys while the preview is open
+func (m *Model) updatePreview(msg tea.KeyMsg) {
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.mode = m.eventsMode()
+		m.error = nil
+	case key.Matches(msg, m.keys.NextChannel):
+		m.previewChannel = (m.previewChannel + 1) % len(previewChannels)
+	case key.Matches(msg, m.keys.PrevChannel):
+		m.previewChannel = (m.previewChannel + len(previewChannels) - 1) % len(previewChannels)
+	}
+}
+
+func (m *Model) renderPreview() string {
+	var tabs []string
+	for i, name := range previewChannels {
+		if i == m.previewChannel {
+			tabs = append(tabs, titleStyle.Render("["+name+"]"))
+		} else {
+			tabs = append(tabs, " "+name+" ")
+		}
+	}
+	header := strings.Join(tabs, " ")
+
+	if m.preview == nil {
+		return header + "\n" + m.box().Render("Looking up the user...")
+	}
+
+	var body string
+	switch previewChannels[m.previewChannel] {
+	case "Discord":
+		body = m.preview.Discord
+	case "Telegram":
+		body = m.preview.Telegram
+	case "Signal":
+		body = m.preview.Signal
+	default:
+		body = m.preview.JSON
+	}
+	return header + "\n" + m.box().Render(strings.TrimRight(body, "\n"))
+}
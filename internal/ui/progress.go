@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"x-tracker/internal/format"
+	"x-tracker/internal/tracker"
+)
+
+// progressBarWidth is the number of cells of the check progress bar
+const progressBarWidth = 20
+
+// progressLines is how many lines the check progress takes while a cycle
+// runs, on top of the usual chrome
+const progressLines = 2
+
+// updateCheckProgress records a step of the running check cycle. The
+// finishing step clears it.
+func (m *Model) updateCheckProgress(p tracker.Progress) {
+	if p.State == tracker.ProgressFinished {
+		m.checkProgress = nil
+		return
+	}
+	if len(m.checkProgress) != p.Total {
+		m.checkProgress = make([]tracker.Progress, p.Total)
+	}
+	if p.Index >= 1 && p.Index <= len(m.checkProgress) {
+		m.checkProgress[p.Index-1] = p
+	}
+}
+
+// checking reports whether a check cycle is showing its progress
+func (m *Model) checking() bool {
+	return m.checkProgress != nil
+}
+
+// renderCheckProgress renders a progress bar with the account being
+// checked and a status line per account, "" between cycles
+func (m *Model) renderCheckProgress() string {
+	if !m.checking() {
+		return ""
+	}
+
+	done := 0
+	var current *tracker.Progress
+	for i := range m.checkProgress {
+		switch m.checkProgress[i].State {
+		case tracker.ProgressChecked, tracker.ProgressFailed:
+			done++
+		case tracker.ProgressChecking:
+			if m.checkProgress[i].Account != "" {
+				current = &m.checkProgress[i]
+			}
+		}
+	}
+	total := len(m.checkProgress)
+
+	filled := progressBarWidth * done / max(total, 1)
+	bar := progressDoneStyle.Render(strings.Repeat("█", filled)) +
+		progressTodoStyle.Render(strings.Repeat("░", progressBarWidth-filled))
+
+	text := fmt.Sprintf("checked %d/%d", done, total)
+	if current != nil {
+		text = fmt.Sprintf("checking @%s %d/%d", current.Account, current.Index, total)
+		if current.Fetched > 0 {
+			text += fmt.Sprintf(", fetched %s IDs", format.Number(current.Fetched))
+		}
+	}
+
+	return bar + " " + activityStyle.Render(truncate(text, m.width-progressBarWidth-1)) + "\n" +
+		m.renderAccountProgress()
+}
+
+// renderAccountProgress renders one status per account of the cycle,
+// dropping the oldest when they don't fit the terminal
+func (m *Model) renderAccountProgress() string {
+	var statuses []string
+	pending := 0
+	for _, p := range m.checkProgress {
+		switch {
+		case p.Account == "":
+			pending++
+		case p.State == tracker.ProgressChecking:
+			statuses = append(statuses, m.spinner.View()+" @"+p.Account)
+		case p.State == tracker.ProgressFailed:
+			statuses = append(statuses, errorStyle.Render("✗")+" @"+p.Account)
+		default:
+			statuses = append(statuses, noticeStyle.Render("✓")+" @"+p.Account)
+		}
+	}
+	if pending > 0 {
+		statuses = append(statuses, activityStyle.Render(fmt.Sprintf("%d pending", pending)))
+	}
+
+	line := strings.Join(statuses, "  ")
+	for m.width > 0 && lipgloss.Width(line) > m.width && len(statuses) > 1 {
+		statuses = statuses[1:]
+		line = "…  " + strings.Join(statuses, "  ")
+	}
+	return line
+}
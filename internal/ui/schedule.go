@@ -0,0 +1,221 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"x-tracker/internal/db"
+	"x-tracker/internal/format"
+	"x-tracker/internal/tracker"
+)
+
+const (
+	// scheduleRuns is how many past check cycles the schedule view lists
+	scheduleRuns = 5
+	// scheduleBarWidth is the width of an account's timeline in cells
+	scheduleBarWidth = 24
+	// scheduleCostliest is how many of the most expensive accounts the
+	// schedule view ranks
+	scheduleCostliest = 3
+	// scheduleCostDays is how many days back the ranking adds up calls for
+	scheduleCostDays = 7
+)
+
+// scheduleLoadedMsg carries the planned checks, the recent check cycles and
+// the accounts whose checks cost the most API calls
+type scheduleLoadedMsg struct {
+	plan      []tracker.PlannedCheck
+	running   bool // plan is the cycle spreading its checks right now
+	runs      []db.CheckRun
+	costliest []db.AccountCost
+}
+
+func (m *Model) openSchedule() tea.Cmd {
+	m.mode = ModeSchedule
+	m.selected = 0
+	return m.loadSchedule
+}
+
+func (m *Model) loadSchedule() tea.Msg {
+	plan, running, err := m.tracker.Plan(m.lastCheckTime.Add(m.effectiveInterval()))
+	if err != nil {
+		return err
+	}
+	runs, err := m.db.GetCheckRuns(scheduleRuns)
+	if err != nil {
+		return err
+	}
+	costliest, err := m.db.GetCostRanking(time.Now().AddDate(0, 0, -scheduleCostDays), scheduleCostliest)
+	if err != nil {
+		return err
+	}
+	return scheduleLoadedMsg{plan: plan, running: running, runs: runs, costliest: costliest}
+}
+
+func (m *Model) updateSchedule(msg tea.KeyMsg) {
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.mode = ModeNormal
+		m.error = nil
+	case key.Matches(msg, m.keys.Up):
+		if m.selected > 0 {
+			m.selected--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.selected < len(m.schedule.plan)-1 {
+			m.selected++
+		}
+	}
+}
+
+// renderSchedule shows when every account is checked in the running or next
+// cycle on a shared timeline, followed by how the last cycles went and which
+// accounts cost the most API calls
+func (m *Model) renderSchedule() string {
+	if m.schedule == nil {
+		return m.box().Render("Loading schedule...")
+	}
+	cfg := m.config
+	plan := m.schedule.plan
+	now := time.Now()
+
+	var s strings.Builder
+	s.WriteString(fmt.Sprintf("Checks every %s", m.checkInterval))
+	if window := time.Duration(float64(m.checkInterval) * cfg.CheckSpread); window > 0 {
+		s.WriteString(fmt.Sprintf(", spread over the first %s", window.Round(time.Second)))
+	} else {
+		s.WriteString(", back to back")
+	}
+	if cfg.CheckJitter > 0 {
+		s.WriteString(fmt.Sprintf(", up to %s jitter each", cfg.CheckJitter))
+	}
+	if stretched := m.tracker.StretchedInterval(); stretched > m.checkInterval {
+		s.WriteString(fmt.Sprintf("\nSpaced out to every %s while the quota is low", stretched.Round(time.Second)))
+	}
+	s.WriteString("\n")
+	if m.schedule.running {
+		s.WriteString(fmt.Sprintf("Cycle running since %s\n\n", plan[0].At.Local().Format("15:04:05")))
+	} else {
+		next := m.lastCheckTime.Add(m.effectiveInterval())
+		wait := time.Until(next).Round(time.Second)
+		if wait < 0 {
+			wait = 0
+		}
+		s.WriteString(fmt.Sprintf("Next cycle at %s, in %s\n\n", next.Local().Format("15:04:05"), wait))
+	}
+
+	if len(plan) == 0 {
+		s.WriteString("No active accounts to check\n")
+	} else {
+		// Every timeline spans from now, or the first check of a running
+		// cycle, to the latest any check may run
+		from := plan[0].At
+		if !m.schedule.running {
+			from = now
+		}
+		end := from.Add(time.Second)
+		for _, check := range plan {
+			if last := check.At.Add(check.Jitter); last.After(end) {
+				end = last
+			}
+		}
+
+		lastChecked := make(map[string]time.Time, len(m.accounts))
+		for _, account := range m.accounts {
+			lastChecked[strings.ToLower(account.Username)] = account.LastCheckedAt
+		}
+
+		rows := max(m.listRows()-len(m.schedule.runs)-len(m.schedule.costliest)-8, minListRows)
+		start, stop := scrollWindow(m.selected, len(plan), rows)
+		s.WriteString(moreMarker("↑", start))
+		for i := start; i < stop; i++ {
+			check := plan[i]
+			due := check.At.Local().Format("15:04:05")
+			if check.Jitter > 0 {
+				due += fmt.Sprintf(" +%s", check.Jitter)
+			}
+			item := fmt.Sprintf("%s  %s  @%s", renderTimeline(check, from, end, now), due, check.Account)
+			if checked := lastChecked[strings.ToLower(check.Account)]; !checked.IsZero() {
+				item += fmt.Sprintf(", last checked %s ago", format.Age(time.Since(checked)))
+			}
+			item = truncate(item, m.itemWidth())
+			if i == m.selected {
+				s.WriteString(selectedItemStyle.Render(item) + "\n")
+			} else {
+				s.WriteString(itemStyle.Render(item) + "\n")
+			}
+		}
+		s.WriteString(moreMarker("↓", len(plan)-stop))
+	}
+
+	var paused []string
+	for _, account := range m.accounts {
+		if account.Paused() {
+			paused = append(paused, "@"+account.Username)
+		}
+	}
+	if len(paused) > 0 {
+		s.WriteString(truncate("Paused: "+strings.Join(paused, ", "), m.itemWidth()) + "\n")
+	}
+
+	s.WriteString("\nRecent cycles:\n")
+	if len(m.schedule.runs) == 0 {
+		s.WriteString("None yet\n")
+	}
+	for _, run := range m.schedule.runs {
+		line := fmt.Sprintf("%s  %d accounts in %s", run.StartedAt.Local().Format("Jan 2 15:04:05"),
+			run.Accounts, run.FinishedAt.Sub(run.StartedAt).Round(time.Second))
+		if run.Failures > 0 {
+			line += warnStyle.Render(fmt.Sprintf(", %d failed", run.Failures))
+		}
+		line += fmt.Sprintf(", quota %s left", format.Number(run.QuotaRemaining))
+		if run.Error != "" {
+			line += errorStyle.Render(" " + run.Error)
+		}
+		s.WriteString(truncate(line, m.itemWidth()) + "\n")
+	}
+
+	if len(m.schedule.costliest) > 0 {
+		s.WriteString(fmt.Sprintf("\nMost API calls, last %d days:\n", scheduleCostDays))
+		for i, cost := range m.schedule.costliest {
+			line := fmt.Sprintf("%d. @%s  %s calls in %d checks, %s per check (%s pages, %s lookups)",
+				i+1, cost.Username, format.Number(cost.Calls()), cost.Checks,
+				format.Number(cost.Calls()/cost.Checks), format.Number(cost.Pages), format.Number(cost.Lookups))
+			s.WriteString(truncate(line, m.itemWidth()) + "\n")
+		}
+	}
+
+	return m.box().Render(s.String())
+}
+
+// renderTimeline draws when check runs between from and end: █ at its slot
+// and ▒ over the jitter it may be pushed back by. Checks already due are
+// drawn dimmed.
+func renderTimeline(check tracker.PlannedCheck, from, end, now time.Time) string {
+	span := end.Sub(from)
+	cell := func(t time.Time) int {
+		return min(max(int(float64(t.Sub(from))/float64(span)*(scheduleBarWidth-1)), 0), scheduleBarWidth-1)
+	}
+	slot := cell(check.At)
+	last := cell(check.At.Add(check.Jitter))
+
+	var bar strings.Builder
+	for i := 0; i < scheduleBarWidth; i++ {
+		switch {
+		case i == slot:
+			bar.WriteString("█")
+		case i > slot && i <= last:
+			bar.WriteString("▒")
+		default:
+			bar.WriteString("·")
+		}
+	}
+	if check.At.Before(now) {
+		return "|" + progressTodoStyle.Render(bar.String()) + "|"
+	}
+	return "|" + progressDoneStyle.Render(bar.String()) + "|"
+}
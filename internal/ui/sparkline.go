@@ -0,0 +1,32 @@
+package ui
+
+import "strings"
+
+// sparklineWidth is how many samples an account row shows
+const sparklineWidth = 20
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline renders values as a row of block characters scaled between
+// their minimum and maximum; a flat series renders as a flat line
+func sparkline(values []int) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo = min(lo, v)
+		hi = max(hi, v)
+	}
+
+	var s strings.Builder
+	for _, v := range values {
+		level := 0
+		if hi > lo {
+			level = (v - lo) * (len(sparkBlocks) - 1) / (hi - lo)
+		}
+		s.WriteRune(sparkBlocks[level])
+	}
+	return s.String()
+}
@@ -0,0 +1,111 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"x-tracker/internal/db"
+	"x-tracker/internal/format"
+)
+
+const (
+	// statsDays is how many days the stats view covers
+	statsDays = 30
+	// statsLimit is how many targets and accounts the stats view ranks
+	statsLimit = 5
+)
+
+// statsLoadedMsg carries the aggregates the stats view shows and the
+// insights found over the same days
+type statsLoadedMsg struct {
+	stats    *db.Stats
+	insights []db.Insight
+}
+
+func (m *Model) openStats() tea.Cmd {
+	m.mode = ModeStats
+	return m.loadStats
+}
+
+func (m *Model) loadStats() tea.Msg {
+	since := time.Now().AddDate(0, 0, -statsDays)
+	stats, err := m.db.GetStats(since, statsLimit)
+	if err != nil {
+		return err
+	}
+	insights, err := m.db.GetInsights(since, "", statsLimit)
+	if err != nil {
+		return err
+	}
+	return statsLoadedMsg{stats: stats, insights: insights}
+}
+
+func (m *Model) updateStats(msg tea.KeyMsg) {
+	if key.Matches(msg, m.keys.Back) {
+		m.mode = ModeNormal
+		m.error = nil
+	}
+}
+
+// renderStats shows the daily changes as sparklines followed by the most
+// followed targets, the accounts with the highest churn and the latest
+// insights
+func (m *Model) renderStats() string {
+	if m.stats == nil {
+		return m.box().Render("Loading statistics...")
+	}
+	stats := m.stats.stats
+
+	var s strings.Builder
+	s.WriteString(fmt.Sprintf("Last %d days: %s follows, %s unfollows, %.1f new follows per week\n\n",
+		statsDays, format.Number(stats.Follows), format.Number(stats.Unfollows), stats.FollowsPerWeek))
+
+	follows := make([]int, len(stats.Days))
+	unfollows := make([]int, len(stats.Days))
+	for i, day := range stats.Days {
+		follows[i] = day.Follows
+		unfollows[i] = day.Unfollows
+	}
+	s.WriteString(itemStyle.Render("Follows per day    "+sparkline(follows)) + "\n")
+	s.WriteString(itemStyle.Render("Unfollows per day  "+sparkline(unfollows)) + "\n")
+
+	s.WriteString("\nMost followed targets:\n")
+	if len(stats.TopTargets) == 0 {
+		s.WriteString(itemStyle.Render("No user is followed by more than one watched account") + "\n")
+	}
+	for i, target := range stats.TopTargets {
+		item := fmt.Sprintf("%d. %s  followed by %d watched accounts", i+1, target.UserID, target.Accounts)
+		s.WriteString(itemStyle.Render(truncate(item, m.itemWidth())) + "\n")
+	}
+
+	s.WriteString("\nHighest churn:\n")
+	if len(stats.Churn) == 0 {
+		s.WriteString(itemStyle.Render("No changes detected") + "\n")
+	}
+	for i, account := range stats.Churn {
+		item := fmt.Sprintf("%d. @%s  +%d -%d, %.1f%% of %s followings, %.1f new follows per week",
+			i+1,
+			account.Username,
+			account.Follows,
+			account.Unfollows,
+			account.Rate*100,
+			format.Number(account.Following),
+			account.FollowsPerWeek)
+		s.WriteString(itemStyle.Render(truncate(item, m.itemWidth())) + "\n")
+	}
+
+	s.WriteString("\nLatest insights:\n")
+	if len(m.stats.insights) == 0 {
+		s.WriteString(itemStyle.Render("No insights found") + "\n")
+	}
+	for _, insight := range m.stats.insights {
+		item := fmt.Sprintf("%s  %s", insight.DetectedAt.Local().Format("Jan 2"), insight.Summary)
+		s.WriteString(itemStyle.Render(truncate(item, m.itemWidth())) + "\n")
+	}
+
+	return m.box().Render(s.String())
+}
@@ -5,74 +5,137 @@ import (
 )
 
 var (
-// Base colors
-subtle    = lipgloss.AdaptiveColor{Light: "#666666", Dark: "#4A4A4A"}
-highlight = lipgloss.AdaptiveColor{Light: "#7B61FF", Dark: "#9D86FF"}
-special   = lipgloss.AdaptiveColor{Light: "#00CC6A", Dark: "#00FF84"}
-
-// Styles
-titleStyle = lipgloss.NewStyle().
-    Foreground(highlight).
-    Bold(true).
-    Padding(0, 0).
-    MarginBottom(0)
-
-statusBarStyle = lipgloss.NewStyle().
-    Foreground(lipgloss.Color("#E2E2E2")).
-    Background(lipgloss.Color("#1A1B26")).
-    MarginTop(1).
-    Padding(0, 0)
-
-errorStyle = lipgloss.NewStyle().
-    Foreground(lipgloss.Color("#FF5555")).
-    Bold(true)
-
-listStyle = lipgloss.NewStyle().
-    Border(lipgloss.RoundedBorder()).
-    BorderForeground(subtle).
-    BorderStyle(lipgloss.RoundedBorder()).
-    Padding(1).
-    MarginTop(1)
-
-itemStyle = lipgloss.NewStyle().
-    PaddingLeft(4).
-    Foreground(lipgloss.Color("#CCCCCC"))
-
-selectedItemStyle = lipgloss.NewStyle().
-    PaddingLeft(2).
-    Foreground(special).
-    Bold(true).
-    SetString("→ ")
-
-// Input field styles
-inputPromptStyle = lipgloss.NewStyle().
-    Foreground(highlight).
-    Bold(true).
-    PaddingRight(1)
-
-inputStyle = lipgloss.NewStyle().
-    Foreground(lipgloss.Color("#FFFFFF")).
-    Background(lipgloss.Color("#2D2D3A")).
-    Padding(0, 1)
-
-placeholderStyle = lipgloss.NewStyle().
-    Foreground(lipgloss.Color("#808080")).
-    Italic(true)
-
-cursorStyle = lipgloss.NewStyle().
-    Foreground(highlight).
-    Bold(true)
-
-focusedInputStyle = inputStyle.Copy().
-    BorderStyle(lipgloss.NormalBorder()).
-    BorderForeground(highlight).
-    Background(lipgloss.Color("#363646"))
-
-helpStyle = lipgloss.NewStyle().
-    Foreground(lipgloss.Color("#ABABAB")).
-    MarginTop(1)
-
-removePromptStyle = lipgloss.NewStyle().
-    Foreground(highlight).
-    Bold(true)
-) 
\ No newline at end of file
+	// Styles, built from the current theme by applyTheme
+	titleStyle         lipgloss.Style
+	statusBarStyle     lipgloss.Style
+	errorStyle         lipgloss.Style
+	noticeStyle        lipgloss.Style
+	listStyle          lipgloss.Style
+	itemStyle          lipgloss.Style
+	selectedItemStyle  lipgloss.Style
+	inputPromptStyle   lipgloss.Style
+	inputStyle         lipgloss.Style
+	placeholderStyle   lipgloss.Style
+	cursorStyle        lipgloss.Style
+	focusedInputStyle  lipgloss.Style
+	helpStyle          lipgloss.Style
+	helpKeyStyle       lipgloss.Style
+	helpDescStyle      lipgloss.Style
+	activityStyle      lipgloss.Style
+	tableHeaderStyle   lipgloss.Style
+	tableCellStyle     lipgloss.Style
+	tableSelectedStyle lipgloss.Style
+	progressDoneStyle  lipgloss.Style
+	progressTodoStyle  lipgloss.Style
+	warnStyle          lipgloss.Style
+	removePromptStyle  lipgloss.Style
+)
+
+func init() {
+	applyTheme(themes[0])
+}
+
+// applyTheme rebuilds every style with the colors of t
+func applyTheme(t Theme) {
+	titleStyle = lipgloss.NewStyle().
+		Foreground(t.Highlight).
+		Bold(true).
+		Padding(0, 0).
+		MarginBottom(0)
+
+	statusBarStyle = lipgloss.NewStyle().
+		Foreground(t.StatusText).
+		Background(t.StatusBackground).
+		Reverse(t.Reverse).
+		MarginTop(1).
+		Padding(0, 0)
+
+	errorStyle = lipgloss.NewStyle().
+		Foreground(t.Error).
+		Bold(true)
+
+	noticeStyle = lipgloss.NewStyle().
+		Foreground(t.Special)
+
+	listStyle = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.Subtle).
+		BorderStyle(lipgloss.RoundedBorder()).
+		Padding(1).
+		MarginTop(1)
+
+	itemStyle = lipgloss.NewStyle().
+		PaddingLeft(4).
+		Foreground(t.Text)
+
+	selectedItemStyle = lipgloss.NewStyle().
+		PaddingLeft(2).
+		Foreground(t.Special).
+		Bold(true).
+		SetString("→ ")
+
+	// Input field styles
+	inputPromptStyle = lipgloss.NewStyle().
+		Foreground(t.Highlight).
+		Bold(true).
+		PaddingRight(1)
+
+	inputStyle = lipgloss.NewStyle().
+		Foreground(t.InputText).
+		Background(t.InputBackground).
+		Padding(0, 1)
+
+	placeholderStyle = lipgloss.NewStyle().
+		Foreground(t.Placeholder).
+		Italic(true)
+
+	cursorStyle = lipgloss.NewStyle().
+		Foreground(t.Highlight).
+		Bold(true)
+
+	focusedInputStyle = inputStyle.
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(t.Highlight).
+		Background(t.InputFocused)
+
+	helpStyle = lipgloss.NewStyle().
+		Foreground(t.Muted).
+		MarginTop(1)
+
+	helpKeyStyle = lipgloss.NewStyle().
+		Foreground(t.Highlight)
+
+	helpDescStyle = lipgloss.NewStyle().
+		Foreground(t.Muted)
+
+	activityStyle = lipgloss.NewStyle().
+		Foreground(t.Muted)
+
+	tableHeaderStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(t.Highlight).
+		Padding(0, 1).
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderBottom(true).
+		BorderForeground(t.Subtle)
+
+	tableCellStyle = lipgloss.NewStyle().
+		Padding(0, 1)
+
+	tableSelectedStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(t.Special)
+
+	progressDoneStyle = lipgloss.NewStyle().
+		Foreground(t.Special)
+
+	progressTodoStyle = lipgloss.NewStyle().
+		Foreground(t.Subtle)
+
+	warnStyle = lipgloss.NewStyle().
+		Foreground(t.Warn)
+
+	removePromptStyle = lipgloss.NewStyle().
+		Foreground(t.Highlight).
+		Bold(true)
+}
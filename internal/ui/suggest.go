@@ -0,0 +1,148 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"x-tracker/internal/api"
+	"x-tracker/internal/db"
+	"x-tracker/internal/format"
+	"x-tracker/internal/tracker"
+)
+
+const (
+	// suggestDelay is how long typing has to pause before a search is
+	// sent, so every keystroke doesn't cost a request
+	suggestDelay = 400 * time.Millisecond
+	// suggestMinLength is the shortest input searched for
+	suggestMinLength = 2
+	// suggestCount is how many suggestions are shown
+	suggestCount = 5
+)
+
+// suggestTickMsg fires once typing has paused on query
+type suggestTickMsg string
+
+// suggestionsMsg carries the search results for query
+type suggestionsMsg struct {
+	query string
+	users []api.UserResponse
+	err   error
+}
+
+// isListSeparator reports whether r separates handles in a typed list
+func isListSeparator(r rune) bool {
+	return r == ',' || unicode.IsSpace(r)
+}
+
+// suggestQuery returns the handle being typed: the last one of a list
+func suggestQuery(input string) string {
+	i := strings.LastIndexFunc(input, isListSeparator)
+	return strings.TrimPrefix(input[i+1:], "@")
+}
+
+// updateSuggestions schedules a search once the typed handle stops
+// changing, reusing earlier results for the same input
+func (m *Model) updateSuggestions() tea.Cmd {
+	query := suggestQuery(m.textInput.Value())
+	if query == m.suggestFor {
+		return nil
+	}
+	m.suggestFor = query
+	m.suggestions = nil
+	m.suggestSelected = 0
+
+	// The search covers X only, Bluesky handles are looked up when added
+	if !m.config.AddSuggestions || len(query) < suggestMinLength || tracker.PlatformOf(query) != db.PlatformX {
+		return nil
+	}
+	if users, ok := m.suggestCache[strings.ToLower(query)]; ok {
+		m.suggestions = users
+		return nil
+	}
+	return tea.Tick(suggestDelay, func(time.Time) tea.Msg {
+		return suggestTickMsg(query)
+	})
+}
+
+// searchSuggestions searches for query if it is still being typed
+func (m *Model) searchSuggestions(query string) tea.Cmd {
+	if m.mode != ModeAddAccount || query != m.suggestFor {
+		return nil
+	}
+	return func() tea.Msg {
+		users, err := m.api.SearchUsers(query, suggestCount)
+		return suggestionsMsg{query: query, users: users, err: err}
+	}
+}
+
+// setSuggestions shows the results of a search that still matches the input.
+// A failed search just leaves the suggestions out.
+func (m *Model) setSuggestions(msg suggestionsMsg) {
+	if msg.err != nil {
+		return
+	}
+	if m.suggestCache == nil {
+		m.suggestCache = make(map[string][]api.UserResponse)
+	}
+	m.suggestCache[strings.ToLower(msg.query)] = msg.users
+	if msg.query == m.suggestFor {
+		m.suggestions = msg.users
+		m.suggestSelected = 0
+	}
+}
+
+// completeSuggestion replaces the handle being typed with the selected
+// suggestion
+func (m *Model) completeSuggestion() {
+	if m.suggestSelected >= len(m.suggestions) {
+		return
+	}
+	value := m.textInput.Value()
+	handle := m.suggestions[m.suggestSelected].Legacy.ScreenName
+	cut := strings.LastIndexFunc(value, isListSeparator)
+	m.textInput.SetValue(value[:cut+1] + handle)
+	m.textInput.CursorEnd()
+
+	// The completed handle needs no further suggestions
+	m.suggestFor = handle
+	m.suggestions = nil
+}
+
+// clearSuggestions forgets the suggestions when add mode is left
+func (m *Model) clearSuggestions() {
+	m.suggestFor = ""
+	m.suggestions = nil
+	m.suggestSelected = 0
+}
+
+// renderSuggestions lists the users matching the typed handle with their
+// display name and follower count
+func (m *Model) renderSuggestions() string {
+	if len(m.suggestions) == 0 {
+		return ""
+	}
+
+	var s strings.Builder
+	for i, user := range m.suggestions {
+		line := "@" + user.Legacy.ScreenName
+		if user.Legacy.Name != "" {
+			line += "  " + user.Legacy.Name
+		}
+		line += fmt.Sprintf("  %s followers", format.Number(user.Legacy.FollowersCount))
+		if user.IsBlueVerified || user.Legacy.Verified {
+			line += " ✓"
+		}
+		line = truncate(line, m.itemWidth())
+		if i == m.suggestSelected {
+			s.WriteString(selectedItemStyle.Render(line) + "\n")
+		} else {
+			s.WriteString(itemStyle.Render(line) + "\n")
+		}
+	}
+	return "\n" + s.String()
+}
@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+
+	"x-tracker/internal/db"
+)
+
+// visibleAccounts returns the accounts shown in the list in its sort
+// order, only those with the tag filtered by if one is set
+func (m *Model) visibleAccounts() []db.WatchedAccount {
+	if m.tagFilter == "" {
+		return m.sortAccounts(m.accounts)
+	}
+	var accounts []db.WatchedAccount
+	for _, account := range m.accounts {
+		if account.HasTag(m.tagFilter) {
+			accounts = append(accounts, account)
+		}
+	}
+	return m.sortAccounts(accounts)
+}
+
+// cycleTagFilter moves the list filter to the next tag in alphabetical
+// order, back to all accounts after the last one
+func (m *Model) cycleTagFilter() {
+	var tags []string
+	for _, account := range m.accounts {
+		for _, tag := range account.Tags {
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	if len(tags) == 0 {
+		m.notice = `No accounts are tagged yet, use "x-tracker tag <username> <tags...>"`
+		return
+	}
+	slices.Sort(tags)
+
+	next := tags[0]
+	if m.tagFilter != "" {
+		next = ""
+		if i := slices.Index(tags, m.tagFilter); i >= 0 && i+1 < len(tags) {
+			next = tags[i+1]
+		}
+	}
+	m.tagFilter = next
+	m.selected = 0
+}
+
+// tagSuffix lists an account's tags after its name in the list
+func tagSuffix(account db.WatchedAccount) string {
+	if len(account.Tags) == 0 {
+		return ""
+	}
+	return " #" + strings.Join(account.Tags, " #")
+}
@@ -0,0 +1,157 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"x-tracker/config"
+)
+
+// Theme holds the colors the styles are built from
+type Theme struct {
+	Name string
+
+	Subtle    lipgloss.TerminalColor // borders, pending progress
+	Highlight lipgloss.TerminalColor // titles, prompts, keys
+	Special   lipgloss.TerminalColor // selection, notices, finished progress
+	Text      lipgloss.TerminalColor // list items
+	Muted     lipgloss.TerminalColor // help and the activity log
+	Error     lipgloss.TerminalColor
+	Warn      lipgloss.TerminalColor
+
+	StatusText       lipgloss.TerminalColor
+	StatusBackground lipgloss.TerminalColor
+	InputText        lipgloss.TerminalColor
+	InputBackground  lipgloss.TerminalColor
+	InputFocused     lipgloss.TerminalColor // input background while focused
+	Placeholder      lipgloss.TerminalColor
+
+	// Reverse draws the status bar in reverse video, for themes without a
+	// background color to set it apart
+	Reverse bool
+}
+
+// themes are selected with THEME, in the order of config.Themes
+var themes = []Theme{
+	{
+		Name:             "dark",
+		Subtle:           lipgloss.AdaptiveColor{Light: "#666666", Dark: "#4A4A4A"},
+		Highlight:        lipgloss.AdaptiveColor{Light: "#7B61FF", Dark: "#9D86FF"},
+		Special:          lipgloss.AdaptiveColor{Light: "#00CC6A", Dark: "#00FF84"},
+		Text:             lipgloss.Color("#CCCCCC"),
+		Muted:            lipgloss.Color("#ABABAB"),
+		Error:            lipgloss.Color("#FF5555"),
+		Warn:             lipgloss.Color("#F1FA8C"),
+		StatusText:       lipgloss.Color("#E2E2E2"),
+		StatusBackground: lipgloss.Color("#1A1B26"),
+		InputText:        lipgloss.Color("#FFFFFF"),
+		InputBackground:  lipgloss.Color("#2D2D3A"),
+		InputFocused:     lipgloss.Color("#363646"),
+		Placeholder:      lipgloss.Color("#808080"),
+	},
+	{
+		Name:             "light",
+		Subtle:           lipgloss.Color("#999999"),
+		Highlight:        lipgloss.Color("#5A3FD9"),
+		Special:          lipgloss.Color("#00875A"),
+		Text:             lipgloss.Color("#333333"),
+		Muted:            lipgloss.Color("#666666"),
+		Error:            lipgloss.Color("#C62828"),
+		Warn:             lipgloss.Color("#A66300"),
+		StatusText:       lipgloss.Color("#1A1B26"),
+		StatusBackground: lipgloss.Color("#E4E4EC"),
+		InputText:        lipgloss.Color("#1A1B26"),
+		InputBackground:  lipgloss.Color("#EDEDF2"),
+		InputFocused:     lipgloss.Color("#E0E0EA"),
+		Placeholder:      lipgloss.Color("#8A8A8A"),
+	},
+	{
+		Name:             "high-contrast",
+		Subtle:           lipgloss.Color("#FFFFFF"),
+		Highlight:        lipgloss.Color("#FFFF00"),
+		Special:          lipgloss.Color("#00FFFF"),
+		Text:             lipgloss.Color("#FFFFFF"),
+		Muted:            lipgloss.Color("#FFFFFF"),
+		Error:            lipgloss.Color("#FF3030"),
+		Warn:             lipgloss.Color("#FFAF00"),
+		StatusText:       lipgloss.Color("#000000"),
+		StatusBackground: lipgloss.Color("#FFFFFF"),
+		InputText:        lipgloss.Color("#FFFFFF"),
+		InputBackground:  lipgloss.Color("#000000"),
+		InputFocused:     lipgloss.Color("#000000"),
+		Placeholder:      lipgloss.Color("#C0C0C0"),
+	},
+	{
+		Name:             "monochrome",
+		Subtle:           lipgloss.NoColor{},
+		Highlight:        lipgloss.NoColor{},
+		Special:          lipgloss.NoColor{},
+		Text:             lipgloss.NoColor{},
+		Muted:            lipgloss.NoColor{},
+		Error:            lipgloss.NoColor{},
+		Warn:             lipgloss.NoColor{},
+		StatusText:       lipgloss.NoColor{},
+		StatusBackground: lipgloss.NoColor{},
+		InputText:        lipgloss.NoColor{},
+		InputBackground:  lipgloss.NoColor{},
+		InputFocused:     lipgloss.NoColor{},
+		Placeholder:      lipgloss.NoColor{},
+		Reverse:          true,
+	},
+}
+
+// themeByName returns the theme called name
+func themeByName(name string) (Theme, error) {
+	for _, theme := range themes {
+		if theme.Name == name {
+			return theme, nil
+		}
+	}
+	return Theme{}, fmt.Errorf("unknown theme %q", name)
+}
+
+// configuredTheme returns the theme the config asks for, monochrome when
+// NO_COLOR is set
+func (m *Model) configuredTheme() Theme {
+	name := m.config.Theme
+	if m.config.NoColor {
+		name = "monochrome"
+	}
+	theme, err := themeByName(name)
+	if err != nil {
+		return themes[0]
+	}
+	return theme
+}
+
+// setTheme restyles the whole UI, including the inputs, spinners and help
+// that hold copies of the styles
+func (m *Model) setTheme(theme Theme) {
+	applyTheme(theme)
+	m.theme = theme.Name
+
+	m.textInput.PlaceholderStyle = placeholderStyle
+	m.textInput.PromptStyle = inputPromptStyle
+	m.textInput.TextStyle = inputStyle
+	m.textInput.Cursor.Style = cursorStyle
+	m.paletteInput.PlaceholderStyle = placeholderStyle
+	m.paletteInput.TextStyle = inputStyle
+	m.paletteInput.Cursor.Style = cursorStyle
+	m.spinner.Style = lipgloss.NewStyle().Foreground(theme.Special)
+	m.brailleSpinner.Style = lipgloss.NewStyle().Foreground(theme.Highlight)
+	m.help = newHelp()
+}
+
+// cycleTheme switches to the next theme until the config is reloaded
+func (m *Model) cycleTheme() {
+	if m.config.NoColor {
+		m.notice = "Colors are turned off by NO_COLOR"
+		return
+	}
+	next := config.Themes[(slices.Index(config.Themes, m.theme)+1)%len(config.Themes)]
+	theme, _ := themeByName(next)
+	m.setTheme(theme)
+	m.notice = fmt.Sprintf("Theme: %s", next)
+}
@@ -0,0 +1,107 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"x-tracker/internal/db"
+	"x-tracker/internal/format"
+)
+
+const (
+	// trendingWindow is how far back the trending view looks for follows
+	trendingWindow = 7 * 24 * time.Hour
+	// trendingLimit is how many targets the trending view loads
+	trendingLimit = 50
+)
+
+// trendingLoadedMsg carries the ranked trending targets
+type trendingLoadedMsg []db.TrendingTarget
+
+func (m *Model) openTrending() tea.Cmd {
+	m.mode = ModeTrending
+	m.selected = 0
+	return m.loadTrending
+}
+
+func (m *Model) loadTrending() tea.Msg {
+	trending, err := m.db.GetTrendingTargets(time.Now().Add(-trendingWindow), trendingLimit)
+	if err != nil {
+		return err
+	}
+	return trendingLoadedMsg(trending)
+}
+
+func (m *Model) updateTrending(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.mode = ModeNormal
+		m.error = nil
+	case key.Matches(msg, m.keys.Up):
+		if m.selected > 0 {
+			m.selected--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.selected < len(m.trending)-1 {
+			m.selected++
+		}
+	case key.Matches(msg, m.keys.Promote):
+		return m.promoteTarget()
+	}
+	return nil
+}
+
+// promoteTarget adds the selected target to the watch list
+func (m *Model) promoteTarget() tea.Cmd {
+	if m.selected >= len(m.trending) {
+		return nil
+	}
+	userID := m.trending[m.selected].UserID
+	m.notice = fmt.Sprintf("Adding user %s to the watch list...", userID)
+	return func() tea.Msg {
+		account, err := m.tracker.AddAccountByID(userID)
+		if err != nil {
+			return err
+		}
+		m.audit("add", "@"+account.Username, "from trending targets")
+		m.notice = fmt.Sprintf("Watching @%s", account.Username)
+		return tea.Batch(m.loadAccounts, m.loadTrending)()
+	}
+}
+
+func (m *Model) renderTrending() string {
+	if len(m.trending) == 0 {
+		return m.box().Render("No user was followed by more than one watched account in the last 7 days")
+	}
+
+	var s strings.Builder
+	s.WriteString("Trending targets, last 7 days:\n\n")
+	start, end := scrollWindow(m.selected, len(m.trending), m.listRows())
+	s.WriteString(moreMarker("↑", start))
+	for i := start; i < end; i++ {
+		target := m.trending[i]
+		followedBy := make([]string, len(target.FollowedBy))
+		for j, name := range target.FollowedBy {
+			followedBy[j] = "@" + name
+		}
+		item := fmt.Sprintf("%d. %s  score %.1f, followed by %s, last followed %s ago",
+			i+1,
+			target.UserID,
+			target.Score,
+			strings.Join(followedBy, ", "),
+			format.Age(time.Since(target.LastFollowedAt)))
+		item = truncate(item, m.itemWidth())
+		if i == m.selected {
+			s.WriteString(selectedItemStyle.Render(item) + "\n")
+		} else {
+			s.WriteString(itemStyle.Render(item) + "\n")
+		}
+	}
+	s.WriteString(moreMarker("↓", len(m.trending)-end))
+
+	return m.box().Render(s.String())
+}
@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"x-tracker/internal/logger"
+)
+
+// wakeThreshold is how far the wall clock has to move ahead of the
+// monotonic clock between two check timer ticks to count as the machine
+// having slept. The monotonic clock, which timers run on, stands still
+// during suspend.
+const wakeThreshold = 30 * time.Second
+
+// caughtUpMsg reports that the catch-up cycle after a sleep has finished
+type caughtUpMsg struct {
+	asleep time.Duration
+	err    error
+}
+
+// clockGap returns how much further the wall clock moved than the
+// monotonic clock since the previous tick: the time spent asleep, or a
+// clock change. It is negative if the clock was set back.
+func (m *Model) clockGap(now time.Time) time.Duration {
+	previous := m.lastClockTick
+	m.lastClockTick = now
+	if previous.IsZero() {
+		return 0
+	}
+	return now.Round(0).Sub(previous.Round(0)) - now.Sub(previous)
+}
+
+// resume reschedules the periodic checks after the machine slept for
+// asleep. Timers stood still during the sleep, so the pending check would
+// come that much late. If a check was due while asleep, a single catch-up
+// cycle runs now instead; otherwise the next check is moved back to when
+// it was due on the wall clock.
+func (m *Model) resume(now time.Time, asleep time.Duration) tea.Cmd {
+	m.checkGeneration.Add(1)
+	sinceScheduled := now.Sub(m.lastCheckTime) + asleep
+
+	if sinceScheduled < m.checkInterval {
+		logger.Info("Resumed after %s asleep (or the clock jumped ahead), next check in %s",
+			asleep.Round(time.Second), (m.checkInterval - sinceScheduled).Round(time.Second))
+		m.lastCheckTime = now.Add(-sinceScheduled)
+		return m.scheduleCheck(m.checkInterval - sinceScheduled)
+	}
+
+	m.lastCheckTime = now
+	m.notice = fmt.Sprintf("Resumed after %s asleep, catching up...", asleep.Round(time.Minute))
+	return tea.Batch(m.scheduleCheck(m.checkInterval), func() tea.Msg {
+		return caughtUpMsg{asleep: asleep, err: m.tracker.CatchUp(asleep)}
+	})
+}
@@ -0,0 +1,65 @@
+package webhook
+
+import (
+	"strings"
+	"time"
+
+	"x-tracker/internal/api"
+)
+
+// BotScore returns a rough 0-100 estimate of how bot-like an account looks.
+// It only uses data already returned by the user lookup, so it costs no
+// extra API calls. Higher means more likely to be a bot.
+func BotScore(user *api.UserByIDResponse) int {
+	if user == nil {
+		return 0
+	}
+
+	score := 0
+	followers := user.Legacy.FollowersCount
+	following := user.Legacy.FriendsCount
+
+	// Account age
+	created, hasCreated := user.CreatedTime()
+	var ageDays float64
+	if hasCreated {
+		ageDays = time.Since(created).Hours() / 24
+		switch {
+		case ageDays < 30:
+			score += 30
+		case ageDays < 180:
+			score += 15
+		}
+	}
+
+	// Followers/following ratio
+	if following > 0 {
+		ratio := float64(followers) / float64(following)
+		switch {
+		case ratio < 0.1:
+			score += 25
+		case ratio < 0.5:
+			score += 10
+		}
+	}
+	if following > 4000 && followers < 100 {
+		score += 10
+	}
+
+	// Default avatar
+	if user.Legacy.DefaultProfileImage || strings.Contains(user.Legacy.ProfileImageURLHTTPS, "default_profile") {
+		score += 20
+	}
+
+	// Posting cadence
+	if user.Legacy.StatusesCount == 0 {
+		score += 15
+	} else if hasCreated && ageDays >= 1 && float64(user.Legacy.StatusesCount)/ageDays > 100 {
+		score += 15
+	}
+
+	if score > 100 {
+		score = 100
+	}
+	return score
+}
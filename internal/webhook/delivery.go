@@ -0,0 +1,81 @@
+package webhook
+
+import (
+    "errors"
+    "fmt"
+    "net/url"
+    "strings"
+    "time"
+
+    "x-tracker/internal/db"
+    "x-tracker/internal/logger"
+)
+
+// DeliveryLog stores every attempt to send a notification, so failed
+// deliveries can be looked into later
+type DeliveryLog interface {
+    RecordDelivery(delivery *db.Delivery) error
+}
+
+// statusError is returned when a channel rejects a message with an HTTP
+// error status
+type statusError struct {
+    prefix string
+    code   int
+}
+
+func (e *statusError) Error() string {
+    return fmt.Sprintf("%s: status=%d", e.prefix, e.code)
+}
+
+// SetDeliveryLog records every notification attempt in log
+func (m *NotificationManager) SetDeliveryLog(log DeliveryLog) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+    m.deliveries = log
+}
+
+// deliver sends one notification, logging and counting a failure as what,
+// and records the attempt, described by attempt, in the delivery log. It
+// reports whether the notification was delivered.
+func (m *NotificationManager) deliver(attempt db.Delivery, what string, send func() error) bool {
+    attempt.AttemptedAt = time.Now()
+    err := send()
+    attempt.Latency = time.Since(attempt.AttemptedAt)
+    if err != nil {
+        m.sendFailed(what, err)
+        attempt.Error = withoutURL(err)
+        var status *statusError
+        if errors.As(err, &status) {
+            attempt.StatusCode = status.code
+        }
+    } else {
+        m.sent.Add(1)
+    }
+
+    m.mu.RLock()
+    log := m.deliveries
+    m.mu.RUnlock()
+    if log != nil {
+        if err := log.RecordDelivery(&attempt); err != nil {
+            logger.Warn("Failed to record %s delivery: %v", attempt.Channel, err)
+        }
+    }
+    return err == nil
+}
+
+// withoutURL returns the error message minus the request URL, which holds
+// the webhook or bot token, so it can be stored
+func withoutURL(err error) string {
+    var urlErr *url.Error
+    if !errors.As(err, &urlErr) {
+        return err.Error()
+    }
+    return strings.Replace(err.Error(), urlErr.Error(), urlErr.Op+" request: "+urlErr.Err.Error(), 1)
+}
+
+// accountDelivery describes a notification about account for the
+// delivery log
+func accountDelivery(channel string, account *db.WatchedAccount, kind, summary string) db.Delivery {
+    return db.Delivery{Channel: channel, Account: account.Username, Kind: kind, Summary: summary}
+}
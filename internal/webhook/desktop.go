@@ -0,0 +1,123 @@
+package webhook
+
+import (
+    "context"
+    "fmt"
+    "os/exec"
+    "strings"
+    "time"
+
+    "x-tracker/internal/db"
+    "x-tracker/internal/format"
+)
+
+// desktopNames is how many users a desktop notification names before
+// summing up the rest
+const desktopNames = 5
+
+// DesktopNotifier shows short native notifications on the machine the
+// tracker runs on: Notification Center on macOS, a toast on Windows and
+// libnotify (notify-send) elsewhere
+type DesktopNotifier struct {
+    timeout time.Duration // how long the platform's notification command may take
+}
+
+func NewDesktopNotifier() *DesktopNotifier {
+    return &DesktopNotifier{timeout: 10 * time.Second}
+}
+
+// show displays a notification with a title and a body of a few lines
+func (d *DesktopNotifier) show(title, body string) error {
+    ctx, cancel := context.WithTimeout(context.Background(), d.timeout)
+    defer cancel()
+
+    cmd := desktopCommand(ctx, title, body)
+    if output, err := cmd.CombinedOutput(); err != nil {
+        if _, missing := err.(*exec.Error); missing {
+            return fmt.Errorf("desktop notifications unavailable: %w", err)
+        }
+        if text := strings.TrimSpace(string(output)); text != "" {
+            return fmt.Errorf("showing desktop notification: %w: %s", err, text)
+        }
+        return fmt.Errorf("showing desktop notification: %w", err)
+    }
+    return nil
+}
+
+func (d *DesktopNotifier) NotifyNewFollows(account *db.WatchedAccount, targets []Target, total int) error {
+    return d.show(fmt.Sprintf("New follows by @%s%s", account.Username, tagLabel(account)),
+        fmt.Sprintf("Followed %s: %s", countNoun(total, "account"), desktopTargets(targets, total)))
+}
+
+func (d *DesktopNotifier) NotifyUnfollows(account *db.WatchedAccount, targets []Target, total int) error {
+    return d.show(fmt.Sprintf("Unfollows by @%s%s", account.Username, tagLabel(account)),
+        fmt.Sprintf("Unfollowed %s: %s", countNoun(total, "account"), desktopTargets(targets, total)))
+}
+
+func (d *DesktopNotifier) NotifyProfileChanges(account *db.WatchedAccount, changes []db.ProfileEvent) error {
+    return d.show(fmt.Sprintf("Profile changed: @%s%s", account.Username, tagLabel(account)),
+        "Changed "+profileChangeSummary(changes))
+}
+
+func (d *DesktopNotifier) NotifyLostFollowers(account *db.WatchedAccount, lost []db.LostFollower) error {
+    names := make([]string, 0, min(len(lost), desktopNames))
+    for _, follower := range lost[:min(len(lost), desktopNames)] {
+        if follower.Username != "" {
+            names = append(names, "@"+follower.Username)
+        } else {
+            names = append(names, "ID "+follower.UserID)
+        }
+    }
+    return d.show(fmt.Sprintf("Lost followers: @%s%s", account.Username, tagLabel(account)),
+        fmt.Sprintf("%s stopped following: %s", countNoun(len(lost), "account"), joinNames(names, len(lost))))
+}
+
+func (d *DesktopNotifier) NotifyAccountStatus(account *db.WatchedAccount, previous db.AccountStatus) error {
+    return d.show("Account status changed", statusMessage(account, previous))
+}
+
+func (d *DesktopNotifier) NotifyOps(title, message string) error {
+    return d.show("⚠️ "+title, message)
+}
+
+func (d *DesktopNotifier) NotifySummary(message string) error {
+    return d.show("X Tracker", message)
+}
+
+// desktopTargets names the first few targets of a notification, e.g.
+// "@alice, @bob and 3 more"
+func desktopTargets(targets []Target, total int) string {
+    names := make([]string, 0, min(len(targets), desktopNames))
+    for _, target := range targets[:min(len(targets), desktopNames)] {
+        name := "ID " + target.UserID
+        if target.User != nil {
+            name = "@" + target.User.Legacy.ScreenName
+        }
+        if len(target.Notable) > 0 {
+            name = "⭐ " + name
+        }
+        names = append(names, name)
+    }
+    return joinNames(names, total)
+}
+
+// joinNames lists names out of total, adding how many were left out
+func joinNames(names []string, total int) string {
+    list := strings.Join(names, ", ")
+    if rest := total - len(names); rest > 0 {
+        if list == "" {
+            return format.Number(rest) + " users"
+        }
+        list += fmt.Sprintf(" and %s more", format.Number(rest))
+    }
+    return list
+}
+
+// countNoun formats a count with a noun that takes a plain "s" in the
+// plural
+func countNoun(n int, noun string) string {
+    if n == 1 {
+        return "1 " + noun
+    }
+    return format.Number(n) + " " + noun + "s"
+}
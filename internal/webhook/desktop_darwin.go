@@ -0,0 +1,19 @@
+package webhook
+
+import (
+    "context"
+    "os/exec"
+    "strings"
+)
+
+// desktopCommand shows a notification in Notification Center through
+// AppleScript
+func desktopCommand(ctx context.Context, title, body string) *exec.Cmd {
+    script := `display notification "` + appleScriptEscape(body) + `" with title "X Tracker" subtitle "` + appleScriptEscape(title) + `"`
+    return exec.CommandContext(ctx, "osascript", "-e", script)
+}
+
+// appleScriptEscape escapes text for an AppleScript string literal
+func appleScriptEscape(text string) string {
+    return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(text)
+}
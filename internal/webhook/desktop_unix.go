@@ -0,0 +1,14 @@
+//go:build !darwin && !windows
+
+package webhook
+
+import (
+    "context"
+    "os/exec"
+)
+
+// desktopCommand shows a notification through libnotify, which most Linux
+// and BSD desktops provide
+func desktopCommand(ctx context.Context, title, body string) *exec.Cmd {
+    return exec.CommandContext(ctx, "notify-send", "--app-name=x-tracker", "--", title, body)
+}
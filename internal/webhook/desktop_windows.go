@@ -0,0 +1,27 @@
+package webhook
+
+import (
+    "context"
+    "os"
+    "os/exec"
+)
+
+// toastScript shows a toast with the title and body passed in the
+// environment, so they need no escaping. Toasts have to come from a
+// registered app, so it borrows PowerShell's ID.
+const toastScript = `
+[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] | Out-Null
+$template = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $template.GetElementsByTagName('text')
+$text.Item(0).AppendChild($template.CreateTextNode($env:X_TRACKER_TITLE)) | Out-Null
+$text.Item(1).AppendChild($template.CreateTextNode($env:X_TRACKER_BODY)) | Out-Null
+$toast = [Windows.UI.Notifications.ToastNotification]::new($template)
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('{1AC14E77-02E7-4E5D-B744-2EB1AE5198B7}\WindowsPowerShell\v1.0\powershell.exe').Show($toast)
+`
+
+// desktopCommand shows a Windows toast notification through PowerShell
+func desktopCommand(ctx context.Context, title, body string) *exec.Cmd {
+    cmd := exec.CommandContext(ctx, "powershell", "-NoProfile", "-NonInteractive", "-Command", toastScript)
+    cmd.Env = append(os.Environ(), "X_TRACKER_TITLE="+title, "X_TRACKER_BODY="+body)
+    return cmd
+}
@@ -5,22 +5,35 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"time"
 
+	"x-tracker/config"
 	"x-tracker/internal/db"
+	"x-tracker/internal/format"
 	"x-tracker/internal/logger"
-	"x-tracker/internal/api"
 )
 
 type DiscordWebhook struct {
-	URL        string
-	httpClient *http.Client
+	URL         string
+	channel     string // name in the delivery log, e.g. "discord (tag crypto)"
+	notableRole string // role mentioned in messages announcing a notable follow, "" for none
+	limits      config.ChannelLimits
+	httpClient  *http.Client
 }
 
 type webhookPayload struct {
-	Username  string         `json:"username"`
-	AvatarURL string         `json:"avatar_url,omitempty"`
-	Embeds    []webhookEmbed `json:"embeds"`
+	Username        string           `json:"username"`
+	AvatarURL       string           `json:"avatar_url,omitempty"`
+	Content         string           `json:"content,omitempty"`
+	AllowedMentions *allowedMentions `json:"allowed_mentions,omitempty"`
+	Embeds          []webhookEmbed   `json:"embeds"`
+}
+
+// allowedMentions limits whom a message's content may ping
+type allowedMentions struct {
+	Parse []string `json:"parse"`
+	Roles []string `json:"roles,omitempty"`
 }
 
 type webhookEmbed struct {
@@ -43,142 +56,392 @@ type webhookEmbedFooter struct {
 	IconURL string `json:"icon_url,omitempty"`
 }
 
+// SentMessage identifies a message the webhook posted
+type SentMessage struct {
+	ID        string `json:"id"`
+	ChannelID string `json:"channel_id"`
+}
+
 func NewDiscordWebhook(webhookURL string) *DiscordWebhook {
 	return &DiscordWebhook{
-		URL: webhookURL,
-		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
+		URL:     webhookURL,
+		channel: ChannelDiscord,
+		limits:  defaultLimits,
+		httpClient: newHTTPClient(10 * time.Second),
 	}
 }
 
 func (d *DiscordWebhook) send(payload webhookPayload) error {
+	_, err := d.post(payload)
+	return err
+}
+
+// post sends the payload and returns the created message. Discord only
+// returns it when asked to wait, which also surfaces delivery errors.
+func (d *DiscordWebhook) post(payload webhookPayload) (*SentMessage, error) {
 	// Add logging for webhook URL
-	logger.Info("Attempting to send Discord webhook to URL: %s", d.URL)
+	logger.Debug("Attempting to send Discord webhook to URL: %s", d.URL)
 
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
-		return fmt.Errorf("marshaling webhook payload: %w", err)
+		return nil, fmt.Errorf("marshaling webhook payload: %w", err)
 	}
 
 	// Log the payload being sent
-	logger.Info("Sending webhook payload: %s", string(jsonData))
+	logger.Debug("Sending webhook payload: %s", string(jsonData))
+
+	target, err := url.Parse(d.URL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing webhook URL: %w", err)
+	}
+	query := target.Query()
+	query.Set("wait", "true")
+	target.RawQuery = query.Encode()
 
-	resp, err := d.httpClient.Post(d.URL, "application/json", bytes.NewBuffer(jsonData))
+	resp, err := d.httpClient.Post(target.String(), "application/json", bytes.NewBuffer(jsonData))
 	if err != nil {
-		return fmt.Errorf("sending webhook: %w", err)
+		return nil, fmt.Errorf("sending webhook: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Log the response status
-	logger.Info("Discord webhook response status: %d", resp.StatusCode)
+	logger.Debug("Discord webhook response status: %d", resp.StatusCode)
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("webhook error: status=%d", resp.StatusCode)
+		return nil, &statusError{prefix: "webhook error", code: resp.StatusCode}
 	}
 
 	logger.Info("Successfully sent Discord webhook notification")
-	return nil
+
+	var message SentMessage
+	if resp.StatusCode == http.StatusNoContent || json.NewDecoder(resp.Body).Decode(&message) != nil || message.ID == "" {
+		return nil, nil
+	}
+	return &message, nil
 }
 
-func (d *DiscordWebhook) NotifyNewFollows(account *db.WatchedAccount, follows []string, api *api.Client) error {
+// NotifyNewFollows announces total new follows, listing targets, which
+// start the list at position start
+func (d *DiscordWebhook) NotifyNewFollows(account *db.WatchedAccount, targets []Target, start, total int) (*SentMessage, error) {
 	if d.URL == "" {
 		logger.Info("Discord webhook URL is empty, skipping follow notification")
-		return nil
+		return nil, nil
 	}
 
-	logger.Info("Preparing follow notification for %s: +%d follows", account.Username, len(follows))
+	logger.Info("Preparing follow notification for %s: +%d follows", account.Username, total)
+	payload := followsPayload(account, targets, start, total, time.Now())
+	if d.notableRole != "" && start == 0 && anyNotable(targets) {
+		payload.Content = fmt.Sprintf("<@&%s> notable follow by @%s", d.notableRole, account.Username)
+		payload.AllowedMentions = &allowedMentions{Parse: []string{}, Roles: []string{d.notableRole}}
+	}
+	return d.post(payload)
+}
 
+// followsPayload builds the message announcing new follows
+func followsPayload(account *db.WatchedAccount, targets []Target, start, total int, at time.Time) webhookPayload {
 	followEmbed := webhookEmbed{
-		Title:       fmt.Sprintf("New Follows Detected for @%s", account.Username),
-		Description: fmt.Sprintf("Started following %d new accounts", len(follows)),
+		Title:       fmt.Sprintf("New Follows Detected for @%s%s%s", account.Username, tagLabel(account), continued(start)),
+		Description: fmt.Sprintf("Started following %s new accounts", format.Number(total)),
 		Color:       0x00ff00,
+		Timestamp:   at.Format(time.RFC3339),
+		Fields:      make([]webhookEmbedField, 0, len(targets)),
+		Footer: webhookEmbedFooter{
+			Text: "X Track",
+		},
+	}
+
+	if anyNotable(targets) {
+		followEmbed.Title = "⭐ " + followEmbed.Title
+		followEmbed.Color = 0xFFD700 // Gold for notable follows
+	}
+
+	// Add fields for each new follow
+	for i, target := range targets {
+		followEmbed.Fields = append(followEmbed.Fields, webhookEmbedField{
+			Name:   fmt.Sprintf("New Follow %d", start+i+1),
+			Value:  discordTargetValue(target),
+			Inline: true,
+		})
+	}
+	if text, ok := renderTemplate(templateFollows, ChannelDiscord, account, targets, start, total, at); ok {
+		templated(&followEmbed, text)
+	}
+
+	return webhookPayload{
+		Username: "X Follow Tracker",
+		Embeds:   []webhookEmbed{followEmbed},
+	}
+}
+
+// NotifyUnfollows announces total unfollows, listing targets, which start
+// the list at position start
+func (d *DiscordWebhook) NotifyUnfollows(account *db.WatchedAccount, targets []Target, start, total int) (*SentMessage, error) {
+	if d.URL == "" {
+		logger.Info("Discord webhook URL is empty, skipping unfollow notification")
+		return nil, nil
+	}
+
+	logger.Info("Preparing unfollow notification for %s: -%d unfollows", account.Username, total)
+	return d.post(unfollowsPayload(account, targets, start, total, time.Now()))
+}
+
+// unfollowsPayload builds the message announcing unfollows
+func unfollowsPayload(account *db.WatchedAccount, targets []Target, start, total int, at time.Time) webhookPayload {
+	unfollowEmbed := webhookEmbed{
+		Title:       fmt.Sprintf("Unfollows Detected for @%s%s%s", account.Username, tagLabel(account), continued(start)),
+		Description: fmt.Sprintf("Unfollowed %s accounts", format.Number(total)),
+		Color:       0xFF0000,
+		Timestamp:   at.Format(time.RFC3339),
+		Fields:      make([]webhookEmbedField, 0, len(targets)),
+		Footer: webhookEmbedFooter{
+			Text: "X Track",
+		},
+	}
+
+	// Add fields for each unfollow
+	for i, target := range targets {
+		unfollowEmbed.Fields = append(unfollowEmbed.Fields, webhookEmbedField{
+			Name:   fmt.Sprintf("Unfollow %d", start+i+1),
+			Value:  discordTargetValue(target),
+			Inline: true,
+		})
+	}
+	if text, ok := renderTemplate(templateUnfollows, ChannelDiscord, account, targets, start, total, at); ok {
+		templated(&unfollowEmbed, text)
+	}
+
+	return webhookPayload{
+		Username: "X Follow Tracker",
+		Embeds:   []webhookEmbed{unfollowEmbed},
+	}
+}
+
+// NotifyEarlyFollows sends the alert for follows of brand-new accounts,
+// mentioning the notable role when one is set
+func (d *DiscordWebhook) NotifyEarlyFollows(account *db.WatchedAccount, targets []Target, start, total int) error {
+	if d.URL == "" {
+		logger.Info("Discord webhook URL is empty, skipping early follow alert")
+		return nil
+	}
+
+	logger.Info("Preparing early follow alert for %s: %d follows", account.Username, total)
+	payload := earlyFollowsPayload(account, targets, start, total, time.Now())
+	if d.notableRole != "" && start == 0 {
+		payload.Content = fmt.Sprintf("<@&%s> early follow by @%s", d.notableRole, account.Username)
+		payload.AllowedMentions = &allowedMentions{Parse: []string{}, Roles: []string{d.notableRole}}
+	}
+	return d.send(payload)
+}
+
+// earlyFollowsPayload builds the alert announcing early follows
+func earlyFollowsPayload(account *db.WatchedAccount, targets []Target, start, total int, at time.Time) webhookPayload {
+	earlyEmbed := webhookEmbed{
+		Title:       fmt.Sprintf("🐣 Early Follow by @%s%s%s", account.Username, tagLabel(account), continued(start)),
+		Description: fmt.Sprintf("Followed %s brand-new accounts", format.Number(total)),
+		Color:       0x9B59B6, // Purple for early follows
+		Timestamp:   at.Format(time.RFC3339),
+		Fields:      make([]webhookEmbedField, 0, len(targets)),
+		Footer: webhookEmbedFooter{
+			Text: "X Track",
+		},
+	}
+
+	for i, target := range targets {
+		earlyEmbed.Fields = append(earlyEmbed.Fields, webhookEmbedField{
+			Name:   fmt.Sprintf("Early Follow %d", start+i+1),
+			Value:  discordTargetValue(target) + "\n" + earlyText(target),
+			Inline: true,
+		})
+	}
+
+	return webhookPayload{
+		Username: "X Follow Tracker",
+		Embeds:   []webhookEmbed{earlyEmbed},
+	}
+}
+
+func (d *DiscordWebhook) NotifyLostFollowers(account *db.WatchedAccount, lost []db.LostFollower) error {
+	if d.URL == "" {
+		logger.Info("Discord webhook URL is empty, skipping lost follower notification")
+		return nil
+	}
+
+	logger.Info("Preparing lost follower notification for %s: -%d followers", account.Username, len(lost))
+
+	lostEmbed := webhookEmbed{
+		Title:       fmt.Sprintf("Lost Followers for @%s%s", account.Username, tagLabel(account)),
+		Description: fmt.Sprintf("%s accounts stopped following @%s", format.Number(len(lost)), account.Username),
+		Color:       0xFF8C00,
 		Timestamp:   time.Now().Format(time.RFC3339),
-		Fields:      make([]webhookEmbedField, 0, len(follows)),
+		Fields:      make([]webhookEmbedField, 0, listed(d.limits, len(lost))),
 		Footer: webhookEmbedFooter{
 			Text: "X Track",
 		},
 	}
 
-	// Add fields for each new follow (up to 25)
-	for i, userID := range follows {
-		if i < 25 {
-			userDetails, err := api.GetUserByID(userID)
-			var username string
-			var following_followers int
-			if err != nil {
-				logger.Info("Failed to get username for ID %s: %v", userID, err)
-				username = userID
-			} else {
-				username = fmt.Sprintf("@%s", userDetails.Legacy.ScreenName)
-				following_followers = userDetails.Legacy.FollowersCount
-			}
-
-			followEmbed.Fields = append(followEmbed.Fields, webhookEmbedField{
-				Name:   fmt.Sprintf("New Follow %d", i+1),
-				Value:  username + " " + fmt.Sprintf("%d followers", following_followers),
-				Inline: true,
-			})
-		}
+	for i, follower := range lost[:listed(d.limits, len(lost))] {
+		lostEmbed.Fields = append(lostEmbed.Fields, webhookEmbedField{
+			Name:  fmt.Sprintf("Lost Follower %d", i+1),
+			Value: truncateField(lostFollowerLine(follower)),
+		})
 	}
 
 	payload := webhookPayload{
 		Username: "X Follow Tracker",
-		Embeds:   []webhookEmbed{followEmbed},
+		Embeds:   []webhookEmbed{lostEmbed},
 	}
 
 	return d.send(payload)
 }
 
-func (d *DiscordWebhook) NotifyUnfollows(account *db.WatchedAccount, unfollows []string, api *api.Client) error {
+// discordTargetValue formats a resolved target for an embed field, ending
+// in the event ID so bots reading the channel can tell events apart
+func discordTargetValue(target Target) string {
+	value := target.UserID + " 0 followers"
+	if target.User != nil {
+		value = fmt.Sprintf("@%s %s followers\nBot score: %d", 
+			target.User.Legacy.ScreenName, 
+			format.Number(target.User.Legacy.FollowersCount), 
+			target.BotScore)
+	}
+	if len(target.Notable) > 0 {
+		value += "\n" + notableText(target)
+	}
+	if target.EventID != "" {
+		value += fmt.Sprintf("\nEvent `%s`", target.EventID)
+	}
+	return value
+}
+
+func (d *DiscordWebhook) NotifyProfileChanges(account *db.WatchedAccount, changes []db.ProfileEvent) error {
 	if d.URL == "" {
-		logger.Info("Discord webhook URL is empty, skipping unfollow notification")
+		logger.Info("Discord webhook URL is empty, skipping profile notification")
 		return nil
 	}
 
-	logger.Info("Preparing unfollow notification for %s: -%d unfollows", account.Username, len(unfollows))
+	logger.Info("Preparing profile notification for %s: %d changes", account.Username, len(changes))
 
-	unfollowEmbed := webhookEmbed{
-		Title:       fmt.Sprintf("Unfollows Detected for @%s", account.Username),
-		Description: fmt.Sprintf("Unfollowed %d accounts", len(unfollows)),
-		Color:       0xFF0000,
+	profileEmbed := webhookEmbed{
+		Title:       fmt.Sprintf("Profile Changed for @%s%s", account.Username, tagLabel(account)),
+		Description: fmt.Sprintf("%d profile fields changed", len(changes)),
+		Color:       0x1DA1F2,
 		Timestamp:   time.Now().Format(time.RFC3339),
-		Fields:      make([]webhookEmbedField, 0, len(unfollows)),
+		Fields:      make([]webhookEmbedField, 0, len(changes)),
 		Footer: webhookEmbedFooter{
 			Text: "X Track",
 		},
 	}
 
-	// Add fields for each unfollow (up to 25)
-	for i, userID := range unfollows {
-		if i < 25 {
-			userDetails, err := api.GetUserByID(userID)
-			var username string
-			var following_followers int
-			if err != nil {
-				logger.Info("Failed to get username for ID %s: %v", userID, err)
-				username = userID
-			} else {
-				username = fmt.Sprintf("@%s", userDetails.Legacy.ScreenName)
-				following_followers = userDetails.Legacy.FollowersCount
-			}
-
-			unfollowEmbed.Fields = append(unfollowEmbed.Fields, webhookEmbedField{
-				Name:   fmt.Sprintf("Unfollow %d", i+1),
-				Value:  username + " " + fmt.Sprintf("%d followers", following_followers),
-				Inline: true,
-			})
-		}
+	for _, change := range changes {
+		profileEmbed.Fields = append(profileEmbed.Fields, webhookEmbedField{
+			Name: profileFieldLabel(change.Field),
+			Value: truncateField(fmt.Sprintf("%s\n→ %s",
+				profileValue(change.Field, change.OldValue),
+				profileValue(change.Field, change.NewValue))),
+		})
 	}
 
 	payload := webhookPayload{
 		Username: "X Follow Tracker",
-		Embeds:   []webhookEmbed{unfollowEmbed},
+		Embeds:   []webhookEmbed{profileEmbed},
+	}
+
+	return d.send(payload)
+}
+
+func (d *DiscordWebhook) NotifyAccountStatus(account *db.WatchedAccount, previous db.AccountStatus) error {
+	if d.URL == "" {
+		logger.Info("Discord webhook URL is empty, skipping status notification")
+		return nil
+	}
+
+	color := 0x808080 // Grey for accounts that disappeared
+	title := fmt.Sprintf("@%s Unavailable", account.Username)
+	if account.Status == db.AccountStatusActive {
+		color = 0x00ff00
+		title = fmt.Sprintf("@%s Available Again", account.Username)
+	} else if account.Status == db.AccountStatusSuspended {
+		title = fmt.Sprintf("@%s Suspended", account.Username)
+	}
+
+	payload := webhookPayload{
+		Username: "X Follow Tracker",
+		Embeds: []webhookEmbed{{
+			Title:       title,
+			Description: statusMessage(account, previous),
+			Color:       color,
+			Timestamp:   time.Now().Format(time.RFC3339),
+			Footer: webhookEmbedFooter{
+				Text: "X Track",
+			},
+		}},
+	}
+
+	return d.send(payload)
+}
+
+func (d *DiscordWebhook) NotifyOps(title, message string) error {
+	if d.URL == "" {
+		logger.Info("Discord webhook URL is empty, skipping ops alert")
+		return nil
+	}
+
+	payload := webhookPayload{
+		Username: "X Follow Tracker",
+		Embeds: []webhookEmbed{{
+			Title:       "⚠️ " + title,
+			Description: message,
+			Color:       0xFFA500, // Orange for warnings
+			Timestamp:   time.Now().Format(time.RFC3339),
+			Footer: webhookEmbedFooter{
+				Text: "X Track",
+			},
+		}},
 	}
 
 	return d.send(payload)
 }
 
+// NotifySummary posts a compact status line, such as the end of a check cycle
+func (d *DiscordWebhook) NotifySummary(message string) error {
+	if d.URL == "" {
+		logger.Info("Discord webhook URL is empty, skipping summary")
+		return nil
+	}
+
+	payload := webhookPayload{
+		Username: "X Follow Tracker",
+		Embeds: []webhookEmbed{{
+			Description: message,
+			Color:       0x808080, // Gray for routine status
+			Timestamp:   time.Now().Format(time.RFC3339),
+			Footer: webhookEmbedFooter{
+				Text: "X Track",
+			},
+		}},
+	}
+
+	return d.send(payload)
+}
+
+// templated replaces an embed's description and fields with the text of a
+// message template, within Discord's 4096 character description limit
+func templated(embed *webhookEmbed, text string) {
+	const maxDescriptionLength = 4096
+	embed.Description = truncateText(maxDescriptionLength, text)
+	embed.Fields = []webhookEmbedField{}
+}
+
+// truncateField keeps a value within Discord's 1024 character field limit
+func truncateField(value string) string {
+	const maxFieldLength = 1024
+	runes := []rune(value)
+	if len(runes) <= maxFieldLength {
+		return value
+	}
+	return string(runes[:maxFieldLength-1]) + "…"
+}
+
 func (d *DiscordWebhook) NotifyFollowingChange(username string, newCount int) error {
 	if d.URL == "" {
 		return nil // Webhook notifications disabled
@@ -186,7 +449,7 @@ func (d *DiscordWebhook) NotifyFollowingChange(username string, newCount int) er
 
 	embed := webhookEmbed{
 		Title:       fmt.Sprintf("Following Count Changed for @%s", username),
-		Description: fmt.Sprintf("New following count: %d", newCount),
+		Description: fmt.Sprintf("New following count: %s", format.Number(newCount)),
 		Color:       0xFFA500, // Orange for changes
 		Timestamp:   time.Now().Format(time.RFC3339),
 		Footer: webhookEmbedFooter{
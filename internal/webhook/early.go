@@ -0,0 +1,96 @@
+package webhook
+
+import (
+    "fmt"
+    "time"
+
+    "x-tracker/internal/api"
+    "x-tracker/internal/db"
+    "x-tracker/internal/format"
+    "x-tracker/internal/logger"
+)
+
+// AnnotationSourceEarly marks the annotation given to the events of early
+// follows
+const AnnotationSourceEarly = "early"
+
+// EarlyLabel is the annotation label of early follows
+const EarlyLabel = "early"
+
+// earlyRule decides which follows are early follows: follows of accounts
+// that are both brand-new and still barely followed
+type earlyRule struct {
+    maxAge       time.Duration // 0 disables early follow alerts
+    maxFollowers int
+}
+
+// matches reports whether following user is an early follow
+func (r earlyRule) matches(user *api.UserByIDResponse, now time.Time) bool {
+    if r.maxAge <= 0 || user == nil {
+        return false
+    }
+    created, ok := user.CreatedTime()
+    return ok && now.Sub(created) < r.maxAge && user.Legacy.FollowersCount < r.maxFollowers
+}
+
+// earlyTargets returns the resolved targets that are early follows
+func (m *NotificationManager) earlyTargets(resolved []Target) []Target {
+    m.mu.RLock()
+    rule := m.config.early
+    m.mu.RUnlock()
+
+    now := time.Now()
+    var early []Target
+    for _, target := range resolved {
+        if rule.matches(target.User, now) {
+            early = append(early, target)
+        }
+    }
+    return early
+}
+
+// notifyEarlyFollows sends the separate alert for early follows. It runs
+// before the bot and account filters, which would otherwise drop exactly
+// the new, barely followed accounts it is looking for.
+func (m *NotificationManager) notifyEarlyFollows(account *db.WatchedAccount, resolved []Target, discord *DiscordWebhook, telegram *TelegramWebhook, signal *SignalWebhook) {
+    early := m.earlyTargets(resolved)
+    if len(early) == 0 {
+        return
+    }
+    logger.Info("%d early follows by %s", len(early), account.Username)
+    m.annotate(early, EarlyLabel, AnnotationSourceEarly)
+
+    total := len(early)
+    summary := fmt.Sprintf("%d early follows", total)
+    if discord != nil {
+        attempt := accountDelivery(discord.channel, account, "early follows", summary)
+        m.deliverPages(discord.limits, attempt, "Discord early follow alert", early, total, func(page []Target, start int) error {
+            return discord.NotifyEarlyFollows(account, page, start, total)
+        })
+    }
+    if telegram != nil {
+        attempt := accountDelivery(ChannelTelegram, account, "early follows", summary)
+        m.deliverPages(telegram.limits, attempt, "Telegram early follow alert", early, total, func(page []Target, start int) error {
+            return telegram.NotifyEarlyFollows(account, page, start, total)
+        })
+    }
+    if signal != nil {
+        attempt := accountDelivery(ChannelSignal, account, "early follows", summary)
+        m.deliverPages(signal.limits, attempt, "Signal early follow alert", early, total, func(page []Target, start int) error {
+            return signal.NotifyEarlyFollows(account, page, start, total)
+        })
+    }
+}
+
+// earlyText tells how new the account of an early follow is, e.g.
+// "🐣 created 2 days ago"
+func earlyText(target Target) string {
+    if target.User == nil {
+        return ""
+    }
+    created, ok := target.User.CreatedTime()
+    if !ok {
+        return ""
+    }
+    return "🐣 created " + format.Age(time.Since(created)) + " ago"
+}
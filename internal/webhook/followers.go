@@ -0,0 +1,19 @@
+package webhook
+
+import (
+    "fmt"
+
+    "x-tracker/internal/db"
+    "x-tracker/internal/format"
+)
+
+// lostFollowerLine describes a lost follower, e.g. "@user (Name, 1,234
+// followers), followed for 3 months"
+func lostFollowerLine(follower db.LostFollower) string {
+    who := fmt.Sprintf("ID: %s (profile unavailable)", follower.UserID)
+    if follower.Username != "" {
+        who = fmt.Sprintf("@%s (%s, %s followers)",
+            follower.Username, follower.DisplayName, format.Number(follower.FollowersCount))
+    }
+    return fmt.Sprintf("%s, followed for %s", who, format.Age(follower.FollowedFor()))
+}
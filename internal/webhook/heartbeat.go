@@ -0,0 +1,59 @@
+package webhook
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"x-tracker/internal/logger"
+)
+
+// Heartbeat periodically pings a monitoring URL (e.g. a healthchecks.io
+// check) so an external service can alert when the tracker stops running
+type Heartbeat struct {
+	url        string
+	interval   time.Duration
+	httpClient *http.Client
+}
+
+func NewHeartbeat(url string, interval time.Duration) *Heartbeat {
+	return &Heartbeat{
+		url:      url,
+		interval: interval,
+		httpClient: newHTTPClient(10 * time.Second),
+	}
+}
+
+// Run pings immediately and then on every interval until stop is closed
+func (h *Heartbeat) Run(stop <-chan struct{}) {
+	logger.Info("Starting heartbeat to %s every %s", h.url, h.interval)
+
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := h.ping(); err != nil {
+			logger.Warn("Heartbeat failed: %v", err)
+		}
+
+		select {
+		case <-stop:
+			logger.Info("Heartbeat stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (h *Heartbeat) ping() error {
+	resp, err := h.httpClient.Get(h.url)
+	if err != nil {
+		return fmt.Errorf("sending heartbeat: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("heartbeat error: status=%d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package webhook
+
+import (
+    "fmt"
+
+    "x-tracker/config"
+    "x-tracker/internal/db"
+)
+
+// defaultLimits apply to channels built without a configuration, such as
+// the ones of test notifications: up to maxNotifyTargets users in a single
+// message
+var defaultLimits = config.ChannelLimits{MaxItems: maxNotifyTargets, MaxMessages: 1}
+
+// summarizes reports whether a channel only sends the counts of a
+// notification about total changes
+func summarizes(limits config.ChannelLimits, total int) bool {
+    return limits.SummarizeAbove > 0 && total > limits.SummarizeAbove
+}
+
+// listed returns how many of n users a channel lists in a single message
+func listed(limits config.ChannelLimits, n int) int {
+    if summarizes(limits, n) {
+        return 0
+    }
+    return min(n, limits.MaxItems)
+}
+
+// pages splits the targets of a notification about total changes into the
+// messages a channel sends: a single one without targets when it only
+// sends the counts, otherwise up to MaxMessages of up to MaxItems targets
+func pages(limits config.ChannelLimits, targets []Target, total int) [][]Target {
+    if summarizes(limits, total) || len(targets) == 0 {
+        return [][]Target{nil}
+    }
+    var split [][]Target
+    for start := 0; start < len(targets) && len(split) < limits.MaxMessages; start += limits.MaxItems {
+        split = append(split, targets[start:min(start+limits.MaxItems, len(targets))])
+    }
+    return split
+}
+
+// deliverPages sends a notification about total changes as the messages
+// the channel's limits split its targets into, numbering them in the
+// delivery log when there are several. send gets the targets of one
+// message and the position of its first target in the whole list.
+func (m *NotificationManager) deliverPages(limits config.ChannelLimits, attempt db.Delivery, what string, targets []Target, total int, send func(page []Target, start int) error) {
+    split := pages(limits, targets, total)
+    start := 0
+    for i, page := range split {
+        pageAttempt, pageWhat := attempt, what
+        if len(split) > 1 {
+            pageAttempt.Summary += fmt.Sprintf(" (%d/%d)", i+1, len(split))
+            pageWhat += fmt.Sprintf(" (%d/%d)", i+1, len(split))
+        }
+        m.deliver(pageAttempt, pageWhat, func() error {
+            return send(page, start)
+        })
+        start += len(page)
+    }
+}
+
+// resolveLimit returns how many targets of a notification the enabled
+// channels list at most, so no user is looked up only to be left out, or
+// maxNotifyTargets if none is enabled, for previews
+func (m *NotificationManager) resolveLimit() int {
+    discord, telegram, signal := m.channels()
+
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+    limit := 0
+    add := func(limits config.ChannelLimits) {
+        limit = max(limit, limits.MaxItems*limits.MaxMessages)
+    }
+    if discord != nil || (m.config.enableDiscord && len(m.tagged) > 0) {
+        add(m.config.discordLimits)
+    }
+    if telegram != nil {
+        add(telegram.limits)
+    }
+    if signal != nil {
+        add(signal.limits)
+    }
+    if limit == 0 {
+        return maxNotifyTargets
+    }
+    return limit
+}
+
+// continued marks the title of every message of a notification but the
+// first
+func continued(start int) string {
+    if start == 0 {
+        return ""
+    }
+    return " (continued)"
+}
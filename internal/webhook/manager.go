@@ -1,66 +1,734 @@
 package webhook
 
 import (
+    "fmt"
+    "strings"
+    "sync"
+    "sync/atomic"
+    "time"
+
+    "x-tracker/config"
     "x-tracker/internal/api"
     "x-tracker/internal/db"
     "x-tracker/internal/logger"
 )
 
+// maxNotifyTargets is how many users a notification lists unless the
+// channel's limits say otherwise
+const maxNotifyTargets = 25
+
+// Target is a followed/unfollowed user resolved for a notification
+type Target struct {
+    UserID   string
+    User     *api.UserByIDResponse // nil if the lookup failed
+    BotScore int
+    EventID  string // UUID of the event announcing the target, empty if there is none
+    Notable  []string // why a followed target is notable, e.g. "verified"; nil if it isn't
+}
+
+// Channel names accepted by SetChannelEnabled
+const (
+    ChannelDiscord  = "discord"
+    ChannelTelegram = "telegram"
+    ChannelSignal   = "signal"
+    ChannelDesktop  = "desktop"
+)
+
+// MessageLog stores which events a posted message announced, so reactions
+// to it can later be turned into annotations
+type MessageLog interface {
+    RecordNotificationMessage(msg db.NotificationMessage) error
+}
+
+// UserLookup resolves the users follow and unfollow notifications
+// mention; *api.Client is one
+type UserLookup interface {
+    GetUserByID(userID string) (*api.UserByIDResponse, error)
+}
+
 type NotificationManager struct {
+    failures atomic.Int64 // notifications that could not be delivered since startup
+    sent     atomic.Int64 // notifications delivered since startup
+    mu       sync.RWMutex // guards config toggles changed at runtime
     discord  *DiscordWebhook
     telegram *TelegramWebhook
+    signal   *SignalWebhook
+    desktop  *DesktopNotifier
+    ops      *DiscordWebhook // separate ops channel, nil to use the notification channels
+    tagged   map[string]*DiscordWebhook // per-tag Discord channels replacing discord for tagged accounts
+    messages MessageLog // nil unless reactions are collected
+    deliveries DeliveryLog // nil to keep no delivery log
+    annotator EventAnnotator // nil to leave notable and early follows unannotated
     config   struct {
-        enableDiscord  bool
-        enableTelegram bool
+        enableDiscord     bool
+        enableTelegram    bool
+        enableSignal      bool
+        enableDesktop     bool
+        discordLimits     config.ChannelLimits // shared by the per-tag Discord channels
+        botScoreThreshold int
+        notable           notableRule
+        early             earlyRule
     }
 }
 
-func NewNotificationManager(discordURL, telegramToken, telegramChatID string, enableDiscord, enableTelegram bool) *NotificationManager {
-    manager := &NotificationManager{
-        config: struct {
-            enableDiscord  bool
-            enableTelegram bool
-        }{
-            enableDiscord:  enableDiscord,
-            enableTelegram: enableTelegram,
-        },
+func NewNotificationManager(cfg *config.Config) *NotificationManager {
+    manager := &NotificationManager{}
+    manager.apply(cfg)
+    return manager
+}
+
+// Reload rebuilds the channels from a reloaded configuration. Runtime
+// toggles made since startup are replaced by the configured values.
+func (m *NotificationManager) Reload(cfg *config.Config) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+    m.apply(cfg)
+    logger.Info("Notification manager reloaded (discord: %t, telegram: %t, signal: %t, desktop: %t)", m.discord != nil, m.telegram != nil, m.signal != nil, m.desktop != nil)
+}
+
+// apply sets up channels and toggles from cfg; callers hold the lock
+func (m *NotificationManager) apply(cfg *config.Config) {
+    m.config.enableDiscord = cfg.EnableDiscordNotifications
+    m.config.enableTelegram = cfg.EnableTelegramNotifications
+    m.config.enableSignal = cfg.EnableSignalNotifications
+    m.config.enableDesktop = cfg.EnableDesktopNotifications
+    m.config.botScoreThreshold = cfg.BotScoreThreshold
+    m.config.discordLimits = cfg.DiscordLimits
+    m.config.notable = notableRule{
+        verified:     cfg.NotableVerified,
+        minFollowers: cfg.NotableMinFollowers,
+        maxAge:       cfg.NotableMaxAge,
     }
-    
-    if enableDiscord && discordURL != "" {
-        manager.discord = NewDiscordWebhook(discordURL)
+    m.config.early = earlyRule{
+        maxAge:       cfg.EarlyFollowMaxAge,
+        maxFollowers: cfg.EarlyFollowMaxFollowers,
     }
-    
-    if enableTelegram && telegramToken != "" && telegramChatID != "" {
-        manager.telegram = NewTelegramWebhook(telegramToken, telegramChatID)
+
+    m.discord = nil
+    if cfg.EnableDiscordNotifications && cfg.DiscordWebhookURL != "" {
+        m.discord = NewDiscordWebhook(cfg.DiscordWebhookURL)
+        m.discord.notableRole = cfg.DiscordNotableRoleID
+        m.discord.limits = cfg.DiscordLimits
     }
-    
-    return manager
+
+    m.telegram = nil
+    if cfg.EnableTelegramNotifications && cfg.TelegramBotToken != "" && cfg.TelegramChatID != "" {
+        m.telegram = NewTelegramWebhook(cfg.TelegramBotToken, cfg.TelegramChatID)
+        m.telegram.limits = cfg.TelegramLimits
+    }
+
+    m.signal = nil
+    if cfg.EnableSignalNotifications && cfg.SignalAPIURL != "" && cfg.SignalNumber != "" && len(cfg.SignalRecipients) > 0 {
+        m.signal = NewSignalWebhook(cfg.SignalAPIURL, cfg.SignalNumber, cfg.SignalRecipients)
+        m.signal.limits = cfg.SignalLimits
+    }
+
+    m.desktop = nil
+    if cfg.EnableDesktopNotifications {
+        m.desktop = NewDesktopNotifier()
+    }
+
+    m.tagged = make(map[string]*DiscordWebhook)
+    if cfg.EnableDiscordNotifications {
+        for tag, url := range cfg.TagDiscordWebhooks {
+            m.tagged[tag] = NewDiscordWebhook(url)
+            m.tagged[tag].channel = fmt.Sprintf("%s (tag %s)", ChannelDiscord, tag)
+            m.tagged[tag].notableRole = cfg.DiscordNotableRoleID
+            m.tagged[tag].limits = cfg.DiscordLimits
+        }
+    }
+
+    m.ops = nil
+    if cfg.OpsDiscordWebhookURL != "" {
+        m.ops = NewDiscordWebhook(cfg.OpsDiscordWebhookURL)
+        m.ops.channel = ChannelDiscord + " ops"
+    }
+}
+
+// SetMessageLog records every Discord follow/unfollow message in log
+func (m *NotificationManager) SetMessageLog(log MessageLog) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+    m.messages = log
 }
 
-func (m *NotificationManager) NotifyNewFollows(account *db.WatchedAccount, follows []string, api *api.Client) {
-    if m.config.enableDiscord && m.discord != nil {
-        if err := m.discord.NotifyNewFollows(account, follows, api); err != nil {
-            logger.Info("Failed to send Discord follow notification: %v", err)
+// recordMessage remembers the events a Discord message announced
+func (m *NotificationManager) recordMessage(sent *SentMessage, account *db.WatchedAccount, eventType db.EventType, targets []Target) {
+    m.mu.RLock()
+    log := m.messages
+    m.mu.RUnlock()
+    if log == nil || sent == nil {
+        return
+    }
+
+    userIDs := make([]string, 0, len(targets))
+    for _, target := range targets {
+        userIDs = append(userIDs, target.UserID)
+    }
+    err := log.RecordNotificationMessage(db.NotificationMessage{
+        MessageID:        sent.ID,
+        ChannelID:        sent.ChannelID,
+        WatchedAccountID: account.ID,
+        EventType:        eventType,
+        UserIDs:          userIDs,
+        SentAt:           time.Now(),
+    })
+    if err != nil {
+        logger.Warn("Failed to record Discord message %s: %v", sent.ID, err)
+    }
+}
+
+// resolveTargets looks up the users of the first limit events and scores
+// them
+func (m *NotificationManager) resolveTargets(events []db.FollowEvent, api UserLookup, limit int) []Target {
+    targets := make([]Target, 0, min(len(events), limit))
+    for i, event := range events {
+        if i >= limit {
+            break
+        }
+
+        target := Target{UserID: event.UserID, EventID: event.UUID}
+        userDetails, err := api.GetUserByID(event.UserID)
+        if err != nil {
+            logger.Warn("Failed to get username for ID %s: %v", event.UserID, err)
+        } else {
+            target.User = userDetails
+            target.BotScore = BotScore(userDetails)
         }
+        targets = append(targets, target)
     }
-    
-    if m.config.enableTelegram && m.telegram != nil {
-        if err := m.telegram.NotifyNewFollows(account, follows, api); err != nil {
-            logger.Info("Failed to send Telegram follow notification: %v", err)
+    return targets
+}
+
+// filtersTargets reports whether the bot filter (if botFilter is set) or
+// the account's filter could drop any of its targets
+func (m *NotificationManager) filtersTargets(account *db.WatchedAccount, botFilter bool) bool {
+    m.mu.RLock()
+    threshold := m.config.botScoreThreshold
+    m.mu.RUnlock()
+    return account.Filter.Active() || (botFilter && threshold > 0)
+}
+
+// resolveFiltered looks up the users of the events, filters them and
+// returns the ones to list, at most as many as the enabled channels show,
+// with the number of events that passed the filters. The filters have to
+// see every event, so all of them are looked up when a filter is active;
+// otherwise only the listed ones are. resolved is every target looked up.
+func (m *NotificationManager) resolveFiltered(account *db.WatchedAccount, events []db.FollowEvent, api UserLookup, botFilter bool) (targets, resolved []Target, total int) {
+    limit := m.resolveLimit()
+    if !m.filtersTargets(account, botFilter) {
+        resolved = m.resolveTargets(events, api, limit)
+        return resolved, resolved, len(events)
+    }
+
+    resolved = m.resolveTargets(events, api, len(events))
+    targets = m.filterTargets(account, resolved, botFilter)
+    total = len(targets)
+    return targets[:min(len(targets), limit)], resolved, total
+}
+
+// isLikelyBot reports whether a target should be suppressed by the bot filter
+func (m *NotificationManager) isLikelyBot(target Target) bool {
+    m.mu.RLock()
+    threshold := m.config.botScoreThreshold
+    m.mu.RUnlock()
+    return threshold > 0 && target.User != nil && target.BotScore >= threshold
+}
+
+// passesFilter evaluates the account's notification filter against a target.
+// Targets we couldn't look up are let through rather than silently dropped.
+func passesFilter(filter db.NotificationFilter, target Target) bool {
+    if !filter.Active() || target.User == nil {
+        return true
+    }
+    if filter.MinFollowers > 0 && target.User.Legacy.FollowersCount <= filter.MinFollowers {
+        return false
+    }
+    if filter.VerifiedOnly && !target.User.IsVerified() {
+        return false
+    }
+    return true
+}
+
+// filterTargets drops targets excluded by the account filter (and the bot
+// filter for follows), returning the kept targets
+func (m *NotificationManager) filterTargets(account *db.WatchedAccount, resolved []Target, botFilter bool) []Target {
+    targets := make([]Target, 0, len(resolved))
+    for _, target := range resolved {
+        if botFilter && m.isLikelyBot(target) {
+            logger.Info("Suppressing likely-bot target @%s of %s (score %d)",
+                target.User.Legacy.ScreenName, account.Username, target.BotScore)
+            continue
+        }
+        if !passesFilter(account.Filter, target) {
+            logger.Info("Target @%s of %s filtered out (%d followers)",
+                target.User.Legacy.ScreenName, account.Username, target.User.Legacy.FollowersCount)
+            continue
         }
+        targets = append(targets, target)
     }
+    return targets
+}
+
+// sendFailed logs and counts a notification that could not be delivered
+func (m *NotificationManager) sendFailed(what string, err error) {
+    m.failures.Add(1)
+    logger.Error("Failed to send %s: %v", what, err)
+}
+
+// Failures returns how many notifications failed to send since startup
+func (m *NotificationManager) Failures() int64 {
+    return m.failures.Load()
 }
 
-func (m *NotificationManager) NotifyUnfollows(account *db.WatchedAccount, unfollows []string, api *api.Client) {
-    if m.config.enableDiscord && m.discord != nil {
-        if err := m.discord.NotifyUnfollows(account, unfollows, api); err != nil {
-            logger.Info("Failed to send Discord unfollow notification: %v", err)
+// Sent returns how many notifications were delivered since startup
+func (m *NotificationManager) Sent() int64 {
+    return m.sent.Load()
+}
+
+// SetChannelEnabled turns a configured channel on or off at runtime
+func (m *NotificationManager) SetChannelEnabled(channel string, enabled bool) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    switch channel {
+    case ChannelDiscord:
+        if m.discord == nil && len(m.tagged) == 0 {
+            return fmt.Errorf("discord webhook is not configured")
+        }
+        m.config.enableDiscord = enabled
+    case ChannelTelegram:
+        if m.telegram == nil {
+            return fmt.Errorf("telegram bot is not configured")
+        }
+        m.config.enableTelegram = enabled
+    case ChannelSignal:
+        if m.signal == nil {
+            return fmt.Errorf("signal gateway is not configured")
+        }
+        m.config.enableSignal = enabled
+    case ChannelDesktop:
+        if m.desktop == nil {
+            return fmt.Errorf("desktop notifications are not configured")
         }
+        m.config.enableDesktop = enabled
+    default:
+        return fmt.Errorf("unknown notification channel %q", channel)
     }
-    
-    if m.config.enableTelegram && m.telegram != nil {
-        if err := m.telegram.NotifyUnfollows(account, unfollows, api); err != nil {
-            logger.Info("Failed to send Telegram unfollow notification: %v", err)
+
+    logger.Info("Notification channel %s enabled: %t", channel, enabled)
+    return nil
+}
+
+// ChannelEnabled reports whether a channel is configured and currently enabled
+func (m *NotificationManager) ChannelEnabled(channel string) bool {
+    discord, telegram, signal := m.channels()
+    switch channel {
+    case ChannelDiscord:
+        return discord != nil
+    case ChannelTelegram:
+        return telegram != nil
+    case ChannelSignal:
+        return signal != nil
+    case ChannelDesktop:
+        return m.desktopChannel() != nil
+    }
+    return false
+}
+
+// channels returns the currently enabled channels, nil for disabled ones
+func (m *NotificationManager) channels() (*DiscordWebhook, *TelegramWebhook, *SignalWebhook) {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+
+    var discord *DiscordWebhook
+    var telegram *TelegramWebhook
+    var signal *SignalWebhook
+    if m.config.enableDiscord {
+        discord = m.discord
+    }
+    if m.config.enableTelegram {
+        telegram = m.telegram
+    }
+    if m.config.enableSignal {
+        signal = m.signal
+    }
+    return discord, telegram, signal
+}
+
+// desktopChannel returns the desktop notifier, nil if it is disabled
+func (m *NotificationManager) desktopChannel() *DesktopNotifier {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+    if !m.config.enableDesktop {
+        return nil
+    }
+    return m.desktop
+}
+
+// channelsFor returns the enabled channels for notifications about
+// account. Its first tag with a Discord webhook of its own, in alphabetical
+// order, replaces the default Discord channel.
+func (m *NotificationManager) channelsFor(account *db.WatchedAccount) (*DiscordWebhook, *TelegramWebhook, *SignalWebhook) {
+    discord, telegram, signal := m.channels()
+
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+    if !m.config.enableDiscord {
+        return discord, telegram, signal
+    }
+    for _, tag := range account.Tags {
+        if tagged := m.tagged[tag]; tagged != nil {
+            return tagged, telegram, signal
         }
     }
-} 
\ No newline at end of file
+    return discord, telegram, signal
+}
+
+func (m *NotificationManager) NotifyNewFollows(account *db.WatchedAccount, follows []db.FollowEvent, api UserLookup) {
+    discord, telegram, signal := m.channelsFor(account)
+    desktop := m.desktopChannel()
+    if discord == nil && telegram == nil && signal == nil && desktop == nil {
+        return
+    }
+
+    // Drop likely-bot and filtered follows before they reach any channel
+    targets, resolved, total := m.resolveFiltered(account, follows, api, true)
+    m.notifyEarlyFollows(account, resolved, discord, telegram, signal)
+
+    if total == 0 {
+        logger.Info("All %d new follows for %s were filtered out, skipping notification", len(follows), account.Username)
+        return
+    }
+    m.markNotable(targets)
+    m.recordNotable(targets)
+
+    summary := fmt.Sprintf("%d follows", total)
+    if discord != nil {
+        attempt := accountDelivery(discord.channel, account, "follows", summary)
+        m.deliverPages(discord.limits, attempt, "Discord follow notification", targets, total, func(page []Target, start int) error {
+            sent, err := discord.NotifyNewFollows(account, page, start, total)
+            if err == nil {
+                m.recordMessage(sent, account, db.EventTypeFollow, page)
+            }
+            return err
+        })
+    }
+
+    if telegram != nil {
+        attempt := accountDelivery(ChannelTelegram, account, "follows", summary)
+        m.deliverPages(telegram.limits, attempt, "Telegram follow notification", targets, total, func(page []Target, start int) error {
+            return telegram.NotifyNewFollows(account, page, start, total)
+        })
+    }
+
+    if signal != nil {
+        attempt := accountDelivery(ChannelSignal, account, "follows", summary)
+        m.deliverPages(signal.limits, attempt, "Signal follow notification", targets, total, func(page []Target, start int) error {
+            return signal.NotifyNewFollows(account, page, start, total)
+        })
+    }
+
+    if desktop != nil {
+        attempt := accountDelivery(ChannelDesktop, account, "follows", summary)
+        m.deliver(attempt, "Desktop follow notification", func() error {
+            return desktop.NotifyNewFollows(account, targets, total)
+        })
+    }
+}
+
+func (m *NotificationManager) NotifyUnfollows(account *db.WatchedAccount, unfollows []db.FollowEvent, api UserLookup) {
+    discord, telegram, signal := m.channelsFor(account)
+    desktop := m.desktopChannel()
+    if discord == nil && telegram == nil && signal == nil && desktop == nil {
+        return
+    }
+
+    targets, _, total := m.resolveFiltered(account, unfollows, api, false)
+    if total == 0 {
+        logger.Info("All %d unfollows for %s were filtered out, skipping notification", len(unfollows), account.Username)
+        return
+    }
+
+    summary := fmt.Sprintf("%d unfollows", total)
+    if discord != nil {
+        attempt := accountDelivery(discord.channel, account, "unfollows", summary)
+        m.deliverPages(discord.limits, attempt, "Discord unfollow notification", targets, total, func(page []Target, start int) error {
+            sent, err := discord.NotifyUnfollows(account, page, start, total)
+            if err == nil {
+                m.recordMessage(sent, account, db.EventTypeUnfollow, page)
+            }
+            return err
+        })
+    }
+
+    if telegram != nil {
+        attempt := accountDelivery(ChannelTelegram, account, "unfollows", summary)
+        m.deliverPages(telegram.limits, attempt, "Telegram unfollow notification", targets, total, func(page []Target, start int) error {
+            return telegram.NotifyUnfollows(account, page, start, total)
+        })
+    }
+
+    if signal != nil {
+        attempt := accountDelivery(ChannelSignal, account, "unfollows", summary)
+        m.deliverPages(signal.limits, attempt, "Signal unfollow notification", targets, total, func(page []Target, start int) error {
+            return signal.NotifyUnfollows(account, page, start, total)
+        })
+    }
+
+    if desktop != nil {
+        attempt := accountDelivery(ChannelDesktop, account, "unfollows", summary)
+        m.deliver(attempt, "Desktop unfollow notification", func() error {
+            return desktop.NotifyUnfollows(account, targets, total)
+        })
+    }
+}
+
+func (m *NotificationManager) NotifyProfileChanges(account *db.WatchedAccount, changes []db.ProfileEvent) {
+    discord, telegram, signal := m.channelsFor(account)
+
+    summary := profileChangeSummary(changes)
+    if discord != nil {
+        attempt := accountDelivery(discord.channel, account, "profile change", summary)
+        m.deliver(attempt, "Discord profile notification", func() error {
+            return discord.NotifyProfileChanges(account, changes)
+        })
+    }
+
+    if telegram != nil {
+        attempt := accountDelivery(ChannelTelegram, account, "profile change", summary)
+        m.deliver(attempt, "Telegram profile notification", func() error {
+            return telegram.NotifyProfileChanges(account, changes)
+        })
+    }
+
+    if signal != nil {
+        attempt := accountDelivery(ChannelSignal, account, "profile change", summary)
+        m.deliver(attempt, "Signal profile notification", func() error {
+            return signal.NotifyProfileChanges(account, changes)
+        })
+    }
+
+    if desktop := m.desktopChannel(); desktop != nil {
+        attempt := accountDelivery(ChannelDesktop, account, "profile change", summary)
+        m.deliver(attempt, "Desktop profile notification", func() error {
+            return desktop.NotifyProfileChanges(account, changes)
+        })
+    }
+}
+
+// NotifyLostFollowers lists the users who stopped following an account
+// whose followers are tracked
+func (m *NotificationManager) NotifyLostFollowers(account *db.WatchedAccount, lost []db.LostFollower) {
+    discord, telegram, signal := m.channelsFor(account)
+
+    summary := fmt.Sprintf("%d lost followers", len(lost))
+    if discord != nil {
+        attempt := accountDelivery(discord.channel, account, "lost followers", summary)
+        m.deliver(attempt, "Discord lost follower notification", func() error {
+            return discord.NotifyLostFollowers(account, lost)
+        })
+    }
+
+    if telegram != nil {
+        attempt := accountDelivery(ChannelTelegram, account, "lost followers", summary)
+        m.deliver(attempt, "Telegram lost follower notification", func() error {
+            return telegram.NotifyLostFollowers(account, lost)
+        })
+    }
+
+    if signal != nil {
+        attempt := accountDelivery(ChannelSignal, account, "lost followers", summary)
+        m.deliver(attempt, "Signal lost follower notification", func() error {
+            return signal.NotifyLostFollowers(account, lost)
+        })
+    }
+
+    if desktop := m.desktopChannel(); desktop != nil {
+        attempt := accountDelivery(ChannelDesktop, account, "lost followers", summary)
+        m.deliver(attempt, "Desktop lost follower notification", func() error {
+            return desktop.NotifyLostFollowers(account, lost)
+        })
+    }
+}
+
+// NotifyAccountStatus announces that a watched account became suspended,
+// unavailable or active again
+func (m *NotificationManager) NotifyAccountStatus(account *db.WatchedAccount, previous db.AccountStatus) {
+    discord, telegram, signal := m.channelsFor(account)
+
+    summary := fmt.Sprintf("%s, was %s", account.Status, previous)
+    if discord != nil {
+        attempt := accountDelivery(discord.channel, account, "status", summary)
+        m.deliver(attempt, "Discord status notification", func() error {
+            return discord.NotifyAccountStatus(account, previous)
+        })
+    }
+
+    if telegram != nil {
+        attempt := accountDelivery(ChannelTelegram, account, "status", summary)
+        m.deliver(attempt, "Telegram status notification", func() error {
+            return telegram.NotifyAccountStatus(account, previous)
+        })
+    }
+
+    if signal != nil {
+        attempt := accountDelivery(ChannelSignal, account, "status", summary)
+        m.deliver(attempt, "Signal status notification", func() error {
+            return signal.NotifyAccountStatus(account, previous)
+        })
+    }
+
+    if desktop := m.desktopChannel(); desktop != nil {
+        attempt := accountDelivery(ChannelDesktop, account, "status", summary)
+        m.deliver(attempt, "Desktop status notification", func() error {
+            return desktop.NotifyAccountStatus(account, previous)
+        })
+    }
+}
+
+// NotifyOps sends an operational alert about the tracker itself rather
+// than a watched account
+func (m *NotificationManager) NotifyOps(title, message string) {
+    if ops := m.opsChannel(); ops != nil {
+        attempt := db.Delivery{Channel: ops.channel, Kind: "ops alert", Summary: title}
+        m.deliver(attempt, "Discord ops alert", func() error {
+            return ops.NotifyOps(title, message)
+        })
+        return
+    }
+
+    discord, telegram, signal := m.channels()
+
+    if discord != nil {
+        attempt := db.Delivery{Channel: discord.channel, Kind: "ops alert", Summary: title}
+        m.deliver(attempt, "Discord ops alert", func() error {
+            return discord.NotifyOps(title, message)
+        })
+    }
+
+    if telegram != nil {
+        attempt := db.Delivery{Channel: ChannelTelegram, Kind: "ops alert", Summary: title}
+        m.deliver(attempt, "Telegram ops alert", func() error {
+            return telegram.NotifyOps(title, message)
+        })
+    }
+
+    if signal != nil {
+        attempt := db.Delivery{Channel: ChannelSignal, Kind: "ops alert", Summary: title}
+        m.deliver(attempt, "Signal ops alert", func() error {
+            return signal.NotifyOps(title, message)
+        })
+    }
+
+    if desktop := m.desktopChannel(); desktop != nil {
+        attempt := db.Delivery{Channel: ChannelDesktop, Kind: "ops alert", Summary: title}
+        m.deliver(attempt, "Desktop ops alert", func() error {
+            return desktop.NotifyOps(title, message)
+        })
+    }
+}
+
+// NotifyCycleSummary sends the one-line summary of a check cycle to the
+// ops channel
+func (m *NotificationManager) NotifyCycleSummary(summary string) {
+    if ops := m.opsChannel(); ops != nil {
+        attempt := db.Delivery{Channel: ops.channel, Kind: "cycle summary", Summary: summary}
+        m.deliver(attempt, "Discord cycle summary", func() error {
+            return ops.NotifySummary(summary)
+        })
+        return
+    }
+
+    discord, telegram, signal := m.channels()
+
+    if discord != nil {
+        attempt := db.Delivery{Channel: discord.channel, Kind: "cycle summary", Summary: summary}
+        m.deliver(attempt, "Discord cycle summary", func() error {
+            return discord.NotifySummary(summary)
+        })
+    }
+
+    if telegram != nil {
+        attempt := db.Delivery{Channel: ChannelTelegram, Kind: "cycle summary", Summary: summary}
+        m.deliver(attempt, "Telegram cycle summary", func() error {
+            return telegram.NotifySummary(summary)
+        })
+    }
+
+    if signal != nil {
+        attempt := db.Delivery{Channel: ChannelSignal, Kind: "cycle summary", Summary: summary}
+        m.deliver(attempt, "Signal cycle summary", func() error {
+            return signal.NotifySummary(summary)
+        })
+    }
+
+    if desktop := m.desktopChannel(); desktop != nil {
+        attempt := db.Delivery{Channel: ChannelDesktop, Kind: "cycle summary", Summary: summary}
+        m.deliver(attempt, "Desktop cycle summary", func() error {
+            return desktop.NotifySummary(summary)
+        })
+    }
+}
+
+// opsChannel returns the dedicated ops webhook, nil if none is configured
+func (m *NotificationManager) opsChannel() *DiscordWebhook {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+    return m.ops
+}
+
+// tagLabel lists an account's tags for notification titles, e.g.
+// " [crypto, founders]", or "" if it has none
+func tagLabel(account *db.WatchedAccount) string {
+    if len(account.Tags) == 0 {
+        return ""
+    }
+    return " [" + strings.Join(account.Tags, ", ") + "]"
+}
+
+// statusMessage describes an account status change in a sentence
+func statusMessage(account *db.WatchedAccount, previous db.AccountStatus) string {
+    switch account.Status {
+    case db.AccountStatusSuspended:
+        return fmt.Sprintf("@%s has been suspended. Checks continue and you'll be notified if it comes back.", account.Username)
+    case db.AccountStatusUnavailable:
+        return fmt.Sprintf("@%s is no longer available (deactivated, deleted or protected). Checks continue and you'll be notified if it comes back.", account.Username)
+    }
+    return fmt.Sprintf("@%s is available again (was %s).", account.Username, previous)
+}
+
+// profileChangeSummary lists the changed fields, e.g. "Bio, Avatar"
+func profileChangeSummary(changes []db.ProfileEvent) string {
+    labels := make([]string, 0, len(changes))
+    for _, change := range changes {
+        labels = append(labels, profileFieldLabel(change.Field))
+    }
+    return strings.Join(labels, ", ")
+}
+
+// profileFieldLabel names a profile field for notifications
+func profileFieldLabel(field db.ProfileField) string {
+    switch field {
+    case db.ProfileFieldUsername:
+        return "Handle"
+    case db.ProfileFieldDisplayName:
+        return "Display name"
+    case db.ProfileFieldBio:
+        return "Bio"
+    case db.ProfileFieldAvatar:
+        return "Avatar"
+    }
+    return string(field)
+}
+
+// profileValue formats a profile field value, marking empty ones
+func profileValue(field db.ProfileField, value string) string {
+    if value == "" {
+        return "(empty)"
+    }
+    if field == db.ProfileFieldUsername {
+        return "@" + value
+    }
+    return value
+}
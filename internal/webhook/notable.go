@@ -0,0 +1,112 @@
+package webhook
+
+import (
+    "strings"
+    "time"
+
+    "x-tracker/internal/api"
+    "x-tracker/internal/format"
+    "x-tracker/internal/logger"
+)
+
+// AnnotationSourceNotable marks the annotation given to the events of
+// notable follows
+const AnnotationSourceNotable = "notable"
+
+// NotableLabel is the annotation label of notable follows
+const NotableLabel = "notable"
+
+// EventAnnotator attaches labels to events, identified by their UUID
+type EventAnnotator interface {
+    AnnotateEvent(uuid, label, source string) error
+}
+
+// notableRule decides which newly followed accounts are notable
+type notableRule struct {
+    verified     bool
+    minFollowers int           // 0 disables
+    maxAge       time.Duration // 0 disables
+}
+
+// reasons returns why following user is notable, nil if it isn't
+func (r notableRule) reasons(user *api.UserByIDResponse, now time.Time) []string {
+    if user == nil {
+        return nil
+    }
+
+    var reasons []string
+    if r.verified && user.IsVerified() {
+        reasons = append(reasons, "verified")
+    }
+    if r.minFollowers > 0 && user.Legacy.FollowersCount >= r.minFollowers {
+        reasons = append(reasons, format.Number(user.Legacy.FollowersCount)+" followers")
+    }
+    if created, ok := user.CreatedTime(); ok && r.maxAge > 0 && now.Sub(created) < r.maxAge {
+        reasons = append(reasons, "created "+format.Age(now.Sub(created))+" ago")
+    }
+    return reasons
+}
+
+// SetEventAnnotator labels the events of notable follows in annotator
+func (m *NotificationManager) SetEventAnnotator(annotator EventAnnotator) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+    m.annotator = annotator
+}
+
+// markNotable fills in why each followed target is notable
+func (m *NotificationManager) markNotable(targets []Target) {
+    m.mu.RLock()
+    rule := m.config.notable
+    m.mu.RUnlock()
+
+    now := time.Now()
+    for i := range targets {
+        targets[i].Notable = rule.reasons(targets[i].User, now)
+    }
+}
+
+// recordNotable annotates the events of the notable targets
+func (m *NotificationManager) recordNotable(targets []Target) {
+    var notable []Target
+    for _, target := range targets {
+        if len(target.Notable) > 0 {
+            notable = append(notable, target)
+        }
+    }
+    m.annotate(notable, NotableLabel, AnnotationSourceNotable)
+}
+
+// annotate labels the events announcing targets
+func (m *NotificationManager) annotate(targets []Target, label, source string) {
+    m.mu.RLock()
+    annotator := m.annotator
+    m.mu.RUnlock()
+    if annotator == nil {
+        return
+    }
+
+    for _, target := range targets {
+        if target.EventID == "" {
+            continue
+        }
+        if err := annotator.AnnotateEvent(target.EventID, label, source); err != nil {
+            logger.Warn("Failed to mark event %s as %s: %v", target.EventID, label, err)
+        }
+    }
+}
+
+// anyNotable reports whether a notification announces a notable follow
+func anyNotable(targets []Target) bool {
+    for _, target := range targets {
+        if len(target.Notable) > 0 {
+            return true
+        }
+    }
+    return false
+}
+
+// notableText describes why a target is notable, e.g. "⭐ verified, 2.4M followers"
+func notableText(target Target) string {
+    return "⭐ " + strings.Join(target.Notable, ", ")
+}
@@ -0,0 +1,91 @@
+package webhook
+
+import (
+    "encoding/json"
+    "fmt"
+    "strings"
+    "time"
+
+    "x-tracker/internal/db"
+)
+
+// Preview is a follow or unfollow notification rendered for every channel
+// without sending it
+type Preview struct {
+    Discord  string // the embed as formatted text
+    Telegram string // the HTML message as sent
+    Signal   string // the plain-text message as sent
+    JSON     string // the Discord webhook request body
+}
+
+// Preview renders the notification that announcing events, which must
+// share their type, would send, timestamped when the first was detected.
+// Only the first message is rendered when a channel's limits split it.
+// The users are looked up like for a real notification, which costs one
+// API request each; filters are not applied.
+func (m *NotificationManager) Preview(account *db.WatchedAccount, events []db.FollowEvent, api UserLookup) Preview {
+    targets := m.resolveTargets(events, api, m.resolveLimit())
+    eventType, at, total := events[0].EventType, events[0].DetectedAt, len(events)
+    if eventType == db.EventTypeFollow {
+        m.markNotable(targets)
+    }
+
+    m.mu.RLock()
+    discordLimits, telegramLimits, signalLimits := m.config.discordLimits, defaultLimits, defaultLimits
+    if m.telegram != nil {
+        telegramLimits = m.telegram.limits
+    }
+    if m.signal != nil {
+        signalLimits = m.signal.limits
+    }
+    m.mu.RUnlock()
+    discordTargets := pages(discordLimits, targets, total)[0]
+    telegramTargets := pages(telegramLimits, targets, total)[0]
+    signalTargets := pages(signalLimits, targets, total)[0]
+
+    payload := followsPayload(account, discordTargets, 0, total, at)
+    telegram := followsMessage(account, telegramTargets, 0, total)
+    signal := signalFollowsMessage(account, signalTargets, 0, total)
+    if eventType == db.EventTypeUnfollow {
+        payload = unfollowsPayload(account, discordTargets, 0, total, at)
+        telegram = unfollowsMessage(account, telegramTargets, 0, total)
+        signal = signalUnfollowsMessage(account, signalTargets, 0, total)
+    }
+
+    body, err := json.MarshalIndent(payload, "", "  ")
+    if err != nil {
+        body = []byte(err.Error())
+    }
+
+    return Preview{
+        Discord:  discordText(payload),
+        Telegram: telegram,
+        Signal:   signal,
+        JSON:     string(body),
+    }
+}
+
+// discordText lays out a webhook message the way Discord shows it: title,
+// description, fields, then the footer and timestamp
+func discordText(payload webhookPayload) string {
+    var s strings.Builder
+    fmt.Fprintf(&s, "%s\n", payload.Username)
+    for _, embed := range payload.Embeds {
+        fmt.Fprintf(&s, "┃ %s\n", embed.Title)
+        if embed.Description != "" {
+            fmt.Fprintf(&s, "┃ %s\n", embed.Description)
+        }
+        for _, field := range embed.Fields {
+            fmt.Fprintf(&s, "┃\n┃ %s\n", field.Name)
+            for _, line := range strings.Split(field.Value, "\n") {
+                fmt.Fprintf(&s, "┃   %s\n", line)
+            }
+        }
+        footer := embed.Footer.Text
+        if t, err := time.Parse(time.RFC3339, embed.Timestamp); err == nil {
+            footer += " • " + t.Local().Format("2006-01-02 15:04")
+        }
+        fmt.Fprintf(&s, "┃\n┃ %s\n", footer)
+    }
+    return s.String()
+}
@@ -0,0 +1,139 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"x-tracker/internal/db"
+	"x-tracker/internal/logger"
+)
+
+// AnnotationSourceDiscord marks annotations that mirror Discord reactions
+const AnnotationSourceDiscord = "discord"
+
+// ReactionPoller reads the reactions on recent notification messages
+// through the Discord bot API and stores the mapped labels as annotations
+// on the events each message announced
+type ReactionPoller struct {
+	botToken   string
+	labels     map[string]string // normalized emoji name -> label
+	interval   time.Duration
+	window     time.Duration // messages older than this are no longer polled
+	db         db.Store
+	httpClient *http.Client
+}
+
+func NewReactionPoller(botToken string, labels map[string]string, interval, window time.Duration, database db.Store) *ReactionPoller {
+	normalized := make(map[string]string, len(labels))
+	for emoji, label := range labels {
+		normalized[normalizeEmoji(emoji)] = label
+	}
+	return &ReactionPoller{
+		botToken: botToken,
+		labels:   normalized,
+		interval: interval,
+		window:   window,
+		db:       database,
+		httpClient: newHTTPClient(10 * time.Second),
+	}
+}
+
+// discordReactions is the part of a channel message we read
+type discordReactions struct {
+	Reactions []struct {
+		Count int `json:"count"`
+		Emoji struct {
+			Name string `json:"name"`
+		} `json:"emoji"`
+	} `json:"reactions"`
+}
+
+// Run polls immediately and then on every interval until stop is closed
+func (r *ReactionPoller) Run(stop <-chan struct{}) {
+	logger.Info("Starting Discord reaction polling every %s", r.interval)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.poll(); err != nil {
+			logger.Warn("Reaction polling failed: %v", err)
+		}
+
+		select {
+		case <-stop:
+			logger.Info("Reaction polling stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll syncs the annotations of every message still inside the window
+func (r *ReactionPoller) poll() error {
+	messages, err := r.db.GetNotificationMessagesSince(time.Now().Add(-r.window))
+	if err != nil {
+		return fmt.Errorf("loading notification messages: %w", err)
+	}
+
+	for _, msg := range messages {
+		labels, err := r.messageLabels(msg)
+		if err != nil {
+			// Rate limits and outages affect every message; retry next round
+			return fmt.Errorf("reading reactions of message %s: %w", msg.MessageID, err)
+		}
+		if labels == nil {
+			continue
+		}
+		if err := r.db.SyncMessageAnnotations(msg, labels, AnnotationSourceDiscord); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// messageLabels fetches a message and maps its reactions to labels. It
+// returns nil labels (as opposed to an empty slice) if the message is gone.
+func (r *ReactionPoller) messageLabels(msg db.NotificationMessage) ([]string, error) {
+	url := fmt.Sprintf("https://discord.com/api/v10/channels/%s/messages/%s", msg.ChannelID, msg.MessageID)
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bot "+r.botToken)
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("discord API error: status=%d", resp.StatusCode)
+	}
+
+	var message discordReactions
+	if err := json.NewDecoder(resp.Body).Decode(&message); err != nil {
+		return nil, fmt.Errorf("decoding message: %w", err)
+	}
+
+	labels := []string{}
+	for _, reaction := range message.Reactions {
+		if label, ok := r.labels[normalizeEmoji(reaction.Emoji.Name)]; ok && reaction.Count > 0 {
+			labels = append(labels, label)
+		}
+	}
+	return labels, nil
+}
+
+// normalizeEmoji drops the variation selector Discord and keyboards add
+// inconsistently, so "⭐" and "⭐️" map to the same label
+func normalizeEmoji(name string) string {
+	return strings.ReplaceAll(name, "\uFE0F", "")
+}
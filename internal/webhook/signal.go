@@ -0,0 +1,177 @@
+package webhook
+
+import (
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "strings"
+    "time"
+
+    "x-tracker/config"
+    "x-tracker/internal/db"
+    "x-tracker/internal/format"
+)
+
+// SignalWebhook sends plain-text messages through a signal-cli REST API
+// gateway (github.com/bbernhard/signal-cli-rest-api)
+type SignalWebhook struct {
+    apiURL     string   // base URL of the gateway, e.g. http://localhost:8080
+    number     string   // number registered with the gateway, sending the messages
+    recipients []string // phone numbers or group IDs receiving them
+    limits     config.ChannelLimits
+    client     *http.Client
+}
+
+func NewSignalWebhook(apiURL, number string, recipients []string) *SignalWebhook {
+    return &SignalWebhook{
+        apiURL:     strings.TrimSuffix(apiURL, "/"),
+        number:     number,
+        recipients: recipients,
+        limits:     defaultLimits,
+        client:     newHTTPClient(10 * time.Second),
+    }
+}
+
+func (s *SignalWebhook) sendMessage(text string) error {
+    payload := map[string]interface{}{
+        "message":    text,
+        "number":     s.number,
+        "recipients": s.recipients,
+    }
+
+    jsonData, err := json.Marshal(payload)
+    if err != nil {
+        return fmt.Errorf("marshaling signal payload: %w", err)
+    }
+
+    resp, err := s.client.Post(s.apiURL+"/v2/send", "application/json", bytes.NewBuffer(jsonData))
+    if err != nil {
+        return fmt.Errorf("sending signal message: %w", err)
+    }
+    defer resp.Body.Close()
+
+    // The gateway answers 201 Created once the message is sent
+    if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+        return &statusError{prefix: "signal gateway error", code: resp.StatusCode}
+    }
+
+    return nil
+}
+
+func (s *SignalWebhook) NotifyNewFollows(account *db.WatchedAccount, targets []Target, start, total int) error {
+    return s.sendMessage(signalFollowsMessage(account, targets, start, total))
+}
+
+// signalFollowsMessage builds the plain-text message announcing new follows
+func signalFollowsMessage(account *db.WatchedAccount, targets []Target, start, total int) string {
+    if text, ok := renderTemplate(templateFollows, ChannelSignal, account, targets, start, total, time.Now()); ok {
+        return text
+    }
+    var message strings.Builder
+
+    star := ""
+    if anyNotable(targets) {
+        star = "⭐ "
+    }
+    fmt.Fprintf(&message, "%sNew Follows Detected for @%s%s%s\n", star, account.Username, tagLabel(account), continued(start))
+    fmt.Fprintf(&message, "Started following %s new accounts\n\n", format.Number(total))
+
+    for i, target := range targets {
+        writeSignalTarget(&message, start+i, target)
+    }
+
+    return message.String()
+}
+
+func (s *SignalWebhook) NotifyEarlyFollows(account *db.WatchedAccount, targets []Target, start, total int) error {
+    var message strings.Builder
+
+    fmt.Fprintf(&message, "🐣 Early Follow by @%s%s%s\n", account.Username, tagLabel(account), continued(start))
+    fmt.Fprintf(&message, "Followed %s brand-new accounts\n\n", format.Number(total))
+
+    for i, target := range targets {
+        writeSignalTarget(&message, start+i, target)
+        fmt.Fprintf(&message, "   %s\n", earlyText(target))
+    }
+
+    return s.sendMessage(message.String())
+}
+
+func (s *SignalWebhook) NotifyUnfollows(account *db.WatchedAccount, targets []Target, start, total int) error {
+    return s.sendMessage(signalUnfollowsMessage(account, targets, start, total))
+}
+
+// signalUnfollowsMessage builds the plain-text message announcing unfollows
+func signalUnfollowsMessage(account *db.WatchedAccount, targets []Target, start, total int) string {
+    if text, ok := renderTemplate(templateUnfollows, ChannelSignal, account, targets, start, total, time.Now()); ok {
+        return text
+    }
+    var message strings.Builder
+
+    fmt.Fprintf(&message, "Unfollows Detected for @%s%s%s\n", account.Username, tagLabel(account), continued(start))
+    fmt.Fprintf(&message, "Unfollowed %s accounts\n\n", format.Number(total))
+
+    for i, target := range targets {
+        writeSignalTarget(&message, start+i, target)
+    }
+
+    return message.String()
+}
+
+func (s *SignalWebhook) NotifyProfileChanges(account *db.WatchedAccount, changes []db.ProfileEvent) error {
+    var message strings.Builder
+
+    fmt.Fprintf(&message, "Profile Changed for @%s%s\n\n", account.Username, tagLabel(account))
+
+    for _, change := range changes {
+        fmt.Fprintf(&message, "%s\n%s\n→ %s\n\n",
+            profileFieldLabel(change.Field),
+            profileValue(change.Field, change.OldValue),
+            profileValue(change.Field, change.NewValue))
+    }
+
+    return s.sendMessage(message.String())
+}
+
+func (s *SignalWebhook) NotifyLostFollowers(account *db.WatchedAccount, lost []db.LostFollower) error {
+    var message strings.Builder
+
+    fmt.Fprintf(&message, "Lost Followers for @%s%s\n", account.Username, tagLabel(account))
+    fmt.Fprintf(&message, "%s accounts stopped following @%s\n\n", format.Number(len(lost)), account.Username)
+
+    for i, follower := range lost[:listed(s.limits, len(lost))] {
+        fmt.Fprintf(&message, "%d. %s\n", i+1, lostFollowerLine(follower))
+    }
+
+    return s.sendMessage(message.String())
+}
+
+func (s *SignalWebhook) NotifyAccountStatus(account *db.WatchedAccount, previous db.AccountStatus) error {
+    return s.sendMessage(fmt.Sprintf("Account Status Changed\n%s", statusMessage(account, previous)))
+}
+
+func (s *SignalWebhook) NotifyOps(title, message string) error {
+    return s.sendMessage(fmt.Sprintf("⚠️ %s\n%s", title, message))
+}
+
+func (s *SignalWebhook) NotifySummary(message string) error {
+    return s.sendMessage(message)
+}
+
+// writeSignalTarget writes one numbered line for a resolved target
+func writeSignalTarget(message *strings.Builder, i int, target Target) {
+    if target.User == nil {
+        fmt.Fprintf(message, "%d. ID: %s\n", i+1, target.UserID)
+        return
+    }
+    fmt.Fprintf(message, "%d. @%s (%s followers, bot score %d)",
+        i+1,
+        target.User.Legacy.ScreenName,
+        format.Number(target.User.Legacy.FollowersCount),
+        target.BotScore)
+    if len(target.Notable) > 0 {
+        message.WriteString(" " + notableText(target))
+    }
+    message.WriteString("\n")
+}
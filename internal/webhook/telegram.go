@@ -4,19 +4,21 @@ import (
     "bytes"
     "encoding/json"
     "fmt"
+    "html"
     "net/http"
-    "net/url"
     "strings"
     "time"
     
-    "x-tracker/internal/api"
+    "x-tracker/config"
     "x-tracker/internal/db"
+    "x-tracker/internal/format"
     "x-tracker/internal/logger"
 )
 
 type TelegramWebhook struct {
     botToken string
     chatID   string
+    limits   config.ChannelLimits
     client   *http.Client
 }
 
@@ -24,9 +26,8 @@ func NewTelegramWebhook(botToken, chatID string) *TelegramWebhook {
     return &TelegramWebhook{
         botToken: botToken,
         chatID:   chatID,
-        client: &http.Client{
-            Timeout: 10 * time.Second,
-        },
+        limits:   defaultLimits,
+        client:   newHTTPClient(10 * time.Second),
     }
 }
 
@@ -56,62 +57,132 @@ func (t *TelegramWebhook) sendMessage(text string) error {
     defer resp.Body.Close()
     
     if resp.StatusCode != http.StatusOK {
-        return fmt.Errorf("telegram API error: status=%d", resp.StatusCode)
+        return &statusError{prefix: "telegram API error", code: resp.StatusCode}
     }
     
     return nil
 }
 
-func (t *TelegramWebhook) NotifyNewFollows(account *db.WatchedAccount, follows []string, api *api.Client) error {
+func (t *TelegramWebhook) NotifyNewFollows(account *db.WatchedAccount, targets []Target, start, total int) error {
+    return t.sendMessage(followsMessage(account, targets, start, total))
+}
+
+// followsMessage builds the HTML message announcing new follows
+func followsMessage(account *db.WatchedAccount, targets []Target, start, total int) string {
+    if text, ok := renderTemplate(templateFollows, ChannelTelegram, account, targets, start, total, time.Now()); ok {
+        return text
+    }
     var message strings.Builder
     
-    fmt.Fprintf(&message, "<b>New Follows Detected for @%s</b>\n", account.Username)
-    fmt.Fprintf(&message, "Started following %d new accounts\n\n", len(follows))
+    star := ""
+    if anyNotable(targets) {
+        star = "⭐ "
+    }
+    fmt.Fprintf(&message, "<b>%sNew Follows Detected for @%s%s%s</b>\n", star, account.Username, html.EscapeString(tagLabel(account)), continued(start))
+    fmt.Fprintf(&message, "Started following %s new accounts\n\n", format.Number(total))
     
-    // Add details for each new follow (up to 25)
-    for i, userID := range follows {
-        if i >= 25 {
-            break
-        }
-        
-        userDetails, err := api.GetUserByID(userID)
-        if err != nil {
-            logger.Info("Failed to get username for ID %s: %v", userID, err)
-            fmt.Fprintf(&message, "%d. ID: %s\n", i+1, userID)
-        } else {
-            fmt.Fprintf(&message, "%d. @%s (%d followers)\n", 
-                i+1, 
-                userDetails.Legacy.ScreenName,
-                userDetails.Legacy.FollowersCount)
-        }
+    // Add details for each new follow
+    for i, target := range targets {
+        writeTelegramTarget(&message, start+i, target)
     }
     
-    return t.sendMessage(message.String())
+    return message.String()
+}
+
+func (t *TelegramWebhook) NotifyEarlyFollows(account *db.WatchedAccount, targets []Target, start, total int) error {
+    return t.sendMessage(earlyFollowsMessage(account, targets, start, total))
+}
+
+// earlyFollowsMessage builds the HTML alert announcing early follows
+func earlyFollowsMessage(account *db.WatchedAccount, targets []Target, start, total int) string {
+    var message strings.Builder
+    
+    fmt.Fprintf(&message, "<b>🐣 Early Follow by @%s%s%s</b>\n", account.Username, html.EscapeString(tagLabel(account)), continued(start))
+    fmt.Fprintf(&message, "Followed %s brand-new accounts\n\n", format.Number(total))
+    
+    for i, target := range targets {
+        writeTelegramTarget(&message, start+i, target)
+        fmt.Fprintf(&message, "   %s\n", earlyText(target))
+    }
+    
+    return message.String()
 }
 
-func (t *TelegramWebhook) NotifyUnfollows(account *db.WatchedAccount, unfollows []string, api *api.Client) error {
+func (t *TelegramWebhook) NotifyUnfollows(account *db.WatchedAccount, targets []Target, start, total int) error {
+    return t.sendMessage(unfollowsMessage(account, targets, start, total))
+}
+
+// unfollowsMessage builds the HTML message announcing unfollows
+func unfollowsMessage(account *db.WatchedAccount, targets []Target, start, total int) string {
+    if text, ok := renderTemplate(templateUnfollows, ChannelTelegram, account, targets, start, total, time.Now()); ok {
+        return text
+    }
     var message strings.Builder
     
-    fmt.Fprintf(&message, "<b>Unfollows Detected for @%s</b>\n", account.Username)
-    fmt.Fprintf(&message, "Unfollowed %d accounts\n\n", len(unfollows))
+    fmt.Fprintf(&message, "<b>Unfollows Detected for @%s%s%s</b>\n", account.Username, html.EscapeString(tagLabel(account)), continued(start))
+    fmt.Fprintf(&message, "Unfollowed %s accounts\n\n", format.Number(total))
     
-    // Add details for each unfollow (up to 25)
-    for i, userID := range unfollows {
-        if i >= 25 {
-            break
-        }
-        
-        userDetails, err := api.GetUserByID(userID)
-        if err != nil {
-            logger.Info("Failed to get username for ID %s: %v", userID, err)
-            fmt.Fprintf(&message, "%d. ID: %s\n", i+1, userID)
-        } else {
-            fmt.Fprintf(&message, "%d. @%s (%d followers)\n", 
-                i+1, 
-                userDetails.Legacy.ScreenName,
-                userDetails.Legacy.FollowersCount)
-        }
+    // Add details for each unfollow
+    for i, target := range targets {
+        writeTelegramTarget(&message, start+i, target)
     }
     
+    return message.String()
+}
+
+func (t *TelegramWebhook) NotifyProfileChanges(account *db.WatchedAccount, changes []db.ProfileEvent) error {
+    var message strings.Builder
+
+    fmt.Fprintf(&message, "<b>Profile Changed for @%s%s</b>\n\n", account.Username, html.EscapeString(tagLabel(account)))
+
+    for _, change := range changes {
+        fmt.Fprintf(&message, "<b>%s</b>\n%s\n→ %s\n\n",
+            profileFieldLabel(change.Field),
+            html.EscapeString(profileValue(change.Field, change.OldValue)),
+            html.EscapeString(profileValue(change.Field, change.NewValue)))
+    }
+
     return t.sendMessage(message.String())
-} 
\ No newline at end of file
+}
+
+func (t *TelegramWebhook) NotifyLostFollowers(account *db.WatchedAccount, lost []db.LostFollower) error {
+    var message strings.Builder
+
+    fmt.Fprintf(&message, "<b>Lost Followers for @%s%s</b>\n", account.Username, html.EscapeString(tagLabel(account)))
+    fmt.Fprintf(&message, "%s accounts stopped following @%s\n\n", format.Number(len(lost)), account.Username)
+
+    for i, follower := range lost[:listed(t.limits, len(lost))] {
+        fmt.Fprintf(&message, "%d. %s\n", i+1, html.EscapeString(lostFollowerLine(follower)))
+    }
+
+    return t.sendMessage(message.String())
+}
+
+func (t *TelegramWebhook) NotifyAccountStatus(account *db.WatchedAccount, previous db.AccountStatus) error {
+    return t.sendMessage(fmt.Sprintf("<b>Account Status Changed</b>\n%s", statusMessage(account, previous)))
+}
+
+func (t *TelegramWebhook) NotifyOps(title, message string) error {
+    return t.sendMessage(fmt.Sprintf("<b>⚠️ %s</b>\n%s", html.EscapeString(title), html.EscapeString(message)))
+}
+
+func (t *TelegramWebhook) NotifySummary(message string) error {
+    return t.sendMessage(html.EscapeString(message))
+}
+
+// writeTelegramTarget writes one numbered line for a resolved target
+func writeTelegramTarget(message *strings.Builder, i int, target Target) {
+    if target.User == nil {
+        fmt.Fprintf(message, "%d. ID: %s\n", i+1, target.UserID)
+        return
+    }
+    fmt.Fprintf(message, "%d. @%s (%s followers, bot score %d)", 
+        i+1, 
+        target.User.Legacy.ScreenName,
+        format.Number(target.User.Legacy.FollowersCount),
+        target.BotScore)
+    if len(target.Notable) > 0 {
+        message.WriteString(" " + notableText(target))
+    }
+    message.WriteString("\n")
+}
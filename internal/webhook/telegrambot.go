@@ -0,0 +1,154 @@
+package webhook
+
+import (
+    "context"
+    "fmt"
+    "html"
+    "net/http"
+    "slices"
+    "strconv"
+    "strings"
+    "time"
+
+    "x-tracker/internal/logger"
+)
+
+// telegramMaxReply keeps replies under Telegram's 4096 character limit
+const telegramMaxReply = 4000
+
+// BotCommand is a command the Telegram bot answers
+type BotCommand struct {
+    Usage       string // e.g. "/add <username>"
+    Description string
+    // Run carries out the command for sender with the words after it and
+    // returns the reply
+    Run func(sender string, args []string) (string, error)
+}
+
+// TelegramBot receives commands in the configured chat through long
+// polling and answers them. Messages from any other chat are ignored, so
+// only the people notifications go to can manage the tracker.
+type TelegramBot struct {
+    botToken string
+    chatID   int64
+    commands map[string]BotCommand
+    client   *http.Client
+    replies  *TelegramWebhook
+}
+
+// NewTelegramBot creates a bot answering commands, keyed by name without
+// the slash, sent in the chat chatID. The chat must be given by its
+// numeric ID, since a channel's @name can't send commands.
+func NewTelegramBot(botToken, chatID string, commands map[string]BotCommand) (*TelegramBot, error) {
+    id, err := strconv.ParseInt(chatID, 10, 64)
+    if err != nil {
+        return nil, fmt.Errorf("TELEGRAM_CHAT_ID must be a numeric chat ID to receive commands, got %q", chatID)
+    }
+    return &TelegramBot{
+        botToken: botToken,
+        chatID:   id,
+        commands: commands,
+        client:   newHTTPClient(telegramPollTimeout + 10*time.Second),
+        replies:  NewTelegramWebhook(botToken, chatID),
+    }, nil
+}
+
+// Run answers commands until stop is closed. Commands sent before it
+// started are skipped rather than carried out late.
+func (b *TelegramBot) Run(stop <-chan struct{}) {
+    logger.Info("Listening for Telegram bot commands")
+
+    ctx, cancel := context.WithCancel(context.Background())
+    defer cancel()
+    go func() {
+        <-stop
+        cancel()
+    }()
+
+    since := time.Now().Truncate(time.Second)
+    offset := 0
+    for {
+        messages, next, err := telegramUpdates(ctx, b.client, b.botToken, offset)
+        if ctx.Err() != nil {
+            logger.Info("Telegram bot commands stopped")
+            return
+        }
+        if err != nil {
+            logger.Warn("Receiving Telegram bot commands failed: %v", err)
+            select {
+            case <-ctx.Done():
+                logger.Info("Telegram bot commands stopped")
+                return
+            case <-time.After(telegramPollTimeout):
+            }
+            continue
+        }
+        offset = next
+
+        for _, message := range messages {
+            if !time.Unix(message.Date, 0).Before(since) {
+                b.handle(message)
+            }
+        }
+    }
+}
+
+// handle runs the command in a message and replies with its result
+func (b *TelegramBot) handle(message *telegramMessage) {
+    name, args := message.command()
+    if name == "" {
+        return
+    }
+    if message.Chat.ID != b.chatID {
+        logger.Warn("Ignoring Telegram command %s from %s in chat %d, which isn't TELEGRAM_CHAT_ID", name, message.sender(), message.Chat.ID)
+        return
+    }
+
+    var reply string
+    name = strings.TrimPrefix(name, "/")
+    if command, ok := b.commands[name]; ok {
+        logger.Info("Telegram command /%s from %s", name, message.sender())
+        result, err := command.Run(message.sender(), args)
+        if err != nil {
+            result = "⚠️ " + err.Error()
+        }
+        reply = html.EscapeString(truncateReply(result))
+    } else if name == "help" || name == "start" {
+        reply = b.help()
+    } else {
+        reply = fmt.Sprintf("Unknown command /%s, send /help for the list", html.EscapeString(name))
+    }
+
+    if err := b.replies.sendMessage(reply); err != nil {
+        logger.Warn("Replying to Telegram command /%s failed: %v", name, withoutURL(err))
+    }
+}
+
+// help lists the commands the bot answers
+func (b *TelegramBot) help() string {
+    names := make([]string, 0, len(b.commands))
+    for name := range b.commands {
+        names = append(names, name)
+    }
+    slices.Sort(names)
+
+    var text strings.Builder
+    text.WriteString("<b>x-tracker commands</b>\n")
+    for _, name := range names {
+        command := b.commands[name]
+        fmt.Fprintf(&text, "%s - %s\n", html.EscapeString(command.Usage), html.EscapeString(command.Description))
+    }
+    return text.String()
+}
+
+// truncateReply cuts a reply down to what fits in one message
+func truncateReply(reply string) string {
+    if len(reply) <= telegramMaxReply {
+        return reply
+    }
+    cut := strings.LastIndex(reply[:telegramMaxReply], "\n")
+    if cut < 0 {
+        cut = telegramMaxReply
+    }
+    return reply[:cut] + "\n…"
+}
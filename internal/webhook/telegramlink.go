@@ -0,0 +1,176 @@
+package webhook
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "net/url"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// telegramPollTimeout is how long one getUpdates request waits for a message
+const telegramPollTimeout = 30 * time.Second
+
+// TelegramChat is a chat the bot received a message in
+type TelegramChat struct {
+    ID        int64  `json:"id"`
+    Type      string `json:"type"` // "private", "group", "supergroup" or "channel"
+    Title     string `json:"title"`
+    Username  string `json:"username"`
+    FirstName string `json:"first_name"`
+}
+
+// Name describes the chat for people: the group title or the user's name
+func (c *TelegramChat) Name() string {
+    switch {
+    case c.Title != "":
+        return c.Title
+    case c.Username != "":
+        return "@" + c.Username
+    case c.FirstName != "":
+        return c.FirstName
+    }
+    return strconv.FormatInt(c.ID, 10)
+}
+
+// telegramResponse is the envelope of every Bot API response
+type telegramResponse struct {
+    OK          bool            `json:"ok"`
+    Description string          `json:"description"`
+    Result      json.RawMessage `json:"result"`
+}
+
+// telegramCall calls a Bot API method and decodes its result into result
+func telegramCall(ctx context.Context, client *http.Client, botToken, method string, params url.Values, result any) error {
+    endpoint := fmt.Sprintf("https://api.telegram.org/bot%s/%s?%s", botToken, method, params.Encode())
+    req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+    if err != nil {
+        return err
+    }
+
+    resp, err := client.Do(req)
+    if err != nil {
+        // The error includes the URL, which holds the token
+        if ctx.Err() != nil {
+            return ctx.Err()
+        }
+        return fmt.Errorf("calling telegram %s: request failed", method)
+    }
+    defer resp.Body.Close()
+
+    var body telegramResponse
+    if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+        return fmt.Errorf("telegram API error: status=%d", resp.StatusCode)
+    }
+    if !body.OK {
+        return fmt.Errorf("telegram API error: status=%d: %s", resp.StatusCode, body.Description)
+    }
+    return json.Unmarshal(body.Result, result)
+}
+
+// TelegramBotUsername returns the username of the bot the token belongs
+// to, confirming the token is valid
+func TelegramBotUsername(ctx context.Context, botToken string) (string, error) {
+    var me struct {
+        Username string `json:"username"`
+    }
+    client := newHTTPClient(10 * time.Second)
+    if err := telegramCall(ctx, client, botToken, "getMe", url.Values{}, &me); err != nil {
+        return "", err
+    }
+    return me.Username, nil
+}
+
+// telegramMessage is a message sent to the bot
+type telegramMessage struct {
+    Date int64        `json:"date"`
+    Text string       `json:"text"`
+    Chat TelegramChat `json:"chat"`
+    From *struct {
+        Username  string `json:"username"`
+        FirstName string `json:"first_name"`
+    } `json:"from"`
+}
+
+// command splits a message into a bot command and its arguments; command
+// is "" if the message isn't one. In groups commands may be addressed as
+// /start@botname, which is stripped.
+func (m *telegramMessage) command() (command string, args []string) {
+    fields := strings.Fields(m.Text)
+    if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+        return "", nil
+    }
+    command, _, _ = strings.Cut(fields[0], "@")
+    return strings.ToLower(command), fields[1:]
+}
+
+// sender names who sent the message
+func (m *telegramMessage) sender() string {
+    switch {
+    case m.From == nil:
+        return m.Chat.Name()
+    case m.From.Username != "":
+        return "@" + m.From.Username
+    }
+    return m.From.FirstName
+}
+
+// telegramUpdates long-polls the bot's messages from offset on, returning
+// them with the offset to ask for next
+func telegramUpdates(ctx context.Context, client *http.Client, botToken string, offset int) ([]*telegramMessage, int, error) {
+    var updates []struct {
+        UpdateID int              `json:"update_id"`
+        Message  *telegramMessage `json:"message"`
+    }
+    params := url.Values{
+        "offset":          {strconv.Itoa(offset)},
+        "timeout":         {strconv.Itoa(int(telegramPollTimeout.Seconds()))},
+        "allowed_updates": {`["message"]`},
+    }
+    if err := telegramCall(ctx, client, botToken, "getUpdates", params, &updates); err != nil {
+        return nil, offset, err
+    }
+
+    var messages []*telegramMessage
+    for _, update := range updates {
+        offset = update.UpdateID + 1
+        if update.Message != nil {
+            messages = append(messages, update.Message)
+        }
+    }
+    return messages, offset, nil
+}
+
+// WaitForTelegramStart long-polls the bot's updates until someone sends it
+// /start, and returns the chat it was sent in. Messages sent before since
+// are skipped so an old /start doesn't link the wrong chat. It fails if
+// the bot has a webhook set, since Telegram then doesn't hand out updates.
+func WaitForTelegramStart(ctx context.Context, botToken string, since time.Time) (*TelegramChat, error) {
+    client := newHTTPClient(telegramPollTimeout + 10*time.Second)
+    offset := 0
+    for {
+        messages, next, err := telegramUpdates(ctx, client, botToken, offset)
+        if err != nil {
+            return nil, err
+        }
+        offset = next
+
+        for _, message := range messages {
+            if time.Unix(message.Date, 0).Before(since.Truncate(time.Second)) {
+                continue
+            }
+            if command, _ := message.command(); command == "/start" {
+                return &message.Chat, nil
+            }
+        }
+    }
+}
+
+// ConfirmTelegramLink tells the linked chat it will receive notifications
+func ConfirmTelegramLink(botToken string, chat *TelegramChat) error {
+    t := NewTelegramWebhook(botToken, strconv.FormatInt(chat.ID, 10))
+    return t.sendMessage("✅ <b>x-tracker</b> will send its notifications to this chat")
+}
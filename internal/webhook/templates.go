@@ -0,0 +1,256 @@
+package webhook
+
+import (
+    "fmt"
+    "html"
+    "path/filepath"
+    "strings"
+    "sync"
+    "text/template"
+    "time"
+
+    "x-tracker/config"
+    "x-tracker/internal/db"
+    "x-tracker/internal/format"
+    "x-tracker/internal/logger"
+)
+
+// Names of the templates that replace built-in messages. A template named
+// after a channel and one of them, e.g. telegram.follows, takes precedence
+// on that channel.
+const (
+    templateFollows   = "follows"
+    templateUnfollows = "unfollows"
+)
+
+var (
+    templatesMu sync.RWMutex
+    // templates holds the user-defined message templates, nil to send the
+    // built-in messages
+    templates *template.Template
+)
+
+// ConfigureTemplates loads the message templates of NOTIFY_TEMPLATES for
+// the notifications sent from now on, or drops them if it is unset. The
+// file must define a follows or unfollows template, or a channel's own.
+func ConfigureTemplates(cfg *config.Config) error {
+    var parsed *template.Template
+    if path := cfg.NotifyTemplates; path != "" {
+        var err error
+        parsed, err = template.New(filepath.Base(path)).Funcs(templateFuncs(ChannelSignal)).ParseFiles(path)
+        if err != nil {
+            return fmt.Errorf("parsing notification templates: %w", err)
+        }
+        if !definesMessage(parsed) {
+            return fmt.Errorf("notification templates %s define neither %q nor %q", path, templateFollows, templateUnfollows)
+        }
+    }
+
+    templatesMu.Lock()
+    templates = parsed
+    templatesMu.Unlock()
+    return nil
+}
+
+// definesMessage reports whether a template set replaces any message
+func definesMessage(set *template.Template) bool {
+    for _, tmpl := range set.Templates() {
+        name := tmpl.Name()
+        if i := strings.LastIndex(name, "."); i >= 0 {
+            name = name[i+1:]
+        }
+        if name == templateFollows || name == templateUnfollows {
+            return true
+        }
+    }
+    return false
+}
+
+// templateData is what a message template is executed with
+type templateData struct {
+    Type      string            // follows or unfollows
+    Channel   string            // discord, telegram or signal
+    Account   *db.WatchedAccount
+    Targets   []templateTarget  // users listed in this message
+    Total     int               // changes the notification is about
+    More      int               // changes listed neither in this nor an earlier message
+    Continued bool              // an earlier message listed the first users
+    At        time.Time
+}
+
+// templateTarget is a followed or unfollowed user as templates see it
+type templateTarget struct {
+    Position  int    // in the whole list, from 1
+    UserID    string
+    Username  string // empty if the lookup failed, as are the fields below
+    Name      string
+    Followers int
+    Following int
+    Verified  bool
+    Created   time.Time
+    BotScore  int
+    Notable   []string // why a followed user is notable, e.g. "verified"
+    EventID   string
+}
+
+// renderTemplate renders the user-defined message of the given name for a
+// channel. It reports false when there is none, or it failed and the
+// built-in message should be sent instead.
+func renderTemplate(name, channel string, account *db.WatchedAccount, targets []Target, start, total int, at time.Time) (string, bool) {
+    templatesMu.RLock()
+    set := templates
+    templatesMu.RUnlock()
+    if set == nil {
+        return "", false
+    }
+    tmpl := set.Lookup(channel + "." + name)
+    if tmpl == nil {
+        tmpl = set.Lookup(name)
+    }
+    if tmpl == nil {
+        return "", false
+    }
+
+    data := templateData{
+        Type:      name,
+        Channel:   channel,
+        Account:   account,
+        Targets:   make([]templateTarget, len(targets)),
+        Total:     total,
+        More:      max(total-start-len(targets), 0),
+        Continued: start > 0,
+        At:        at,
+    }
+    for i, target := range targets {
+        data.Targets[i] = newTemplateTarget(start+i+1, target)
+    }
+
+    // Links and escaping depend on the channel, so its functions are bound
+    // to a copy of the set
+    bound, err := set.Clone()
+    if err == nil {
+        var out strings.Builder
+        err = bound.Funcs(templateFuncs(channel)).ExecuteTemplate(&out, tmpl.Name(), data)
+        if text := strings.TrimSpace(out.String()); err == nil && text != "" {
+            return text, true
+        }
+    }
+    if err == nil {
+        err = fmt.Errorf("empty output")
+    }
+    logger.Warn("Notification template %s failed, sending the built-in message: %v", tmpl.Name(), err)
+    return "", false
+}
+
+func newTemplateTarget(position int, target Target) templateTarget {
+    t := templateTarget{
+        Position: position,
+        UserID:   target.UserID,
+        BotScore: target.BotScore,
+        Notable:  target.Notable,
+        EventID:  target.EventID,
+    }
+    if user := target.User; user != nil {
+        t.Username = user.Legacy.ScreenName
+        t.Name = user.Legacy.Name
+        t.Followers = user.Legacy.FollowersCount
+        t.Following = user.Legacy.FriendsCount
+        t.Verified = user.IsVerified()
+        t.Created, _ = user.CreatedTime()
+    }
+    return t
+}
+
+// templateFuncs returns the helpers templates can call, with links and
+// escaping in the markup of channel
+func templateFuncs(channel string) template.FuncMap {
+    escape := func(s string) string { return s }
+    link := func(text, url string) string {
+        if text == url {
+            return url
+        }
+        return fmt.Sprintf("%s (%s)", text, url)
+    }
+    switch channel {
+    case ChannelDiscord:
+        escape = discordEscaper.Replace
+        link = func(text, url string) string {
+            return fmt.Sprintf("[%s](%s)", discordEscaper.Replace(text), url)
+        }
+    case ChannelTelegram:
+        escape = html.EscapeString
+        link = func(text, url string) string {
+            return fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(url), html.EscapeString(text))
+        }
+    }
+
+    return template.FuncMap{
+        "number":   format.Number,
+        "humanize": format.Compact,
+        "ago": func(t time.Time) string {
+            return relativeTime(t, time.Now())
+        },
+        "truncate":   truncateText,
+        "plural":     pluralize,
+        "join":       func(sep string, items []string) string { return strings.Join(items, sep) },
+        "escape":     escape,
+        "link":       link,
+        "profileURL": profileURL,
+        "profileLink": func(username string) string {
+            return link("@"+username, profileURL(username))
+        },
+    }
+}
+
+// discordEscaper keeps text from being read as Discord markdown
+var discordEscaper = strings.NewReplacer(
+    `\`, `\\`, "*", `\*`, "_", `\_`, "~", `\~`, "`", "\\`", "|", `\|`, ">", `\>`, "[", `\[`, "]", `\]`,
+)
+
+// profileURL returns the address of a user's profile on X, or on Bluesky
+// for handles with a dot in them
+func profileURL(username string) string {
+    if strings.Contains(username, ".") {
+        return "https://bsky.app/profile/" + username
+    }
+    return "https://x.com/" + username
+}
+
+// truncateText shortens s to at most n characters, ending in … if it was
+// cut
+func truncateText(n int, s string) string {
+    runes := []rune(s)
+    if n <= 0 || len(runes) <= n {
+        return s
+    }
+    return string(runes[:n-1]) + "…"
+}
+
+// pluralize returns a count with its noun, e.g. "1 follow" or "3 follows",
+// taking the plural form as a second word if adding an s doesn't make it
+func pluralize(n int, noun string, pluralForm ...string) string {
+    if n == 1 {
+        return "1 " + noun
+    }
+    if len(pluralForm) > 0 {
+        noun = pluralForm[0]
+    } else {
+        noun += "s"
+    }
+    return format.Number(n) + " " + noun
+}
+
+// relativeTime tells how long before now t was, e.g. "3 days ago"
+func relativeTime(t, now time.Time) string {
+    if t.IsZero() {
+        return "never"
+    }
+    d := now.Sub(t)
+    switch {
+    case d < time.Minute:
+        return "just now"
+    case d < time.Hour:
+        return pluralize(int(d/time.Minute), "minute") + " ago"
+    }
+    return format.Age(d) + " ago"
+}
@@ -0,0 +1,94 @@
+package webhook
+
+import (
+    "slices"
+    "time"
+
+    "x-tracker/internal/api"
+    "x-tracker/internal/db"
+)
+
+// TestResult is the outcome of sending the test notification to one channel
+type TestResult struct {
+    Channel string // e.g. "discord", "discord (tag crypto)", "telegram", "signal" or "desktop"
+    Err     error
+}
+
+// testAccount and testTargets make up the sample notification, clearly
+// labelled so nobody mistakes it for a real event
+var testAccount = db.WatchedAccount{Username: "example", Tags: []string{"test notification"}}
+
+func testTargets() []Target {
+    user := &api.UserByIDResponse{RestID: "783214"}
+    user.Legacy.ScreenName = "X"
+    user.Legacy.Name = "X"
+    user.Legacy.FollowersCount = 67_000_000
+    return []Target{{UserID: user.RestID, User: user}}
+}
+
+// SendTest sends a sample follow and unfollow notification through every
+// enabled channel, including per-tag Discord channels, and a sample alert
+// to the ops channel if there is one. Nothing is recorded and failures
+// don't count towards Failures; they are returned per channel instead.
+func (m *NotificationManager) SendTest() []TestResult {
+    discord, telegram, signal := m.channels()
+    targets := testTargets()
+    now := time.Now()
+
+    var results []TestResult
+    sendDiscord := func(d *DiscordWebhook) {
+        err := d.send(followsPayload(&testAccount, targets, 0, len(targets), now))
+        if err == nil {
+            err = d.send(unfollowsPayload(&testAccount, targets, 0, len(targets), now))
+        }
+        results = append(results, TestResult{Channel: d.channel, Err: err})
+    }
+
+    if discord != nil {
+        sendDiscord(discord)
+    }
+    m.mu.RLock()
+    tagged := make(map[string]*DiscordWebhook, len(m.tagged))
+    for tag, d := range m.tagged {
+        tagged[tag] = d
+    }
+    enableDiscord := m.config.enableDiscord
+    m.mu.RUnlock()
+    if enableDiscord {
+        tags := make([]string, 0, len(tagged))
+        for tag := range tagged {
+            tags = append(tags, tag)
+        }
+        slices.Sort(tags)
+        for _, tag := range tags {
+            sendDiscord(tagged[tag])
+        }
+    }
+
+    if telegram != nil {
+        err := telegram.sendMessage(followsMessage(&testAccount, targets, 0, len(targets)))
+        if err == nil {
+            err = telegram.sendMessage(unfollowsMessage(&testAccount, targets, 0, len(targets)))
+        }
+        results = append(results, TestResult{Channel: ChannelTelegram, Err: err})
+    }
+
+    if signal != nil {
+        err := signal.sendMessage(signalFollowsMessage(&testAccount, targets, 0, len(targets)))
+        if err == nil {
+            err = signal.sendMessage(signalUnfollowsMessage(&testAccount, targets, 0, len(targets)))
+        }
+        results = append(results, TestResult{Channel: ChannelSignal, Err: err})
+    }
+
+    if desktop := m.desktopChannel(); desktop != nil {
+        err := desktop.NotifyNewFollows(&testAccount, targets, len(targets))
+        results = append(results, TestResult{Channel: ChannelDesktop, Err: err})
+    }
+
+    if ops := m.opsChannel(); ops != nil {
+        err := ops.NotifyOps("Test notification", "This is a test of the ops channel sent by x-tracker. No action is needed.")
+        results = append(results, TestResult{Channel: ops.channel, Err: err})
+    }
+    return results
+}
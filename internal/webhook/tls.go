@@ -0,0 +1,80 @@
+package webhook
+
+import (
+    "crypto/tls"
+    "crypto/x509"
+    "fmt"
+    "net/http"
+    "os"
+    "sync"
+    "time"
+
+    "x-tracker/config"
+)
+
+var (
+    transportMu sync.RWMutex
+    // transport carries every request to a notification channel; it only
+    // differs from the default when a proxy, custom CA or client
+    // certificate is set
+    transport http.RoundTripper = http.DefaultTransport
+)
+
+// ConfigureTransport sets the proxy and TLS settings of the HTTP clients
+// created from now on: HTTP_PROXY or SOCKS5_PROXY, WEBHOOK_CA_FILE, a PEM
+// bundle trusted in addition to the system roots, and WEBHOOK_CLIENT_CERT
+// and WEBHOOK_CLIENT_KEY, a client certificate presented to servers that
+// ask for one. Unset ones are left at the default.
+func ConfigureTransport(cfg *config.Config) error {
+    caFile, certFile, keyFile := cfg.WebhookCAFile, cfg.WebhookClientCert, cfg.WebhookClientKey
+    proxy := cfg.ProxyURL()
+    if proxy == nil && caFile == "" && certFile == "" && keyFile == "" {
+        transportMu.Lock()
+        transport = http.DefaultTransport
+        transportMu.Unlock()
+        return nil
+    }
+
+    custom := http.DefaultTransport.(*http.Transport).Clone()
+    if proxy != nil {
+        custom.Proxy = http.ProxyURL(proxy)
+    }
+    if caFile != "" || certFile != "" || keyFile != "" {
+        tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+        if caFile != "" {
+            pem, err := os.ReadFile(caFile)
+            if err != nil {
+                return fmt.Errorf("reading CA bundle: %w", err)
+            }
+            pool, err := x509.SystemCertPool()
+            if err != nil {
+                pool = x509.NewCertPool()
+            }
+            if !pool.AppendCertsFromPEM(pem) {
+                return fmt.Errorf("no certificates found in CA bundle %s", caFile)
+            }
+            tlsConfig.RootCAs = pool
+        }
+        if certFile != "" || keyFile != "" {
+            cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+            if err != nil {
+                return fmt.Errorf("loading client certificate: %w", err)
+            }
+            tlsConfig.Certificates = []tls.Certificate{cert}
+        }
+        custom.TLSClientConfig = tlsConfig
+    }
+
+    transportMu.Lock()
+    transport = custom
+    transportMu.Unlock()
+    return nil
+}
+
+// newHTTPClient returns a client for a notification channel using the
+// configured proxy and TLS settings
+func newHTTPClient(timeout time.Duration) *http.Client {
+    transportMu.RLock()
+    defer transportMu.RUnlock()
+    return &http.Client{Timeout: timeout, Transport: transport}
+}